@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRequireBearerToken(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("mcp"))
+	})
+	tests := []struct {
+		name          string
+		authorization string
+		wantCode      int
+	}{
+		{name: "missing", wantCode: http.StatusUnauthorized},
+		{name: "wrong", authorization: "Bearer other", wantCode: http.StatusUnauthorized},
+		{name: "prefix of the token", authorization: "Bearer sec", wantCode: http.StatusUnauthorized},
+		{name: "wrong scheme", authorization: "Basic secret", wantCode: http.StatusUnauthorized},
+		{name: "no scheme", authorization: "secret", wantCode: http.StatusUnauthorized},
+		{name: "correct", authorization: "Bearer secret", wantCode: http.StatusOK},
+		{name: "lowercase scheme", authorization: "bearer secret", wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			requireBearerToken("secret", next).ServeHTTP(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Errorf("request with Authorization %q = %d, want %d", tt.authorization, rec.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("401 response has WWW-Authenticate %q, want %q", rec.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+		})
+	}
+}
+
+func TestHTTPHandlerAuth(t *testing.T) {
+	r := &readiness{}
+	r.installed.Store(true)
+	h := newHTTPHandler(http.NotFoundHandler(), r, "secret")
+	tests := []struct {
+		path     string
+		wantCode int
+	}{
+		{path: "/healthz", wantCode: http.StatusOK},
+		{path: "/readyz", wantCode: http.StatusOK},
+		{path: "/version", wantCode: http.StatusUnauthorized},
+		{path: "/mcp", wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.wantCode {
+			t.Errorf("GET %s without a token = %d, want %d", tt.path, rec.Code, tt.wantCode)
+		}
+	}
+}
+
+func TestResolveAuthToken(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		token     string
+		tokenFile string
+		env       string
+		want      string
+		wantErr   bool
+	}{
+		{name: "none"},
+		{name: "flag", token: "from-flag", env: "from-env", want: "from-flag"},
+		{name: "file", tokenFile: tokenFile, env: "from-env", want: "from-file"},
+		{name: "env", env: "from-env", want: "from-env"},
+		{name: "flag and file", token: "from-flag", tokenFile: tokenFile, wantErr: true},
+		{name: "missing file", tokenFile: filepath.Join(dir, "missing"), wantErr: true},
+		{name: "empty file", tokenFile: emptyFile, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(authTokenEnv, tt.env)
+			got, err := resolveAuthToken(tt.token, tt.tokenFile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveAuthToken() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveAuthToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
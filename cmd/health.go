@@ -0,0 +1,72 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// readiness tracks whether the server is ready to serve MCP requests, for the
+// probes of load balancers and Kubernetes in http mode.
+type readiness struct {
+	installed atomic.Bool
+	// requireAuth makes the server ready only once the credentials check
+	// passed.
+	requireAuth bool
+	authPassed  atomic.Bool
+}
+
+// notReadyReason returns why the server is not ready, or "" if it is.
+func (r *readiness) notReadyReason() string {
+	if !r.installed.Load() {
+		return "the tools and prompts are not installed yet"
+	}
+	if r.requireAuth && !r.authPassed.Load() {
+		return "the Application Default Credentials check has not passed"
+	}
+	return ""
+}
+
+// newHTTPHandler returns the handler of the http mode: mcpHandler, and the
+// /healthz, /readyz and /version endpoints. If authToken is set, mcpHandler
+// and /version require it, while the probes stay open.
+func newHTTPHandler(mcpHandler http.Handler, r *readiness, authToken string) http.Handler {
+	protect := func(h http.Handler) http.Handler {
+		if authToken == "" {
+			return h
+		}
+		return requireBearerToken(authToken, h)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, _ *http.Request) {
+		if reason := r.notReadyReason(); reason != "" {
+			http.Error(w, "not ready: "+reason, http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	mux.Handle("GET /version", protect(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"version": version})
+	})))
+	mux.Handle("/", protect(mcpHandler))
+	return mux
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	mcpHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("mcp"))
+	})
+	get := func(h http.Handler, path string) (int, string) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	tests := []struct {
+		name        string
+		installed   bool
+		requireAuth bool
+		authPassed  bool
+		wantReady   int
+	}{
+		{name: "installing", wantReady: http.StatusServiceUnavailable},
+		{name: "installed", installed: true, wantReady: http.StatusOK},
+		{name: "waiting for auth", installed: true, requireAuth: true, wantReady: http.StatusServiceUnavailable},
+		{name: "auth passed", installed: true, requireAuth: true, authPassed: true, wantReady: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &readiness{requireAuth: tt.requireAuth}
+			r.installed.Store(tt.installed)
+			r.authPassed.Store(tt.authPassed)
+			h := newHTTPHandler(mcpHandler, r, "")
+
+			if code, _ := get(h, "/healthz"); code != http.StatusOK {
+				t.Errorf("GET /healthz = %d, want %d", code, http.StatusOK)
+			}
+			if code, body := get(h, "/readyz"); code != tt.wantReady {
+				t.Errorf("GET /readyz = %d %q, want %d", code, body, tt.wantReady)
+			}
+			if code, body := get(h, "/mcp"); code != http.StatusOK || body != "mcp" {
+				t.Errorf("GET /mcp = %d %q, want the MCP handler", code, body)
+			}
+		})
+	}
+}
+
+func TestVersionEndpoint(t *testing.T) {
+	h := newHTTPHandler(http.NotFoundHandler(), &readiness{}, "")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /version = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("GET /version Content-Type = %q, want application/json", ct)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("GET /version returned invalid JSON %q: %v", rec.Body.String(), err)
+	}
+	if got["version"] != version {
+		t.Errorf("GET /version = %v, want version %q", got, version)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// logLevel is the level of the server logs, which the configuration file
+// can lower to debug after the logging is set up.
+var logLevel = new(slog.LevelVar)
+
+// parseLogLevel parses a level of --log-level: debug, info, warn or error.
+func parseLogLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q, want debug, info, warn or error", name)
+	}
+	return level, nil
+}
+
+// setupLogging makes slog, and the log package through it, write the records
+// at logLevel or above to the file at path, or to stderr if path is "". It
+// returns the file to close when the server stops.
+func setupLogging(path string) (io.Closer, error) {
+	var w io.WriteCloser = nopCloser{os.Stderr}
+	if path != "" {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the log file: %w", err)
+		}
+		w = f
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: logLevel})))
+	return w, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// debugWriter logs the messages mcp.LoggingTransport writes to it at debug
+// level.
+type debugWriter struct{}
+
+func (debugWriter) Write(p []byte) (int, error) {
+	slog.Debug("MCP message", "message", strings.TrimSpace(string(p)))
+	return len(p), nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    slog.Level
+		wantErr bool
+	}{
+		{name: "debug", want: slog.LevelDebug},
+		{name: "info", want: slog.LevelInfo},
+		{name: "WARN", want: slog.LevelWarn},
+		{name: "error", want: slog.LevelError},
+		{name: "verbose", wantErr: true},
+		{name: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLogLevel(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLogLevel(%q) error = %v, wantErr %t", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseLogLevel(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetupLoggingFile(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() {
+		slog.SetDefault(defaultLogger)
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+		logLevel.Set(slog.LevelInfo)
+	})
+
+	path := filepath.Join(t.TempDir(), "gke-mcp.log")
+	logLevel.Set(slog.LevelWarn)
+	closer, err := setupLogging(path)
+	if err != nil {
+		t.Fatalf("setupLogging() failed: %v", err)
+	}
+	slog.Info("Hidden below the level")
+	slog.Warn("Logged", "tool", "list_clusters")
+	logLevel.Set(slog.LevelDebug)
+	log.Printf("From the log package")
+	slog.Debug("Logged after lowering the level")
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs := string(b)
+	if strings.Contains(logs, "Hidden below the level") {
+		t.Errorf("the log file has a record below the level:\n%s", logs)
+	}
+	for _, want := range []string{
+		`level=WARN msg=Logged tool=list_clusters`,
+		`level=DEBUG msg="Logged after lowering the level"`,
+		`level=INFO msg="From the log package"`,
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("the log file doesn't contain %q:\n%s", want, logs)
+		}
+	}
+}
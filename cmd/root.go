@@ -19,10 +19,15 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"net/http"
+	"log/slog"
+	"net"
 	"os"
+	"os/signal"
 	"runtime/debug"
 	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	container "cloud.google.com/go/container/apiv1"
 	"cloud.google.com/go/container/apiv1/containerpb"
@@ -32,7 +37,6 @@ import (
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/tools"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/spf13/cobra"
-	"google.golang.org/api/option"
 )
 
 const (
@@ -43,8 +47,20 @@ var (
 	version = "(unknown)"
 
 	// command flags
-	serverMode string
-	serverPort int
+	serverMode               string
+	serverPort               int
+	releaseNotesHTMLFallback bool
+	verbose                  bool
+	readOnly                 bool
+	callTimeout              time.Duration
+	maxAttempts              int
+	configPath               string
+	readyAfterAuth           bool
+	authToken                string
+	authTokenFile            string
+	logLevelName             string
+	logFile                  string
+	shutdownGracePeriod      time.Duration
 
 	// rootCmd represents the base command when called without any subcommands
 	rootCmd = &cobra.Command{
@@ -70,6 +86,18 @@ var (
 		Run:   runInstallCursorCmd,
 	}
 
+	installVSCodeCmd = &cobra.Command{
+		Use:   "vscode",
+		Short: "Install the GKE MCP Server into your VS Code settings for GitHub Copilot.",
+		Run:   runInstallVSCodeCmd,
+	}
+
+	installAllCmd = &cobra.Command{
+		Use:   "all",
+		Short: "Install the GKE MCP Server into the settings of every AI tool found on this machine.",
+		Run:   runInstallAllCmd,
+	}
+
 	installClaudeDesktopCmd = &cobra.Command{
 		Use:   "claude-desktop",
 		Short: "Install the GKE MCP Server into your Claude Desktop settings.",
@@ -82,8 +110,87 @@ var (
 		Run:   runInstallClaudeCodeCmd,
 	}
 
+	installGooseCmd = &cobra.Command{
+		Use:   "goose",
+		Short: "Install the GKE MCP Server as a Goose extension.",
+		Run:   runInstallGooseCmd,
+	}
+
+	installJetBrainsCmd = &cobra.Command{
+		Use:   "jetbrains",
+		Short: "Install the GKE MCP Server into the AI Assistant settings of your JetBrains IDEs.",
+		Run:   runInstallJetBrainsCmd,
+	}
+
+	installManifestCmd = &cobra.Command{
+		Use:   "manifest",
+		Short: "Print the GKE MCP Server definition to add to the settings of any other AI tool.",
+		Run:   runInstallManifestCmd,
+	}
+
+	uninstallCmd = &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove the GKE MCP Server from your AI tool settings.",
+	}
+
+	uninstallGeminiCLICmd = &cobra.Command{
+		Use:   "gemini-cli",
+		Short: "Remove the GKE MCP Server from your Gemini CLI settings.",
+		Run:   uninstallRunner("gemini-cli", install.UninstallGeminiCLIExtension),
+	}
+
+	uninstallCursorCmd = &cobra.Command{
+		Use:   "cursor",
+		Short: "Remove the GKE MCP Server from your Cursor settings.",
+		Run:   uninstallRunner("cursor", install.UninstallCursorMCPExtension),
+	}
+
+	uninstallVSCodeCmd = &cobra.Command{
+		Use:   "vscode",
+		Short: "Remove the GKE MCP Server from your VS Code settings.",
+		Run:   uninstallRunner("VS Code", install.UninstallVSCodeMCPExtension),
+	}
+
+	uninstallClaudeDesktopCmd = &cobra.Command{
+		Use:   "claude-desktop",
+		Short: "Remove the GKE MCP Server from your Claude Desktop settings.",
+		Run:   uninstallRunner("Claude Desktop", install.UninstallClaudeDesktopExtension),
+	}
+
+	uninstallClaudeCodeCmd = &cobra.Command{
+		Use:   "claude-code",
+		Short: "Remove the GKE MCP Server from your Claude Code CLI settings.",
+		Run:   uninstallRunner("Claude Code", install.UninstallClaudeCodeExtension),
+	}
+
+	uninstallGooseCmd = &cobra.Command{
+		Use:   "goose",
+		Short: "Remove the GKE MCP Server from your Goose extensions.",
+		Run:   uninstallRunner("Goose", install.UninstallGooseExtension),
+	}
+
+	uninstallJetBrainsCmd = &cobra.Command{
+		Use:   "jetbrains",
+		Short: "Remove the GKE MCP Server from the AI Assistant settings of your JetBrains IDEs.",
+		Run:   uninstallRunner("JetBrains AI Assistant", install.UninstallJetBrainsExtension),
+	}
+
 	installDeveloper   bool
 	installProjectOnly bool
+	installDryRun      bool
+	installYes         bool
+	installManual      bool
+	installTransport   string
+	installServerPort  int
+	installURL         string
+	manifestFormat     string
+	manifestOut        string
+	installExePath     string
+	installServerArgs  []string
+	installForce       bool
+	installEnv         []string
+	installProjectID   string
+	installLocation    string
 )
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -99,47 +206,166 @@ func init() {
 	if bi, ok := debug.ReadBuildInfo(); ok {
 		version = bi.Main.Version
 	} else {
-		log.Printf("Failed to read build info to get version.")
+		slog.Warn("Failed to read build info to get version")
 	}
 
 	rootCmd.Flags().StringVar(&serverMode, "server-mode", "stdio", "transport to use for the server: stdio (default) or http")
 	rootCmd.Flags().IntVar(&serverPort, "server-port", 8080, "server port to use when server-mode is http; defaults to 8080")
+	rootCmd.Flags().BoolVar(&releaseNotesHTMLFallback, "release-notes-html-fallback", true, "scrape the GKE release notes page if the release notes feed cannot be read")
+	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "log the details of what the tools do, as --log-level=debug")
+	rootCmd.Flags().StringVar(&logLevelName, "log-level", "info", "level of the server logs: debug, info, warn or error")
+	rootCmd.Flags().StringVar(&logFile, "log-file", "", "file to append the server logs to instead of stderr")
+	rootCmd.Flags().BoolVar(&readOnly, "read-only", false, "leave out the tools that change GCP resources, the local kubeconfig or files, or run commands on nodes")
+	rootCmd.Flags().DurationVar(&callTimeout, "call-timeout", config.DefaultCallTimeout, "time each GCP API call may take, 0 for unbounded")
+	rootCmd.Flags().IntVar(&maxAttempts, "max-attempts", 1, "number of times a GCP API call failing with a quota or transient availability error is made")
+	rootCmd.Flags().BoolVar(&readyAfterAuth, "ready-after-auth-check", false, "in http mode, report ready on /readyz only once the Application Default Credentials check passed")
+	rootCmd.Flags().DurationVar(&shutdownGracePeriod, "shutdown-grace-period", defaultShutdownGracePeriod, "in http mode, time the requests in flight get to finish on SIGINT or SIGTERM before they are cancelled")
+	rootCmd.Flags().StringVar(&authToken, "auth-token", "", "in http mode, token clients must send as \"Authorization: Bearer <token>\"; defaults to $"+authTokenEnv)
+	rootCmd.Flags().StringVar(&authTokenFile, "auth-token-file", "", "file holding the token of --auth-token, to keep it out of the process arguments")
+	rootCmd.Flags().StringVar(&configPath, "config", "", "configuration file of the server; defaults to ~/.config/gke-mcp/config.yaml if it exists")
 	rootCmd.AddCommand(installCmd)
+	rootCmd.AddCommand(uninstallCmd)
+
+	installCmd.PersistentFlags().BoolVar(&installDryRun, "dry-run", false, "Print the changes the installation would make without making them")
+	installCmd.PersistentFlags().StringVar(&installTransport, "transport", "stdio", "transport the AI tool connects to the server with: stdio (default) to run it, or http to connect to a server started with --server-mode http")
+	installCmd.PersistentFlags().IntVar(&installServerPort, "server-port", 8080, "server port to connect to when transport is http; defaults to 8080")
+	installCmd.PersistentFlags().StringVar(&installURL, "url", "", "URL of the server to connect to when transport is http, e.g. http://localhost:8080/mcp or a remote server; defaults to http://localhost:<server-port>")
+	installCmd.PersistentFlags().StringVar(&installExePath, "exe-path", "", "Path of the gke-mcp binary or wrapper script the AI tool runs; defaults to this binary")
+	installCmd.PersistentFlags().StringArrayVar(&installServerArgs, "server-args", nil, "Argument the AI tool passes to the server, e.g. --server-args=--verbose; repeat for more arguments")
+	installCmd.PersistentFlags().StringArrayVar(&installEnv, "env", nil, "Environment variable KEY=VALUE the AI tool sets for the server; repeat for more variables")
+	installCmd.PersistentFlags().StringVar(&installProjectID, "project", "", "Google Cloud project the server uses by default, over the gcloud configuration")
+	installCmd.PersistentFlags().StringVar(&installLocation, "location", "", "Google Cloud region or zone the server uses by default, over the gcloud configuration")
+	installCmd.PersistentFlags().BoolVar(&installForce, "force", false, "Install even if --exe-path is not an executable file, and overwrite an up to date or unreadable Gemini CLI extension")
 
 	installCmd.AddCommand(installGeminiCLICmd)
 	installCmd.AddCommand(installCursorCmd)
+	installCmd.AddCommand(installVSCodeCmd)
 	installCmd.AddCommand(installClaudeDesktopCmd)
 	installCmd.AddCommand(installClaudeCodeCmd)
+	installCmd.AddCommand(installGooseCmd)
+	installCmd.AddCommand(installJetBrainsCmd)
+	installCmd.AddCommand(installManifestCmd)
+	installCmd.AddCommand(installAllCmd)
 
 	installGeminiCLICmd.Flags().BoolVarP(&installDeveloper, "developer", "d", false, "Install the MCP Server in developer mode for Gemini CLI")
 	installGeminiCLICmd.Flags().BoolVarP(&installProjectOnly, "project-only", "p", false, "Install the MCP Server only for the current project. Please run this in the root directory of your project")
 
 	installCursorCmd.Flags().BoolVarP(&installProjectOnly, "project-only", "p", false, "Install the MCP Server only for the current project. Please run this in the root directory of your project")
+	installVSCodeCmd.Flags().BoolVarP(&installProjectOnly, "project-only", "p", false, "Install the MCP Server only for the current project. Please run this in the root directory of your project")
 	installClaudeCodeCmd.Flags().BoolVarP(&installProjectOnly, "project-only", "p", false, "Install the MCP Server only for the current project. Please run this in the root directory of your project")
+	installClaudeCodeCmd.Flags().BoolVarP(&installYes, "yes", "y", false, "Proceed without asking for confirmation, e.g. when installing from a script")
+	installClaudeCodeCmd.Flags().BoolVar(&installManual, "manual", false, "Print the server configuration to add to Claude Code yourself instead of running claude, e.g. when it is not in the PATH")
+	installAllCmd.Flags().BoolVarP(&installProjectOnly, "project-only", "p", false, "Install the MCP Server only for the current project. Please run this in the root directory of your project")
+	installAllCmd.Flags().BoolVarP(&installYes, "yes", "y", false, "Proceed without asking for confirmation, e.g. when installing from a script")
+	installManifestCmd.Flags().BoolVarP(&installProjectOnly, "project-only", "p", false, "Write the usage guide in the current project rather than the home directory. Please run this in the root directory of your project")
+	installManifestCmd.Flags().StringVar(&manifestFormat, "format", install.ManifestJSON, "Format of the server definition: json, toml or yaml")
+	installManifestCmd.Flags().StringVar(&manifestOut, "out", "", "File to write the server definition to instead of stdout")
+
+	uninstallCmd.AddCommand(uninstallGeminiCLICmd)
+	uninstallCmd.AddCommand(uninstallCursorCmd)
+	uninstallCmd.AddCommand(uninstallVSCodeCmd)
+	uninstallCmd.AddCommand(uninstallClaudeDesktopCmd)
+	uninstallCmd.AddCommand(uninstallClaudeCodeCmd)
+	uninstallCmd.AddCommand(uninstallGooseCmd)
+	uninstallCmd.AddCommand(uninstallJetBrainsCmd)
+
+	for _, c := range []*cobra.Command{uninstallGeminiCLICmd, uninstallCursorCmd, uninstallVSCodeCmd, uninstallClaudeCodeCmd} {
+		c.Flags().BoolVarP(&installProjectOnly, "project-only", "p", false, "Remove the MCP Server installed only for the current project. Please run this in the root directory of your project")
+	}
 }
 
 type startOptions struct {
 	serverMode string
 	serverPort int
+	configPath string
+	// readyAfterAuth makes /readyz wait for the credentials check in http
+	// mode.
+	readyAfterAuth      bool
+	shutdownGracePeriod time.Duration
+	// authToken is the token clients must send in http mode, or "" to not
+	// require any.
+	authToken string
+	// The options below are nil if their flags are not set, to use the
+	// environment or the configuration file.
+	logLevel                 *slog.Level
+	releaseNotesHTMLFallback *bool
+	verbose                  *bool
+	readOnly                 *bool
+	callTimeout              *time.Duration
+	maxAttempts              *int
 }
 
 func runRootCmd(cmd *cobra.Command, args []string) {
 	opts := startOptions{
-		serverMode: serverMode,
-		serverPort: serverPort,
+		serverMode:          serverMode,
+		serverPort:          serverPort,
+		configPath:          configPath,
+		readyAfterAuth:      readyAfterAuth,
+		shutdownGracePeriod: shutdownGracePeriod,
+	}
+	if cmd.Flags().Changed("log-level") {
+		level, err := parseLogLevel(logLevelName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts.logLevel = &level
+		logLevel.Set(level)
+	}
+	logCloser, err := setupLogging(logFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer logCloser.Close()
+
+	token, err := resolveAuthToken(authToken, authTokenFile)
+	if err != nil {
+		slog.Error("Failed to get the auth token", "err", err)
+		os.Exit(1)
+	}
+	opts.authToken = token
+	if cmd.Flags().Changed("release-notes-html-fallback") {
+		opts.releaseNotesHTMLFallback = &releaseNotesHTMLFallback
+	}
+	if cmd.Flags().Changed("verbose") {
+		opts.verbose = &verbose
+	}
+	if cmd.Flags().Changed("read-only") {
+		opts.readOnly = &readOnly
 	}
-	startMCPServer(cmd.Context(), opts)
+	if cmd.Flags().Changed("call-timeout") {
+		opts.callTimeout = &callTimeout
+	}
+	if cmd.Flags().Changed("max-attempts") {
+		opts.maxAttempts = &maxAttempts
+	}
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	startMCPServer(ctx, opts)
 }
 
 func startMCPServer(ctx context.Context, opts startOptions) {
-	c := config.New(version)
-
-	instructions := ""
-	if err := adcAuthCheck(ctx, c); err != nil {
-		if strings.Contains(err.Error(), "Unauthenticated") {
-			log.Printf("GKE API calls requires Application Default Credentials (https://cloud.google.com/docs/authentication/application-default-credentials). Get credentials with `gcloud auth application-default login` before calling MCP tools.")
-			instructions += "GKE API calls requires Application Default Credentials (https://cloud.google.com/docs/authentication/application-default-credentials). Get credentials with `gcloud auth application-default login` before calling MCP tools."
-		}
+	c, err := config.New(version, opts.configPath)
+	if err != nil {
+		slog.Error("Failed to load configuration", "err", err)
+		os.Exit(1)
+	}
+	if opts.releaseNotesHTMLFallback != nil {
+		c.SetReleaseNotesHTMLFallback(*opts.releaseNotesHTMLFallback)
+	}
+	if opts.verbose != nil {
+		c.SetVerbose(*opts.verbose)
+	}
+	if opts.logLevel == nil && c.Verbose() {
+		logLevel.Set(slog.LevelDebug)
+	}
+	if opts.readOnly != nil {
+		c.SetReadOnly(*opts.readOnly)
+	}
+	if opts.callTimeout != nil {
+		c.SetCallTimeout(*opts.callTimeout)
+	}
+	if opts.maxAttempts != nil {
+		c.SetMaxAttempts(*opts.maxAttempts)
 	}
 
 	s := mcp.NewServer(
@@ -148,12 +374,46 @@ func startMCPServer(ctx context.Context, opts startOptions) {
 			Version: version,
 		},
 		&mcp.ServerOptions{
-			Instructions: instructions,
 			HasTools:     true,
 			HasResources: true,
 		},
 	)
 
+	// Check the credentials off the startup path, as it looks up the gcloud
+	// configuration and calls the GKE API. Clients that initialize after the
+	// check failed get told how to authenticate in the instructions.
+	var authInstructions atomic.Value
+	ready := &readiness{requireAuth: opts.readyAfterAuth}
+	go func() {
+		err := adcAuthCheck(ctx, c)
+		if err == nil {
+			ready.authPassed.Store(true)
+		} else if strings.Contains(err.Error(), "Unauthenticated") {
+			slog.Warn("GKE API calls requires Application Default Credentials (https://cloud.google.com/docs/authentication/application-default-credentials). Get credentials with `gcloud auth application-default login` before calling MCP tools.")
+			authInstructions.Store("GKE API calls requires Application Default Credentials (https://cloud.google.com/docs/authentication/application-default-credentials). Get credentials with `gcloud auth application-default login` before calling MCP tools.")
+		}
+	}()
+	// The tool calls in flight are cancelled when the server stops, after the
+	// grace period in http mode.
+	callsCtx, cancelCalls := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelCalls()
+	s.AddReceivingMiddleware(cancelCallsMiddleware(callsCtx))
+	s.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			// Tell the APIs which client drives the server in the user agent.
+			if r, ok := req.(*mcp.InitializeRequest); ok && r.Params != nil && r.Params.ClientInfo != nil {
+				c.SetClient(r.Params.ClientInfo.Name, r.Params.ClientInfo.Version)
+			}
+			res, err := next(ctx, method, req)
+			if r, ok := res.(*mcp.InitializeResult); ok {
+				if instructions, ok := authInstructions.Load().(string); ok {
+					r.Instructions = instructions
+				}
+			}
+			return res, err
+		}
+	})
+
 	resource := &mcp.Resource{
 		URI:         geminiInstructionsURI,
 		Name:        "GEMINI.md",
@@ -174,38 +434,57 @@ func startMCPServer(ctx context.Context, opts startOptions) {
 	})
 
 	if err := prompts.Install(ctx, s, c); err != nil {
-		log.Fatalf("Failed to install prompts: %v\n", err)
+		slog.Error("Failed to install prompts", "err", err)
+		os.Exit(1)
 	}
 
 	if err := tools.Install(ctx, s, c); err != nil {
-		log.Fatalf("Failed to install tools: %v\n", err)
+		slog.Error("Failed to install tools", "err", err)
+		os.Exit(1)
 	}
+	ready.installed.Store(true)
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("Failed to close clients", "err", err)
+		}
+	}()
 
 	// start server in the right mode
-	log.Printf("Starting GKE MCP Server (%s) in mode '%s'", version, opts.serverMode)
-	var err error
+	slog.Info("Starting GKE MCP Server", "version", version, "mode", opts.serverMode)
 	endpoint := fmt.Sprintf(":%d", opts.serverPort)
 
 	switch opts.serverMode {
-	case "stdio":
-		tr := &mcp.LoggingTransport{Transport: &mcp.StdioTransport{}, Writer: log.Writer()}
-		err = s.Run(ctx, tr)
 	case "http":
-		handler := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
-			return s
-		}, nil)
-		log.Printf("Listening for HTTP connections on port: %d", opts.serverPort)
-		err = http.ListenAndServe(endpoint, handler)
+		var lis net.Listener
+		if lis, err = net.Listen("tcp", endpoint); err != nil {
+			break
+		}
+		if opts.authToken == "" {
+			slog.Warn("No --auth-token is set, anyone who can reach the port can use the server", "port", opts.serverPort)
+		}
+		slog.Info("Listening for HTTP connections", "port", opts.serverPort)
+		handler := newHTTPHandler(mcpHandler(s), ready, opts.authToken)
+		err = serveHTTP(ctx, lis, s, handler, opts.shutdownGracePeriod, cancelCalls)
 	default:
-		log.Printf("Unknown mode '%s', defaulting to 'stdio'", opts.serverMode)
-		tr := &mcp.LoggingTransport{Transport: &mcp.StdioTransport{}, Writer: log.Writer()}
+		if opts.serverMode != "stdio" {
+			slog.Warn("Unknown mode, defaulting to stdio", "mode", opts.serverMode)
+		}
+		// The client stopping the server doesn't wait for its calls.
+		stop := context.AfterFunc(ctx, cancelCalls)
+		defer stop()
+		// Keep stdout for the protocol, anything else printed to it goes to
+		// stderr.
+		stdout := os.Stdout
+		os.Stdout = os.Stderr
+		defer func() { os.Stdout = stdout }()
+		tr := &mcp.LoggingTransport{Transport: &mcp.IOTransport{Reader: os.Stdin, Writer: stdout}, Writer: debugWriter{}}
 		err = s.Run(ctx, tr)
 	}
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
-			log.Printf("Server shutting down.")
+			slog.Info("Server shutting down")
 		} else {
-			log.Printf("Server error: %v\n", err)
+			slog.Error("Server error", "err", err)
 		}
 	}
 }
@@ -223,7 +502,7 @@ func adcAuthCheck(ctx context.Context, c *config.Config) error {
 		location = "us-central1"
 	}
 
-	cmClient, err := container.NewClusterManagerClient(ctx, option.WithUserAgent(c.UserAgent()))
+	cmClient, err := container.NewClusterManagerClient(ctx, c.ClientOptions()...)
 	if err != nil {
 		return fmt.Errorf("failed to create cluster manager client: %w", err)
 	}
@@ -240,6 +519,18 @@ func installOptions() (*install.InstallOptions, error) {
 		version,
 		installProjectOnly,
 		installDeveloper,
+		installDryRun,
+		installYes,
+		installTransport,
+		installServerPort,
+		installExePath,
+		installServerArgs,
+		installForce,
+		installEnv,
+		installProjectID,
+		installLocation,
+		installManual,
+		installURL,
 	)
 }
 
@@ -252,7 +543,7 @@ func runInstallGeminiCLICmd(cmd *cobra.Command, args []string) {
 	if err := install.GeminiCLIExtension(opts); err != nil {
 		log.Fatalf("Failed to install for gemini-cli: %v", err)
 	}
-	fmt.Println("Successfully installed GKE MCP server as a gemini-cli extension.")
+	printInstalled(opts, "Successfully installed GKE MCP server as a gemini-cli extension.")
 }
 
 func runInstallCursorCmd(cmd *cobra.Command, args []string) {
@@ -264,7 +555,19 @@ func runInstallCursorCmd(cmd *cobra.Command, args []string) {
 	if err := install.CursorMCPExtension(opts); err != nil {
 		log.Fatalf("Failed to install for cursor: %v", err)
 	}
-	fmt.Println("Successfully installed GKE MCP server as a cursor MCP server.")
+	printInstalled(opts, "Successfully installed GKE MCP server as a cursor MCP server.")
+}
+
+func runInstallVSCodeCmd(cmd *cobra.Command, args []string) {
+	opts, err := installOptions()
+	if err != nil {
+		log.Fatalf("Failed to get install options: %v", err)
+	}
+
+	if err := install.VSCodeMCPExtension(opts); err != nil {
+		log.Fatalf("Failed to install for VS Code: %v", err)
+	}
+	printInstalled(opts, "Successfully installed GKE MCP server as a VS Code MCP server.")
 }
 
 func runInstallClaudeDesktopCmd(cmd *cobra.Command, args []string) {
@@ -276,7 +579,7 @@ func runInstallClaudeDesktopCmd(cmd *cobra.Command, args []string) {
 	if err := install.ClaudeDesktopExtension(opts); err != nil {
 		log.Fatalf("Failed to install for Claude Desktop: %v", err)
 	}
-	fmt.Println("Successfully installed GKE MCP server in Claude Desktop configuration.")
+	printInstalled(opts, "Successfully installed GKE MCP server in Claude Desktop configuration.")
 }
 
 func runInstallClaudeCodeCmd(cmd *cobra.Command, args []string) {
@@ -289,5 +592,125 @@ func runInstallClaudeCodeCmd(cmd *cobra.Command, args []string) {
 		log.Fatalf("Failed to install for Claude Code: %v", err)
 	}
 
-	fmt.Println("Successfully installed GKE MCP server for Claude Code.")
+	if installManual {
+		printInstalled(opts, "Successfully installed the GKE MCP usage instructions for Claude Code, add the server as shown above to finish.")
+		return
+	}
+	printInstalled(opts, "Successfully installed GKE MCP server for Claude Code.")
+}
+
+func runInstallGooseCmd(cmd *cobra.Command, args []string) {
+	opts, err := installOptions()
+	if err != nil {
+		log.Fatalf("Failed to get install options: %v", err)
+	}
+
+	if err := install.GooseExtension(opts); err != nil {
+		log.Fatalf("Failed to install for Goose: %v", err)
+	}
+	printInstalled(opts, "Successfully installed GKE MCP server as a Goose extension.")
+}
+
+func runInstallJetBrainsCmd(cmd *cobra.Command, args []string) {
+	opts, err := installOptions()
+	if err != nil {
+		log.Fatalf("Failed to get install options: %v", err)
+	}
+
+	if err := install.JetBrainsExtension(opts); err != nil {
+		log.Fatalf("Failed to install for JetBrains AI Assistant: %v", err)
+	}
+	printInstalled(opts, "Successfully installed GKE MCP server in the JetBrains AI Assistant configuration.")
+}
+
+func runInstallManifestCmd(cmd *cobra.Command, args []string) {
+	opts, err := installOptions()
+	if err != nil {
+		log.Fatalf("Failed to get install options: %v", err)
+	}
+
+	usageGuide, err := install.WriteManifest(opts, manifestFormat, manifestOut)
+	if err != nil {
+		log.Fatalf("Failed to write the server definition: %v", err)
+	}
+
+	// Keep stdout for the definition, so that it can be redirected
+	if manifestOut == "" {
+		fmt.Fprintf(os.Stderr, "Add the server above to the settings of your AI tool, and %s to its instructions.\n", usageGuide)
+		return
+	}
+	printInstalled(opts, fmt.Sprintf("Wrote the GKE MCP server definition to %s, add it to the settings of your AI tool, and %s to its instructions.", manifestOut, usageGuide))
+}
+
+func runInstallAllCmd(cmd *cobra.Command, args []string) {
+	opts, err := installOptions()
+	if err != nil {
+		log.Fatalf("Failed to get install options: %v", err)
+	}
+
+	results := install.InstallAll(opts, install.Clients())
+
+	fmt.Println("Summary:")
+	failed := false
+	for _, r := range results {
+		switch r.Status {
+		case install.StatusSkipped:
+			if r.Err != nil {
+				fmt.Printf("  %s: skipped, %v\n", r.Client, r.Err)
+				continue
+			}
+			fmt.Printf("  %s: skipped, not found on this machine\n", r.Client)
+		case install.StatusFailed:
+			failed = true
+			fmt.Printf("  %s: failed: %v\n", r.Client, r.Err)
+		default:
+			fmt.Printf("  %s: %s\n", r.Client, r.Status)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+	printInstalled(opts, "Successfully installed GKE MCP server into the AI tools found.")
+}
+
+// uninstallRunner returns the Run function of an uninstall command, which
+// reports what remove removed from tool.
+func uninstallRunner(tool string, remove func(*install.InstallOptions) ([]string, error)) func(*cobra.Command, []string) {
+	return func(cmd *cobra.Command, args []string) {
+		opts, err := installOptions()
+		if err != nil {
+			log.Fatalf("Failed to get install options: %v", err)
+		}
+
+		removed, err := remove(opts)
+		for _, r := range removed {
+			fmt.Printf("Removed %s.\n", r)
+		}
+		if err != nil {
+			log.Fatalf("Failed to uninstall for %s: %v", tool, err)
+		}
+		if len(removed) == 0 {
+			fmt.Printf("GKE MCP server is not installed for %s, nothing to remove.\n", tool)
+			return
+		}
+		fmt.Printf("Successfully uninstalled GKE MCP server for %s.\n", tool)
+	}
+}
+
+// printInstalled prints msg and the backups made after a successful
+// installation, or that nothing was changed after a dry run.
+func printInstalled(opts *install.InstallOptions, msg string) {
+	if installDryRun {
+		fmt.Println("Dry run: no changes were made.")
+		return
+	}
+	fmt.Println(msg)
+	for _, b := range opts.Backups() {
+		fmt.Printf("Saved a backup of the original file at %s.\n", b)
+	}
+	if installTransport == "http" && installURL != "" {
+		fmt.Printf("Warning: the clients only connect to %s, make sure a GKE MCP server is running there before using them.\n", installURL)
+	} else if installTransport == "http" {
+		fmt.Printf("Start the server with `gke-mcp --server-mode http --server-port %d` before using it.\n", installServerPort)
+	}
 }
@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultShutdownGracePeriod leaves the requests in flight time to finish
+// within the 30s Kubernetes gives a pod to stop.
+const defaultShutdownGracePeriod = 20 * time.Second
+
+// cancelCallsMiddleware cancels the requests handled by the server, and so
+// kills the commands the tools run, once ctx is done. The MCP SDK detaches
+// the requests from the context of the session and of the HTTP requests.
+func cancelCallsMiddleware(ctx context.Context) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(reqCtx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			reqCtx, cancel := context.WithCancel(reqCtx)
+			defer cancel()
+			stop := context.AfterFunc(ctx, cancel)
+			defer stop()
+			return next(reqCtx, method, req)
+		}
+	}
+}
+
+// serveHTTP serves handler, the handler of s, on lis until ctx is done, then
+// stops accepting requests and waits up to gracePeriod for those in flight,
+// before cancelling them with cancelCalls.
+func serveHTTP(ctx context.Context, lis net.Listener, s *mcp.Server, handler http.Handler, gracePeriod time.Duration, cancelCalls context.CancelFunc) error {
+	srv := &http.Server{Handler: handler}
+	// Closing the sessions ends their streams once their requests finished,
+	// which Shutdown waits for otherwise.
+	srv.RegisterOnShutdown(func() {
+		for ss := range s.Sessions() {
+			go ss.Close()
+		}
+	})
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.Serve(lis)
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down, waiting for the requests in flight", "grace_period", gracePeriod)
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), gracePeriod)
+	defer cancel()
+	err := srv.Shutdown(shutdownCtx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		slog.Warn("Requests still in flight after the grace period, cancelling them", "grace_period", gracePeriod)
+		cancelCalls()
+		err = srv.Close()
+	}
+	if serveErr := <-errc; !errors.Is(serveErr, http.ErrServerClosed) {
+		return serveErr
+	}
+	return err
+}
+
+// mcpHandler returns the handler of the MCP streamable HTTP transport for s.
+func mcpHandler(s *mcp.Server) http.Handler {
+	return mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
+		return s
+	}, nil)
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestServeHTTPShutdown(t *testing.T) {
+	tests := []struct {
+		name        string
+		gracePeriod time.Duration
+		// finish makes the tool call finish on its own during the shutdown,
+		// otherwise it runs until cancelled.
+		finish        bool
+		wantCancelled bool
+	}{
+		{name: "drained", gracePeriod: time.Minute, finish: true},
+		{name: "cancelled after the grace period", gracePeriod: 100 * time.Millisecond, wantCancelled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			callsCtx, cancelCalls := context.WithCancel(context.Background())
+			defer cancelCalls()
+
+			started := make(chan struct{})
+			finish := make(chan struct{})
+			cancelled := make(chan bool, 1)
+			s := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+			s.AddReceivingMiddleware(cancelCallsMiddleware(callsCtx))
+			mcp.AddTool(s, &mcp.Tool{Name: "wait"}, func(ctx context.Context, _ *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+				close(started)
+				select {
+				case <-finish:
+					cancelled <- false
+				case <-ctx.Done():
+					cancelled <- true
+				}
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil, nil
+			})
+
+			lis, err := net.Listen("tcp", "localhost:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			ready := &readiness{}
+			ready.installed.Store(true)
+			served := make(chan error, 1)
+			go func() {
+				served <- serveHTTP(ctx, lis, s, newHTTPHandler(mcpHandler(s), ready, ""), tt.gracePeriod, cancelCalls)
+			}()
+
+			client := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil)
+			cs, err := client.Connect(ctx, &mcp.StreamableClientTransport{Endpoint: "http://" + lis.Addr().String()}, nil)
+			if err != nil {
+				t.Fatalf("Connect() failed: %v", err)
+			}
+			defer cs.Close()
+			called := make(chan error, 1)
+			go func() {
+				_, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "wait"})
+				called <- err
+			}()
+
+			<-started
+			cancel()
+			if tt.finish {
+				close(finish)
+			}
+
+			select {
+			case err := <-served:
+				if err != nil && !tt.wantCancelled {
+					t.Errorf("serveHTTP() = %v, want a clean shutdown", err)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatal("serveHTTP() didn't return after the grace period")
+			}
+			if got := <-cancelled; got != tt.wantCancelled {
+				t.Errorf("the tool call was cancelled = %t, want %t", got, tt.wantCancelled)
+			}
+			if err := <-called; err != nil && !tt.wantCancelled {
+				t.Errorf("CallTool() = %v, want the call to finish during the shutdown", err)
+			}
+			if _, err := net.Dial("tcp", lis.Addr().String()); err == nil {
+				t.Error("the server still accepts connections after the shutdown")
+			}
+		})
+	}
+}
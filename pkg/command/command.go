@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package command runs the external commands, e.g. gcloud, the tools rely on.
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout is the time a command may run when no timeout is given.
+	DefaultTimeout = 2 * time.Minute
+
+	// waitDelay bounds the wait for the output of a killed command, which
+	// its child processes may keep open.
+	waitDelay = 5 * time.Second
+)
+
+// Error is returned when a command fails. It holds what the command wrote to
+// stderr, which usually explains the failure, e.g. a missing gcloud component.
+type Error struct {
+	// Command is the command line that failed.
+	Command string
+	// ExitCode is the exit code of the command, or -1 if it did not exit,
+	// e.g. because it timed out.
+	ExitCode int
+	Stderr   string
+	// Timeout is set if the command was killed after running that long.
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *Error) Error() string {
+	var msg string
+	switch {
+	case e.Timeout > 0:
+		msg = fmt.Sprintf("%s timed out after %s", e.Command, e.Timeout)
+	case e.ExitCode >= 0:
+		msg = fmt.Sprintf("%s failed with exit code %d", e.Command, e.ExitCode)
+	default:
+		msg = fmt.Sprintf("%s failed: %v", e.Command, e.Err)
+	}
+	if e.Stderr != "" {
+		msg += ": " + e.Stderr
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Run runs the command and returns its stdout. The command is killed when ctx
+// is done or after timeout, DefaultTimeout if zero. On failure the error is an
+// *Error.
+func Run(ctx context.Context, timeout time.Duration, name string, args ...string) ([]byte, error) {
+	return run(ctx, timeout, nil, name, args...)
+}
+
+// run is Run with the environment variables env added to the command's.
+func run(ctx context.Context, timeout time.Duration, env []string, name string, args ...string) ([]byte, error) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = waitDelay
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	out, err := cmd.Output()
+	if err == nil {
+		return out, nil
+	}
+	e := &Error{
+		Command:  strings.Join(append([]string{name}, args...), " "),
+		ExitCode: -1,
+		Stderr:   strings.TrimSpace(stderr.String()),
+		Err:      err,
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		e.Timeout = timeout
+		e.Err = ctx.Err()
+		return nil, e
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.Exited() {
+		e.ExitCode = exitErr.ExitCode()
+	}
+	return nil, e
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		script       string
+		want         string
+		wantExitCode int
+		wantErr      string
+	}{
+		{
+			name:   "success",
+			script: "echo out; echo progress >&2",
+			want:   "out\n",
+		},
+		{
+			name:         "failure",
+			script:       "echo partial; echo 'ERROR: (gcloud) Invalid choice: ai' >&2; exit 2",
+			wantExitCode: 2,
+			wantErr:      "failed with exit code 2: ERROR: (gcloud) Invalid choice: ai",
+		},
+		{
+			name:         "timeout",
+			timeout:      50 * time.Millisecond,
+			script:       "exec sleep 5",
+			wantExitCode: -1,
+			wantErr:      "timed out after 50ms",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := Run(context.Background(), tc.timeout, "sh", "-c", tc.script)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Run() error = %v", err)
+				}
+				if string(out) != tc.want {
+					t.Errorf("Run() = %q, want %q", out, tc.want)
+				}
+				return
+			}
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("Run() error = %v, want an *Error", err)
+			}
+			if !strings.Contains(e.Error(), tc.wantErr) {
+				t.Errorf("Run() error = %q, want it to contain %q", e.Error(), tc.wantErr)
+			}
+			if e.ExitCode != tc.wantExitCode {
+				t.Errorf("Run() exit code = %d, want %d", e.ExitCode, tc.wantExitCode)
+			}
+		})
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Run(ctx, 0, "sh", "-c", "exec sleep 5"); err == nil {
+		t.Errorf("Run() with a canceled context succeeded, want error")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// gcloudEnv turns off the prompts of gcloud, which would wait for an answer
+// no one can give.
+var gcloudEnv = []string{"CLOUDSDK_CORE_DISABLE_PROMPTS=1"}
+
+// Gcloud is a gcloud command line. The project and format are passed
+// explicitly, so that the command doesn't depend on the gcloud configuration
+// when the tool was given a project.
+type Gcloud struct {
+	// Args are the command and its arguments, e.g. compute instances list.
+	Args []string
+	// Project is passed as --project if set.
+	Project string
+	// Format is passed as --format if set.
+	Format string
+}
+
+// Argv returns the arguments gcloud is run with.
+func (g Gcloud) Argv() []string {
+	argv := append([]string{}, g.Args...)
+	if g.Project != "" {
+		argv = append(argv, "--project="+g.Project)
+	}
+	if g.Format != "" {
+		argv = append(argv, "--format="+g.Format)
+	}
+	return argv
+}
+
+// Run runs gcloud like Run and returns its stdout.
+func (g Gcloud) Run(ctx context.Context, timeout time.Duration) ([]byte, error) {
+	return run(ctx, timeout, gcloudEnv, "gcloud", g.Argv()...)
+}
+
+// Command returns the gcloud command, for the callers that need to handle
+// its output themselves.
+func (g Gcloud) Command(ctx context.Context) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "gcloud", g.Argv()...)
+	cmd.Env = append(os.Environ(), gcloudEnv...)
+	return cmd
+}
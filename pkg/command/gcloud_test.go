@@ -0,0 +1,76 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGcloudArgv(t *testing.T) {
+	tests := []struct {
+		name string
+		g    Gcloud
+		want []string
+	}{
+		{
+			name: "args only",
+			g:    Gcloud{Args: []string{"compute", "zones", "list"}},
+			want: []string{"compute", "zones", "list"},
+		},
+		{
+			name: "project and format",
+			g:    Gcloud{Args: []string{"compute", "zones", "list"}, Project: "p", Format: "value(name)"},
+			want: []string{"compute", "zones", "list", "--project=p", "--format=value(name)"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.g.Argv(); !slices.Equal(got, tc.want) {
+				t.Errorf("Argv() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGcloudRun(t *testing.T) {
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho \"$CLOUDSDK_CORE_DISABLE_PROMPTS $*\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "gcloud"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	g := Gcloud{Args: []string{"config", "list"}, Project: "p", Format: "json"}
+	want := "1 config list --project=p --format=json"
+	out, err := g.Run(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != want {
+		t.Errorf("Run() = %q, want %q", got, want)
+	}
+	out, err = g.Command(context.Background()).Output()
+	if err != nil {
+		t.Fatalf("Command().Output() error = %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != want {
+		t.Errorf("Command().Output() = %q, want %q", got, want)
+	}
+}
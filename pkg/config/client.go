@@ -0,0 +1,199 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultCallTimeout bounds each API call, so that a hung call doesn't stall
+// a tool.
+const DefaultCallTimeout = time.Minute
+
+// CallTimeout returns the time each API call may take, or 0 if unbounded.
+func (c *Config) CallTimeout() time.Duration {
+	return c.callTimeout
+}
+
+// SetCallTimeout sets the time each API call may take, 0 for unbounded.
+func (c *Config) SetCallTimeout(timeout time.Duration) {
+	c.callTimeout = timeout
+}
+
+// MaxAttempts returns the number of times the API clients make an API call
+// that fails with a quota or transient availability error, on top of the
+// retries of the tools.
+func (c *Config) MaxAttempts() int {
+	return max(c.maxAttempts, 1)
+}
+
+// SetMaxAttempts sets the number of times the API clients make an API call
+// that fails with a quota or transient availability error.
+func (c *Config) SetMaxAttempts(attempts int) {
+	c.maxAttempts = attempts
+}
+
+// SetEndpoint points the API clients at endpoint, without authentication nor
+// TLS, e.g. at local fakes in tests. The REST clients send plain HTTP to it.
+func (c *Config) SetEndpoint(endpoint string) {
+	c.endpoint = endpoint
+}
+
+// ClientOptions returns the options of the gRPC API clients: the user agent,
+// the call timeout and retries, and the endpoint override. The MCP client is
+// sent in the x-goog-api-client header of each call.
+func (c *Config) ClientOptions() []option.ClientOption {
+	opts := []option.ClientOption{
+		option.WithUserAgent(c.userAgent),
+		option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(c.unaryInterceptor)),
+	}
+	if c.endpoint != "" {
+		opts = append(opts,
+			option.WithEndpoint(c.endpoint),
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		)
+	}
+	return opts
+}
+
+// RESTClientOptions returns the options of the REST API clients, e.g. of the
+// discovery-based and BigQuery clients: the user agent, the call timeout and
+// retries, and the endpoint override.
+func (c *Config) RESTClientOptions(ctx context.Context) ([]option.ClientOption, error) {
+	opts := []option.ClientOption{option.WithUserAgent(c.userAgent)}
+	if c.endpoint != "" {
+		opts = append(opts,
+			option.WithEndpoint("http://"+c.endpoint+"/"),
+			option.WithoutAuthentication(),
+		)
+	}
+	trans, err := htransport.NewTransport(ctx, &restTransport{c: c, base: http.DefaultTransport}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return append(opts, option.WithHTTPClient(&http.Client{Transport: trans})), nil
+}
+
+// restTransport adds the MCP client to the user agent of each REST API call,
+// applies the call timeout to each attempt, and retries the calls answered
+// with a quota or transient availability error up to MaxAttempts times.
+type restTransport struct {
+	c    *Config
+	base http.RoundTripper
+}
+
+func (t *restTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	policy := retry.DefaultPolicy
+	policy.MaxAttempts = t.c.MaxAttempts()
+	if req.Body != nil && req.GetBody == nil {
+		// The body cannot be sent again.
+		policy.MaxAttempts = 1
+	}
+	var resp *http.Response
+	attempt := 0
+	err := retry.Do(req.Context(), policy, func() error {
+		if resp != nil {
+			resp.Body.Close()
+			resp = nil
+		}
+		ctx, cancel := t.c.withCallTimeout(req.Context())
+		r := req.Clone(ctx)
+		if token := t.c.clientToken(); token != "" {
+			r.Header.Set("User-Agent", strings.TrimSpace(r.Header.Get("User-Agent")+" "+token))
+		}
+		if attempt++; attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				cancel()
+				return err
+			}
+			r.Body = body
+		}
+		var err error
+		resp, err = t.base.RoundTrip(r)
+		if err != nil {
+			cancel()
+			return err
+		}
+		// The timeout also bounds reading the body.
+		resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		// Map the statuses to the codes retry.Do retries.
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests:
+			return status.Error(codes.ResourceExhausted, resp.Status)
+		case http.StatusServiceUnavailable:
+			return status.Error(codes.Unavailable, resp.Status)
+		}
+		return nil
+	})
+	if resp != nil {
+		// The error of the last attempt is left to the client to parse.
+		return resp, nil
+	}
+	return nil, err
+}
+
+// cancelOnClose cancels the context of a response once its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// withCallTimeout returns a context bounding an API call by CallTimeout.
+func (c *Config) withCallTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if timeout := c.CallTimeout(); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// unaryInterceptor adds the MCP client to the x-goog-api-client header of a
+// call, applies the call timeout to each attempt, and retries the call up to
+// MaxAttempts times.
+func (c *Config) unaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if token := c.clientToken(); token != "" {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		md = md.Copy()
+		md.Set("x-goog-api-client", strings.TrimSpace(strings.Join(md.Get("x-goog-api-client"), " ")+" "+token))
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+	policy := retry.DefaultPolicy
+	policy.MaxAttempts = c.MaxAttempts()
+	return retry.Do(ctx, policy, func() error {
+		callCtx, cancel := c.withCallTimeout(ctx)
+		defer cancel()
+		return invoker(callCtx, method, req, reply, cc, opts...)
+	})
+}
@@ -0,0 +1,248 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	container "cloud.google.com/go/container/apiv1"
+	"cloud.google.com/go/container/apiv1/containerpb"
+	monitoringv3 "google.golang.org/api/monitoring/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryInterceptor(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	tests := []struct {
+		name         string
+		maxAttempts  int
+		errs         []error
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:         "success",
+			errs:         []error{nil},
+			wantAttempts: 1,
+		},
+		{
+			name:         "no retries by default",
+			errs:         []error{unavailable, nil},
+			wantAttempts: 1,
+			wantErr:      unavailable,
+		},
+		{
+			name:         "retried",
+			maxAttempts:  3,
+			errs:         []error{unavailable, unavailable, nil},
+			wantAttempts: 3,
+		},
+		{
+			name:         "not retryable",
+			maxAttempts:  3,
+			errs:         []error{status.Error(codes.NotFound, "not found"), nil},
+			wantAttempts: 1,
+			wantErr:      status.Error(codes.NotFound, "not found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{callTimeout: time.Minute}
+			c.SetMaxAttempts(tt.maxAttempts)
+			attempts := 0
+			invoker := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				if _, ok := ctx.Deadline(); !ok {
+					t.Errorf("attempt %d has no deadline", attempts)
+				}
+				err := tt.errs[attempts]
+				attempts++
+				return err
+			}
+			err := c.unaryInterceptor(context.Background(), "/test/Method", nil, nil, nil, invoker)
+			if status.Code(err) != status.Code(tt.wantErr) {
+				t.Errorf("unaryInterceptor() = %v, want %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("unaryInterceptor() made %d attempts, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestUnaryInterceptorTimeout(t *testing.T) {
+	c := &Config{callTimeout: 10 * time.Millisecond}
+	invoker := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if err := c.unaryInterceptor(context.Background(), "/test/Method", nil, nil, nil, invoker); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unaryInterceptor() of a hung call = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestWithCallTimeout(t *testing.T) {
+	c := &Config{callTimeout: time.Minute}
+	ctx, cancel := c.withCallTimeout(context.Background())
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Minute {
+		t.Errorf("withCallTimeout() deadline = %v, %v, want one within a minute", deadline, ok)
+	}
+
+	c.SetCallTimeout(0)
+	ctx, cancel = c.withCallTimeout(context.Background())
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Errorf("withCallTimeout() without a call timeout set a deadline")
+	}
+}
+
+type fakeClusterManager struct {
+	containerpb.UnimplementedClusterManagerServer
+	userAgent string
+	apiClient string
+}
+
+func (f *fakeClusterManager) GetServerConfig(ctx context.Context, _ *containerpb.GetServerConfigRequest) (*containerpb.ServerConfig, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	f.userAgent = strings.Join(md.Get("user-agent"), " ")
+	f.apiClient = strings.Join(md.Get("x-goog-api-client"), " ")
+	return &containerpb.ServerConfig{DefaultClusterVersion: "1.33"}, nil
+}
+
+func TestSetEndpoint(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	fake := &fakeClusterManager{}
+	containerpb.RegisterClusterManagerServer(srv, fake)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	ctx := context.Background()
+	c := &Config{userAgent: "gke-mcp/test", callTimeout: time.Minute}
+	c.SetEndpoint(lis.Addr().String())
+	cm, err := container.NewClusterManagerClient(ctx, c.ClientOptions()...)
+	if err != nil {
+		t.Fatalf("NewClusterManagerClient() failed: %v", err)
+	}
+	defer cm.Close()
+
+	resp, err := cm.GetServerConfig(ctx, &containerpb.GetServerConfigRequest{Name: "projects/p/locations/us-central1"})
+	if err != nil {
+		t.Fatalf("GetServerConfig() failed: %v", err)
+	}
+	if resp.GetDefaultClusterVersion() != "1.33" {
+		t.Errorf("GetServerConfig() = %v, want the response of the fake", resp)
+	}
+	if !strings.Contains(fake.userAgent, "gke-mcp/test") {
+		t.Errorf("the fake got user agent %q, want it to contain %q", fake.userAgent, "gke-mcp/test")
+	}
+
+	// The client created before the MCP client connected sends it too.
+	c.SetClient("gemini-cli", "1.2")
+	if _, err := cm.GetServerConfig(ctx, &containerpb.GetServerConfigRequest{Name: "projects/p/locations/us-central1"}); err != nil {
+		t.Fatalf("GetServerConfig() failed: %v", err)
+	}
+	if !strings.Contains(fake.apiClient, "client/gemini-cli-1.2") {
+		t.Errorf("the fake got x-goog-api-client %q, want it to contain %q", fake.apiClient, "client/gemini-cli-1.2")
+	}
+	if !strings.Contains(fake.apiClient, "gapic/") {
+		t.Errorf("the fake got x-goog-api-client %q, want it to keep the library tokens", fake.apiClient)
+	}
+}
+
+func TestRESTClientOptions(t *testing.T) {
+	var requests int
+	var userAgent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		userAgent = r.Header.Get("User-Agent")
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"alerts": [{"name": "projects/p/alerts/a"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := context.Background()
+	c := &Config{userAgent: "gke-mcp/test", callTimeout: time.Minute}
+	c.SetEndpoint(strings.TrimPrefix(srv.URL, "http://"))
+	c.SetMaxAttempts(2)
+	opts, err := c.RESTClientOptions(ctx)
+	if err != nil {
+		t.Fatalf("RESTClientOptions() failed: %v", err)
+	}
+	svc, err := monitoringv3.NewService(ctx, opts...)
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+	resp, err := svc.Projects.Alerts.List("projects/p").Context(ctx).Do()
+	if err != nil {
+		t.Fatalf("Alerts.List() failed: %v", err)
+	}
+	if len(resp.Alerts) != 1 {
+		t.Errorf("Alerts.List() = %v, want the response of the fake", resp.Alerts)
+	}
+	if requests != 2 {
+		t.Errorf("the fake got %d requests, want the unavailable one retried once", requests)
+	}
+	if !strings.Contains(userAgent, "gke-mcp/test") {
+		t.Errorf("the fake got user agent %q, want it to contain %q", userAgent, "gke-mcp/test")
+	}
+
+	// The service created before the MCP client connected sends it too.
+	c.SetClient("gemini-cli", "1.2")
+	if _, err := svc.Projects.Alerts.List("projects/p").Context(ctx).Do(); err != nil {
+		t.Fatalf("Alerts.List() failed: %v", err)
+	}
+	if !strings.HasSuffix(userAgent, " client/gemini-cli-1.2") {
+		t.Errorf("the fake got user agent %q, want it to end with %q", userAgent, "client/gemini-cli-1.2")
+	}
+}
+
+func TestRESTClientOptionsTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := context.Background()
+	c := &Config{callTimeout: 10 * time.Millisecond}
+	c.SetEndpoint(strings.TrimPrefix(srv.URL, "http://"))
+	opts, err := c.RESTClientOptions(ctx)
+	if err != nil {
+		t.Fatalf("RESTClientOptions() failed: %v", err)
+	}
+	svc, err := monitoringv3.NewService(ctx, opts...)
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+	if _, err := svc.Projects.Alerts.List("projects/p").Context(ctx).Do(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Alerts.List() of a hung call = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -15,52 +15,366 @@
 package config
 
 import (
-	"log"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"golang.org/x/oauth2/google"
 )
 
 type Config struct {
-	userAgent        string
+	userAgent string
+	cacheDir  string
+	// lookupDefaults resolves the default project and location from the
+	// environment, the configuration file and the gcloud configuration on
+	// first use.
+	lookupDefaults   bool
+	file             File
+	projectIDOnce    sync.Once
 	defaultProjectID string
+	locationOnce     sync.Once
 	defaultLocation  string
+	// releaseNotesHTMLFallback scrapes the GKE release notes page if their
+	// feed cannot be read.
+	releaseNotesHTMLFallback bool
+	// verbose logs the details of what the tools do.
+	verbose bool
+	// readOnly leaves out the tools that change anything.
+	readOnly bool
+	// callTimeout, maxAttempts and endpoint set up the API clients.
+	callTimeout time.Duration
+	maxAttempts int
+	endpoint    string
+
+	mu             sync.Mutex
+	closers        []func() error
+	billingExports map[string]BillingExport
+	// client is the MCP client connected to the server, as name-version.
+	client string
 }
 
+// BillingExport locates the detailed billing export of a project in
+// BigQuery.
+type BillingExport struct {
+	// DatasetID is the dataset holding the export, as project.dataset.
+	DatasetID        string
+	BillingAccountID string
+}
+
+// UserAgent returns the user agent of the API calls, with the platform and,
+// once one connected, the MCP client, e.g.
+// "gke-mcp/0.3.0 (darwin/arm64) client/gemini-cli-1.2".
 func (c *Config) UserAgent() string {
+	if token := c.clientToken(); token != "" {
+		return c.userAgent + " " + token
+	}
 	return c.userAgent
 }
 
+// clientToken returns the MCP client connected to the server as a user agent
+// token, e.g. "client/gemini-cli-1.2", or "" before one connected. The API
+// clients add it to each call, as they may be created before the client
+// connected.
+func (c *Config) clientToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client == "" {
+		return ""
+	}
+	return "client/" + c.client
+}
+
+// SetClient sets the MCP client connected to the server, from the
+// initialization handshake. With several sessions over HTTP, the last one
+// wins.
+func (c *Config) SetClient(name, version string) {
+	client := userAgentToken(name)
+	if v := userAgentToken(version); client != "" && v != "" {
+		client += "-" + v
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.client = client
+}
+
+// userAgentToken returns s with the whitespace, parentheses and slashes, which
+// would break the user agent up, replaced with dashes.
+func userAgentToken(s string) string {
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("()/", r)
+	}), "-")
+}
+
+// DefaultProjectID returns the project of GKE_MCP_PROJECT_ID, else of the
+// configuration file, else of the gcloud configuration, else of the
+// Application Default Credentials. It is looked up on the first call and
+// cached for the lifetime of the process.
 func (c *Config) DefaultProjectID() string {
+	c.projectIDOnce.Do(func() {
+		if c.lookupDefaults {
+			c.defaultProjectID = getDefaultProjectID(c.file.ProjectID)
+		}
+	})
 	return c.defaultProjectID
 }
 
+// DefaultLocation returns the location of GKE_MCP_LOCATION, the configuration
+// file or the region, else the zone, of the gcloud configuration. It is looked
+// up on the first call and cached for the lifetime of the process.
 func (c *Config) DefaultLocation() string {
+	c.locationOnce.Do(func() {
+		if c.lookupDefaults {
+			c.defaultLocation = getDefaultLocation(c.file.Location)
+		}
+	})
 	return c.defaultLocation
 }
 
-func New(version string) *Config {
-	return &Config{
-		userAgent:        "gke-mcp/" + version,
-		defaultProjectID: getDefaultProjectID(),
-		defaultLocation:  getDefaultLocation(),
+// CacheDir returns the directory the tools cache downloads in, or "" if they
+// should not cache on disk.
+func (c *Config) CacheDir() string {
+	return c.cacheDir
+}
+
+// ReleaseNotesHTMLFallback reports whether the GKE release notes page is
+// scraped if the release notes feed cannot be read.
+func (c *Config) ReleaseNotesHTMLFallback() bool {
+	return c.releaseNotesHTMLFallback
+}
+
+// SetReleaseNotesHTMLFallback sets whether the GKE release notes page is
+// scraped if the release notes feed cannot be read.
+func (c *Config) SetReleaseNotesHTMLFallback(enabled bool) {
+	c.releaseNotesHTMLFallback = enabled
+}
+
+// Verbose reports whether the tools log the details of what they do.
+func (c *Config) Verbose() bool {
+	return c.verbose
+}
+
+// SetVerbose sets whether the tools log the details of what they do.
+func (c *Config) SetVerbose(verbose bool) {
+	c.verbose = verbose
+}
+
+// ReadOnly reports whether the tools that change GCP resources, the local
+// configuration or run commands on nodes are left out.
+func (c *Config) ReadOnly() bool {
+	return c.readOnly
+}
+
+// SetReadOnly sets whether the tools that change anything are left out.
+func (c *Config) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
+
+// MaxLogLimit returns the largest number of log entries query_logs returns in
+// one call set in the configuration file, or 0 for the default of the tool.
+func (c *Config) MaxLogLimit() int {
+	return c.file.MaxLogLimit
+}
+
+// DeniedTools returns the names of the tools the configuration file leaves out
+// of the server.
+func (c *Config) DeniedTools() []string {
+	return c.file.DeniedTools
+}
+
+// SetCacheDir overrides the directory the tools cache downloads in, e.g. with
+// a temporary directory in tests.
+func (c *Config) SetCacheDir(dir string) {
+	c.cacheDir = dir
+}
+
+// BillingExport returns the billing export found for projectID earlier in
+// the session.
+func (c *Config) BillingExport(projectID string) (BillingExport, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.billingExports[projectID]
+	return e, ok
+}
+
+// SetBillingExport caches the billing export of projectID for the session.
+func (c *Config) SetBillingExport(projectID string, e BillingExport) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.billingExports == nil {
+		c.billingExports = map[string]BillingExport{}
+	}
+	c.billingExports[projectID] = e
+}
+
+// OnClose registers f to be called by Close, e.g. to close the API clients
+// the tools create at install time.
+func (c *Config) OnClose(f func() error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closers = append(c.closers, f)
+}
+
+// Close calls the functions registered with OnClose in reverse order and
+// returns their errors joined.
+func (c *Config) Close() error {
+	c.mu.Lock()
+	closers := c.closers
+	c.closers = nil
+	c.mu.Unlock()
+
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		errs = append(errs, closers[i]())
+	}
+	return errors.Join(errs...)
+}
+
+// New returns the configuration of the server, read from the configuration
+// file at configPath, or at DefaultFilePath if empty.
+func New(version, configPath string) (*Config, error) {
+	f, err := LoadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{
+		userAgent:                fmt.Sprintf("gke-mcp/%s (%s/%s)", version, runtime.GOOS, runtime.GOARCH),
+		cacheDir:                 f.CacheDir,
+		lookupDefaults:           true,
+		file:                     *f,
+		releaseNotesHTMLFallback: true,
+		callTimeout:              DefaultCallTimeout,
+		maxAttempts:              f.MaxAttempts,
+	}
+	if f.CallTimeout != "" {
+		// LoadFile checked the duration.
+		c.callTimeout, _ = time.ParseDuration(f.CallTimeout)
+	}
+	if c.cacheDir == "" {
+		c.cacheDir = getCacheDir()
+	}
+	if f.ReleaseNotesHTMLFallback != nil {
+		c.releaseNotesHTMLFallback = *f.ReleaseNotesHTMLFallback
+	}
+	if f.Verbose != nil {
+		c.verbose = *f.Verbose
+	}
+	if f.ReadOnly != nil {
+		c.readOnly = *f.ReadOnly
+	}
+	if env := os.Getenv(ReadOnlyEnv); env != "" {
+		readOnly, err := strconv.ParseBool(env)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", ReadOnlyEnv, err)
+		}
+		c.readOnly = readOnly
+	}
+	return c, nil
+}
+
+func getCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		slog.Warn("Failed to get the user cache directory, caching in memory only", "err", err)
+		return ""
 	}
+	return filepath.Join(dir, "gke-mcp")
 }
 
-func getDefaultProjectID() string {
-	projectID, err := getGcloudConfig("core/project")
+// The environment variables that pin the server to a project and location,
+// over the gcloud configuration.
+const (
+	ProjectIDEnv = "GKE_MCP_PROJECT_ID"
+	LocationEnv  = "GKE_MCP_LOCATION"
+)
+
+// ReadOnlyEnv is the environment variable that turns on the read-only mode,
+// over the configuration file.
+const ReadOnlyEnv = "GKE_MCP_READ_ONLY"
+
+// gcloudConfigTimeout bounds each gcloud config lookup, so that a slow or
+// hanging gcloud doesn't hold up the tools.
+const gcloudConfigTimeout = 5 * time.Second
+
+// gcloudConfig returns the value of a gcloud configuration property, tests
+// replace it to not run gcloud.
+var gcloudConfig = getGcloudConfig
+
+func getDefaultProjectID(fileProjectID string) string {
+	if projectID := os.Getenv(ProjectIDEnv); projectID != "" {
+		slog.Info("Using default project from "+ProjectIDEnv, "project", projectID)
+		return projectID
+	}
+	if fileProjectID != "" {
+		slog.Info("Using default project from the configuration file", "project", fileProjectID)
+		return fileProjectID
+	}
+	projectID, err := gcloudConfig("core/project")
 	if err != nil {
-		log.Printf("Failed to get default project: %v", err)
+		slog.Warn("Failed to get default project from gcloud", "err", err)
+	} else if projectID != "" {
+		slog.Info("Using default project from the gcloud configuration", "project", projectID)
+		return projectID
+	}
+	// Fall back to the project of the credentials, e.g. in containers with a
+	// service account key but without gcloud.
+	projectID, err = getADCProjectID()
+	if err != nil {
+		slog.Warn("Failed to get default project from Application Default Credentials", "err", err)
 		return ""
 	}
+	if projectID != "" {
+		slog.Info("Using default project from Application Default Credentials", "project", projectID)
+	}
 	return projectID
 }
 
-func getDefaultLocation() string {
-	region, err := getGcloudConfig("compute/region")
+// getADCProjectID returns the project of the Application Default Credentials,
+// or their quota project if they have none.
+func getADCProjectID() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gcloudConfigTimeout)
+	defer cancel()
+	creds, err := google.FindDefaultCredentials(ctx)
+	if err != nil {
+		return "", err
+	}
+	if creds.ProjectID != "" {
+		return creds.ProjectID, nil
+	}
+	if len(creds.JSON) == 0 {
+		return "", nil
+	}
+	var f struct {
+		QuotaProjectID string `json:"quota_project_id"`
+	}
+	if err := json.Unmarshal(creds.JSON, &f); err != nil {
+		return "", err
+	}
+	return f.QuotaProjectID, nil
+}
+
+func getDefaultLocation(fileLocation string) string {
+	if location := os.Getenv(LocationEnv); location != "" {
+		return location
+	}
+	if fileLocation != "" {
+		return fileLocation
+	}
+	region, err := gcloudConfig("compute/region")
 	if err == nil {
 		return region
 	}
-	zone, err := getGcloudConfig("compute/zone")
+	zone, err := gcloudConfig("compute/zone")
 	if err == nil {
 		return zone
 	}
@@ -68,7 +382,9 @@ func getDefaultLocation() string {
 }
 
 func getGcloudConfig(key string) (string, error) {
-	out, err := exec.Command("gcloud", "config", "get", key).Output()
+	ctx, cancel := context.WithTimeout(context.Background(), gcloudConfigTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "gcloud", "config", "get", key).Output()
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,260 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestClose(t *testing.T) {
+	c := &Config{}
+	var order []int
+	errFirst := errors.New("first")
+	c.OnClose(func() error {
+		order = append(order, 1)
+		return errFirst
+	})
+	c.OnClose(func() error {
+		order = append(order, 2)
+		return nil
+	})
+
+	err := c.Close()
+	if !errors.Is(err, errFirst) {
+		t.Errorf("Close() = %v, want %v", err, errFirst)
+	}
+	if want := []int{2, 1}; !slices.Equal(order, want) {
+		t.Errorf("Close() called closers in order %v, want %v", order, want)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Errorf("second Close() = %v, want nil", err)
+	}
+	if len(order) != 2 {
+		t.Errorf("second Close() called closers again: %v", order)
+	}
+}
+
+func TestBillingExport(t *testing.T) {
+	c := &Config{}
+	if _, ok := c.BillingExport("p"); ok {
+		t.Fatalf("BillingExport(%q) found an export before it was set", "p")
+	}
+	want := BillingExport{DatasetID: "b.export", BillingAccountID: "012345-6789AB-CDEF01"}
+	c.SetBillingExport("p", want)
+	if got, ok := c.BillingExport("p"); !ok || got != want {
+		t.Errorf("BillingExport(%q) = %+v, %v, want %+v, true", "p", got, ok, want)
+	}
+	if _, ok := c.BillingExport("other"); ok {
+		t.Errorf("BillingExport(%q) found the export of another project", "other")
+	}
+}
+
+func TestVerbose(t *testing.T) {
+	c := &Config{}
+	if c.Verbose() {
+		t.Errorf("Verbose() = true before it was set")
+	}
+	c.SetVerbose(true)
+	if !c.Verbose() {
+		t.Errorf("Verbose() = false after SetVerbose(true)")
+	}
+}
+
+func TestDefaultsFromEnv(t *testing.T) {
+	t.Setenv(ProjectIDEnv, "pinned-project")
+	t.Setenv(LocationEnv, "europe-west4")
+	if got := getDefaultProjectID("file-project"); got != "pinned-project" {
+		t.Errorf("getDefaultProjectID(%q) = %q, want %q", "file-project", got, "pinned-project")
+	}
+	if got := getDefaultLocation("us-east1"); got != "europe-west4" {
+		t.Errorf("getDefaultLocation(%q) = %q, want %q", "us-east1", got, "europe-west4")
+	}
+}
+
+func TestDefaultsLookedUpOnce(t *testing.T) {
+	t.Setenv(ProjectIDEnv, "")
+	t.Setenv(LocationEnv, "")
+	calls := map[string]int{}
+	orig := gcloudConfig
+	gcloudConfig = func(key string) (string, error) {
+		calls[key]++
+		switch key {
+		case "core/project":
+			return "gcloud-project", nil
+		case "compute/region":
+			return "", errors.New("unset")
+		}
+		return "us-central1-a", nil
+	}
+	t.Cleanup(func() { gcloudConfig = orig })
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	c, err := New("test", "")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if len(calls) != 0 {
+		t.Fatalf("New() looked up the gcloud configuration: %v", calls)
+	}
+	for i := 0; i < 2; i++ {
+		if got := c.DefaultProjectID(); got != "gcloud-project" {
+			t.Errorf("DefaultProjectID() = %q, want %q", got, "gcloud-project")
+		}
+		if got := c.DefaultLocation(); got != "us-central1-a" {
+			t.Errorf("DefaultLocation() = %q, want %q", got, "us-central1-a")
+		}
+	}
+	want := map[string]int{"core/project": 1, "compute/region": 1, "compute/zone": 1}
+	if !maps.Equal(calls, want) {
+		t.Errorf("gcloud config lookups = %v, want %v", calls, want)
+	}
+}
+
+func TestDefaultsWithoutNew(t *testing.T) {
+	orig := gcloudConfig
+	gcloudConfig = func(key string) (string, error) {
+		t.Errorf("gcloudConfig(%q) called for a Config not made by New", key)
+		return "", nil
+	}
+	t.Cleanup(func() { gcloudConfig = orig })
+
+	c := &Config{}
+	if got := c.DefaultProjectID(); got != "" {
+		t.Errorf("DefaultProjectID() = %q, want empty", got)
+	}
+	if got := c.DefaultLocation(); got != "" {
+		t.Errorf("DefaultLocation() = %q, want empty", got)
+	}
+}
+
+func TestADCProjectID(t *testing.T) {
+	tests := []struct {
+		name string
+		adc  string
+		want string
+	}{
+		{
+			name: "service account",
+			adc:  `{"type": "service_account", "project_id": "sa-project", "client_email": "sa@sa-project.iam.gserviceaccount.com", "private_key": ""}`,
+			want: "sa-project",
+		},
+		{
+			name: "user quota project",
+			adc:  `{"type": "authorized_user", "client_id": "id", "client_secret": "secret", "refresh_token": "token", "quota_project_id": "quota-project"}`,
+			want: "quota-project",
+		},
+		{
+			name: "no project",
+			adc:  `{"type": "authorized_user", "client_id": "id", "client_secret": "secret", "refresh_token": "token"}`,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adc := filepath.Join(t.TempDir(), "adc.json")
+			if err := os.WriteFile(adc, []byte(tt.adc), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", adc)
+
+			got, err := getADCProjectID()
+			if err != nil {
+				t.Fatalf("getADCProjectID() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("getADCProjectID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultProjectIDFromADC(t *testing.T) {
+	t.Setenv(ProjectIDEnv, "")
+	adc := filepath.Join(t.TempDir(), "adc.json")
+	if err := os.WriteFile(adc, []byte(`{"type": "service_account", "project_id": "sa-project", "client_email": "sa@sa-project.iam.gserviceaccount.com", "private_key": ""}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", adc)
+
+	tests := []struct {
+		name   string
+		gcloud func(string) (string, error)
+		want   string
+	}{
+		{
+			name:   "gcloud missing",
+			gcloud: func(string) (string, error) { return "", exec.ErrNotFound },
+			want:   "sa-project",
+		},
+		{
+			name:   "gcloud project unset",
+			gcloud: func(string) (string, error) { return "", nil },
+			want:   "sa-project",
+		},
+		{
+			name:   "gcloud project set",
+			gcloud: func(string) (string, error) { return "gcloud-project", nil },
+			want:   "gcloud-project",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := gcloudConfig
+			gcloudConfig = tt.gcloud
+			t.Cleanup(func() { gcloudConfig = orig })
+
+			if got := getDefaultProjectID(""); got != tt.want {
+				t.Errorf("getDefaultProjectID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	c, err := New("0.3.0", "")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	platform := "gke-mcp/0.3.0 (" + runtime.GOOS + "/" + runtime.GOARCH + ")"
+	if got := c.UserAgent(); got != platform {
+		t.Errorf("UserAgent() = %q, want %q", got, platform)
+	}
+
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{name: "gemini-cli", version: "1.2", want: platform + " client/gemini-cli-1.2"},
+		{name: "Visual Studio Code", version: "1.104.0 (insiders)", want: platform + " client/Visual-Studio-Code-1.104.0-insiders"},
+		{name: "claude-code", want: platform + " client/claude-code"},
+		{want: platform},
+	}
+	for _, tt := range tests {
+		c.SetClient(tt.name, tt.version)
+		if got := c.UserAgent(); got != tt.want {
+			t.Errorf("UserAgent() after SetClient(%q, %q) = %q, want %q", tt.name, tt.version, got, tt.want)
+		}
+	}
+}
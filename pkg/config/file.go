@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// File is the optional configuration file of the server. Its values are used
+// over the gcloud configuration, and environment variables and flags are used
+// over them.
+type File struct {
+	// ProjectID is the default project of the tools.
+	ProjectID string `yaml:"project"`
+	// Location is the default region or zone of the tools.
+	Location string `yaml:"location"`
+	// FallbackLocations are the locations used, in order, when neither the
+	// user nor Location give one.
+	FallbackLocations []string `yaml:"fallback_locations"`
+	// CacheDir is the directory the tools cache downloads in.
+	CacheDir string `yaml:"cache_dir"`
+	// ReleaseNotesHTMLFallback sets whether the GKE release notes page is
+	// scraped if the release notes feed cannot be read.
+	ReleaseNotesHTMLFallback *bool `yaml:"release_notes_html_fallback"`
+	// Verbose sets whether the tools log the details of what they do.
+	Verbose *bool `yaml:"verbose"`
+	// ReadOnly sets whether the tools that change anything are left out.
+	ReadOnly *bool `yaml:"read_only"`
+	// CallTimeout is the time each API call may take, e.g. 30s, or 0 for
+	// unbounded.
+	CallTimeout string `yaml:"call_timeout"`
+	// MaxAttempts is the number of times an API call failing with a quota or
+	// transient availability error is made.
+	MaxAttempts int `yaml:"max_attempts"`
+	// MaxLogLimit is the largest number of log entries query_logs returns
+	// in one call, 0 for the default of the tool.
+	MaxLogLimit int `yaml:"max_log_limit"`
+	// DeniedTools are the names of the tools left out of the server.
+	DeniedTools []string `yaml:"denied_tools"`
+}
+
+// DefaultFilePath returns the path of the configuration file read if no
+// other is given, in $XDG_CONFIG_HOME/gke-mcp or ~/.config/gke-mcp.
+func DefaultFilePath() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "gke-mcp", "config.yaml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "gke-mcp", "config.yaml"), nil
+}
+
+// LoadFile reads the configuration file at path, or at DefaultFilePath if
+// path is empty. A missing file is an error only if path is set. Unknown keys
+// are logged and ignored.
+func LoadFile(path string) (*File, error) {
+	explicit := path != ""
+	if !explicit {
+		var err error
+		if path, err = DefaultFilePath(); err != nil {
+			slog.Warn("Failed to find the configuration file, skipping it", "err", err)
+			return &File{}, nil
+		}
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+	f, err := parseFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file %s: %w", path, err)
+	}
+	slog.Info("Loaded configuration file", "path", path)
+	return f, nil
+}
+
+func parseFile(data []byte) (*File, error) {
+	f := &File{}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	// An empty file has no document.
+	if len(doc.Content) == 0 {
+		return f, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a mapping at line %d", root.Line)
+	}
+	known := fileKeys()
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if key := root.Content[i]; !known[key.Value] {
+			slog.Warn("Ignoring unknown key of the configuration file", "key", key.Value, "line", key.Line)
+		}
+	}
+	if err := root.Decode(f); err != nil {
+		return nil, err
+	}
+	if f.CallTimeout != "" {
+		if _, err := time.ParseDuration(f.CallTimeout); err != nil {
+			return nil, fmt.Errorf("invalid call_timeout: %w", err)
+		}
+	}
+	if f.MaxLogLimit < 0 {
+		return nil, fmt.Errorf("invalid max_log_limit: cannot be negative")
+	}
+	return f, nil
+}
+
+// fileKeys returns the keys of the configuration file.
+func fileKeys() map[string]bool {
+	keys := map[string]bool{}
+	t := reflect.TypeOf(File{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		keys[name] = true
+	}
+	return keys
+}
@@ -0,0 +1,205 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `project: file-project
+location: us-east1
+cache_dir: /tmp/gke-mcp
+verbose: true
+release_notes_html_fallback: false
+max_log_limit: 50
+denied_tools: [get_kubeconfig]
+log_limit: 50
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() {
+		slog.SetDefault(defaultLogger)
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+	})
+
+	f, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() failed: %v", err)
+	}
+	if f.ProjectID != "file-project" || f.Location != "us-east1" || f.CacheDir != "/tmp/gke-mcp" {
+		t.Errorf("LoadFile() = %+v, want the project, location and cache_dir of the file", f)
+	}
+	if f.Verbose == nil || !*f.Verbose {
+		t.Errorf("LoadFile() Verbose = %v, want true", f.Verbose)
+	}
+	if f.ReleaseNotesHTMLFallback == nil || *f.ReleaseNotesHTMLFallback {
+		t.Errorf("LoadFile() ReleaseNotesHTMLFallback = %v, want false", f.ReleaseNotesHTMLFallback)
+	}
+	if f.MaxLogLimit != 50 {
+		t.Errorf("LoadFile() MaxLogLimit = %d, want 50", f.MaxLogLimit)
+	}
+	if !reflect.DeepEqual(f.DeniedTools, []string{"get_kubeconfig"}) {
+		t.Errorf("LoadFile() DeniedTools = %q, want [get_kubeconfig]", f.DeniedTools)
+	}
+	if !strings.Contains(logs.String(), `key=log_limit line=8`) {
+		t.Errorf("LoadFile() didn't warn of the unknown key, logs:\n%s", logs.String())
+	}
+	if strings.Contains(logs.String(), `key=denied_tools`) {
+		t.Errorf("LoadFile() warned of the known key denied_tools, logs:\n%s", logs.String())
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "missing"},
+		{name: "not a mapping", data: "- project\n"},
+		{name: "wrong type", data: "verbose: often\n"},
+		{name: "invalid", data: "project: [\n"},
+		{name: "invalid call timeout", data: "call_timeout: 30\n"},
+		{name: "negative max log limit", data: "max_log_limit: -1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "-")+".yaml")
+			if tt.data != "" {
+				if err := os.WriteFile(path, []byte(tt.data), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := LoadFile(path); err == nil {
+				t.Errorf("LoadFile() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestLoadDefaultFile(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	f, err := LoadFile("")
+	if err != nil {
+		t.Fatalf("LoadFile() without a file failed: %v", err)
+	}
+	if !reflect.DeepEqual(*f, File{}) {
+		t.Errorf("LoadFile() without a file = %+v, want empty", f)
+	}
+
+	path := filepath.Join(xdg, "gke-mcp", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("project: file-project\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = LoadFile(""); err != nil || f.ProjectID != "file-project" {
+		t.Errorf("LoadFile() = %+v, %v, want the project of %s", f, err, path)
+	}
+}
+
+func TestNewFromFile(t *testing.T) {
+	t.Setenv(ProjectIDEnv, "")
+	t.Setenv(LocationEnv, "")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := "project: file-project\nlocation: us-east1\ncache_dir: /tmp/gke-mcp\nverbose: true\ncall_timeout: 30s\nmax_attempts: 3\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	orig := gcloudConfig
+	gcloudConfig = func(key string) (string, error) {
+		t.Errorf("gcloudConfig(%q) called although the configuration file sets it", key)
+		return "", nil
+	}
+	t.Cleanup(func() { gcloudConfig = orig })
+
+	c, err := New("test", path)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if got := c.DefaultProjectID(); got != "file-project" {
+		t.Errorf("DefaultProjectID() = %q, want %q", got, "file-project")
+	}
+	if got := c.DefaultLocation(); got != "us-east1" {
+		t.Errorf("DefaultLocation() = %q, want %q", got, "us-east1")
+	}
+	if got := c.CacheDir(); got != "/tmp/gke-mcp" {
+		t.Errorf("CacheDir() = %q, want %q", got, "/tmp/gke-mcp")
+	}
+	if !c.Verbose() {
+		t.Errorf("Verbose() = false, want true")
+	}
+	if !c.ReleaseNotesHTMLFallback() {
+		t.Errorf("ReleaseNotesHTMLFallback() = false, want the default true")
+	}
+	if got := c.CallTimeout(); got != 30*time.Second {
+		t.Errorf("CallTimeout() = %v, want %v", got, 30*time.Second)
+	}
+	if got := c.MaxAttempts(); got != 3 {
+		t.Errorf("MaxAttempts() = %d, want 3", got)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("read_only: true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		env     string
+		want    bool
+		wantErr bool
+	}{
+		{env: "", want: true},
+		{env: "false", want: false},
+		{env: "1", want: true},
+		{env: "sometimes", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Setenv(ReadOnlyEnv, tt.env)
+		c, err := New("test", path)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("New() with %s=%q succeeded, want an error", ReadOnlyEnv, tt.env)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("New() with %s=%q failed: %v", ReadOnlyEnv, tt.env, err)
+		}
+		if got := c.ReadOnly(); got != tt.want {
+			t.Errorf("ReadOnly() with %s=%q = %v, want %v", ReadOnlyEnv, tt.env, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+)
+
+// FallbackLocationsEnv is the environment variable with the comma separated
+// locations the tools use, in order, when neither the user nor the defaults
+// give one.
+const FallbackLocationsEnv = "GKE_MCP_FALLBACK_LOCATIONS"
+
+// ClusterLocator returns the locations of the clusters named name in
+// projectID, across all locations.
+type ClusterLocator func(ctx context.Context, projectID, name string) ([]string, error)
+
+// FallbackLocations returns the locations of GKE_MCP_FALLBACK_LOCATIONS, or
+// else of the configuration file, in order of preference.
+func (c *Config) FallbackLocations() []string {
+	if env := os.Getenv(FallbackLocationsEnv); env != "" {
+		var locations []string
+		for _, l := range strings.Split(env, ",") {
+			if l = strings.TrimSpace(l); l != "" {
+				locations = append(locations, l)
+			}
+		}
+		return locations
+	}
+	return c.file.FallbackLocations
+}
+
+// ResolveLocation returns the first non-empty of location, the default
+// location and the first fallback location.
+func (c *Config) ResolveLocation(location string) string {
+	if location != "" {
+		return location
+	}
+	if l := c.DefaultLocation(); l != "" {
+		return l
+	}
+	if fallbacks := c.FallbackLocations(); len(fallbacks) > 0 {
+		return fallbacks[0]
+	}
+	return ""
+}
+
+// ResolveClusterLocation returns the location of the cluster name like
+// ResolveLocation, except that without a location nor a default location it
+// looks for the cluster in all locations with locate first. If the cluster
+// exists in several locations, the first of them in the fallback locations is
+// used, and an error listing them is returned if there is none.
+func (c *Config) ResolveClusterLocation(ctx context.Context, projectID, location, name string, locate ClusterLocator) (string, error) {
+	if location != "" {
+		return location, nil
+	}
+	if l := c.DefaultLocation(); l != "" {
+		return l, nil
+	}
+	if locate == nil || projectID == "" || name == "" {
+		return c.ResolveLocation(""), nil
+	}
+	candidates, err := locate(ctx, projectID, name)
+	if err != nil {
+		slog.Warn("Failed to look for the location of the cluster, using the fallback locations", "cluster", name, "err", err)
+		return c.ResolveLocation(""), nil
+	}
+	switch len(candidates) {
+	case 0:
+		return c.ResolveLocation(""), nil
+	case 1:
+		return candidates[0], nil
+	}
+	for _, l := range c.FallbackLocations() {
+		if slices.Contains(candidates, l) {
+			return l, nil
+		}
+	}
+	return "", fmt.Errorf("cluster %s exists in several locations, set location to one of: %s", name, strings.Join(candidates, ", "))
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFallbackLocations(t *testing.T) {
+	c := &Config{file: File{FallbackLocations: []string{"us-east1"}}}
+	t.Setenv(FallbackLocationsEnv, "")
+	if got, want := c.FallbackLocations(), []string{"us-east1"}; !slices.Equal(got, want) {
+		t.Errorf("FallbackLocations() = %v, want %v from the file", got, want)
+	}
+	t.Setenv(FallbackLocationsEnv, "us-central1, europe-west4,")
+	if got, want := c.FallbackLocations(), []string{"us-central1", "europe-west4"}; !slices.Equal(got, want) {
+		t.Errorf("FallbackLocations() = %v, want %v from %s", got, want, FallbackLocationsEnv)
+	}
+}
+
+func TestResolveLocation(t *testing.T) {
+	t.Setenv(FallbackLocationsEnv, "")
+	tests := []struct {
+		name     string
+		c        *Config
+		location string
+		want     string
+	}{
+		{
+			name:     "explicit",
+			c:        &Config{defaultLocation: "us-east1", file: File{FallbackLocations: []string{"us-central1"}}},
+			location: "europe-west4",
+			want:     "europe-west4",
+		},
+		{
+			name: "default",
+			c:    &Config{defaultLocation: "us-east1", file: File{FallbackLocations: []string{"us-central1"}}},
+			want: "us-east1",
+		},
+		{
+			name: "fallback",
+			c:    &Config{file: File{FallbackLocations: []string{"us-central1", "us-east1"}}},
+			want: "us-central1",
+		},
+		{
+			name: "none",
+			c:    &Config{},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.ResolveLocation(tt.location); got != tt.want {
+				t.Errorf("ResolveLocation(%q) = %q, want %q", tt.location, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveClusterLocation(t *testing.T) {
+	t.Setenv(FallbackLocationsEnv, "")
+	locator := func(locations []string, err error) ClusterLocator {
+		return func(_ context.Context, projectID, name string) ([]string, error) {
+			if projectID != "p" || name != "c" {
+				t.Errorf("locator called with project %q and name %q, want %q and %q", projectID, name, "p", "c")
+			}
+			return locations, err
+		}
+	}
+	tests := []struct {
+		name      string
+		c         *Config
+		location  string
+		locate    ClusterLocator
+		want      string
+		wantError string
+	}{
+		{
+			name:     "explicit",
+			c:        &Config{},
+			location: "us-east1",
+			locate:   locator([]string{"us-central1"}, nil),
+			want:     "us-east1",
+		},
+		{
+			name:   "default",
+			c:      &Config{defaultLocation: "us-east1"},
+			locate: locator([]string{"us-central1"}, nil),
+			want:   "us-east1",
+		},
+		{
+			name:   "single match",
+			c:      &Config{file: File{FallbackLocations: []string{"us-east1"}}},
+			locate: locator([]string{"us-central1"}, nil),
+			want:   "us-central1",
+		},
+		{
+			name:   "no match",
+			c:      &Config{file: File{FallbackLocations: []string{"us-east1"}}},
+			locate: locator(nil, nil),
+			want:   "us-east1",
+		},
+		{
+			name:   "lookup failed",
+			c:      &Config{file: File{FallbackLocations: []string{"us-east1"}}},
+			locate: locator(nil, errors.New("permission denied")),
+			want:   "us-east1",
+		},
+		{
+			name:   "ambiguous with fallback",
+			c:      &Config{file: File{FallbackLocations: []string{"asia-east1", "europe-west4", "us-central1"}}},
+			locate: locator([]string{"us-central1", "europe-west4"}, nil),
+			want:   "europe-west4",
+		},
+		{
+			name:      "ambiguous",
+			c:         &Config{},
+			locate:    locator([]string{"us-central1", "europe-west4"}, nil),
+			wantError: "us-central1, europe-west4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.ResolveClusterLocation(context.Background(), "p", tt.location, "c", tt.locate)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Errorf("ResolveClusterLocation() error = %v, want one listing %s", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveClusterLocation() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveClusterLocation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
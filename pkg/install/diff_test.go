@@ -0,0 +1,65 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	testCases := []struct {
+		name   string
+		old    string
+		new    string
+		exists bool
+		want   string
+	}{
+		{
+			name: "new file",
+			new:  "a\nb\n",
+			want: "--- /dev/null\n+++ f.json\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:   "unchanged",
+			old:    "a\n",
+			new:    "a\n",
+			exists: true,
+			want:   "No changes to f.json\n",
+		},
+		{
+			name:   "change with context",
+			old:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			new:    "1\n2\n3\n4\n5\nsix\n7\n8\n9\n",
+			exists: true,
+			want:   "--- f.json\n+++ f.json\n@@ -3,7 +3,7 @@\n 3\n 4\n 5\n-6\n+six\n 7\n 8\n 9\n",
+		},
+		{
+			name:   "separate hunks",
+			old:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			new:    "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n",
+			exists: true,
+			want:   "--- f.json\n+++ f.json\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -8,3 +8,4 @@\n 8\n 9\n 10\n+11\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := unifiedDiff("f.json", []byte(tc.old), []byte(tc.new), tc.exists)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unifiedDiff() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
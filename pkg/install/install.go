@@ -16,7 +16,20 @@ package install
 import (
 	_ "embed"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+)
+
+// The transports the installed clients can connect to the server with
+const (
+	transportStdio = "stdio"
+	transportHTTP  = "http"
 )
 
 type InstallOptions struct {
@@ -24,16 +37,67 @@ type InstallOptions struct {
 	installDir    string
 	exePath       string
 	developerMode bool
+	projectOnly   bool
+	dryRun        bool
+	assumeYes     bool
+	manual        bool
+	transport     string
+	serverPort    int
+	url           string
+	serverArgs    []string
+	force         bool
+	env           map[string]string
+	backups       []string
 }
 
+// now returns the time backups are named after, tests replace it
+var now = time.Now
+
 func NewInstallOptions(
 	version string,
 	projectOnly bool,
 	developerMode bool,
+	dryRun bool,
+	assumeYes bool,
+	transport string,
+	serverPort int,
+	exePath string,
+	serverArgs []string,
+	force bool,
+	envArgs []string,
+	projectID string,
+	location string,
+	manual bool,
+	serverURL string,
 ) (*InstallOptions, error) {
+	switch transport {
+	case transportStdio:
+		if serverURL != "" {
+			return nil, fmt.Errorf("a server URL only applies to the %s transport", transportHTTP)
+		}
+	case transportHTTP:
+		if serverURL != "" {
+			if err := checkServerURL(serverURL); err != nil {
+				return nil, err
+			}
+		} else if serverPort <= 0 || serverPort > 65535 {
+			return nil, fmt.Errorf("invalid server port %d", serverPort)
+		}
+	default:
+		return nil, fmt.Errorf("unknown transport %q, must be %s or %s", transport, transportStdio, transportHTTP)
+	}
+	if transport == transportHTTP && len(serverArgs) > 0 {
+		return nil, fmt.Errorf("server args only apply to the %s transport, pass them to the server started in HTTP mode instead", transportStdio)
+	}
+	env, err := serverEnv(envArgs, projectID, location)
+	if err != nil {
+		return nil, err
+	}
+	if transport == transportHTTP && len(env) > 0 {
+		return nil, fmt.Errorf("environment variables only apply to the %s transport, set them for the server started in HTTP mode instead", transportStdio)
+	}
 
 	installDir := ""
-	var err error
 	if projectOnly {
 		installDir, err = os.Getwd()
 		if err != nil {
@@ -46,9 +110,19 @@ func NewInstallOptions(
 		}
 	}
 
-	exePath, err := os.Executable()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get executable path: %w", err)
+	if exePath == "" {
+		exePath, err = os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get executable path: %w", err)
+		}
+	} else {
+		exePath, err = filepath.Abs(exePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get absolute executable path: %w", err)
+		}
+		if err := checkExecutable(exePath); err != nil && !force {
+			return nil, fmt.Errorf("%w; pass --force to install it anyway", err)
+		}
 	}
 
 	return &InstallOptions{
@@ -56,8 +130,181 @@ func NewInstallOptions(
 		installDir:    installDir,
 		exePath:       exePath,
 		developerMode: developerMode,
+		projectOnly:   projectOnly,
+		dryRun:        dryRun,
+		assumeYes:     assumeYes,
+		manual:        manual,
+		transport:     transport,
+		serverPort:    serverPort,
+		url:           serverURL,
+		serverArgs:    serverArgs,
+		force:         force,
+		env:           env,
 	}, nil
 }
 
+// serverEnv returns the environment variables of the server from KEY=VALUE
+// envArgs, and the ones pinning it to projectID and location if set
+func serverEnv(envArgs []string, projectID, location string) (map[string]string, error) {
+	env := make(map[string]string)
+	for _, arg := range envArgs {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid environment variable %q, must be KEY=VALUE", arg)
+		}
+		env[key] = value
+	}
+	if projectID != "" {
+		env[config.ProjectIDEnv] = projectID
+		// For the gcloud and kubectl commands the server runs.
+		env["CLOUDSDK_CORE_PROJECT"] = projectID
+	}
+	if location != "" {
+		env[config.LocationEnv] = location
+	}
+	if len(env) == 0 {
+		return nil, nil
+	}
+	return env, nil
+}
+
+// checkExecutable returns an error if there is no executable file at path
+func checkExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("could not find executable: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("executable %s is a directory", path)
+	}
+	// Windows has no executable permission bits.
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("%s is not executable", path)
+	}
+	return nil
+}
+
+// isHTTP reports whether the clients connect to a server started separately in
+// HTTP mode rather than run it over stdio
+func (o *InstallOptions) isHTTP() bool {
+	return o.transport == transportHTTP
+}
+
+// serverURL returns the URL of the server in HTTP mode, the local server on
+// the server port unless a URL was given
+func (o *InstallOptions) serverURL() string {
+	if o.url != "" {
+		return o.url
+	}
+	return fmt.Sprintf("http://localhost:%d", o.serverPort)
+}
+
+// checkServerURL returns an error if rawURL is not the http or https URL of a
+// server
+func checkServerURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid server URL %q: %w", rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid server URL %q, must start with http:// or https://", rawURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid server URL %q, has no host", rawURL)
+	}
+	return nil
+}
+
+// mkdirAll creates the directory at path, unless this is a dry run
+func (o *InstallOptions) mkdirAll(path string) error {
+	if o.dryRun {
+		return nil
+	}
+	return os.MkdirAll(path, 0755)
+}
+
+// writeFile writes data to the file at path, or prints the changes it would
+// make to stdout in a dry run
+func (o *InstallOptions) writeFile(path string, data []byte) error {
+	old, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	exists := err == nil
+	if o.dryRun {
+		fmt.Print(unifiedDiff(path, old, data, exists))
+		return nil
+	}
+	if exists && string(old) != string(data) {
+		backup, err := backupFile(path, old)
+		if err != nil {
+			return err
+		}
+		if backup != "" {
+			o.backups = append(o.backups, backup)
+		}
+	}
+	return writeFileAtomic(path, data)
+}
+
+// Backups returns the backups made of existing files before changing them
+func (o *InstallOptions) Backups() []string {
+	return o.backups
+}
+
+// backupFile copies the contents old of the file at path to path.bak-<date>
+// and returns the backup path, unless the file was backed up before, in which
+// case the backup of the file as the user left it is kept.
+func backupFile(path string, old []byte) (string, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), filepath.Base(path)+".bak-") {
+			return "", nil
+		}
+	}
+	backup := path + ".bak-" + now().Format("20060102")
+	if err := writeFileAtomic(backup, old); err != nil {
+		return "", fmt.Errorf("could not back up %s: %w", path, err)
+	}
+	return backup, nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// to path, so that the file is never left half written. The mode of an
+// existing file is kept, and a symlink is followed to the file it points to.
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
 //go:embed GEMINI.md
 var GeminiMarkdown []byte
@@ -0,0 +1,172 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Client is an AI client the GKE MCP Server can be installed into
+type Client struct {
+	Name    string
+	Detect  func() bool
+	Install func(*InstallOptions) error
+}
+
+// The statuses of the installation into a client
+const (
+	StatusInstalled = "installed"
+	StatusSkipped   = "skipped"
+	StatusFailed    = "failed"
+)
+
+// ErrUnsupported is matched by the errors of the installers of clients that
+// don't support the install options, e.g. --project-only or --transport http
+var ErrUnsupported = errors.New("unsupported install options")
+
+// unsupportedError is an error matching ErrUnsupported
+type unsupportedError struct {
+	msg string
+}
+
+func (e *unsupportedError) Error() string {
+	return e.msg
+}
+
+func (e *unsupportedError) Is(target error) bool {
+	return target == ErrUnsupported
+}
+
+// unsupportedf returns an error matching ErrUnsupported with the formatted
+// message
+func unsupportedf(format string, args ...any) error {
+	return &unsupportedError{msg: fmt.Sprintf(format, args...)}
+}
+
+// InstallResult is the outcome of the installation into a client
+type InstallResult struct {
+	Client string
+	Status string
+	Err    error
+}
+
+// Clients returns the AI clients with an installer
+func Clients() []Client {
+	return []Client{
+		{Name: "gemini-cli", Detect: DetectGeminiCLI, Install: GeminiCLIExtension},
+		{Name: "cursor", Detect: DetectCursor, Install: CursorMCPExtension},
+		{Name: "vscode", Detect: DetectVSCode, Install: VSCodeMCPExtension},
+		{Name: "claude-desktop", Detect: DetectClaudeDesktop, Install: ClaudeDesktopExtension},
+		{Name: "claude-code", Detect: DetectClaudeCode, Install: ClaudeCodeExtension},
+		{Name: "goose", Detect: DetectGoose, Install: GooseExtension},
+		{Name: "jetbrains", Detect: DetectJetBrains, Install: JetBrainsExtension},
+	}
+}
+
+// InstallAll installs the GKE MCP Server into the clients that are detected
+// on this machine. A failed installation doesn't stop the next ones, and the
+// clients that don't support the install options are skipped with the reason.
+func InstallAll(opts *InstallOptions, clients []Client) []InstallResult {
+	results := make([]InstallResult, 0, len(clients))
+	for _, c := range clients {
+		if !c.Detect() {
+			results = append(results, InstallResult{Client: c.Name, Status: StatusSkipped})
+			continue
+		}
+		err := c.Install(opts)
+		switch {
+		case errors.Is(err, ErrUnsupported):
+			results = append(results, InstallResult{Client: c.Name, Status: StatusSkipped, Err: err})
+		case err != nil:
+			results = append(results, InstallResult{Client: c.Name, Status: StatusFailed, Err: err})
+		default:
+			results = append(results, InstallResult{Client: c.Name, Status: StatusInstalled})
+		}
+	}
+	return results
+}
+
+// DetectGeminiCLI reports whether Gemini CLI is installed
+func DetectGeminiCLI() bool {
+	if paths, err := homeConfigPaths(); err == nil && dirExists(paths.geminiDir()) {
+		return true
+	}
+	return onPath("gemini")
+}
+
+// DetectCursor reports whether Cursor is installed
+func DetectCursor() bool {
+	return homeDirExists(".cursor") || onPath("cursor")
+}
+
+// DetectVSCode reports whether VS Code is installed
+func DetectVSCode() bool {
+	if userDir, err := getVSCodeUserDir(); err == nil && dirExists(userDir) {
+		return true
+	}
+	return onPath("code")
+}
+
+// DetectClaudeDesktop reports whether Claude Desktop is installed
+func DetectClaudeDesktop() bool {
+	configPath, err := getClaudeDesktopConfigPath()
+	return err == nil && dirExists(filepath.Dir(configPath))
+}
+
+// DetectClaudeCode reports whether the Claude Code CLI is installed
+func DetectClaudeCode() bool {
+	return onPath("claude")
+}
+
+// DetectGoose reports whether Goose is installed
+func DetectGoose() bool {
+	if paths, err := homeConfigPaths(); err == nil {
+		if configPath, err := paths.gooseConfigPath(); err == nil && dirExists(filepath.Dir(configPath)) {
+			return true
+		}
+	}
+	return onPath("goose")
+}
+
+// DetectJetBrains reports whether a JetBrains IDE is installed
+func DetectJetBrains() bool {
+	paths, err := homeConfigPaths()
+	if err != nil {
+		return false
+	}
+	dirs, err := jetbrainsIDEDirs(paths)
+	return err == nil && len(dirs) > 0
+}
+
+// homeDirExists reports whether the directory name exists in the home directory
+func homeDirExists(name string) bool {
+	homeDir, err := os.UserHomeDir()
+	return err == nil && dirExists(filepath.Join(homeDir, name))
+}
+
+// dirExists reports whether path is a directory
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// onPath reports whether the command is found in the PATH
+func onPath(command string) bool {
+	_, err := exec.LookPath(command)
+	return err == nil
+}
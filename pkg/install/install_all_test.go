@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestInstallAll(t *testing.T) {
+	var installed []string
+	fakeInstall := func(name string, err error) func(*InstallOptions) error {
+		return func(*InstallOptions) error {
+			installed = append(installed, name)
+			return err
+		}
+	}
+	errBroken := errors.New("broken config")
+	errGlobalOnly := unsupportedf("no project configuration")
+	clients := []Client{
+		{Name: "first", Detect: func() bool { return true }, Install: fakeInstall("first", errBroken)},
+		{Name: "missing", Detect: func() bool { return false }, Install: fakeInstall("missing", nil)},
+		{Name: "global-only", Detect: func() bool { return true }, Install: fakeInstall("global-only", errGlobalOnly)},
+		{Name: "last", Detect: func() bool { return true }, Install: fakeInstall("last", nil)},
+	}
+
+	results := InstallAll(&InstallOptions{}, clients)
+
+	want := []InstallResult{
+		{Client: "first", Status: StatusFailed, Err: errBroken},
+		{Client: "missing", Status: StatusSkipped},
+		{Client: "global-only", Status: StatusSkipped, Err: errGlobalOnly},
+		{Client: "last", Status: StatusInstalled},
+	}
+	if diff := cmp.Diff(want, results, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("InstallAll() results mismatch. Diff:\n%v", diff)
+	}
+
+	// The failure of the first client must not stop the last one
+	if diff := cmp.Diff([]string{"first", "global-only", "last"}, installed); diff != "" {
+		t.Errorf("InstallAll() installed unexpected clients. Diff:\n%v", diff)
+	}
+}
+
+func TestInstallAllUnsupportedOptions(t *testing.T) {
+	detected := func() bool { return true }
+	tests := []struct {
+		name string
+		opts *InstallOptions
+	}{
+		{name: "project only", opts: &InstallOptions{projectOnly: true}},
+		{name: "http", opts: &InstallOptions{transport: transportHTTP, serverPort: 8080}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clients := []Client{{Name: "claude-desktop", Detect: detected, Install: ClaudeDesktopExtension}}
+			results := InstallAll(tt.opts, clients)
+			if len(results) != 1 || results[0].Status != StatusSkipped || !errors.Is(results[0].Err, ErrUnsupported) {
+				t.Errorf("InstallAll() = %+v, want claude-desktop skipped as unsupported", results)
+			}
+		})
+	}
+}
+
+func TestDetectClients(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, true)
+	defer cleanup()
+
+	cleanupEnv := mockAppData(t, tmpDir)
+	defer cleanupEnv()
+
+	// Find no commands, only what is created in the mocked home directory
+	binDir := filepath.Join(tmpDir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatalf("Failed to create bin directory: %v", err)
+	}
+	originalPath := os.Getenv("PATH")
+	os.Setenv("PATH", binDir)
+	defer os.Setenv("PATH", originalPath)
+
+	detectors := map[string]func() bool{
+		"gemini-cli":     DetectGeminiCLI,
+		"cursor":         DetectCursor,
+		"vscode":         DetectVSCode,
+		"claude-desktop": DetectClaudeDesktop,
+		"claude-code":    DetectClaudeCode,
+		"goose":          DetectGoose,
+		"jetbrains":      DetectJetBrains,
+	}
+	for name, detect := range detectors {
+		if detect() {
+			t.Errorf("Expected %s not to be detected in an empty home directory", name)
+		}
+	}
+
+	vscodeDir, err := getVSCodeUserDir()
+	if err != nil {
+		t.Fatalf("could not determine VS Code user directory: %v", err)
+	}
+	claudeConfigPath, err := getClaudeDesktopConfigPath()
+	if err != nil {
+		t.Fatalf("could not determine Claude Desktop config path: %v", err)
+	}
+	paths, err := homeConfigPaths()
+	if err != nil {
+		t.Fatalf("could not determine config paths: %v", err)
+	}
+	gooseConfigPath, err := paths.gooseConfigPath()
+	if err != nil {
+		t.Fatalf("could not determine Goose config path: %v", err)
+	}
+	jetbrainsDir, err := paths.jetbrainsDir()
+	if err != nil {
+		t.Fatalf("could not determine JetBrains config path: %v", err)
+	}
+	for _, dir := range []string{
+		filepath.Join(tmpDir, ".gemini"),
+		filepath.Join(tmpDir, ".cursor"),
+		vscodeDir,
+		filepath.Dir(claudeConfigPath),
+		filepath.Dir(gooseConfigPath),
+		filepath.Join(jetbrainsDir, "GoLand2025.1"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+	claudePath := filepath.Join(binDir, "claude")
+	if runtime.GOOS == "windows" {
+		claudePath += ".bat"
+	}
+	if err := os.WriteFile(claudePath, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to create claude command: %v", err)
+	}
+
+	for name, detect := range detectors {
+		if !detect() {
+			t.Errorf("Expected %s to be detected", name)
+		}
+	}
+}
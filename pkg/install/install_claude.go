@@ -19,54 +19,56 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
+	"slices"
 	"strings"
 )
 
 // ClaudeDesktopExtension installs the GKE MCP Server into Claude Desktop settings
 func ClaudeDesktopExtension(opts *InstallOptions) error {
-	configPath, err := getClaudeDesktopConfigPath()
+	// Claude Desktop only has a global configuration, which only supports local servers
+	if opts.projectOnly {
+		return unsupportedf("Claude Desktop has no project configuration; install it without --project-only")
+	}
+	if opts.isHTTP() {
+		return unsupportedf("Claude Desktop can only run the server over stdio from its configuration; add %s as a custom connector in its settings instead", opts.serverURL())
+	}
+
+	configPath, err := opts.configPaths().claudeDesktopConfigPath()
 	if err != nil {
 		return fmt.Errorf("could not determine Claude Desktop config path: %w", err)
 	}
 
 	// Ensure the directory exists
-	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+	if err := opts.mkdirAll(filepath.Dir(configPath)); err != nil {
 		return fmt.Errorf("could not create Claude Desktop config directory: %w", err)
 	}
 
-	// Read existing configuration if it exists
-	config := make(map[string]interface{})
-	if data, err := os.ReadFile(configPath); err == nil {
-		if err := json.Unmarshal(data, &config); err != nil {
-			return fmt.Errorf("could not parse existing Claude Desktop config: %w", err)
-		}
-	} else if !os.IsNotExist(err) {
+	// Read existing configuration if it exists, only changing the gke-mcp server
+	data, err := os.ReadFile(configPath)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("could not read Claude Desktop config: %w", err)
 	}
 
-	// Add or update the gke-mcp server configuration
-	mcpServers, ok := config["mcpServers"].(map[string]interface{})
-	if !ok {
-		// Handle the case where mcpServers does not exist or is not a map
-		mcpServers = make(map[string]interface{})
-		config["mcpServers"] = mcpServers
-	}
-
-	mcpServers["gke-mcp"] = map[string]interface{}{
-		"command": opts.exePath,
+	if exists {
+		var config map[string]interface{}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("could not parse existing Claude Desktop config: %w", err)
+		}
 	}
 
-	// Write the updated config back
-	data, err := json.MarshalIndent(config, "", "  ")
+	// Add or update the gke-mcp server configuration, replacing mcpServers if it is not a map
+	data, err = mergeMCPServer(data, exists, "mcpServers", opts.serverDefinition().mcpServerEntry("url"))
 	if err != nil {
-		return fmt.Errorf("could not marshal Claude Desktop config: %w", err)
+		return fmt.Errorf("could not update Claude Desktop config: %w", err)
 	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	// Write the updated config back
+	if err := opts.writeFile(configPath, data); err != nil {
 		return fmt.Errorf("could not write Claude Desktop config: %w", err)
 	}
 
@@ -75,36 +77,29 @@ func ClaudeDesktopExtension(opts *InstallOptions) error {
 
 // getClaudeDesktopConfigPath returns the platform-specific path to Claude Desktop's config file
 func getClaudeDesktopConfigPath() (string, error) {
-	var configDir string
-
-	switch runtime.GOOS {
-	case "darwin": // macOS
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", err
-		}
-		configDir = filepath.Join(homeDir, "Library", "Application Support", "Claude")
-	case "windows":
-		appData := os.Getenv("APPDATA")
-		if appData == "" {
-			return "", fmt.Errorf("APPDATA environment variable not set")
-		}
-		configDir = filepath.Join(appData, "Claude")
-	case "linux":
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", err
-		}
-		configDir = filepath.Join(homeDir, ".config", "Claude")
-	default:
-		return "", fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+	paths, err := homeConfigPaths()
+	if err != nil {
+		return "", err
 	}
+	return paths.claudeDesktopConfigPath()
+}
 
-	return filepath.Join(configDir, "claude_desktop_config.json"), nil
+// stdinIsTerminal reports whether stdin is a terminal a user can answer the
+// confirmation prompt from
+var stdinIsTerminal = func() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
 // ClaudeCodeExtension installs the GKE MCP Server for Claude Code CLI
 func ClaudeCodeExtension(opts *InstallOptions) error {
+	// Check for claude before changing any files, to not leave a half-installed server
+	if !opts.manual {
+		if _, err := exec.LookPath("claude"); err != nil {
+			return fmt.Errorf("the claude command was not found in the PATH; install Claude Code with `npm install -g @anthropic-ai/claude-code` and retry, or pass --manual to add the server to its configuration yourself")
+		}
+	}
+
 	installDir := opts.installDir
 	claudeMDPath := filepath.Join(installDir, "CLAUDE.md")
 
@@ -122,46 +117,59 @@ func ClaudeCodeExtension(opts *InstallOptions) error {
 		return fmt.Errorf("failed to check file status: %w", err)
 	}
 
-	fmt.Print("Would you like to proceed? (yes/no): ")
-	reader := bufio.NewReader(os.Stdin)
-	response, err := reader.ReadString('\n')
-	if err != nil {
-		return fmt.Errorf("failed to read user input: %w", err)
-	}
+	// A dry run changes nothing, so there is nothing to confirm.
+	if !opts.dryRun && !opts.assumeYes {
+		if !stdinIsTerminal() {
+			return fmt.Errorf("cannot ask for confirmation as stdin is not a terminal; pass --yes to proceed without it")
+		}
+		fmt.Print("Would you like to proceed? (yes/no): ")
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read user input: %w", err)
+		}
 
-	if strings.ToLower(strings.TrimSpace(response)) != "yes" {
-		fmt.Println("Installation canceled.")
-		return nil
+		if strings.ToLower(strings.TrimSpace(response)) != "yes" {
+			fmt.Println("Installation canceled.")
+			return nil
+		}
 	}
 
 	// Create the GKE_MCP_USAGE_GUIDE.md file
 	usageGuideMDPath := filepath.Join(installDir, "GKE_MCP_USAGE_GUIDE.md")
-	if err := os.WriteFile(usageGuideMDPath, []byte(GeminiMarkdown), 0644); err != nil {
+	_, err = os.Stat(usageGuideMDPath)
+	usageGuideExisted := err == nil
+	if err := opts.writeFile(usageGuideMDPath, []byte(GeminiMarkdown)); err != nil {
 		return fmt.Errorf("could not create GKE_MCP_USAGE_GUIDE.md: %w", err)
 	}
-	fmt.Println("Created GKE_MCP_USAGE_GUIDE.md.")
+	if !opts.dryRun {
+		fmt.Println("Created GKE_MCP_USAGE_GUIDE.md.")
+	}
 
 	// Add the reference line with the actual path to CLAUDE.md
 	claudeLine := fmt.Sprintf("\n# GKE-MCP Server Instructions\n - @%s", usageGuideMDPath)
 
-	file, err := os.OpenFile(claudeMDPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("could not open or create CLAUDE.md: %w", err)
+	claudeContent, err := os.ReadFile(claudeMDPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read CLAUDE.md: %w", err)
 	}
-	defer file.Close()
 
-	if _, err := file.WriteString(claudeLine); err != nil {
+	if err := opts.writeFile(claudeMDPath, append(claudeContent, claudeLine...)); err != nil {
 		return fmt.Errorf("could not append to CLAUDE.md: %w", err)
 	}
-	fmt.Println("Added a reference to GKE_MCP_USAGE_GUIDE.md in CLAUDE.md.")
+	if !opts.dryRun {
+		fmt.Println("Added a reference to GKE_MCP_USAGE_GUIDE.md in CLAUDE.md.")
+	}
+
+	if opts.manual {
+		return printClaudeCodeSnippet(opts)
+	}
 
 	// Execute the command to add the MCP server
-	command := "claude"
-	args := []string{
-		"mcp",
-		"add",
-		"gke-mcp",
-		opts.exePath,
+	command, args := claudeMCPAddCommand(opts)
+	if opts.dryRun {
+		fmt.Printf("Would run: %s %s\n", command, strings.Join(args, " "))
+		return nil
 	}
 
 	cmdToRun := exec.Command(command, args...)
@@ -169,8 +177,72 @@ func ClaudeCodeExtension(opts *InstallOptions) error {
 	cmdToRun.Stderr = os.Stderr
 
 	if err := cmdToRun.Run(); err != nil {
-		return fmt.Errorf("failed to run command 'claude mcp add': %w", err)
+		// Roll back the instructions, which refer to a server Claude Code doesn't know
+		if rollbackErr := rollbackClaudeMD(claudeMDPath, claudeContent, exists, usageGuideMDPath, usageGuideExisted); rollbackErr != nil {
+			return fmt.Errorf("failed to run command 'claude mcp add': %w; also failed to revert CLAUDE.md: %v", err, rollbackErr)
+		}
+		return fmt.Errorf("failed to run command 'claude mcp add', CLAUDE.md was reverted: %w", err)
+	}
+
+	return nil
+}
+
+// claudeMCPAddCommand returns the command adding the gke-mcp server to Claude Code
+func claudeMCPAddCommand(opts *InstallOptions) (string, []string) {
+	server := opts.serverDefinition()
+	args := []string{
+		"mcp",
+		"add",
+	}
+	if opts.projectOnly {
+		// Share the server with the project in its .mcp.json
+		args = append(args, "--scope", "project")
+	}
+	for _, key := range slices.Sorted(maps.Keys(server.env)) {
+		args = append(args, "--env", key+"="+server.env[key])
+	}
+	if server.transport == transportHTTP {
+		args = append(args, "--transport", "http", "gke-mcp", server.url)
+	} else if len(server.args) > 0 {
+		// The server arguments start with dashes, keep claude from parsing them
+		args = append(append(args, "gke-mcp", "--", server.command), server.args...)
+	} else {
+		args = append(args, "gke-mcp", server.command)
+	}
+	return "claude", args
+}
+
+// printClaudeCodeSnippet prints the configuration of the gke-mcp server, for
+// users without the claude command to add to Claude Code themselves
+func printClaudeCodeSnippet(opts *InstallOptions) error {
+	server := opts.serverDefinition()
+	entry := server.mcpServerEntry("url")
+	entry["type"] = server.transport
+	snippet, err := json.MarshalIndent(map[string]interface{}{
+		"mcpServers": map[string]interface{}{"gke-mcp": entry},
+	}, "", "  ")
+	if err != nil {
+		return err
 	}
 
+	where := "~/.claude.json to use it in every project"
+	if opts.projectOnly {
+		where = ".mcp.json at the root of the project to share it with the project"
+	}
+	fmt.Printf("Add the gke-mcp server to the mcpServers of %s:\n%s\n", where, snippet)
 	return nil
 }
+
+// rollbackClaudeMD restores CLAUDE.md to content, or removes it if it didn't
+// exist, and removes the usage guide if it was created
+func rollbackClaudeMD(claudeMDPath string, content []byte, existed bool, usageGuideMDPath string, usageGuideExisted bool) error {
+	if !usageGuideExisted {
+		if err := os.Remove(usageGuideMDPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if !existed {
+		return os.Remove(claudeMDPath)
+	}
+	return writeFileAtomic(claudeMDPath, content)
+}
@@ -16,7 +16,7 @@ package install
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 )
@@ -37,62 +37,53 @@ This rule provides context for using the gke-mcp tool within Cursor.
 // CursorMCPExtension installs the gke-mcp server as a Cursor MCP extension
 func CursorMCPExtension(opts *InstallOptions) error {
 	mcpDir := filepath.Join(opts.installDir, ".cursor")
+	if !opts.projectOnly {
+		mcpDir = opts.configPaths().cursorDir()
+	}
 
-	if err := os.MkdirAll(mcpDir, 0755); err != nil {
+	if err := opts.mkdirAll(mcpDir); err != nil {
 		return fmt.Errorf("could not create Cursor directory at %s: %w", mcpDir, err)
 	}
 	mcpPath := filepath.Join(mcpDir, "mcp.json")
 
-	// Read existing configuration if it exists, using unstructured approach to avoid data loss
-	var config map[string]interface{}
-
-	if _, err := os.Stat(mcpPath); err == nil {
-		// File exists, read and parse it
-		data, err := os.ReadFile(mcpPath)
-		if err != nil {
-			return fmt.Errorf("could not read existing MCP configuration: %w", err)
-		}
+	// Read existing configuration if it exists, only changing the gke-mcp server to avoid data loss
+	data, err := os.ReadFile(mcpPath)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read existing MCP configuration: %w", err)
+	}
 
+	if exists {
+		var config map[string]interface{}
 		if err := json.Unmarshal(data, &config); err != nil {
 			return fmt.Errorf("could not parse existing MCP configuration: %w", err)
 		}
-	} else {
-		// File doesn't exist, create new config
-		config = make(map[string]interface{})
-	}
 
-	// Ensure mcpServers exists
-	if _, exists := config["mcpServers"]; !exists {
-		config["mcpServers"] = make(map[string]interface{})
+		if _, ok := config["mcpServers"].(map[string]interface{}); !ok && config["mcpServers"] != nil {
+			// Handle the case where mcpServers is not a map
+			slog.Warn("mcpServers in Cursor MCP config is not a map, creating new one")
+		}
 	}
 
 	// Add or update the gke-mcp server configuration
-	mcpServers, ok := config["mcpServers"].(map[string]interface{})
-	if !ok {
-		// Handle the case where mcpServers is not a map
-		log.Printf("Warning: mcpServers in Cursor MCP config is not a map, creating new one")
-		config["mcpServers"] = make(map[string]interface{})
-		mcpServers = config["mcpServers"].(map[string]interface{})
-	}
-
-	mcpServers["gke-mcp"] = map[string]interface{}{
-		"command": opts.exePath,
-		"type":    "stdio",
+	entry := opts.serverDefinition().mcpServerEntry("url")
+	if !opts.isHTTP() {
+		entry["type"] = transportStdio
 	}
 
-	// Write the updated configuration back to the file
-	data, err := json.MarshalIndent(config, "", "  ")
+	data, err = mergeMCPServer(data, exists, "mcpServers", entry)
 	if err != nil {
-		return fmt.Errorf("could not marshal MCP configuration: %w", err)
+		return fmt.Errorf("could not update MCP configuration: %w", err)
 	}
 
-	if err := os.WriteFile(mcpPath, data, 0644); err != nil {
+	// Write the updated configuration back to the file
+	if err := opts.writeFile(mcpPath, data); err != nil {
 		return fmt.Errorf("could not write MCP configuration: %w", err)
 	}
 
 	// Create the rules directory and gke-mcp.mdc file
 	rulesDir := filepath.Join(mcpDir, "rules")
-	if err := os.MkdirAll(rulesDir, 0755); err != nil {
+	if err := opts.mkdirAll(rulesDir); err != nil {
 		return fmt.Errorf("could not create rules directory: %w", err)
 	}
 
@@ -100,7 +91,7 @@ func CursorMCPExtension(opts *InstallOptions) error {
 	ruleContent := append([]byte(cursorRuleHeader), GeminiMarkdown...)
 
 	rulePath := filepath.Join(rulesDir, "gke-mcp.mdc")
-	if err := os.WriteFile(rulePath, ruleContent, 0644); err != nil {
+	if err := opts.writeFile(rulePath, ruleContent); err != nil {
 		return fmt.Errorf("could not write gke-mcp rule file: %w", err)
 	}
 
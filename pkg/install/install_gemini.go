@@ -17,7 +17,7 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -31,7 +31,7 @@ func GeminiCLIExtension(opts *InstallOptions) error {
 		if strings.HasPrefix(opts.exePath, os.TempDir()) {
 			return fmt.Errorf("cannot install in developer mode using `go run`. Try again using `go build` and `./gke-mcp`")
 		}
-		log.Printf("version: %s", opts.version)
+		slog.Debug("Installing the Gemini CLI extension", "version", opts.version)
 		contextFilename = filepath.Join(filepath.Dir(opts.exePath), "pkg", "install", "GEMINI.md")
 		if _, err := os.ReadFile(contextFilename); err != nil {
 			return fmt.Errorf("could not read context file from %s: %w", contextFilename, err)
@@ -39,40 +39,138 @@ func GeminiCLIExtension(opts *InstallOptions) error {
 	}
 
 	extensionDir := filepath.Join(opts.installDir, ".gemini", "extensions", "gke-mcp")
-	if err := os.MkdirAll(extensionDir, 0755); err != nil {
+	if opts.projectOnly {
+		// Gemini CLI loads workspace extensions from the .gemini directory of
+		// the directory it is started in
+		workspaceDir := filepath.Join(opts.installDir, ".gemini")
+		if !dirExists(workspaceDir) {
+			fmt.Printf("Note: %s does not exist and will be created; start Gemini CLI from %s to load the extension.\n", workspaceDir, opts.installDir)
+		}
+	} else {
+		extensionDir = opts.configPaths().geminiExtensionDir()
+	}
+	if err := opts.mkdirAll(extensionDir); err != nil {
 		return fmt.Errorf("could not create extension directory: %w", err)
 	}
+	if other := otherGeminiExtensionDir(opts, extensionDir); other != "" {
+		fmt.Printf("Warning: gke-mcp is also installed as a Gemini CLI extension in %s, which makes Gemini CLI register its tools twice. Remove one of them with `gke-mcp uninstall gemini-cli`, with --project-only for the workspace one.\n", other)
+	}
+
+	// Gemini CLI connects to streamable HTTP servers with httpUrl.
+	server := opts.serverDefinition().mcpServerEntry("httpUrl")
+
+	manifestPath := filepath.Join(extensionDir, "gemini-extension.json")
+
+	// Read the existing manifest, to keep the keys users added to it
+	var manifest map[string]interface{}
+	if data, err := os.ReadFile(manifestPath); err == nil {
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			if !opts.force {
+				return fmt.Errorf("could not parse existing manifest.json, pass --force to overwrite it: %w", err)
+			}
+			manifest = nil
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("could not read existing manifest.json: %w", err)
+	}
+
+	change, upToDate := geminiExtensionChange(manifest, opts.version, contextFilename, server, opts.force)
+	if upToDate {
+		fmt.Printf("The gke-mcp Gemini CLI extension is already up to date (version %s).\n", opts.version)
+		return nil
+	}
+	if manifest == nil {
+		manifest = make(map[string]interface{})
+	}
 
 	// Create the manifest file as described in https://github.com/google-gemini/gemini-cli/blob/main/docs/extension.md.
-	manifest := map[string]interface{}{
-		"name":            "gke-mcp",
-		"version":         opts.version,
-		"description":     "Enable MCP-compatible AI agents to interact with Google Kubernetes Engine.",
-		"contextFileName": contextFilename,
-		"mcpServers": map[string]interface{}{
-			"gke": map[string]interface{}{
-				"command": opts.exePath,
-			},
-		},
+	manifest["name"] = "gke-mcp"
+	manifest["version"] = opts.version
+	manifest["description"] = "Enable MCP-compatible AI agents to interact with Google Kubernetes Engine."
+	manifest["contextFileName"] = contextFilename
+	mcpServers, ok := manifest["mcpServers"].(map[string]interface{})
+	if !ok {
+		mcpServers = make(map[string]interface{})
+		manifest["mcpServers"] = mcpServers
 	}
+	mcpServers["gke"] = server
 
-	manifestPath := filepath.Join(extensionDir, "gemini-extension.json")
 	data, err := json.MarshalIndent(manifest, "", "  ")
 	if err != nil {
 		return fmt.Errorf("could not marshal manifest.json: %w", err)
 	}
 
-	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
+	if err := opts.writeFile(manifestPath, data); err != nil {
 		return fmt.Errorf("could not write manifest.json: %w", err)
 	}
 
 	// In developer mode we don't need to create the GEMINI.md file.
 	if !opts.developerMode {
 		geminiMdPath := filepath.Join(extensionDir, "GEMINI.md")
-		if err := os.WriteFile(geminiMdPath, GeminiMarkdown, 0644); err != nil {
+		if err := opts.writeFile(geminiMdPath, GeminiMarkdown); err != nil {
 			return fmt.Errorf("could not write GEMINI.md: %w", err)
 		}
 	}
 
+	if change != "" && !opts.dryRun {
+		fmt.Println(change)
+	}
+
 	return nil
 }
+
+// geminiExtensionChange describes how installing changes the existing
+// manifest, or is empty for a new installation. It reports whether the
+// manifest is already up to date instead, unless force is set.
+func geminiExtensionChange(existing map[string]interface{}, version, contextFilename string, server map[string]interface{}, force bool) (string, bool) {
+	if existing == nil {
+		return "", false
+	}
+	oldVersion, _ := existing["version"].(string)
+	if oldVersion != version {
+		return fmt.Sprintf("Upgraded the gke-mcp Gemini CLI extension from %s to %s.", oldVersion, version), false
+	}
+	mcpServers, _ := existing["mcpServers"].(map[string]interface{})
+	if !jsonEqual(mcpServers["gke"], server) || existing["contextFileName"] != contextFilename {
+		return "Reinstalled the gke-mcp Gemini CLI extension (command path changed).", false
+	}
+	if force {
+		return "Reinstalled the gke-mcp Gemini CLI extension (forced).", false
+	}
+	return "", true
+}
+
+// jsonEqual reports whether a and b have the same JSON encoding
+func jsonEqual(a, b interface{}) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aData) == string(bData)
+}
+
+// otherGeminiExtensionDir returns the directory of the gke-mcp extension that
+// Gemini CLI would load along with the one in extensionDir: the global one for
+// a workspace install, or the one of the current directory for a global
+// install. It returns an empty string if there is none.
+func otherGeminiExtensionDir(opts *InstallOptions, extensionDir string) string {
+	var other string
+	if opts.projectOnly {
+		paths, err := homeConfigPaths()
+		if err != nil {
+			return ""
+		}
+		other = paths.geminiExtensionDir()
+	} else {
+		wd, err := os.Getwd()
+		if err != nil {
+			return ""
+		}
+		other = filepath.Join(wd, ".gemini", "extensions", "gke-mcp")
+	}
+	if filepath.Clean(other) == filepath.Clean(extensionDir) {
+		return ""
+	}
+	if _, err := os.Stat(filepath.Join(other, "gemini-extension.json")); err != nil {
+		return ""
+	}
+	return other
+}
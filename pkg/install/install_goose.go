@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// gooseTimeout is the timeout in seconds of the calls of Goose to the server
+const gooseTimeout = 300
+
+// GooseExtension installs the gke-mcp server as an extension of Goose
+func GooseExtension(opts *InstallOptions) error {
+	// Goose only has a global configuration
+	if opts.projectOnly {
+		return unsupportedf("Goose has no project configuration; install it without --project-only")
+	}
+
+	configPath, err := opts.configPaths().gooseConfigPath()
+	if err != nil {
+		return fmt.Errorf("could not determine Goose config path: %w", err)
+	}
+	if err := opts.mkdirAll(filepath.Dir(configPath)); err != nil {
+		return fmt.Errorf("could not create Goose config directory: %w", err)
+	}
+
+	// Read existing configuration if it exists, only changing the gke-mcp extension
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read Goose config: %w", err)
+	}
+
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("could not parse existing Goose config: %w", err)
+	}
+	if _, ok := config["extensions"].(map[string]interface{}); !ok && config["extensions"] != nil {
+		slog.Warn("extensions in Goose config is not a map, creating new one")
+	}
+
+	data, err = setYAMLMember(data, []string{"extensions", "gke-mcp"}, gooseExtensionEntry(opts))
+	if err != nil {
+		return fmt.Errorf("could not update Goose config: %w", err)
+	}
+	if err := opts.writeFile(configPath, data); err != nil {
+		return fmt.Errorf("could not write Goose config: %w", err)
+	}
+
+	return nil
+}
+
+// gooseExtensionEntry returns the gke-mcp extension in the Goose config
+func gooseExtensionEntry(opts *InstallOptions) map[string]interface{} {
+	entry := map[string]interface{}{
+		"name":        "gke-mcp",
+		"description": "Enable MCP-compatible AI agents to interact with Google Kubernetes Engine.",
+		"enabled":     true,
+		"timeout":     gooseTimeout,
+	}
+	server := opts.serverDefinition()
+	if server.transport == transportHTTP {
+		entry["type"] = "streamable_http"
+		entry["uri"] = server.url
+		return entry
+	}
+
+	// Goose expects every field of stdio extensions, even when empty
+	args := server.args
+	if args == nil {
+		args = []string{}
+	}
+	envs := server.env
+	if envs == nil {
+		envs = map[string]string{}
+	}
+	entry["type"] = transportStdio
+	entry["cmd"] = server.command
+	entry["args"] = args
+	entry["envs"] = envs
+	return entry
+}
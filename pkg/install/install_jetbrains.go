@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// jetbrainsIDEDirPattern matches the configuration directory of an IDE
+// version, e.g. GoLand2025.1, and not the ones of the other JetBrains tools
+var jetbrainsIDEDirPattern = regexp.MustCompile(`^[A-Za-z]+[0-9]{4}\.[0-9]+$`)
+
+// JetBrainsExtension installs the gke-mcp server into the AI Assistant of
+// every JetBrains IDE found
+func JetBrainsExtension(opts *InstallOptions) error {
+	// The AI Assistant is configured per IDE
+	if opts.projectOnly {
+		return unsupportedf("JetBrains AI Assistant has no project configuration; install it without --project-only")
+	}
+
+	dirs, err := jetbrainsIDEDirs(opts.configPaths())
+	if err != nil {
+		return err
+	}
+	if len(dirs) == 0 {
+		return fmt.Errorf("found no JetBrains IDE configuration; start the IDE once before installing")
+	}
+
+	entry := opts.serverDefinition().mcpServerEntry("url")
+	for _, dir := range dirs {
+		if err := jetbrainsMCPConfig(opts, filepath.Join(dir, "mcp.json"), entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jetbrainsMCPConfig sets the gke-mcp server of the MCP configuration at mcpPath to entry
+func jetbrainsMCPConfig(opts *InstallOptions, mcpPath string, entry map[string]interface{}) error {
+	// Read existing configuration if it exists, only changing the gke-mcp server to avoid data loss
+	data, err := os.ReadFile(mcpPath)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read existing MCP configuration %s: %w", mcpPath, err)
+	}
+
+	if exists {
+		var config map[string]interface{}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("could not parse existing MCP configuration %s: %w", mcpPath, err)
+		}
+
+		if _, ok := config["mcpServers"].(map[string]interface{}); !ok && config["mcpServers"] != nil {
+			slog.Warn("mcpServers is not a map, creating new one", "path", mcpPath)
+		}
+	}
+
+	data, err = mergeMCPServer(data, exists, "mcpServers", entry)
+	if err != nil {
+		return fmt.Errorf("could not update MCP configuration %s: %w", mcpPath, err)
+	}
+	if err := opts.writeFile(mcpPath, data); err != nil {
+		return fmt.Errorf("could not write MCP configuration %s: %w", mcpPath, err)
+	}
+	return nil
+}
+
+// jetbrainsIDEDirs returns the configuration directories of the JetBrains
+// IDEs, one per IDE and version
+func jetbrainsIDEDirs(paths configPaths) ([]string, error) {
+	jetbrainsDir, err := paths.jetbrainsDir()
+	if err != nil {
+		return nil, fmt.Errorf("could not determine JetBrains config path: %w", err)
+	}
+	entries, err := os.ReadDir(jetbrainsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read JetBrains config directory: %w", err)
+	}
+
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() && jetbrainsIDEDirPattern.MatchString(e.Name()) {
+			dirs = append(dirs, filepath.Join(jetbrainsDir, e.Name()))
+		}
+	}
+	return dirs, nil
+}
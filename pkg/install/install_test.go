@@ -17,13 +17,16 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"go.yaml.in/yaml/v3"
 )
 
 // testSetup creates a temporary directory and optionally mocks the HOME environment
@@ -38,11 +41,16 @@ func testSetup(t *testing.T, mockHome bool) (string, func()) {
 	}
 
 	if mockHome {
-		originalHome := os.Getenv("HOME")
+		// GEMINI_CLI_HOME and XDG_CONFIG_HOME would take the configuration out of the mocked home
+		originalHome, originalXDG, originalGeminiHome := os.Getenv("HOME"), os.Getenv("XDG_CONFIG_HOME"), os.Getenv("GEMINI_CLI_HOME")
 		os.Setenv("HOME", tmpDir)
+		os.Unsetenv("XDG_CONFIG_HOME")
+		os.Unsetenv("GEMINI_CLI_HOME")
 		cleanup = func() {
 			os.RemoveAll(tmpDir)
 			os.Setenv("HOME", originalHome)
+			os.Setenv("XDG_CONFIG_HOME", originalXDG)
+			os.Setenv("GEMINI_CLI_HOME", originalGeminiHome)
 		}
 	}
 
@@ -65,10 +73,12 @@ func mockAppData(t *testing.T, tmpDir string) func() {
 
 // mockInput simulates user input for interactive prompts
 func mockInput(input string) func() {
-	// Create a pipe to simulate user input
+	// Create a pipe to simulate user input, answered as if from a terminal
 	r, w, _ := os.Pipe()
 	oldStdin := os.Stdin
 	os.Stdin = r
+	oldStdinIsTerminal := stdinIsTerminal
+	stdinIsTerminal = func() bool { return true }
 
 	// Write the input to the pipe
 	go func() {
@@ -79,6 +89,7 @@ func mockInput(input string) func() {
 	// Return cleanup function
 	return func() {
 		os.Stdin = oldStdin
+		stdinIsTerminal = oldStdinIsTerminal
 		r.Close()
 	}
 }
@@ -331,6 +342,72 @@ func TestGeminiCLIExtension(t *testing.T) {
 	}
 }
 
+func TestGeminiCLIExtensionGeminiCLIHome(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, true)
+	defer cleanup()
+
+	geminiHome := t.TempDir()
+	t.Setenv("GEMINI_CLI_HOME", geminiHome)
+
+	opts := &InstallOptions{
+		version:    "0.1.0-test",
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+	}
+	if err := GeminiCLIExtension(opts); err != nil {
+		t.Fatalf("GeminiCLIExtension() failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(geminiHome, ".gemini", "extensions", "gke-mcp", "gemini-extension.json")); err != nil {
+		t.Errorf("Expected the extension to be installed in GEMINI_CLI_HOME: %v", err)
+	}
+	verifyRemoved(t, filepath.Join(tmpDir, ".gemini"))
+}
+
+func TestGeminiCLIExtensionWorkspaceConflict(t *testing.T) {
+	homeDir, cleanup := testSetup(t, true)
+	defer cleanup()
+	workspaceDir := t.TempDir()
+
+	globalOpts := &InstallOptions{
+		version:    "0.1.0-test",
+		installDir: homeDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+	}
+	out := captureStdout(t, func() {
+		if err := GeminiCLIExtension(globalOpts); err != nil {
+			t.Fatalf("GeminiCLIExtension() failed: %v", err)
+		}
+	})
+	if strings.Contains(out, "Warning") {
+		t.Errorf("Expected no warning without a workspace extension, got:\n%s", out)
+	}
+
+	// A workspace install creates the .gemini directory, and warns of the global extension
+	workspaceOpts := &InstallOptions{
+		version:     "0.1.0-test",
+		installDir:  workspaceDir,
+		exePath:     "/usr/local/bin/gke-mcp",
+		projectOnly: true,
+	}
+	out = captureStdout(t, func() {
+		if err := GeminiCLIExtension(workspaceOpts); err != nil {
+			t.Fatalf("GeminiCLIExtension() failed: %v", err)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(workspaceDir, ".gemini", "extensions", "gke-mcp", "gemini-extension.json")); err != nil {
+		t.Errorf("Expected the extension to be installed in the workspace: %v", err)
+	}
+	for _, want := range []string{
+		"Note: " + filepath.Join(workspaceDir, ".gemini") + " does not exist",
+		"Warning: gke-mcp is also installed as a Gemini CLI extension in " + filepath.Join(homeDir, ".gemini", "extensions", "gke-mcp"),
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected the output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
 func TestGeminiCLIExtensionDeveloperMode(t *testing.T) {
 	tmpDir, err := os.MkdirTemp(".", ".gemini-cli-test")
 	if err != nil {
@@ -546,6 +623,276 @@ func TestCursorMCPExtensionWithMalformedConfig(t *testing.T) {
 	}
 }
 
+func TestCursorMCPExtensionBacksUpConfig(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, true)
+	defer cleanup()
+
+	origNow := now
+	now = func() time.Time { return time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = origNow }()
+
+	cursorDir := filepath.Join(tmpDir, ".cursor")
+	mcpPath := createExistingConfig(t, cursorDir, map[string]interface{}{"mcpServers": existingServers})
+	original, err := os.ReadFile(mcpPath)
+	if err != nil {
+		t.Fatalf("Failed to read MCP config file: %v", err)
+	}
+
+	testExePath := "/usr/local/bin/gke-mcp"
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    testExePath,
+	}
+	if err := CursorMCPExtension(opts); err != nil {
+		t.Fatalf("CursorMCPExtension() failed: %v", err)
+	}
+
+	backupPath := mcpPath + ".bak-20250101"
+	if diff := cmp.Diff([]string{backupPath}, opts.Backups()); diff != "" {
+		t.Errorf("Backups() mismatch (-want +got):\n%s", diff)
+	}
+	backup, err := os.ReadFile(backupPath)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+	if !bytes.Equal(original, backup) {
+		t.Errorf("Expected backup to hold the original config, got:\n%s", backup)
+	}
+	verifyMCPConfig(t, mcpPath, testExePath)
+
+	// Later installations keep the first backup, of the file as the user left it
+	now = func() time.Time { return time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC) }
+	opts = &InstallOptions{
+		installDir: tmpDir,
+		exePath:    "/opt/gke-mcp",
+	}
+	if err := CursorMCPExtension(opts); err != nil {
+		t.Fatalf("CursorMCPExtension() failed: %v", err)
+	}
+	if len(opts.Backups()) != 0 {
+		t.Errorf("Expected no new backups, got %v", opts.Backups())
+	}
+	verifyMCPConfig(t, mcpPath, "/opt/gke-mcp")
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(cursorDir)
+	if err != nil {
+		t.Fatalf("Failed to read cursor dir: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if diff := cmp.Diff([]string{"mcp.json", "mcp.json.bak-20250101", "rules"}, names); diff != "" {
+		t.Errorf("Cursor dir mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestClaudeDesktopExtensionBacksUpConfig(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, true)
+	defer cleanup()
+
+	cleanupEnv := mockAppData(t, tmpDir)
+	defer cleanupEnv()
+
+	configPath, err := getClaudeDesktopConfigPath()
+	if err != nil {
+		t.Fatalf("could not determine Claude Desktop config path: %v", err)
+	}
+	createExistingClaudeConfig(t, configPath, map[string]interface{}{"mcpServers": existingServers})
+
+	testExePath := "/usr/local/bin/gke-mcp"
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    testExePath,
+	}
+	if err := ClaudeDesktopExtension(opts); err != nil {
+		t.Fatalf("ClaudeDesktopExtension() failed: %v", err)
+	}
+
+	if len(opts.Backups()) != 1 {
+		t.Fatalf("Expected one backup, got %v", opts.Backups())
+	}
+	backup := readConfig(t, opts.Backups()[0])
+	if diff := cmp.Diff(map[string]interface{}{"mcpServers": existingServers}, backup); diff != "" {
+		t.Errorf("Backup mismatch (-want +got):\n%s", diff)
+	}
+	verifyClaudeDesktopConfig(t, configPath, testExePath)
+}
+
+// verifyVSCodeInstallation checks that the VS Code installation created the MCP configuration and
+// the instructions file, and returns the parsed configuration
+func verifyVSCodeInstallation(t *testing.T, opts *InstallOptions) map[string]interface{} {
+	mcpPath, instructionsPath, err := getVSCodePaths(opts)
+	if err != nil {
+		t.Fatalf("could not determine VS Code config path: %v", err)
+	}
+
+	mcpData, err := os.ReadFile(mcpPath)
+	if err != nil {
+		t.Fatalf("Failed to read MCP config file: %v", err)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(mcpData, &config); err != nil {
+		t.Fatalf("Failed to unmarshal MCP config: %v", err)
+	}
+
+	servers, ok := config["servers"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected servers to be a map, got %T", config["servers"])
+	}
+
+	gkeMcp, ok := servers["gke-mcp"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected gke-mcp to be a map, got %T", servers["gke-mcp"])
+	}
+
+	if gkeMcp["command"] != opts.exePath {
+		t.Errorf("Expected command to be %s, got %v", opts.exePath, gkeMcp["command"])
+	}
+
+	if gkeMcp["type"] != "stdio" {
+		t.Errorf("Expected type to be 'stdio', got %v", gkeMcp["type"])
+	}
+
+	instructionsData, err := os.ReadFile(instructionsPath)
+	if err != nil {
+		t.Fatalf("Failed to read instructions file: %v", err)
+	}
+
+	if !bytes.Equal(instructionsData, append([]byte(vscodeInstructionsHeader), GeminiMarkdown...)) {
+		t.Errorf("Expected instructions file to contain the header and GeminiMarkdown content")
+	}
+
+	return config
+}
+
+func TestVSCodeMCPExtensionGlobal(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, true)
+	defer cleanup()
+
+	cleanupEnv := mockAppData(t, tmpDir)
+	defer cleanupEnv()
+
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+	}
+	if err := VSCodeMCPExtension(opts); err != nil {
+		t.Fatalf("VSCodeMCPExtension() failed: %v", err)
+	}
+
+	verifyVSCodeInstallation(t, opts)
+
+	if _, err := os.Stat(filepath.Join(tmpDir, ".vscode")); !os.IsNotExist(err) {
+		t.Errorf("Expected no .vscode directory for a global installation")
+	}
+}
+
+func TestVSCodeMCPExtensionProjectOnly(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, false)
+	defer cleanup()
+
+	opts := &InstallOptions{
+		installDir:  tmpDir,
+		exePath:     "/usr/local/bin/gke-mcp",
+		projectOnly: true,
+	}
+	if err := VSCodeMCPExtension(opts); err != nil {
+		t.Fatalf("VSCodeMCPExtension() failed: %v", err)
+	}
+
+	verifyVSCodeInstallation(t, opts)
+
+	for _, path := range []string{
+		filepath.Join(tmpDir, ".vscode", "mcp.json"),
+		filepath.Join(tmpDir, ".github", "instructions", "gke-mcp.instructions.md"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to be created: %v", path, err)
+		}
+	}
+}
+
+func TestVSCodeMCPExtensionWithExistingConfig(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, false)
+	defer cleanup()
+
+	existingConfig := map[string]interface{}{
+		"servers": map[string]interface{}{
+			"existing-server": map[string]interface{}{
+				"command": "/usr/bin/existing",
+				"type":    "stdio",
+			},
+		},
+		"inputs": []interface{}{},
+	}
+	createExistingConfig(t, filepath.Join(tmpDir, ".vscode"), existingConfig)
+
+	opts := &InstallOptions{
+		installDir:  tmpDir,
+		exePath:     "/usr/local/bin/gke-mcp",
+		projectOnly: true,
+	}
+	if err := VSCodeMCPExtension(opts); err != nil {
+		t.Fatalf("VSCodeMCPExtension() failed: %v", err)
+	}
+
+	config := verifyVSCodeInstallation(t, opts)
+
+	// Check that the existing server and other settings are preserved
+	existingServer, ok := config["servers"].(map[string]interface{})["existing-server"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected existing-server to be preserved, got %v", config["servers"])
+	}
+
+	if existingServer["command"] != "/usr/bin/existing" {
+		t.Errorf("Expected existing server command to be preserved, got %v", existingServer["command"])
+	}
+
+	if _, ok := config["inputs"].([]interface{}); !ok {
+		t.Errorf("Expected inputs to be preserved, got %v", config["inputs"])
+	}
+}
+
+func TestVSCodeMCPExtensionWithMalformedConfig(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, false)
+	defer cleanup()
+
+	// Create malformed MCP configuration (servers as string instead of map)
+	malformedConfig := map[string]interface{}{
+		"servers":      "this should be a map, not a string",
+		"otherSetting": "value",
+	}
+	createExistingConfig(t, filepath.Join(tmpDir, ".vscode"), malformedConfig)
+
+	opts := &InstallOptions{
+		installDir:  tmpDir,
+		exePath:     "/usr/local/bin/gke-mcp",
+		projectOnly: true,
+	}
+	if err := VSCodeMCPExtension(opts); err != nil {
+		t.Fatalf("VSCodeMCPExtension() failed: %v", err)
+	}
+
+	config := verifyVSCodeInstallation(t, opts)
+
+	if config["otherSetting"] != "value" {
+		t.Errorf("Expected otherSetting to be preserved, got %v", config["otherSetting"])
+	}
+
+	// A file that is not JSON at all is reported rather than overwritten
+	mcpPath := filepath.Join(tmpDir, ".vscode", "mcp.json")
+	if err := os.WriteFile(mcpPath, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if err := VSCodeMCPExtension(opts); err == nil || !strings.Contains(err.Error(), "could not parse existing MCP configuration") {
+		t.Errorf("VSCodeMCPExtension() error = %v, want a parse error", err)
+	}
+}
+
 // verifyGkeMcpInClaudeConfig checks for the presence and correctness of the gke-mcp server entry.
 func verifyGkeMcpInClaudeConfig(t *testing.T, config map[string]interface{}, expectedExePath string) {
 	// Verify mcpServers exists and is a map
@@ -822,6 +1169,9 @@ func TestClaudeCodeExtensionUserDeclines(t *testing.T) {
 
 	testExePath := "/usr/local/bin/gke-mcp"
 
+	_, cleanupCommand := MockClaudeCommand(t)
+	defer cleanupCommand()
+
 	// Mock user input to answer "no" to the confirmation prompt
 	cleanupInput := mockInput("no\n")
 	defer cleanupInput()
@@ -848,3 +1198,1042 @@ func TestClaudeCodeExtensionUserDeclines(t *testing.T) {
 		t.Errorf("Expected GKE_MCP_USAGE_GUIDE.md to NOT be created when user declines, but it was")
 	}
 }
+
+func TestClaudeCodeExtensionNonInteractive(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, false)
+	defer cleanup()
+
+	testExePath := "/usr/local/bin/gke-mcp"
+
+	logFile, cleanupCommand := MockClaudeCommand(t)
+	defer cleanupCommand()
+
+	// Simulate a script, whose stdin is not a terminal
+	oldStdinIsTerminal := stdinIsTerminal
+	stdinIsTerminal = func() bool { return false }
+	defer func() { stdinIsTerminal = oldStdinIsTerminal }()
+
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    testExePath,
+	}
+
+	// Without --yes, fail rather than wait for an answer
+	err := ClaudeCodeExtension(opts)
+	if err == nil || !strings.Contains(err.Error(), "pass --yes") {
+		t.Fatalf("ClaudeCodeExtension() error = %v, want guidance to pass --yes", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "CLAUDE.md")); err == nil {
+		t.Errorf("Expected CLAUDE.md to NOT be created without confirmation, but it was")
+	}
+
+	// With --yes, install without asking
+	opts.assumeYes = true
+	if err := ClaudeCodeExtension(opts); err != nil {
+		t.Fatalf("ClaudeCodeExtension() with --yes failed: %v", err)
+	}
+
+	verifyClaudeCodeInstallation(t, tmpDir, testExePath)
+
+	verifyArgs(t, logFile, testExePath)
+}
+
+// captureStdout returns what f prints to stdout
+func captureStdout(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	oldStdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = oldStdout }()
+
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+	f()
+	w.Close()
+	return <-out
+}
+
+func TestClaudeCodeExtensionWithoutClaude(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, false)
+	defer cleanup()
+
+	// Nothing is found in an empty PATH
+	originalPath := os.Getenv("PATH")
+	os.Setenv("PATH", t.TempDir())
+	defer os.Setenv("PATH", originalPath)
+
+	testExePath := "/usr/local/bin/gke-mcp"
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    testExePath,
+		assumeYes:  true,
+	}
+	err := ClaudeCodeExtension(opts)
+	if err == nil || !strings.Contains(err.Error(), "--manual") {
+		t.Fatalf("ClaudeCodeExtension() error = %v, want guidance to install claude or pass --manual", err)
+	}
+	verifyRemoved(t, filepath.Join(tmpDir, "CLAUDE.md"), filepath.Join(tmpDir, "GKE_MCP_USAGE_GUIDE.md"))
+
+	// With --manual, install the instructions and print the server to add
+	opts.manual = true
+	out := captureStdout(t, func() {
+		if err := ClaudeCodeExtension(opts); err != nil {
+			t.Fatalf("ClaudeCodeExtension() with --manual failed: %v", err)
+		}
+	})
+	verifyClaudeCodeInstallation(t, tmpDir, testExePath)
+
+	_, snippet, ok := strings.Cut(out, "~/.claude.json to use it in every project:\n")
+	if !ok {
+		t.Fatalf("Expected the configuration to add to ~/.claude.json, got:\n%s", out)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(snippet), &config); err != nil {
+		t.Fatalf("Failed to unmarshal printed configuration: %v\n%s", err, snippet)
+	}
+	want := map[string]interface{}{
+		"mcpServers": map[string]interface{}{
+			"gke-mcp": map[string]interface{}{"command": testExePath, "type": "stdio"},
+		},
+	}
+	if diff := cmp.Diff(want, config); diff != "" {
+		t.Errorf("Printed configuration mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestClaudeCodeExtensionRollsBackOnFailure(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, false)
+	defer cleanup()
+
+	// A claude command that always fails
+	binDir := t.TempDir()
+	claudePath, script := filepath.Join(binDir, "claude"), "#!/bin/sh\nexit 1\n"
+	if runtime.GOOS == "windows" {
+		claudePath, script = claudePath+".bat", "@exit /b 1\r\n"
+	}
+	if err := os.WriteFile(claudePath, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create failing claude command: %v", err)
+	}
+	originalPath := os.Getenv("PATH")
+	os.Setenv("PATH", binDir)
+	defer os.Setenv("PATH", originalPath)
+
+	claudeMDPath := filepath.Join(tmpDir, "CLAUDE.md")
+	original := "# Existing Content\n"
+	if err := os.WriteFile(claudeMDPath, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to create existing CLAUDE.md: %v", err)
+	}
+
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+		assumeYes:  true,
+	}
+	err := ClaudeCodeExtension(opts)
+	if err == nil || !strings.Contains(err.Error(), "CLAUDE.md was reverted") {
+		t.Fatalf("ClaudeCodeExtension() error = %v, want a reverted CLAUDE.md", err)
+	}
+
+	content, err := os.ReadFile(claudeMDPath)
+	if err != nil {
+		t.Fatalf("Failed to read CLAUDE.md: %v", err)
+	}
+	if string(content) != original {
+		t.Errorf("Expected CLAUDE.md to be reverted to %q, got %q", original, content)
+	}
+	verifyRemoved(t, filepath.Join(tmpDir, "GKE_MCP_USAGE_GUIDE.md"))
+}
+
+func TestInstallHTTPTransport(t *testing.T) {
+	testCases := []struct {
+		name       string
+		install    func(*InstallOptions) error
+		configPath []string
+		serversKey string
+		serverName string
+		want       map[string]interface{}
+	}{
+		{
+			name:       "gemini-cli",
+			install:    GeminiCLIExtension,
+			configPath: []string{".gemini", "extensions", "gke-mcp", "gemini-extension.json"},
+			serversKey: "mcpServers",
+			serverName: "gke",
+			want:       map[string]interface{}{"httpUrl": "http://localhost:9090"},
+		},
+		{
+			name:       "cursor",
+			install:    CursorMCPExtension,
+			configPath: []string{".cursor", "mcp.json"},
+			serversKey: "mcpServers",
+			serverName: "gke-mcp",
+			want:       map[string]interface{}{"url": "http://localhost:9090"},
+		},
+		{
+			name:       "vscode",
+			install:    VSCodeMCPExtension,
+			configPath: []string{".vscode", "mcp.json"},
+			serversKey: "servers",
+			serverName: "gke-mcp",
+			want:       map[string]interface{}{"type": "http", "url": "http://localhost:9090"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir, cleanup := testSetup(t, false)
+			defer cleanup()
+
+			opts := &InstallOptions{
+				version:     "0.1.0-test",
+				installDir:  tmpDir,
+				exePath:     "/usr/local/bin/gke-mcp",
+				projectOnly: true,
+				transport:   transportHTTP,
+				serverPort:  9090,
+			}
+			if err := tc.install(opts); err != nil {
+				t.Fatalf("install failed: %v", err)
+			}
+
+			configData, err := os.ReadFile(filepath.Join(append([]string{tmpDir}, tc.configPath...)...))
+			if err != nil {
+				t.Fatalf("Failed to read config file: %v", err)
+			}
+
+			var config map[string]interface{}
+			if err := json.Unmarshal(configData, &config); err != nil {
+				t.Fatalf("Failed to unmarshal config: %v", err)
+			}
+
+			servers, ok := config[tc.serversKey].(map[string]interface{})
+			if !ok {
+				t.Fatalf("Expected %s to be a map, got %T", tc.serversKey, config[tc.serversKey])
+			}
+
+			if diff := cmp.Diff(tc.want, servers[tc.serverName]); diff != "" {
+				t.Errorf("Server entry mismatch. Diff:\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestClaudeCodeExtensionScopeAndTransport(t *testing.T) {
+	testCases := []struct {
+		name        string
+		projectOnly bool
+		transport   string
+		wantArgs    string
+	}{
+		{name: "project-only", projectOnly: true, transport: transportStdio, wantArgs: "mcp add --scope project gke-mcp /usr/local/bin/gke-mcp"},
+		{name: "http", transport: transportHTTP, wantArgs: "mcp add --transport http gke-mcp http://localhost:9090"},
+		{name: "project-only http", projectOnly: true, transport: transportHTTP, wantArgs: "mcp add --scope project --transport http gke-mcp http://localhost:9090"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir, cleanup := testSetup(t, false)
+			defer cleanup()
+
+			logFile, cleanupCommand := MockClaudeCommand(t)
+			defer cleanupCommand()
+
+			opts := &InstallOptions{
+				installDir:  tmpDir,
+				exePath:     "/usr/local/bin/gke-mcp",
+				projectOnly: tc.projectOnly,
+				assumeYes:   true,
+				transport:   tc.transport,
+				serverPort:  9090,
+			}
+			if err := ClaudeCodeExtension(opts); err != nil {
+				t.Fatalf("ClaudeCodeExtension() failed: %v", err)
+			}
+
+			logContent, err := os.ReadFile(logFile)
+			if err != nil {
+				t.Fatalf("Failed to read command log: %v", err)
+			}
+
+			if !strings.Contains(string(logContent), tc.wantArgs) {
+				t.Errorf("Expected claude command to be called with args '%s', but log contains: %s", tc.wantArgs, logContent)
+			}
+		})
+	}
+}
+
+func TestClaudeDesktopExtensionUnsupportedOptions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		opts    *InstallOptions
+		wantErr string
+	}{
+		{name: "project-only", opts: &InstallOptions{projectOnly: true}, wantErr: "no project configuration"},
+		{name: "http", opts: &InstallOptions{transport: transportHTTP, serverPort: 9090}, wantErr: "http://localhost:9090 as a custom connector"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir, cleanup := testSetup(t, true)
+			defer cleanup()
+
+			cleanupEnv := mockAppData(t, tmpDir)
+			defer cleanupEnv()
+
+			tc.opts.installDir = tmpDir
+			tc.opts.exePath = "/usr/local/bin/gke-mcp"
+			err := ClaudeDesktopExtension(tc.opts)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("ClaudeDesktopExtension() error = %v, want it to contain %q", err, tc.wantErr)
+			}
+
+			configPath, err := getClaudeDesktopConfigPath()
+			if err != nil {
+				t.Fatalf("could not determine Claude Desktop config path: %v", err)
+			}
+
+			if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+				t.Errorf("Expected no Claude Desktop config to be written, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewInstallOptionsTransport(t *testing.T) {
+	testCases := []struct {
+		name       string
+		transport  string
+		serverPort int
+		url        string
+		wantErr    string
+	}{
+		{name: "stdio", transport: "stdio"},
+		{name: "http", transport: "http", serverPort: 8080},
+		{name: "http without port", transport: "http", wantErr: "invalid server port 0"},
+		{name: "http with url", transport: "http", url: "https://gke-mcp.example.com/mcp"},
+		{name: "http with url without scheme", transport: "http", url: "localhost:8080/mcp", wantErr: "must start with http:// or https://"},
+		{name: "http with url without host", transport: "http", url: "http:///mcp", wantErr: "has no host"},
+		{name: "stdio with url", transport: "stdio", url: "http://localhost:8080/mcp", wantErr: "only applies to the http transport"},
+		{name: "unknown", transport: "sse", serverPort: 8080, wantErr: `unknown transport "sse"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewInstallOptions("0.1.0-test", false, false, false, false, tc.transport, tc.serverPort, "", nil, false, nil, "", "", false, tc.url)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("NewInstallOptions() returned unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("NewInstallOptions() error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewInstallOptionsExePath(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, false)
+	defer cleanup()
+
+	executable := filepath.Join(tmpDir, "gke-mcp-wrapper")
+	if err := os.WriteFile(executable, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to write executable: %v", err)
+	}
+	notExecutable := filepath.Join(tmpDir, "notes.txt")
+	if err := os.WriteFile(notExecutable, []byte("notes"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		exePath    string
+		serverArgs []string
+		transport  string
+		force      bool
+		wantErr    string
+		// Windows has no executable permission bits to check
+		skipOnWindows bool
+	}{
+		{name: "executable", exePath: executable, serverArgs: []string{"--verbose"}},
+		{name: "not executable", exePath: notExecutable, wantErr: "is not executable; pass --force", skipOnWindows: true},
+		{name: "missing", exePath: filepath.Join(tmpDir, "missing"), wantErr: "could not find executable"},
+		{name: "directory", exePath: tmpDir, wantErr: "is a directory"},
+		{name: "missing with force", exePath: filepath.Join(tmpDir, "missing"), force: true},
+		{name: "server args with http", serverArgs: []string{"--verbose"}, transport: transportHTTP, wantErr: "server args only apply to the stdio transport"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skipOnWindows && runtime.GOOS == "windows" {
+				t.Skip("no executable permission bits on Windows")
+			}
+			transport := tc.transport
+			if transport == "" {
+				transport = transportStdio
+			}
+			opts, err := NewInstallOptions("0.1.0-test", false, false, false, false, transport, 8080, tc.exePath, tc.serverArgs, tc.force, nil, "", "", false, "")
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("NewInstallOptions() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewInstallOptions() returned unexpected error: %v", err)
+			}
+			if opts.exePath != tc.exePath {
+				t.Errorf("NewInstallOptions() exePath = %q, want %q", opts.exePath, tc.exePath)
+			}
+			if diff := cmp.Diff(tc.serverArgs, opts.serverArgs); diff != "" {
+				t.Errorf("NewInstallOptions() serverArgs mismatch. Diff:\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestInstallServerArgs(t *testing.T) {
+	testExePath := "/opt/bin/gke-mcp-wrapper"
+	serverArgs := []interface{}{"--verbose", "--release-notes-html-fallback=false"}
+	testCases := []struct {
+		name       string
+		install    func(*InstallOptions) error
+		configPath []string
+		serversKey string
+		serverName string
+		want       map[string]interface{}
+	}{
+		{
+			name:       "gemini-cli",
+			install:    GeminiCLIExtension,
+			configPath: []string{".gemini", "extensions", "gke-mcp", "gemini-extension.json"},
+			serversKey: "mcpServers",
+			serverName: "gke",
+			want:       map[string]interface{}{"command": testExePath, "args": serverArgs},
+		},
+		{
+			name:       "cursor",
+			install:    CursorMCPExtension,
+			configPath: []string{".cursor", "mcp.json"},
+			serversKey: "mcpServers",
+			serverName: "gke-mcp",
+			want:       map[string]interface{}{"command": testExePath, "args": serverArgs, "type": "stdio"},
+		},
+		{
+			name:       "vscode",
+			install:    VSCodeMCPExtension,
+			configPath: []string{".vscode", "mcp.json"},
+			serversKey: "servers",
+			serverName: "gke-mcp",
+			want:       map[string]interface{}{"command": testExePath, "args": serverArgs, "type": "stdio"},
+		},
+		{
+			name:       "claude-desktop",
+			install:    ClaudeDesktopExtension,
+			serversKey: "mcpServers",
+			serverName: "gke-mcp",
+			want:       map[string]interface{}{"command": testExePath, "args": serverArgs},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir, cleanup := testSetup(t, true)
+			defer cleanup()
+
+			cleanupEnv := mockAppData(t, tmpDir)
+			defer cleanupEnv()
+
+			opts := &InstallOptions{
+				version:     "0.1.0-test",
+				installDir:  tmpDir,
+				exePath:     testExePath,
+				projectOnly: tc.configPath != nil,
+				transport:   transportStdio,
+				serverArgs:  []string{"--verbose", "--release-notes-html-fallback=false"},
+			}
+			if err := tc.install(opts); err != nil {
+				t.Fatalf("install failed: %v", err)
+			}
+
+			configPath := filepath.Join(append([]string{tmpDir}, tc.configPath...)...)
+			if tc.configPath == nil {
+				var err error
+				if configPath, err = getClaudeDesktopConfigPath(); err != nil {
+					t.Fatalf("could not determine Claude Desktop config path: %v", err)
+				}
+			}
+			configData, err := os.ReadFile(configPath)
+			if err != nil {
+				t.Fatalf("Failed to read config file: %v", err)
+			}
+
+			var config map[string]interface{}
+			if err := json.Unmarshal(configData, &config); err != nil {
+				t.Fatalf("Failed to unmarshal config: %v", err)
+			}
+
+			servers, ok := config[tc.serversKey].(map[string]interface{})
+			if !ok {
+				t.Fatalf("Expected %s to be a map, got %T", tc.serversKey, config[tc.serversKey])
+			}
+
+			if diff := cmp.Diff(tc.want, servers[tc.serverName]); diff != "" {
+				t.Errorf("Server entry mismatch. Diff:\n%v", diff)
+			}
+		})
+	}
+
+	t.Run("claude-code", func(t *testing.T) {
+		tmpDir, cleanup := testSetup(t, false)
+		defer cleanup()
+
+		logFile, cleanupCommand := MockClaudeCommand(t)
+		defer cleanupCommand()
+
+		opts := &InstallOptions{
+			installDir: tmpDir,
+			exePath:    testExePath,
+			assumeYes:  true,
+			transport:  transportStdio,
+			serverArgs: []string{"--verbose"},
+		}
+		if err := ClaudeCodeExtension(opts); err != nil {
+			t.Fatalf("ClaudeCodeExtension() failed: %v", err)
+		}
+
+		logContent, err := os.ReadFile(logFile)
+		if err != nil {
+			t.Fatalf("Failed to read command log: %v", err)
+		}
+
+		wantArgs := "mcp add gke-mcp -- " + testExePath + " --verbose"
+		if !strings.Contains(string(logContent), wantArgs) {
+			t.Errorf("Expected claude command to be called with args '%s', but log contains: %s", wantArgs, logContent)
+		}
+	})
+}
+
+func TestGeminiExtensionChange(t *testing.T) {
+	server := map[string]interface{}{"command": "/usr/local/bin/gke-mcp", "args": []string{"--verbose"}}
+	existing := map[string]interface{}{
+		"version":         "0.1.0",
+		"contextFileName": "GEMINI.md",
+		"mcpServers": map[string]interface{}{
+			"gke": map[string]interface{}{"command": "/usr/local/bin/gke-mcp", "args": []interface{}{"--verbose"}},
+		},
+	}
+	testCases := []struct {
+		name         string
+		existing     map[string]interface{}
+		version      string
+		server       map[string]interface{}
+		force        bool
+		wantChange   string
+		wantUpToDate bool
+	}{
+		{name: "new", version: "0.1.0", server: server},
+		{name: "up to date", existing: existing, version: "0.1.0", server: server, wantUpToDate: true},
+		{name: "upgraded", existing: existing, version: "0.2.0", server: server, wantChange: "Upgraded the gke-mcp Gemini CLI extension from 0.1.0 to 0.2.0."},
+		{name: "command changed", existing: existing, version: "0.1.0", server: map[string]interface{}{"command": "/opt/gke-mcp"}, wantChange: "Reinstalled the gke-mcp Gemini CLI extension (command path changed)."},
+		{name: "forced", existing: existing, version: "0.1.0", server: server, force: true, wantChange: "Reinstalled the gke-mcp Gemini CLI extension (forced)."},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			change, upToDate := geminiExtensionChange(tc.existing, tc.version, "GEMINI.md", tc.server, tc.force)
+			if change != tc.wantChange || upToDate != tc.wantUpToDate {
+				t.Errorf("geminiExtensionChange() = %q, %v, want %q, %v", change, upToDate, tc.wantChange, tc.wantUpToDate)
+			}
+		})
+	}
+}
+
+func TestGeminiCLIExtensionUpgrade(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, false)
+	defer cleanup()
+
+	opts := &InstallOptions{
+		version:    "0.1.0",
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+	}
+	if err := GeminiCLIExtension(opts); err != nil {
+		t.Fatalf("GeminiCLIExtension() failed: %v", err)
+	}
+
+	// Add keys the installer doesn't know about to the manifest
+	manifestPath := filepath.Join(tmpDir, ".gemini", "extensions", "gke-mcp", "gemini-extension.json")
+	manifest := readConfig(t, manifestPath)
+	manifest["excludeTools"] = []interface{}{"run_shell_command"}
+	manifest["mcpServers"].(map[string]interface{})["other"] = map[string]interface{}{"command": "/usr/bin/other"}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal manifest: %v", err)
+	}
+	if err := os.WriteFile(manifestPath, manifestData, 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	// Installing the same version changes nothing
+	if err := GeminiCLIExtension(opts); err != nil {
+		t.Fatalf("GeminiCLIExtension() failed: %v", err)
+	}
+	if diff := cmp.Diff(manifest, readConfig(t, manifestPath)); diff != "" {
+		t.Errorf("Expected an up to date manifest to be left alone. Diff:\n%v", diff)
+	}
+
+	// Upgrading keeps the added keys
+	opts.version = "0.2.0"
+	if err := GeminiCLIExtension(opts); err != nil {
+		t.Fatalf("GeminiCLIExtension() failed: %v", err)
+	}
+	manifest["version"] = "0.2.0"
+	if diff := cmp.Diff(manifest, readConfig(t, manifestPath)); diff != "" {
+		t.Errorf("Expected the upgrade to only change the version. Diff:\n%v", diff)
+	}
+
+	// A malformed manifest is only overwritten with --force
+	if err := os.WriteFile(manifestPath, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+	if err := GeminiCLIExtension(opts); err == nil || !strings.Contains(err.Error(), "pass --force") {
+		t.Errorf("GeminiCLIExtension() error = %v, want guidance to pass --force", err)
+	}
+	opts.force = true
+	if err := GeminiCLIExtension(opts); err != nil {
+		t.Fatalf("GeminiCLIExtension() with --force failed: %v", err)
+	}
+	if got := readConfig(t, manifestPath)["version"]; got != "0.2.0" {
+		t.Errorf("Expected the forced install to write version 0.2.0, got %v", got)
+	}
+}
+
+func TestServerEnv(t *testing.T) {
+	testCases := []struct {
+		name      string
+		envArgs   []string
+		projectID string
+		location  string
+		want      map[string]string
+		wantErr   string
+	}{
+		{name: "none"},
+		{
+			name:      "project and location",
+			envArgs:   []string{"HTTPS_PROXY=http://proxy:3128", "EMPTY="},
+			projectID: "my-project",
+			location:  "us-central1",
+			want: map[string]string{
+				"HTTPS_PROXY":           "http://proxy:3128",
+				"EMPTY":                 "",
+				"GKE_MCP_PROJECT_ID":    "my-project",
+				"CLOUDSDK_CORE_PROJECT": "my-project",
+				"GKE_MCP_LOCATION":      "us-central1",
+			},
+		},
+		{name: "no value", envArgs: []string{"HTTPS_PROXY"}, wantErr: `invalid environment variable "HTTPS_PROXY"`},
+		{name: "no key", envArgs: []string{"=value"}, wantErr: "must be KEY=VALUE"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := serverEnv(tc.envArgs, tc.projectID, tc.location)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("serverEnv() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("serverEnv() returned unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("serverEnv() mismatch. Diff:\n%v", diff)
+			}
+		})
+	}
+
+	if _, err := NewInstallOptions("0.1.0-test", false, false, false, false, transportHTTP, 8080, "", nil, false, nil, "my-project", "", false, ""); err == nil || !strings.Contains(err.Error(), "environment variables only apply to the stdio transport") {
+		t.Errorf("NewInstallOptions() with http and a project error = %v, want a transport error", err)
+	}
+}
+
+func TestInstallEnv(t *testing.T) {
+	env := map[string]string{"GKE_MCP_PROJECT_ID": "my-project", "CLOUDSDK_CORE_PROJECT": "my-project"}
+	wantEnv := map[string]interface{}{"GKE_MCP_PROJECT_ID": "my-project", "CLOUDSDK_CORE_PROJECT": "my-project"}
+
+	tmpDir, cleanup := testSetup(t, true)
+	defer cleanup()
+
+	cleanupEnv := mockAppData(t, tmpDir)
+	defer cleanupEnv()
+
+	logFile, cleanupCommand := MockClaudeCommand(t)
+	defer cleanupCommand()
+
+	opts := &InstallOptions{
+		version:    "0.1.0-test",
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+		assumeYes:  true,
+		transport:  transportStdio,
+		env:        env,
+	}
+	for _, install := range []func(*InstallOptions) error{GeminiCLIExtension, CursorMCPExtension, ClaudeDesktopExtension, ClaudeCodeExtension} {
+		if err := install(opts); err != nil {
+			t.Fatalf("install failed: %v", err)
+		}
+	}
+
+	claudeConfigPath, err := getClaudeDesktopConfigPath()
+	if err != nil {
+		t.Fatalf("could not determine Claude Desktop config path: %v", err)
+	}
+	for _, entry := range []struct {
+		path       string
+		serverName string
+	}{
+		{filepath.Join(tmpDir, ".gemini", "extensions", "gke-mcp", "gemini-extension.json"), "gke"},
+		{filepath.Join(tmpDir, ".cursor", "mcp.json"), "gke-mcp"},
+		{claudeConfigPath, "gke-mcp"},
+	} {
+		server := readConfig(t, entry.path)["mcpServers"].(map[string]interface{})[entry.serverName].(map[string]interface{})
+		if diff := cmp.Diff(wantEnv, server["env"]); diff != "" {
+			t.Errorf("env of the server in %s mismatch. Diff:\n%v", entry.path, diff)
+		}
+	}
+
+	logContent, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read command log: %v", err)
+	}
+
+	wantArgs := "mcp add --env CLOUDSDK_CORE_PROJECT=my-project --env GKE_MCP_PROJECT_ID=my-project gke-mcp /usr/local/bin/gke-mcp"
+	if !strings.Contains(string(logContent), wantArgs) {
+		t.Errorf("Expected claude command to be called with args '%s', but log contains: %s", wantArgs, logContent)
+	}
+}
+
+// snapshotDir returns the contents of the files and directories under dir
+func snapshotDir(t *testing.T, dir string) map[string]string {
+	snapshot := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			snapshot[path] = "dir"
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		snapshot[path] = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to snapshot %s: %v", dir, err)
+	}
+	return snapshot
+}
+
+func TestInstallDryRun(t *testing.T) {
+	testCases := []struct {
+		name        string
+		install     func(*InstallOptions) error
+		projectOnly bool
+	}{
+		{name: "gemini-cli", install: GeminiCLIExtension},
+		{name: "cursor", install: CursorMCPExtension},
+		{name: "vscode", install: VSCodeMCPExtension},
+		{name: "vscode project-only", install: VSCodeMCPExtension, projectOnly: true},
+		{name: "claude-desktop", install: ClaudeDesktopExtension},
+		{name: "claude-code", install: ClaudeCodeExtension},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir, cleanup := testSetup(t, true)
+			defer cleanup()
+
+			cleanupEnv := mockAppData(t, tmpDir)
+			defer cleanupEnv()
+
+			logFile, cleanupCommand := MockClaudeCommand(t)
+			defer cleanupCommand()
+
+			// An existing configuration must not be changed either
+			createExistingConfig(t, filepath.Join(tmpDir, ".cursor"), map[string]interface{}{
+				"mcpServers": map[string]interface{}{},
+			})
+			if err := os.WriteFile(filepath.Join(tmpDir, "CLAUDE.md"), []byte("# Existing Content\n"), 0644); err != nil {
+				t.Fatalf("Failed to create existing CLAUDE.md: %v", err)
+			}
+
+			before := snapshotDir(t, tmpDir)
+
+			opts := &InstallOptions{
+				version:     "0.1.0-test",
+				installDir:  tmpDir,
+				exePath:     "/usr/local/bin/gke-mcp",
+				projectOnly: tc.projectOnly,
+				dryRun:      true,
+			}
+			if err := tc.install(opts); err != nil {
+				t.Fatalf("install failed: %v", err)
+			}
+
+			if diff := cmp.Diff(before, snapshotDir(t, tmpDir)); diff != "" {
+				t.Errorf("Expected a dry run to change no files. Diff:\n%v", diff)
+			}
+
+			if _, err := os.Stat(logFile); !os.IsNotExist(err) {
+				t.Errorf("Expected a dry run to run no commands, but claude was run")
+			}
+		})
+	}
+}
+
+// readGooseExtension returns the gke-mcp extension of the Goose config at path
+func readGooseExtension(t *testing.T, path string) map[string]interface{} {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read Goose config: %v", err)
+	}
+	var config struct {
+		Extensions map[string]map[string]interface{} `yaml:"extensions"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		t.Fatalf("Failed to unmarshal Goose config: %v", err)
+	}
+	return config.Extensions["gke-mcp"]
+}
+
+func TestGooseExtension(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, true)
+	defer cleanup()
+
+	cleanupEnv := mockAppData(t, tmpDir)
+	defer cleanupEnv()
+
+	configPath, err := (&InstallOptions{installDir: tmpDir}).configPaths().gooseConfigPath()
+	if err != nil {
+		t.Fatalf("could not determine Goose config path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatalf("Failed to create Goose config dir: %v", err)
+	}
+	existing := "# Goose settings\nGOOSE_PROVIDER: openai\nextensions:\n  developer:\n    enabled: true\n    type: builtin\n"
+	if err := os.WriteFile(configPath, []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to write Goose config: %v", err)
+	}
+
+	testExePath := "/usr/local/bin/gke-mcp"
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    testExePath,
+		serverArgs: []string{"--verbose"},
+		env:        map[string]string{"GKE_MCP_PROJECT_ID": "my-project"},
+	}
+	if err := GooseExtension(opts); err != nil {
+		t.Fatalf("GooseExtension() failed: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"name":        "gke-mcp",
+		"description": "Enable MCP-compatible AI agents to interact with Google Kubernetes Engine.",
+		"enabled":     true,
+		"timeout":     300,
+		"type":        "stdio",
+		"cmd":         testExePath,
+		"args":        []interface{}{"--verbose"},
+		"envs":        map[string]interface{}{"GKE_MCP_PROJECT_ID": "my-project"},
+	}
+	if diff := cmp.Diff(want, readGooseExtension(t, configPath)); diff != "" {
+		t.Errorf("Goose extension mismatch (-want +got):\n%s", diff)
+	}
+
+	// The rest of the configuration is kept, and backed up before the change
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read Goose config: %v", err)
+	}
+	if !strings.HasPrefix(string(data), existing) {
+		t.Errorf("Expected the Goose config to start with the existing one, got:\n%s", data)
+	}
+	if len(opts.Backups()) != 1 {
+		t.Errorf("Expected one backup, got %v", opts.Backups())
+	}
+}
+
+func TestGooseExtensionHTTP(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, true)
+	defer cleanup()
+
+	cleanupEnv := mockAppData(t, tmpDir)
+	defer cleanupEnv()
+
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+		transport:  transportHTTP,
+		serverPort: 9090,
+	}
+	if err := GooseExtension(opts); err != nil {
+		t.Fatalf("GooseExtension() failed: %v", err)
+	}
+
+	configPath, err := opts.configPaths().gooseConfigPath()
+	if err != nil {
+		t.Fatalf("could not determine Goose config path: %v", err)
+	}
+	extension := readGooseExtension(t, configPath)
+	if extension["type"] != "streamable_http" || extension["uri"] != "http://localhost:9090" {
+		t.Errorf("Expected a streamable_http extension at http://localhost:9090, got %v", extension)
+	}
+
+	opts.projectOnly = true
+	if err := GooseExtension(opts); err == nil || !strings.Contains(err.Error(), "no project configuration") {
+		t.Errorf("GooseExtension() with --project-only error = %v, want no project configuration", err)
+	}
+}
+
+func TestJetBrainsExtension(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, true)
+	defer cleanup()
+
+	cleanupEnv := mockAppData(t, tmpDir)
+	defer cleanupEnv()
+
+	testExePath := "/usr/local/bin/gke-mcp"
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    testExePath,
+	}
+
+	// Without an IDE, there is nothing to install into
+	if err := JetBrainsExtension(opts); err == nil || !strings.Contains(err.Error(), "no JetBrains IDE configuration") {
+		t.Fatalf("JetBrainsExtension() error = %v, want no JetBrains IDE configuration", err)
+	}
+
+	jetbrainsDir, err := opts.configPaths().jetbrainsDir()
+	if err != nil {
+		t.Fatalf("could not determine JetBrains config path: %v", err)
+	}
+	for _, dir := range []string{"GoLand2025.1", "PyCharm2024.3", "consentOptions"} {
+		if err := os.MkdirAll(filepath.Join(jetbrainsDir, dir), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+	goLandConfig := createExistingConfig(t, filepath.Join(jetbrainsDir, "GoLand2025.1"), map[string]interface{}{
+		"mcpServers": existingServers,
+	})
+
+	if err := JetBrainsExtension(opts); err != nil {
+		t.Fatalf("JetBrainsExtension() failed: %v", err)
+	}
+
+	wantServer := map[string]interface{}{"command": testExePath}
+	for _, path := range []string{goLandConfig, filepath.Join(jetbrainsDir, "PyCharm2024.3", "mcp.json")} {
+		servers, ok := readConfig(t, path)["mcpServers"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected mcpServers in %s to be a map", path)
+		}
+		if diff := cmp.Diff(wantServer, servers["gke-mcp"]); diff != "" {
+			t.Errorf("gke-mcp server in %s mismatch (-want +got):\n%s", path, diff)
+		}
+	}
+	servers := readConfig(t, goLandConfig)["mcpServers"].(map[string]interface{})
+	if diff := cmp.Diff(existingServers["existing-server"], servers["existing-server"]); diff != "" {
+		t.Errorf("Expected the existing server to be kept. Diff:\n%v", diff)
+	}
+	verifyRemoved(t, filepath.Join(jetbrainsDir, "consentOptions", "mcp.json"))
+}
+
+func TestInstallRemoteURL(t *testing.T) {
+	const remoteURL = "https://gke-mcp.example.com/mcp"
+	testCases := []struct {
+		name       string
+		install    func(*InstallOptions) error
+		configPath []string
+		serversKey string
+		serverName string
+		want       map[string]interface{}
+	}{
+		{
+			name:       "gemini-cli",
+			install:    GeminiCLIExtension,
+			configPath: []string{".gemini", "extensions", "gke-mcp", "gemini-extension.json"},
+			serversKey: "mcpServers",
+			serverName: "gke",
+			want:       map[string]interface{}{"httpUrl": remoteURL},
+		},
+		{
+			name:       "cursor",
+			install:    CursorMCPExtension,
+			configPath: []string{".cursor", "mcp.json"},
+			serversKey: "mcpServers",
+			serverName: "gke-mcp",
+			want:       map[string]interface{}{"url": remoteURL},
+		},
+		{
+			name:       "vscode",
+			install:    VSCodeMCPExtension,
+			configPath: []string{".vscode", "mcp.json"},
+			serversKey: "servers",
+			serverName: "gke-mcp",
+			want:       map[string]interface{}{"type": "http", "url": remoteURL},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir, cleanup := testSetup(t, false)
+			defer cleanup()
+
+			opts := &InstallOptions{
+				version:     "0.1.0-test",
+				installDir:  tmpDir,
+				exePath:     "/usr/local/bin/gke-mcp",
+				projectOnly: true,
+				transport:   transportHTTP,
+				url:         remoteURL,
+			}
+			if err := tc.install(opts); err != nil {
+				t.Fatalf("install failed: %v", err)
+			}
+
+			config := readConfig(t, filepath.Join(append([]string{tmpDir}, tc.configPath...)...))
+			servers, ok := config[tc.serversKey].(map[string]interface{})
+			if !ok {
+				t.Fatalf("Expected %s to be a map, got %T", tc.serversKey, config[tc.serversKey])
+			}
+			if diff := cmp.Diff(tc.want, servers[tc.serverName]); diff != "" {
+				t.Errorf("Server entry mismatch. Diff:\n%v", diff)
+			}
+		})
+	}
+
+	t.Run("claude-code", func(t *testing.T) {
+		tmpDir, cleanup := testSetup(t, false)
+		defer cleanup()
+
+		logFile, cleanupCommand := MockClaudeCommand(t)
+		defer cleanupCommand()
+
+		opts := &InstallOptions{
+			installDir: tmpDir,
+			exePath:    "/usr/local/bin/gke-mcp",
+			assumeYes:  true,
+			transport:  transportHTTP,
+			url:        remoteURL,
+		}
+		if err := ClaudeCodeExtension(opts); err != nil {
+			t.Fatalf("ClaudeCodeExtension() failed: %v", err)
+		}
+
+		logContent, err := os.ReadFile(logFile)
+		if err != nil {
+			t.Fatalf("Failed to read command log: %v", err)
+		}
+		if want := "mcp add --transport http gke-mcp " + remoteURL; !strings.Contains(string(logContent), want) {
+			t.Errorf("Expected claude command to be called with args '%s', but log contains: %s", want, logContent)
+		}
+	})
+}
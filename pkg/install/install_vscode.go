@@ -0,0 +1,117 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// vscodeInstructionsHeader is the header content for the Copilot instructions file
+const vscodeInstructionsHeader = `---
+applyTo: "**"
+description: Provides guidance for using the gke-mcp tool with GitHub Copilot.
+---
+
+# GKE MCP Tool Instructions
+
+These instructions provide context for using the gke-mcp tool within VS Code.
+
+`
+
+// VSCodeMCPExtension installs the gke-mcp server as a VS Code MCP server for
+// GitHub Copilot agent mode
+func VSCodeMCPExtension(opts *InstallOptions) error {
+	mcpPath, instructionsPath, err := getVSCodePaths(opts)
+	if err != nil {
+		return fmt.Errorf("could not determine VS Code config path: %w", err)
+	}
+
+	if err := opts.mkdirAll(filepath.Dir(mcpPath)); err != nil {
+		return fmt.Errorf("could not create VS Code directory at %s: %w", filepath.Dir(mcpPath), err)
+	}
+
+	// Read existing configuration if it exists, only changing the gke-mcp server to avoid data loss
+	data, err := os.ReadFile(mcpPath)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read existing MCP configuration: %w", err)
+	}
+
+	if exists {
+		var config map[string]interface{}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("could not parse existing MCP configuration: %w", err)
+		}
+
+		if _, ok := config["servers"].(map[string]interface{}); !ok && config["servers"] != nil {
+			slog.Warn("servers in VS Code MCP config is not a map, creating new one")
+		}
+	}
+
+	// Add or update the gke-mcp server configuration, VS Code keeps them under servers
+	server := opts.serverDefinition()
+	entry := server.mcpServerEntry("url")
+	entry["type"] = server.transport
+
+	data, err = mergeMCPServer(data, exists, "servers", entry)
+	if err != nil {
+		return fmt.Errorf("could not update MCP configuration: %w", err)
+	}
+
+	// Write the updated configuration back to the file
+	if err := opts.writeFile(mcpPath, data); err != nil {
+		return fmt.Errorf("could not write MCP configuration: %w", err)
+	}
+
+	// Create the Copilot instructions file with custom heading and GEMINI.md content
+	if err := opts.mkdirAll(filepath.Dir(instructionsPath)); err != nil {
+		return fmt.Errorf("could not create instructions directory: %w", err)
+	}
+
+	instructionsContent := append([]byte(vscodeInstructionsHeader), GeminiMarkdown...)
+	if err := opts.writeFile(instructionsPath, instructionsContent); err != nil {
+		return fmt.Errorf("could not write gke-mcp instructions file: %w", err)
+	}
+
+	return nil
+}
+
+// getVSCodePaths returns the paths of the MCP configuration and the Copilot
+// instructions file, in the workspace for a project-only installation and in
+// the platform-specific VS Code user directory otherwise
+func getVSCodePaths(opts *InstallOptions) (string, string, error) {
+	if opts.projectOnly {
+		return filepath.Join(opts.installDir, ".vscode", "mcp.json"),
+			filepath.Join(opts.installDir, ".github", "instructions", "gke-mcp.instructions.md"), nil
+	}
+
+	userDir, err := opts.configPaths().vscodeUserDir()
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(userDir, "mcp.json"), filepath.Join(userDir, "prompts", "gke-mcp.instructions.md"), nil
+}
+
+// getVSCodeUserDir returns the platform-specific VS Code user settings directory
+func getVSCodeUserDir() (string, error) {
+	paths, err := homeConfigPaths()
+	if err != nil {
+		return "", err
+	}
+	return paths.vscodeUserDir()
+}
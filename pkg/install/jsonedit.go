@@ -0,0 +1,250 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The JSON editing functions change a single member of the configuration
+// files of the AI clients and leave the rest of the bytes as they are, so that
+// the key order, formatting and numbers users chose are kept.
+
+// jsonMember is the location of a member in a JSON object
+type jsonMember struct {
+	key        string
+	keyStart   int
+	valueStart int
+	valueEnd   int
+}
+
+// jsonObject is the location of a JSON object and its members
+type jsonObject struct {
+	open    int
+	close   int
+	members []jsonMember
+}
+
+// mergeMCPServer returns the configuration data, or a new one if it doesn't
+// exist, with the gke-mcp server under serversKey set to entry.
+func mergeMCPServer(data []byte, exists bool, serversKey string, entry map[string]interface{}) ([]byte, error) {
+	if !exists {
+		return json.MarshalIndent(map[string]interface{}{
+			serversKey: map[string]interface{}{"gke-mcp": entry},
+		}, "", "  ")
+	}
+	return setJSONMember(data, []string{serversKey, "gke-mcp"}, entry)
+}
+
+// setJSONMember sets the member at path, e.g. mcpServers then gke-mcp, of the
+// JSON object in data to value. The objects on the path are created if they
+// are missing, or replaced if they are not objects.
+func setJSONMember(data []byte, path []string, value interface{}) ([]byte, error) {
+	obj, err := parseJSONObject(data, skipJSONSpace(data, 0, ""))
+	if err != nil {
+		return nil, err
+	}
+	return setMember(data, obj, path, value)
+}
+
+// deleteJSONMember removes the member at path of the JSON object in data, and
+// reports whether it was there.
+func deleteJSONMember(data []byte, path []string) ([]byte, bool, error) {
+	obj, err := parseJSONObject(data, skipJSONSpace(data, 0, ""))
+	if err != nil {
+		return nil, false, err
+	}
+	for {
+		m, ok := obj.member(path[0])
+		if !ok {
+			return data, false, nil
+		}
+		if len(path) == 1 {
+			return obj.remove(data, m), true, nil
+		}
+		if data[m.valueStart] != '{' {
+			return data, false, nil
+		}
+		if obj, err = parseJSONObject(data, m.valueStart); err != nil {
+			return nil, false, err
+		}
+		path = path[1:]
+	}
+}
+
+func setMember(data []byte, obj *jsonObject, path []string, value interface{}) ([]byte, error) {
+	m, ok := obj.member(path[0])
+	if ok && len(path) > 1 && data[m.valueStart] == '{' {
+		inner, err := parseJSONObject(data, m.valueStart)
+		if err != nil {
+			return nil, err
+		}
+		return setMember(data, inner, path[1:], value)
+	}
+
+	// Nest the value in the objects missing from the path.
+	for i := len(path) - 1; i > 0; i-- {
+		value = map[string]interface{}{path[i]: value}
+	}
+	indent, unit, multiline := obj.layout(data)
+	if ok {
+		encoded, err := encodeJSON(value, indent, unit, multiline)
+		if err != nil {
+			return nil, err
+		}
+		return splice(data, m.valueStart, m.valueEnd, encoded), nil
+	}
+	return obj.insert(data, path[0], value)
+}
+
+// member returns the last member named key, which is the one JSON decoders use.
+func (o *jsonObject) member(key string) (jsonMember, bool) {
+	for i := len(o.members) - 1; i >= 0; i-- {
+		if o.members[i].key == key {
+			return o.members[i], true
+		}
+	}
+	return jsonMember{}, false
+}
+
+// layout returns the indentation of the members of the object and of each
+// nesting level, and whether the members are on their own lines.
+func (o *jsonObject) layout(data []byte) (string, string, bool) {
+	objIndent := lineIndent(data, o.open)
+	if len(o.members) == 0 {
+		return objIndent + "  ", "  ", true
+	}
+	if !bytes.ContainsRune(data[o.open:o.members[0].keyStart], '\n') {
+		return "", "", false
+	}
+	indent := lineIndent(data, o.members[0].keyStart)
+	unit := strings.TrimPrefix(indent, objIndent)
+	if unit == "" {
+		unit = "  "
+	}
+	return indent, unit, true
+}
+
+// insert adds the member key with value at the end of the object.
+func (o *jsonObject) insert(data []byte, key string, value interface{}) ([]byte, error) {
+	indent, unit, multiline := o.layout(data)
+	encodedKey, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := encodeJSON(value, indent, unit, multiline)
+	if err != nil {
+		return nil, err
+	}
+	member := string(encodedKey) + ": " + string(encoded)
+
+	if len(o.members) == 0 {
+		return splice(data, o.open+1, o.close, []byte("\n"+indent+member+"\n"+lineIndent(data, o.open))), nil
+	}
+	last := o.members[len(o.members)-1].valueEnd
+	if multiline {
+		return splice(data, last, last, []byte(",\n"+indent+member)), nil
+	}
+	return splice(data, last, last, []byte(", "+member)), nil
+}
+
+// remove deletes the member m and the separator next to it.
+func (o *jsonObject) remove(data []byte, m jsonMember) []byte {
+	if len(o.members) == 1 {
+		return splice(data, o.open+1, o.close, nil)
+	}
+	for i, other := range o.members {
+		if other.keyStart != m.keyStart {
+			continue
+		}
+		if i > 0 {
+			return splice(data, o.members[i-1].valueEnd, m.valueEnd, nil)
+		}
+		return splice(data, m.keyStart, o.members[1].keyStart, nil)
+	}
+	return data
+}
+
+// parseJSONObject returns the location of the JSON object starting at offset
+// start of data and of its members.
+func parseJSONObject(data []byte, start int) (*jsonObject, error) {
+	dec := json.NewDecoder(bytes.NewReader(data[start:]))
+	offset := func() int { return start + int(dec.InputOffset()) }
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object, got %v", tok)
+	}
+	obj := &jsonObject{open: start}
+	for dec.More() {
+		keyStart := skipJSONSpace(data, offset(), ",")
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		valueStart := skipJSONSpace(data, offset(), ":")
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		obj.members = append(obj.members, jsonMember{key: key, keyStart: keyStart, valueStart: valueStart, valueEnd: offset()})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	obj.close = offset() - 1
+	return obj, nil
+}
+
+// encodeJSON encodes value for a member indented with indent, on several lines
+// indented by unit if multiline.
+func encodeJSON(value interface{}, indent, unit string, multiline bool) ([]byte, error) {
+	if multiline {
+		return json.MarshalIndent(value, indent, unit)
+	}
+	return json.Marshal(value)
+}
+
+// skipJSONSpace returns the offset of the first byte from i on that is neither
+// JSON whitespace nor in extra.
+func skipJSONSpace(data []byte, i int, extra string) int {
+	for i < len(data) && strings.ContainsRune(" \t\r\n"+extra, rune(data[i])) {
+		i++
+	}
+	return i
+}
+
+// lineIndent returns the whitespace at the start of the line holding offset i.
+func lineIndent(data []byte, i int) string {
+	start := bytes.LastIndexByte(data[:i], '\n') + 1
+	end := start
+	for end < i && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+	return string(data[start:end])
+}
+
+// splice returns data with the bytes from start to end replaced by insert.
+func splice(data []byte, start, end int, insert []byte) []byte {
+	out := make([]byte, 0, len(data)-(end-start)+len(insert))
+	out = append(out, data[:start]...)
+	out = append(out, insert...)
+	return append(out, data[end:]...)
+}
@@ -0,0 +1,256 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// gkeMCPEntry is the gke-mcp server the golden files are merged with
+var gkeMCPEntry = map[string]interface{}{
+	"command": "/usr/local/bin/gke-mcp",
+	"type":    "stdio",
+}
+
+func TestMergeMCPServer(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{
+			name: "keeps order, indentation and numbers",
+			config: `{
+    "zoom": 1.0e3,
+    "mcpServers": {
+        "other": {"command": "/usr/bin/other"}
+    },
+    "apiKey": "abc"
+}
+`,
+			want: `{
+    "zoom": 1.0e3,
+    "mcpServers": {
+        "other": {"command": "/usr/bin/other"},
+        "gke-mcp": {
+            "command": "/usr/local/bin/gke-mcp",
+            "type": "stdio"
+        }
+    },
+    "apiKey": "abc"
+}
+`,
+		},
+		{
+			name:   "tab indentation",
+			config: "{\n\t\"mcpServers\": {\n\t\t\"other\": {}\n\t}\n}",
+			want:   "{\n\t\"mcpServers\": {\n\t\t\"other\": {},\n\t\t\"gke-mcp\": {\n\t\t\t\"command\": \"/usr/local/bin/gke-mcp\",\n\t\t\t\"type\": \"stdio\"\n\t\t}\n\t}\n}",
+		},
+		{
+			name:   "single line",
+			config: `{"b": 2, "mcpServers": {"other": {}}, "a": 1}`,
+			want:   `{"b": 2, "mcpServers": {"other": {}, "gke-mcp": {"command":"/usr/local/bin/gke-mcp","type":"stdio"}}, "a": 1}`,
+		},
+		{
+			name: "empty servers",
+			config: `{
+  "theme": "dark",
+  "mcpServers": {}
+}`,
+			want: `{
+  "theme": "dark",
+  "mcpServers": {
+    "gke-mcp": {
+      "command": "/usr/local/bin/gke-mcp",
+      "type": "stdio"
+    }
+  }
+}`,
+		},
+		{
+			name: "missing servers",
+			config: `{
+  "theme": "dark"
+}`,
+			want: `{
+  "theme": "dark",
+  "mcpServers": {
+    "gke-mcp": {
+      "command": "/usr/local/bin/gke-mcp",
+      "type": "stdio"
+    }
+  }
+}`,
+		},
+		{
+			name: "servers not an object",
+			config: `{
+  "mcpServers": "invalid",
+  "theme": "dark"
+}`,
+			want: `{
+  "mcpServers": {
+    "gke-mcp": {
+      "command": "/usr/local/bin/gke-mcp",
+      "type": "stdio"
+    }
+  },
+  "theme": "dark"
+}`,
+		},
+		{
+			name: "replaces gke-mcp",
+			config: `{
+  "mcpServers": {
+    "gke-mcp": {"command": "/old/gke-mcp", "env": {"A": "1"}},
+    "other": {}
+  }
+}`,
+			want: `{
+  "mcpServers": {
+    "gke-mcp": {
+      "command": "/usr/local/bin/gke-mcp",
+      "type": "stdio"
+    },
+    "other": {}
+  }
+}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := mergeMCPServer([]byte(tc.config), true, "mcpServers", gkeMCPEntry)
+			if err != nil {
+				t.Fatalf("mergeMCPServer() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("mergeMCPServer() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMergeMCPServerMalformed(t *testing.T) {
+	for _, config := range []string{`[]`, `{"mcpServers": `} {
+		if _, err := mergeMCPServer([]byte(config), true, "mcpServers", gkeMCPEntry); err == nil {
+			t.Errorf("mergeMCPServer(%q) succeeded, want an error", config)
+		}
+	}
+}
+
+func TestDeleteJSONMember(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{
+			name:   "first member",
+			config: "{\n  \"s\": {\n    \"gke-mcp\": {},\n    \"other\": 1\n  }\n}",
+			want:   "{\n  \"s\": {\n    \"other\": 1\n  }\n}",
+		},
+		{
+			name:   "last member",
+			config: "{\n  \"s\": {\n    \"other\": 1,\n    \"gke-mcp\": {}\n  }\n}",
+			want:   "{\n  \"s\": {\n    \"other\": 1\n  }\n}",
+		},
+		{
+			name:   "only member",
+			config: `{"s": {"gke-mcp": {}}, "a": 1}`,
+			want:   `{"s": {}, "a": 1}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, found, err := deleteJSONMember([]byte(tc.config), []string{"s", "gke-mcp"})
+			if err != nil {
+				t.Fatalf("deleteJSONMember() failed: %v", err)
+			}
+			if !found {
+				t.Errorf("deleteJSONMember() did not find gke-mcp")
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("deleteJSONMember() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCursorMCPExtensionKeepsFormatting(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, true)
+	defer cleanup()
+
+	original := `{
+    "//": "keys and odd numbers are kept as written",
+    "mcpServers": {
+        "existing-server": {"command": "/usr/bin/existing", "args": ["-v"]}
+    },
+    "telemetry": false,
+    "timeout": 30.50
+}
+`
+	mcpPath := filepath.Join(tmpDir, ".cursor", "mcp.json")
+	if err := os.MkdirAll(filepath.Dir(mcpPath), 0755); err != nil {
+		t.Fatalf("Failed to create cursor dir: %v", err)
+	}
+	if err := os.WriteFile(mcpPath, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+	}
+	if err := CursorMCPExtension(opts); err != nil {
+		t.Fatalf("CursorMCPExtension() failed: %v", err)
+	}
+
+	want := `{
+    "//": "keys and odd numbers are kept as written",
+    "mcpServers": {
+        "existing-server": {"command": "/usr/bin/existing", "args": ["-v"]},
+        "gke-mcp": {
+            "command": "/usr/local/bin/gke-mcp",
+            "type": "stdio"
+        }
+    },
+    "telemetry": false,
+    "timeout": 30.50
+}
+`
+	got, err := os.ReadFile(mcpPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("Installed config mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := UninstallCursorMCPExtension(opts); err != nil {
+		t.Fatalf("UninstallCursorMCPExtension() failed: %v", err)
+	}
+	got, err = os.ReadFile(mcpPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if diff := cmp.Diff(original, string(got)); diff != "" {
+		t.Errorf("Uninstall did not restore the config (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// The formats of the server manifest, the config dialects of most MCP clients
+const (
+	ManifestJSON = "json"
+	ManifestTOML = "toml"
+	ManifestYAML = "yaml"
+)
+
+// tomlBareKey matches the TOML keys that need no quotes
+var tomlBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// WriteManifest writes the definition of the gke-mcp server in format to out,
+// or prints it to stdout if out is empty, for the users of MCP clients without
+// an installer to add to their configuration. It also writes the usage guide
+// to GKE_MCP_USAGE_GUIDE.md in the install directory and returns its path, to
+// be added to the instructions of the client.
+func WriteManifest(opts *InstallOptions, format, out string) (string, error) {
+	manifest, err := serverManifest(opts.serverDefinition(), format)
+	if err != nil {
+		return "", err
+	}
+
+	if err := opts.mkdirAll(opts.installDir); err != nil {
+		return "", fmt.Errorf("could not create directory %s: %w", opts.installDir, err)
+	}
+	usageGuideMDPath := filepath.Join(opts.installDir, "GKE_MCP_USAGE_GUIDE.md")
+	if err := opts.writeFile(usageGuideMDPath, GeminiMarkdown); err != nil {
+		return "", fmt.Errorf("could not write GKE_MCP_USAGE_GUIDE.md: %w", err)
+	}
+
+	if out == "" {
+		_, err := os.Stdout.Write(manifest)
+		return usageGuideMDPath, err
+	}
+	if err := opts.mkdirAll(filepath.Dir(out)); err != nil {
+		return "", fmt.Errorf("could not create directory of %s: %w", out, err)
+	}
+	if err := opts.writeFile(out, manifest); err != nil {
+		return "", fmt.Errorf("could not write manifest: %w", err)
+	}
+	return usageGuideMDPath, nil
+}
+
+// serverManifest returns the server in format: under mcpServers for JSON and
+// YAML, or the mcp_servers table for TOML
+func serverManifest(server serverDefinition, format string) ([]byte, error) {
+	entry := server.mcpServerEntry("url")
+	entry["type"] = server.transport
+	config := map[string]interface{}{
+		"mcpServers": map[string]interface{}{"gke-mcp": entry},
+	}
+
+	switch format {
+	case ManifestJSON:
+		data, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case ManifestYAML:
+		return encodeYAML(config, 2)
+	case ManifestTOML:
+		return tomlManifest(server), nil
+	default:
+		return nil, fmt.Errorf("unknown manifest format %q, must be %s, %s or %s", format, ManifestJSON, ManifestTOML, ManifestYAML)
+	}
+}
+
+// tomlManifest returns the server as a table of mcp_servers, which TOML
+// clients such as Codex use
+func tomlManifest(server serverDefinition) []byte {
+	var b bytes.Buffer
+	b.WriteString("[mcp_servers.gke-mcp]\n")
+	if server.transport == transportHTTP {
+		fmt.Fprintf(&b, "url = %s\n", tomlString(server.url))
+		return b.Bytes()
+	}
+
+	fmt.Fprintf(&b, "command = %s\n", tomlString(server.command))
+	if len(server.args) > 0 {
+		args := make([]string, len(server.args))
+		for i, arg := range server.args {
+			args[i] = tomlString(arg)
+		}
+		fmt.Fprintf(&b, "args = [%s]\n", strings.Join(args, ", "))
+	}
+	if len(server.env) > 0 {
+		b.WriteString("\n[mcp_servers.gke-mcp.env]\n")
+		for _, key := range slices.Sorted(maps.Keys(server.env)) {
+			name := key
+			if !tomlBareKey.MatchString(key) {
+				name = tomlString(key)
+			}
+			fmt.Fprintf(&b, "%s = %s\n", name, tomlString(server.env[key]))
+		}
+	}
+	return b.Bytes()
+}
+
+// tomlString returns s as a TOML basic string, whose escapes are the ones of
+// JSON strings
+func tomlString(s string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	// Encoding a string can't fail
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
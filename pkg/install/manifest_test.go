@@ -0,0 +1,191 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServerManifest(t *testing.T) {
+	stdio := serverDefinition{
+		transport: transportStdio,
+		command:   "/usr/local/bin/gke-mcp",
+		args:      []string{"--verbose", "say \"hi\""},
+		env:       map[string]string{"GKE_MCP_PROJECT_ID": "my-project", "odd.key": "value"},
+	}
+	http := serverDefinition{
+		transport: transportHTTP,
+		url:       "https://gke-mcp.example.com/mcp",
+	}
+
+	testCases := []struct {
+		name   string
+		server serverDefinition
+		format string
+		want   string
+	}{
+		{
+			name:   "json stdio",
+			server: stdio,
+			format: ManifestJSON,
+			want: `{
+  "mcpServers": {
+    "gke-mcp": {
+      "args": [
+        "--verbose",
+        "say \"hi\""
+      ],
+      "command": "/usr/local/bin/gke-mcp",
+      "env": {
+        "GKE_MCP_PROJECT_ID": "my-project",
+        "odd.key": "value"
+      },
+      "type": "stdio"
+    }
+  }
+}
+`,
+		},
+		{
+			name:   "json http",
+			server: http,
+			format: ManifestJSON,
+			want: `{
+  "mcpServers": {
+    "gke-mcp": {
+      "type": "http",
+      "url": "https://gke-mcp.example.com/mcp"
+    }
+  }
+}
+`,
+		},
+		{
+			name:   "yaml stdio",
+			server: stdio,
+			format: ManifestYAML,
+			want: `mcpServers:
+  gke-mcp:
+    args:
+      - --verbose
+      - say "hi"
+    command: /usr/local/bin/gke-mcp
+    env:
+      GKE_MCP_PROJECT_ID: my-project
+      odd.key: value
+    type: stdio
+`,
+		},
+		{
+			name:   "yaml http",
+			server: http,
+			format: ManifestYAML,
+			want: `mcpServers:
+  gke-mcp:
+    type: http
+    url: https://gke-mcp.example.com/mcp
+`,
+		},
+		{
+			name:   "toml stdio",
+			server: stdio,
+			format: ManifestTOML,
+			want: `[mcp_servers.gke-mcp]
+command = "/usr/local/bin/gke-mcp"
+args = ["--verbose", "say \"hi\""]
+
+[mcp_servers.gke-mcp.env]
+GKE_MCP_PROJECT_ID = "my-project"
+"odd.key" = "value"
+`,
+		},
+		{
+			name:   "toml http",
+			server: http,
+			format: ManifestTOML,
+			want: `[mcp_servers.gke-mcp]
+url = "https://gke-mcp.example.com/mcp"
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := serverManifest(tc.server, tc.format)
+			if err != nil {
+				t.Fatalf("serverManifest() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("serverManifest() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := serverManifest(stdio, "xml"); err == nil || !strings.Contains(err.Error(), `unknown manifest format "xml"`) {
+		t.Errorf("serverManifest() with xml error = %v, want an unknown format", err)
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, false)
+	defer cleanup()
+
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+		dryRun:     true,
+	}
+	out := filepath.Join(tmpDir, "config", "gke-mcp.json")
+
+	// A dry run writes nothing
+	captureStdout(t, func() {
+		if _, err := WriteManifest(opts, ManifestJSON, out); err != nil {
+			t.Fatalf("WriteManifest() failed: %v", err)
+		}
+	})
+	verifyRemoved(t, out, filepath.Join(tmpDir, "GKE_MCP_USAGE_GUIDE.md"))
+
+	opts.dryRun = false
+	usageGuide, err := WriteManifest(opts, ManifestJSON, out)
+	if err != nil {
+		t.Fatalf("WriteManifest() failed: %v", err)
+	}
+
+	if want := filepath.Join(tmpDir, "GKE_MCP_USAGE_GUIDE.md"); usageGuide != want {
+		t.Errorf("WriteManifest() usage guide = %q, want %q", usageGuide, want)
+	}
+	guide, err := os.ReadFile(usageGuide)
+	if err != nil {
+		t.Fatalf("Failed to read usage guide: %v", err)
+	}
+	if !bytes.Equal(guide, GeminiMarkdown) {
+		t.Errorf("Expected the usage guide to match GeminiMarkdown")
+	}
+	verifyMCPConfig(t, out, "/usr/local/bin/gke-mcp")
+
+	// Without out, the manifest is printed
+	printed := captureStdout(t, func() {
+		if _, err := WriteManifest(opts, ManifestTOML, ""); err != nil {
+			t.Fatalf("WriteManifest() failed: %v", err)
+		}
+	})
+	if want := "[mcp_servers.gke-mcp]\ncommand = \"/usr/local/bin/gke-mcp\"\n"; printed != want {
+		t.Errorf("WriteManifest() printed %q, want %q", printed, want)
+	}
+}
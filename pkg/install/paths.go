@@ -0,0 +1,135 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// configPaths resolves where the AI clients keep their user configuration on
+// an operating system, from the home directory and the environment.
+type configPaths struct {
+	goos   string
+	home   string
+	getenv func(string) string
+}
+
+// configPaths returns the configPaths of this machine for a global install,
+// whose installDir is the home directory
+func (o *InstallOptions) configPaths() configPaths {
+	return configPaths{goos: runtime.GOOS, home: o.installDir, getenv: os.Getenv}
+}
+
+// homeConfigPaths returns the configPaths of this machine for the home
+// directory of the user
+func homeConfigPaths() (configPaths, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return configPaths{}, err
+	}
+	return configPaths{goos: runtime.GOOS, home: home, getenv: os.Getenv}, nil
+}
+
+// appConfigDir returns the directory desktop applications keep their
+// configuration in: Application Support on macOS, APPDATA on Windows and
+// XDG_CONFIG_HOME, ~/.config by default, on the other systems.
+func (p configPaths) appConfigDir() (string, error) {
+	switch p.goos {
+	case "darwin":
+		return filepath.Join(p.home, "Library", "Application Support"), nil
+	case "windows":
+		appData := p.getenv("APPDATA")
+		if appData == "" {
+			return "", fmt.Errorf("APPDATA environment variable not set")
+		}
+		return appData, nil
+	default:
+		return p.xdgConfigHome(), nil
+	}
+}
+
+// xdgConfigHome returns XDG_CONFIG_HOME, ~/.config by default, where command
+// line tools keep their configuration on every system but Windows
+func (p configPaths) xdgConfigHome() string {
+	// Relative paths are invalid and must be ignored, as per the XDG Base Directory Specification
+	if xdg := p.getenv("XDG_CONFIG_HOME"); filepath.IsAbs(xdg) {
+		return xdg
+	}
+	return filepath.Join(p.home, ".config")
+}
+
+// cursorDir returns the global Cursor directory, in the home directory on
+// every system
+func (p configPaths) cursorDir() string {
+	return filepath.Join(p.home, ".cursor")
+}
+
+// geminiDir returns the global Gemini CLI directory, in the home directory on
+// every system unless GEMINI_CLI_HOME replaces it, as Gemini CLI does
+func (p configPaths) geminiDir() string {
+	if home := p.getenv("GEMINI_CLI_HOME"); home != "" {
+		return filepath.Join(home, ".gemini")
+	}
+	return filepath.Join(p.home, ".gemini")
+}
+
+// geminiExtensionDir returns the directory of the global gke-mcp Gemini CLI
+// extension
+func (p configPaths) geminiExtensionDir() string {
+	return filepath.Join(p.geminiDir(), "extensions", "gke-mcp")
+}
+
+// vscodeUserDir returns the VS Code user settings directory
+func (p configPaths) vscodeUserDir() (string, error) {
+	dir, err := p.appConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "Code", "User"), nil
+}
+
+// claudeDesktopConfigPath returns the path of the Claude Desktop config file
+func (p configPaths) claudeDesktopConfigPath() (string, error) {
+	dir, err := p.appConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "Claude", "claude_desktop_config.json"), nil
+}
+
+// gooseConfigPath returns the path of the Goose config file, in
+// XDG_CONFIG_HOME also on macOS
+func (p configPaths) gooseConfigPath() (string, error) {
+	if p.goos == "windows" {
+		appData := p.getenv("APPDATA")
+		if appData == "" {
+			return "", fmt.Errorf("APPDATA environment variable not set")
+		}
+		return filepath.Join(appData, "Block", "goose", "config", "config.yaml"), nil
+	}
+	return filepath.Join(p.xdgConfigHome(), "goose", "config.yaml"), nil
+}
+
+// jetbrainsDir returns the directory holding the configuration directory of
+// each JetBrains IDE and version, e.g. GoLand2025.1
+func (p configPaths) jetbrainsDir() (string, error) {
+	dir, err := p.appConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "JetBrains"), nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigPaths(t *testing.T) {
+	home := filepath.Join("home", "user")
+	appData := filepath.Join("Users", "user", "AppData", "Roaming")
+	// An absolute path on every system
+	xdg := filepath.Join(t.TempDir(), "config")
+
+	testCases := []struct {
+		name              string
+		goos              string
+		env               map[string]string
+		wantVSCode        string
+		wantClaudeDesktop string
+		wantErr           string
+	}{
+		{
+			name:              "darwin",
+			goos:              "darwin",
+			env:               map[string]string{"APPDATA": appData, "XDG_CONFIG_HOME": xdg},
+			wantVSCode:        filepath.Join(home, "Library", "Application Support", "Code", "User"),
+			wantClaudeDesktop: filepath.Join(home, "Library", "Application Support", "Claude", "claude_desktop_config.json"),
+		},
+		{
+			name:              "windows",
+			goos:              "windows",
+			env:               map[string]string{"APPDATA": appData, "XDG_CONFIG_HOME": xdg},
+			wantVSCode:        filepath.Join(appData, "Code", "User"),
+			wantClaudeDesktop: filepath.Join(appData, "Claude", "claude_desktop_config.json"),
+		},
+		{
+			name:    "windows without APPDATA",
+			goos:    "windows",
+			wantErr: "APPDATA environment variable not set",
+		},
+		{
+			name:              "linux",
+			goos:              "linux",
+			wantVSCode:        filepath.Join(home, ".config", "Code", "User"),
+			wantClaudeDesktop: filepath.Join(home, ".config", "Claude", "claude_desktop_config.json"),
+		},
+		{
+			name:              "linux with XDG_CONFIG_HOME",
+			goos:              "linux",
+			env:               map[string]string{"XDG_CONFIG_HOME": xdg},
+			wantVSCode:        filepath.Join(xdg, "Code", "User"),
+			wantClaudeDesktop: filepath.Join(xdg, "Claude", "claude_desktop_config.json"),
+		},
+		{
+			name:              "linux with relative XDG_CONFIG_HOME",
+			goos:              "linux",
+			env:               map[string]string{"XDG_CONFIG_HOME": "config"},
+			wantVSCode:        filepath.Join(home, ".config", "Code", "User"),
+			wantClaudeDesktop: filepath.Join(home, ".config", "Claude", "claude_desktop_config.json"),
+		},
+		{
+			name:              "freebsd with XDG_CONFIG_HOME",
+			goos:              "freebsd",
+			env:               map[string]string{"XDG_CONFIG_HOME": xdg},
+			wantVSCode:        filepath.Join(xdg, "Code", "User"),
+			wantClaudeDesktop: filepath.Join(xdg, "Claude", "claude_desktop_config.json"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			paths := configPaths{
+				goos:   tc.goos,
+				home:   home,
+				getenv: func(key string) string { return tc.env[key] },
+			}
+
+			// Cursor and Gemini CLI use the home directory on every system
+			if got, want := paths.cursorDir(), filepath.Join(home, ".cursor"); got != want {
+				t.Errorf("cursorDir() = %q, want %q", got, want)
+			}
+			if got, want := paths.geminiExtensionDir(), filepath.Join(home, ".gemini", "extensions", "gke-mcp"); got != want {
+				t.Errorf("geminiExtensionDir() = %q, want %q", got, want)
+			}
+
+			vscode, err := paths.vscodeUserDir()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("vscodeUserDir() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("vscodeUserDir() failed: %v", err)
+			} else if vscode != tc.wantVSCode {
+				t.Errorf("vscodeUserDir() = %q, want %q", vscode, tc.wantVSCode)
+			}
+
+			claudeDesktop, err := paths.claudeDesktopConfigPath()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("claudeDesktopConfigPath() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("claudeDesktopConfigPath() failed: %v", err)
+			} else if claudeDesktop != tc.wantClaudeDesktop {
+				t.Errorf("claudeDesktopConfigPath() = %q, want %q", claudeDesktop, tc.wantClaudeDesktop)
+			}
+		})
+	}
+}
+
+func TestConfigPathsGeminiCLIHome(t *testing.T) {
+	paths := configPaths{
+		goos:   "linux",
+		home:   filepath.Join("home", "user"),
+		getenv: func(key string) string { return map[string]string{"GEMINI_CLI_HOME": "gemini"}[key] },
+	}
+	if got, want := paths.geminiExtensionDir(), filepath.Join("gemini", ".gemini", "extensions", "gke-mcp"); got != want {
+		t.Errorf("geminiExtensionDir() = %q, want %q", got, want)
+	}
+}
+
+func TestConfigPathsGooseAndJetBrains(t *testing.T) {
+	home := filepath.Join("home", "user")
+	appData := filepath.Join("Users", "user", "AppData", "Roaming")
+	// An absolute path on every system
+	xdg := filepath.Join(t.TempDir(), "config")
+
+	testCases := []struct {
+		name          string
+		goos          string
+		env           map[string]string
+		wantGoose     string
+		wantJetBrains string
+	}{
+		{
+			name:          "darwin",
+			goos:          "darwin",
+			wantGoose:     filepath.Join(home, ".config", "goose", "config.yaml"),
+			wantJetBrains: filepath.Join(home, "Library", "Application Support", "JetBrains"),
+		},
+		{
+			name:          "darwin with XDG_CONFIG_HOME",
+			goos:          "darwin",
+			env:           map[string]string{"XDG_CONFIG_HOME": xdg},
+			wantGoose:     filepath.Join(xdg, "goose", "config.yaml"),
+			wantJetBrains: filepath.Join(home, "Library", "Application Support", "JetBrains"),
+		},
+		{
+			name:          "windows",
+			goos:          "windows",
+			env:           map[string]string{"APPDATA": appData},
+			wantGoose:     filepath.Join(appData, "Block", "goose", "config", "config.yaml"),
+			wantJetBrains: filepath.Join(appData, "JetBrains"),
+		},
+		{
+			name:          "linux",
+			goos:          "linux",
+			env:           map[string]string{"XDG_CONFIG_HOME": xdg},
+			wantGoose:     filepath.Join(xdg, "goose", "config.yaml"),
+			wantJetBrains: filepath.Join(xdg, "JetBrains"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			paths := configPaths{
+				goos:   tc.goos,
+				home:   home,
+				getenv: func(key string) string { return tc.env[key] },
+			}
+			if got, err := paths.gooseConfigPath(); err != nil || got != tc.wantGoose {
+				t.Errorf("gooseConfigPath() = %q, %v, want %q", got, err, tc.wantGoose)
+			}
+			if got, err := paths.jetbrainsDir(); err != nil || got != tc.wantJetBrains {
+				t.Errorf("jetbrainsDir() = %q, %v, want %q", got, err, tc.wantJetBrains)
+			}
+		})
+	}
+}
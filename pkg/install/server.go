@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+// serverDefinition is what the installers register in the AI clients: the
+// command, arguments and environment a client runs the gke-mcp server with
+// over stdio, or the URL it connects to over HTTP. Each installer writes it in
+// the dialect of its client.
+type serverDefinition struct {
+	transport string
+	command   string
+	args      []string
+	env       map[string]string
+	url       string
+}
+
+// serverDefinition returns the server the installers register
+func (o *InstallOptions) serverDefinition() serverDefinition {
+	if o.isHTTP() {
+		return serverDefinition{transport: transportHTTP, url: o.serverURL()}
+	}
+	return serverDefinition{
+		transport: transportStdio,
+		command:   o.exePath,
+		args:      o.serverArgs,
+		env:       o.env,
+	}
+}
+
+// mcpServerEntry returns the server in the mcpServers dialect most clients
+// share: its command, with args and env when set, or its URL under urlKey.
+func (d serverDefinition) mcpServerEntry(urlKey string) map[string]interface{} {
+	if d.transport == transportHTTP {
+		return map[string]interface{}{urlKey: d.url}
+	}
+	entry := map[string]interface{}{
+		"command": d.command,
+	}
+	if len(d.args) > 0 {
+		entry["args"] = d.args
+	}
+	if len(d.env) > 0 {
+		entry["env"] = d.env
+	}
+	return entry
+}
@@ -0,0 +1,216 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// The uninstallers remove what the matching installers added and return a
+// description of each thing they removed. They leave everything else in place,
+// so running them again removes nothing.
+
+// UninstallGeminiCLIExtension removes the gke-mcp Gemini CLI extension
+func UninstallGeminiCLIExtension(opts *InstallOptions) ([]string, error) {
+	extensionDir := filepath.Join(opts.installDir, ".gemini", "extensions", "gke-mcp")
+	if !opts.projectOnly {
+		extensionDir = opts.configPaths().geminiExtensionDir()
+	}
+	return removeFiles(extensionDir)
+}
+
+// UninstallCursorMCPExtension removes the gke-mcp server and rule from Cursor
+func UninstallCursorMCPExtension(opts *InstallOptions) ([]string, error) {
+	mcpDir := filepath.Join(opts.installDir, ".cursor")
+	if !opts.projectOnly {
+		mcpDir = opts.configPaths().cursorDir()
+	}
+	removed, err := removeMCPServer(filepath.Join(mcpDir, "mcp.json"), "mcpServers")
+	if err != nil {
+		return removed, err
+	}
+	files, err := removeFiles(filepath.Join(mcpDir, "rules", "gke-mcp.mdc"))
+	return append(removed, files...), err
+}
+
+// UninstallVSCodeMCPExtension removes the gke-mcp server and Copilot
+// instructions from VS Code
+func UninstallVSCodeMCPExtension(opts *InstallOptions) ([]string, error) {
+	mcpPath, instructionsPath, err := getVSCodePaths(opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine VS Code config path: %w", err)
+	}
+	removed, err := removeMCPServer(mcpPath, "servers")
+	if err != nil {
+		return removed, err
+	}
+	files, err := removeFiles(instructionsPath)
+	return append(removed, files...), err
+}
+
+// UninstallClaudeDesktopExtension removes the gke-mcp server from the Claude
+// Desktop settings
+func UninstallClaudeDesktopExtension(opts *InstallOptions) ([]string, error) {
+	configPath, err := opts.configPaths().claudeDesktopConfigPath()
+	if err != nil {
+		return nil, fmt.Errorf("could not determine Claude Desktop config path: %w", err)
+	}
+	return removeMCPServer(configPath, "mcpServers")
+}
+
+// UninstallGooseExtension removes the gke-mcp extension from the Goose config
+func UninstallGooseExtension(opts *InstallOptions) ([]string, error) {
+	configPath, err := opts.configPaths().gooseConfigPath()
+	if err != nil {
+		return nil, fmt.Errorf("could not determine Goose config path: %w", err)
+	}
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read Goose config %s: %w", configPath, err)
+	}
+
+	data, found, err := deleteYAMLMember(data, []string{"extensions", "gke-mcp"})
+	if err != nil {
+		return nil, fmt.Errorf("could not update Goose config %s: %w", configPath, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	if err := writeFileAtomic(configPath, data); err != nil {
+		return nil, fmt.Errorf("could not write Goose config %s: %w", configPath, err)
+	}
+	return []string{"gke-mcp extension in " + configPath}, nil
+}
+
+// UninstallJetBrainsExtension removes the gke-mcp server from the AI Assistant
+// of every JetBrains IDE
+func UninstallJetBrainsExtension(opts *InstallOptions) ([]string, error) {
+	dirs, err := jetbrainsIDEDirs(opts.configPaths())
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, dir := range dirs {
+		r, err := removeMCPServer(filepath.Join(dir, "mcp.json"), "mcpServers")
+		removed = append(removed, r...)
+		if err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// UninstallClaudeCodeExtension removes the gke-mcp server from Claude Code,
+// along with the usage guide and its reference in CLAUDE.md
+func UninstallClaudeCodeExtension(opts *InstallOptions) ([]string, error) {
+	var removed []string
+
+	// claude mcp get fails when the server is not registered.
+	if err := exec.Command("claude", "mcp", "get", "gke-mcp").Run(); err == nil {
+		cmdToRun := exec.Command("claude", "mcp", "remove", "gke-mcp")
+		cmdToRun.Stderr = os.Stderr
+		if err := cmdToRun.Run(); err != nil {
+			return nil, fmt.Errorf("failed to run command 'claude mcp remove': %w", err)
+		}
+		removed = append(removed, "gke-mcp server registered in Claude Code")
+	}
+
+	usageGuideMDPath := filepath.Join(opts.installDir, "GKE_MCP_USAGE_GUIDE.md")
+	claudeMDPath := filepath.Join(opts.installDir, "CLAUDE.md")
+	data, err := os.ReadFile(claudeMDPath)
+	if err != nil && !os.IsNotExist(err) {
+		return removed, fmt.Errorf("could not read CLAUDE.md: %w", err)
+	}
+	claudeLine := fmt.Sprintf("\n# GKE-MCP Server Instructions\n - @%s", usageGuideMDPath)
+	if content := string(data); strings.Contains(content, claudeLine) {
+		content = strings.ReplaceAll(content, claudeLine, "")
+		if content == "" {
+			// The installer created CLAUDE.md.
+			err = os.Remove(claudeMDPath)
+		} else {
+			err = writeFileAtomic(claudeMDPath, []byte(content))
+		}
+		if err != nil {
+			return removed, fmt.Errorf("could not update CLAUDE.md: %w", err)
+		}
+		removed = append(removed, "reference to GKE_MCP_USAGE_GUIDE.md in "+claudeMDPath)
+	}
+
+	files, err := removeFiles(usageGuideMDPath)
+	return append(removed, files...), err
+}
+
+// removeMCPServer removes the gke-mcp entry from the serversKey object of the
+// JSON configuration at path, keeping the other servers and settings. A
+// configuration left empty is removed, as the installer created it.
+func removeMCPServer(path, serversKey string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read MCP configuration %s: %w", path, err)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("could not parse MCP configuration %s: %w", path, err)
+	}
+	servers, ok := config[serversKey].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	if _, ok := servers["gke-mcp"]; !ok {
+		return nil, nil
+	}
+	if len(servers) == 1 && len(config) == 1 {
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("could not remove MCP configuration %s: %w", path, err)
+		}
+		return []string{"gke-mcp server in " + path}, nil
+	}
+
+	// Only remove the gke-mcp server, keeping the formatting of the rest
+	data, _, err = deleteJSONMember(data, []string{serversKey, "gke-mcp"})
+	if err != nil {
+		return nil, fmt.Errorf("could not update MCP configuration %s: %w", path, err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return nil, fmt.Errorf("could not write MCP configuration %s: %w", path, err)
+	}
+	return []string{"gke-mcp server in " + path}, nil
+}
+
+// removeFiles removes the files or directories at paths that exist.
+func removeFiles(paths ...string) ([]string, error) {
+	var removed []string
+	for _, path := range paths {
+		if _, err := os.Lstat(path); os.IsNotExist(err) {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return removed, fmt.Errorf("could not remove %s: %w", path, err)
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
@@ -0,0 +1,334 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// existingServers is a configuration with another server, which uninstalling must keep
+var existingServers = map[string]interface{}{
+	"existing-server": map[string]interface{}{
+		"command": "/usr/bin/existing",
+		"type":    "stdio",
+	},
+}
+
+// readConfig reads the JSON configuration at path
+func readConfig(t *testing.T, path string) map[string]interface{} {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("Failed to unmarshal config: %v", err)
+	}
+	return config
+}
+
+// verifyUninstall runs uninstall twice, checking that the first run removed want and that the
+// second run removes nothing
+func verifyUninstall(t *testing.T, uninstall func(*InstallOptions) ([]string, error), opts *InstallOptions, want []string) {
+	removed, err := uninstall(opts)
+	if err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+
+	if diff := cmp.Diff(want, removed); diff != "" {
+		t.Errorf("uninstall removed unexpected items. Diff:\n%v", diff)
+	}
+
+	removed, err = uninstall(opts)
+	if err != nil {
+		t.Fatalf("second uninstall failed: %v", err)
+	}
+
+	if len(removed) != 0 {
+		t.Errorf("Expected second uninstall to remove nothing, got %v", removed)
+	}
+}
+
+// verifyRemoved checks that the paths do not exist
+func verifyRemoved(t *testing.T, paths ...string) {
+	for _, path := range paths {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed, got %v", path, err)
+		}
+	}
+}
+
+func TestUninstallGeminiCLIExtension(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, false)
+	defer cleanup()
+
+	opts := &InstallOptions{
+		version:    "0.1.0-test",
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+	}
+	if err := GeminiCLIExtension(opts); err != nil {
+		t.Fatalf("GeminiCLIExtension() failed: %v", err)
+	}
+
+	extensionDir := filepath.Join(tmpDir, ".gemini", "extensions", "gke-mcp")
+	verifyUninstall(t, UninstallGeminiCLIExtension, opts, []string{extensionDir})
+	verifyRemoved(t, extensionDir)
+}
+
+func TestUninstallCursorMCPExtension(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, false)
+	defer cleanup()
+
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+	}
+	if err := CursorMCPExtension(opts); err != nil {
+		t.Fatalf("CursorMCPExtension() failed: %v", err)
+	}
+
+	// The configuration the installer created is removed with the server.
+	mcpPath := filepath.Join(tmpDir, ".cursor", "mcp.json")
+	rulePath := filepath.Join(tmpDir, ".cursor", "rules", "gke-mcp.mdc")
+	verifyUninstall(t, UninstallCursorMCPExtension, opts, []string{"gke-mcp server in " + mcpPath, rulePath})
+	verifyRemoved(t, mcpPath, rulePath)
+}
+
+func TestUninstallCursorMCPExtensionWithExistingConfig(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, false)
+	defer cleanup()
+
+	existingConfig := map[string]interface{}{
+		"mcpServers":   existingServers,
+		"otherSetting": "value",
+	}
+	mcpPath := createExistingConfig(t, filepath.Join(tmpDir, ".cursor"), existingConfig)
+
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+	}
+	if err := CursorMCPExtension(opts); err != nil {
+		t.Fatalf("CursorMCPExtension() failed: %v", err)
+	}
+
+	rulePath := filepath.Join(tmpDir, ".cursor", "rules", "gke-mcp.mdc")
+	verifyUninstall(t, UninstallCursorMCPExtension, opts, []string{"gke-mcp server in " + mcpPath, rulePath})
+
+	if diff := cmp.Diff(existingConfig, readConfig(t, mcpPath)); diff != "" {
+		t.Errorf("Expected the MCP configuration to return to its prior state. Diff:\n%v", diff)
+	}
+}
+
+func TestUninstallVSCodeMCPExtension(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, false)
+	defer cleanup()
+
+	existingConfig := map[string]interface{}{
+		"servers": existingServers,
+		"inputs":  []interface{}{},
+	}
+	mcpPath := createExistingConfig(t, filepath.Join(tmpDir, ".vscode"), existingConfig)
+
+	opts := &InstallOptions{
+		installDir:  tmpDir,
+		exePath:     "/usr/local/bin/gke-mcp",
+		projectOnly: true,
+	}
+	if err := VSCodeMCPExtension(opts); err != nil {
+		t.Fatalf("VSCodeMCPExtension() failed: %v", err)
+	}
+
+	instructionsPath := filepath.Join(tmpDir, ".github", "instructions", "gke-mcp.instructions.md")
+	verifyUninstall(t, UninstallVSCodeMCPExtension, opts, []string{"gke-mcp server in " + mcpPath, instructionsPath})
+	verifyRemoved(t, instructionsPath)
+
+	if diff := cmp.Diff(existingConfig, readConfig(t, mcpPath)); diff != "" {
+		t.Errorf("Expected the MCP configuration to return to its prior state. Diff:\n%v", diff)
+	}
+}
+
+func TestUninstallClaudeDesktopExtension(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, true)
+	defer cleanup()
+
+	cleanupEnv := mockAppData(t, tmpDir)
+	defer cleanupEnv()
+
+	configPath, err := getClaudeDesktopConfigPath()
+	if err != nil {
+		t.Fatalf("could not determine Claude Desktop config path: %v", err)
+	}
+
+	existingConfig := map[string]interface{}{
+		"mcpServers":   existingServers,
+		"otherSetting": "value",
+	}
+	createExistingClaudeConfig(t, configPath, existingConfig)
+
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+	}
+	if err := ClaudeDesktopExtension(opts); err != nil {
+		t.Fatalf("ClaudeDesktopExtension() failed: %v", err)
+	}
+
+	verifyUninstall(t, UninstallClaudeDesktopExtension, opts, []string{"gke-mcp server in " + configPath})
+
+	if diff := cmp.Diff(existingConfig, readConfig(t, configPath)); diff != "" {
+		t.Errorf("Expected the Claude Desktop configuration to return to its prior state. Diff:\n%v", diff)
+	}
+}
+
+func TestUninstallClaudeCodeExtension(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, false)
+	defer cleanup()
+
+	claudeMDPath := filepath.Join(tmpDir, "CLAUDE.md")
+	existingContent := "# Existing Content\nSome existing instructions."
+	if err := os.WriteFile(claudeMDPath, []byte(existingContent), 0644); err != nil {
+		t.Fatalf("Failed to create existing CLAUDE.md: %v", err)
+	}
+
+	logFile, cleanupCommand := MockClaudeCommand(t)
+	defer cleanupCommand()
+
+	cleanupInput := mockInput("yes\n")
+	defer cleanupInput()
+
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+	}
+	if err := ClaudeCodeExtension(opts); err != nil {
+		t.Fatalf("ClaudeCodeExtension() failed: %v", err)
+	}
+
+	// The mock claude command always finds the server, so only check the first run.
+	usageGuidePath := filepath.Join(tmpDir, "GKE_MCP_USAGE_GUIDE.md")
+	removed, err := UninstallClaudeCodeExtension(opts)
+	if err != nil {
+		t.Fatalf("UninstallClaudeCodeExtension() failed: %v", err)
+	}
+
+	want := []string{
+		"gke-mcp server registered in Claude Code",
+		"reference to GKE_MCP_USAGE_GUIDE.md in " + claudeMDPath,
+		usageGuidePath,
+	}
+	if diff := cmp.Diff(want, removed); diff != "" {
+		t.Errorf("UninstallClaudeCodeExtension() removed unexpected items. Diff:\n%v", diff)
+	}
+
+	verifyRemoved(t, usageGuidePath)
+
+	claudeContent, err := os.ReadFile(claudeMDPath)
+	if err != nil {
+		t.Fatalf("Failed to read CLAUDE.md: %v", err)
+	}
+
+	if string(claudeContent) != existingContent {
+		t.Errorf("Expected CLAUDE.md to return to its prior state, got %q", claudeContent)
+	}
+
+	logContent, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read command log: %v", err)
+	}
+
+	if !strings.Contains(string(logContent), "mcp remove gke-mcp") {
+		t.Errorf("Expected claude command to be called with args 'mcp remove gke-mcp', but log contains: %s", logContent)
+	}
+}
+
+func TestUninstallGooseExtension(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, true)
+	defer cleanup()
+
+	cleanupEnv := mockAppData(t, tmpDir)
+	defer cleanupEnv()
+
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+	}
+	configPath, err := opts.configPaths().gooseConfigPath()
+	if err != nil {
+		t.Fatalf("could not determine Goose config path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatalf("Failed to create Goose config dir: %v", err)
+	}
+	existing := "# Goose settings\nGOOSE_PROVIDER: openai\nextensions:\n  developer:\n    enabled: true\n"
+	if err := os.WriteFile(configPath, []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to write Goose config: %v", err)
+	}
+
+	if err := GooseExtension(opts); err != nil {
+		t.Fatalf("GooseExtension() failed: %v", err)
+	}
+
+	verifyUninstall(t, UninstallGooseExtension, opts, []string{"gke-mcp extension in " + configPath})
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read Goose config: %v", err)
+	}
+	if diff := cmp.Diff(existing, string(data)); diff != "" {
+		t.Errorf("Expected the Goose configuration to return to its prior state. Diff:\n%v", diff)
+	}
+}
+
+func TestUninstallJetBrainsExtension(t *testing.T) {
+	tmpDir, cleanup := testSetup(t, true)
+	defer cleanup()
+
+	cleanupEnv := mockAppData(t, tmpDir)
+	defer cleanupEnv()
+
+	opts := &InstallOptions{
+		installDir: tmpDir,
+		exePath:    "/usr/local/bin/gke-mcp",
+	}
+	jetbrainsDir, err := opts.configPaths().jetbrainsDir()
+	if err != nil {
+		t.Fatalf("could not determine JetBrains config path: %v", err)
+	}
+	existingConfig := map[string]interface{}{"mcpServers": existingServers}
+	goLandConfig := createExistingConfig(t, filepath.Join(jetbrainsDir, "GoLand2025.1"), existingConfig)
+	if err := os.MkdirAll(filepath.Join(jetbrainsDir, "PyCharm2024.3"), 0755); err != nil {
+		t.Fatalf("Failed to create PyCharm dir: %v", err)
+	}
+	pyCharmConfig := filepath.Join(jetbrainsDir, "PyCharm2024.3", "mcp.json")
+
+	if err := JetBrainsExtension(opts); err != nil {
+		t.Fatalf("JetBrainsExtension() failed: %v", err)
+	}
+
+	verifyUninstall(t, UninstallJetBrainsExtension, opts, []string{"gke-mcp server in " + goLandConfig, "gke-mcp server in " + pyCharmConfig})
+	verifyRemoved(t, pyCharmConfig)
+
+	if diff := cmp.Diff(existingConfig, readConfig(t, goLandConfig)); diff != "" {
+		t.Errorf("Expected the MCP configuration to return to its prior state. Diff:\n%v", diff)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// The YAML editing functions change a single member of the YAML configuration
+// files of the AI clients. Unlike the JSON ones they re-encode the file, but
+// keep its comments, key order and indentation.
+
+// setYAMLMember sets the member at path, e.g. extensions then gke-mcp, of the
+// YAML mapping in data to value. The mappings on the path are created if they
+// are missing, or replaced if they are not mappings.
+func setYAMLMember(data []byte, path []string, value interface{}) ([]byte, error) {
+	doc, err := parseYAMLMapping(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var encoded yaml.Node
+	if err := encoded.Encode(value); err != nil {
+		return nil, err
+	}
+
+	mapping := doc.Content[0]
+	for i, key := range path {
+		member := yamlMember(mapping, key)
+		if member == nil {
+			// Members added to an empty {} are written as a block
+			if len(mapping.Content) == 0 {
+				mapping.Style = 0
+			}
+			member = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, member)
+		}
+		if i == len(path)-1 {
+			// Keep the comments around the replaced value
+			encoded.HeadComment, encoded.LineComment, encoded.FootComment = member.HeadComment, member.LineComment, member.FootComment
+			*member = encoded
+			break
+		}
+		if member.Kind != yaml.MappingNode {
+			*member = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		mapping = member
+	}
+	return encodeYAML(doc, yamlIndent(data))
+}
+
+// deleteYAMLMember removes the member at path of the YAML mapping in data, and
+// reports whether it was there.
+func deleteYAMLMember(data []byte, path []string) ([]byte, bool, error) {
+	doc, err := parseYAMLMapping(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	mapping := doc.Content[0]
+	for _, key := range path[:len(path)-1] {
+		mapping = yamlMember(mapping, key)
+		if mapping == nil || mapping.Kind != yaml.MappingNode {
+			return data, false, nil
+		}
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == path[len(path)-1] {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			out, err := encodeYAML(doc, yamlIndent(data))
+			return out, true, err
+		}
+	}
+	return data, false, nil
+}
+
+// parseYAMLMapping parses data as a YAML document holding a mapping, an empty
+// one if data is empty
+func parseYAMLMapping(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a YAML mapping")
+	}
+	return &doc, nil
+}
+
+// yamlMember returns the value of the last member named key of mapping, which
+// is the one YAML decoders use, or nil if there is none
+func yamlMember(mapping *yaml.Node, key string) *yaml.Node {
+	for i := len(mapping.Content) - 2; i >= 0; i -= 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// encodeYAML encodes v, e.g. a YAML document, indented by indent spaces
+func encodeYAML(v interface{}, indent int) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// yamlIndent returns the indentation of the first indented mapping key of
+// data, or 2 spaces if there is none
+func yamlIndent(data []byte) int {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+		if indent == 0 || trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "-") {
+			continue
+		}
+		return indent
+	}
+	return 2
+}
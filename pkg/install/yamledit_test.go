@@ -0,0 +1,177 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package install
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// gkeMCPExtension is the gke-mcp extension the YAML files are merged with
+var gkeMCPExtension = map[string]interface{}{
+	"cmd":  "/usr/local/bin/gke-mcp",
+	"args": []string{"--verbose"},
+}
+
+func TestSetYAMLMember(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{
+			name: "keeps comments, order and indentation",
+			config: `# Goose configuration
+GOOSE_PROVIDER: openai # the default provider
+extensions:
+    developer:
+        enabled: true
+        name: developer
+GOOSE_MODEL: gpt-4o
+`,
+			want: `# Goose configuration
+GOOSE_PROVIDER: openai # the default provider
+extensions:
+    developer:
+        enabled: true
+        name: developer
+    gke-mcp:
+        args:
+            - --verbose
+        cmd: /usr/local/bin/gke-mcp
+GOOSE_MODEL: gpt-4o
+`,
+		},
+		{
+			name:   "empty file",
+			config: "",
+			want: `extensions:
+  gke-mcp:
+    args:
+      - --verbose
+    cmd: /usr/local/bin/gke-mcp
+`,
+		},
+		{
+			name:   "empty extensions",
+			config: "GOOSE_MODEL: gpt-4o\nextensions: {}\n",
+			want: `GOOSE_MODEL: gpt-4o
+extensions:
+  gke-mcp:
+    args:
+      - --verbose
+    cmd: /usr/local/bin/gke-mcp
+`,
+		},
+		{
+			name:   "extensions not a mapping",
+			config: "extensions: invalid\n",
+			want: `extensions:
+  gke-mcp:
+    args:
+      - --verbose
+    cmd: /usr/local/bin/gke-mcp
+`,
+		},
+		{
+			name: "replaces gke-mcp",
+			config: `extensions:
+  gke-mcp:
+    cmd: /old/gke-mcp
+  other:
+    enabled: false
+`,
+			want: `extensions:
+  gke-mcp:
+    args:
+      - --verbose
+    cmd: /usr/local/bin/gke-mcp
+  other:
+    enabled: false
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := setYAMLMember([]byte(tc.config), []string{"extensions", "gke-mcp"}, gkeMCPExtension)
+			if err != nil {
+				t.Fatalf("setYAMLMember() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("setYAMLMember() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetYAMLMemberMalformed(t *testing.T) {
+	for _, config := range []string{"- a list\n", "extensions: [\n"} {
+		if _, err := setYAMLMember([]byte(config), []string{"extensions", "gke-mcp"}, gkeMCPExtension); err == nil {
+			t.Errorf("setYAMLMember(%q) succeeded, want an error", config)
+		}
+	}
+}
+
+func TestDeleteYAMLMember(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    string
+		want      string
+		wantFound bool
+	}{
+		{
+			name: "keeps the other members",
+			config: `# Goose configuration
+extensions:
+  gke-mcp:
+    cmd: /usr/local/bin/gke-mcp
+  other:
+    enabled: false # disabled for now
+`,
+			want: `# Goose configuration
+extensions:
+  other:
+    enabled: false # disabled for now
+`,
+			wantFound: true,
+		},
+		{
+			name:   "missing",
+			config: "extensions:\n  other: {}\n",
+			want:   "extensions:\n  other: {}\n",
+		},
+		{
+			name:   "extensions not a mapping",
+			config: "extensions: invalid\n",
+			want:   "extensions: invalid\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, found, err := deleteYAMLMember([]byte(tc.config), []string{"extensions", "gke-mcp"})
+			if err != nil {
+				t.Fatalf("deleteYAMLMember() failed: %v", err)
+			}
+			if found != tc.wantFound {
+				t.Errorf("deleteYAMLMember() found = %v, want %v", found, tc.wantFound)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("deleteYAMLMember() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
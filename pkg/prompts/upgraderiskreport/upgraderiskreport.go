@@ -50,12 +50,12 @@ If 'Target Version' is not provided:
 Assume you have the ability to run the following commands to gather necessary information:
   - **Cluster Details:** Use ` + "`gcloud`" + ` to get cluster details like control plane version, release channel, node pool versions, etc.
   - **In-Cluster Resources:** Use ` + "`kubectl`" + ` (after ` + "`gcloud container clusters get-credentials`" + `) for inspecting workloads, APIs in use, etc.
-  - **Kubernetes Changelogs:** Use the ` + "`get_k8s_changelog`" + ` tool to fetch kubernetes changelogs.
-  - **GKE Release Notes:** Use the ` + "`get_gke_release_notes`" + ` tool to fetch GKE release notes.
+  - **Kubernetes Changelogs:** Use the ` + "`get_k8s_changelog_range`" + ` tool to fetch the kubernetes changelogs of all minor versions in one call, or the ` + "`get_k8s_changelog`" + ` tool for a single minor version.
+  - **GKE Release Notes:** Use the ` + "`get_gke_release_notes`" + ` tool to fetch GKE release notes. Pass the cluster's ` + "`name`" + ` (or its ` + "`channel`" + `) to get the release notes of its release channel only, and set ` + "`types`" + ` to ` + "`[breaking, deprecation, issue, change]`" + ` to get the risk-relevant notes only.
 
 **6. Changelog Analysis:**
   - **Minor Versions:** Include changelogs for ALL minor versions from the current control plane minor version up to AND INCLUDING the target minor version. (e.g., 1.29.x to 1.31.y requires looking at changes in 1.29, 1.30, 1.31).
-  - **Patch Versions:** Analyze changes for EVERY patch version BETWEEN the current version (exclusive) and the target version (inclusive). (e.g., 1.29.1 to 1.29.5 means analyzing 1.29.2, 1.29.3, 1.29.4, 1.29.5).
+  - **Patch Versions:** Analyze changes for EVERY patch version BETWEEN the current version (exclusive) and the target version (inclusive). (e.g., 1.29.1 to 1.29.5 means analyzing 1.29.2, 1.29.3, 1.29.4, 1.29.5). Use the ` + "`from_patch`" + ` and ` + "`to_patch`" + ` arguments of the ` + "`get_k8s_changelog`" + ` tool to only get these patch versions.
   - **GKE Versions:** Analyze changes for GKE version BETWEEN the current version (exclusive) and the target version (inclusive). (e.g., 1.29.1-gke.123000 to 1.29.5-gke.234000 means analyzing 1.29.1-gke.123500, 1.29.1-gke.124000 etc, and 1.29.5-gke.234000).
 
 **7. Risk Identification - Focus on:**
@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"fmt"
+
+	"google.golang.org/api/iterator"
+)
+
+// Collected holds the items read by Collect.
+type Collected[T any] struct {
+	Items []T
+	// Truncated is set if the iterator had more than the requested number of
+	// items.
+	Truncated bool
+	// Err is the error that ended the iteration after Items were read.
+	Err error
+}
+
+// Collect reads up to limit items from it. A limit of 0 or less reads all
+// items. An error before the first item fails the call, while a later error
+// is reported in Collected.Err together with the items read so far, so that
+// large listings still return partial results.
+func Collect[T any](it interface{ Next() (T, error) }, limit int) (*Collected[T], error) {
+	c := &Collected[T]{}
+	for {
+		v, err := it.Next()
+		if err == iterator.Done {
+			return c, nil
+		}
+		if err != nil {
+			if len(c.Items) == 0 {
+				return nil, err
+			}
+			c.Err = err
+			return c, nil
+		}
+		if limit > 0 && len(c.Items) == limit {
+			c.Truncated = true
+			return c, nil
+		}
+		c.Items = append(c.Items, v)
+	}
+}
+
+// PartialWarning returns a warning describing why the listing stopped early,
+// to be appended to the tool output, or "" if it completed.
+func (c *Collected[T]) PartialWarning() string {
+	if c.Err == nil {
+		return ""
+	}
+	if IsRetryable(c.Err) {
+		return fmt.Sprintf("\n\nWarning: Partial results: listing failed after %d items were retrieved and retries were exhausted: %v", len(c.Items), c.Err)
+	}
+	return fmt.Sprintf("\n\nWarning: Partial results: listing failed after %d items were retrieved: %v", len(c.Items), c.Err)
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCollect(t *testing.T) {
+	pages := [][]int{{1, 2}, {3, 4}, {5}}
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	permissionDenied := status.Error(codes.PermissionDenied, "denied")
+	tests := []struct {
+		name          string
+		limit         int
+		failAt        int
+		err           error
+		want          []int
+		wantTruncated bool
+		wantErr       error
+		wantPartial   error
+		wantWarning   string
+	}{
+		{
+			name:   "all items",
+			failAt: -1,
+			want:   []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:   "limit equals item count",
+			limit:  5,
+			failAt: -1,
+			want:   []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:          "limited",
+			limit:         3,
+			failAt:        -1,
+			want:          []int{1, 2, 3},
+			wantTruncated: true,
+		},
+		{
+			name:        "mid-stream retryable error",
+			failAt:      1,
+			err:         unavailable,
+			want:        []int{1, 2},
+			wantPartial: unavailable,
+			wantWarning: "listing failed after 2 items were retrieved and retries were exhausted",
+		},
+		{
+			name:        "mid-stream permanent error",
+			failAt:      2,
+			err:         permissionDenied,
+			want:        []int{1, 2, 3, 4},
+			wantPartial: permissionDenied,
+			wantWarning: "listing failed after 4 items were retrieved: ",
+		},
+		{
+			name:    "error before the first item",
+			failAt:  0,
+			err:     permissionDenied,
+			wantErr: permissionDenied,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			failures := 1
+			it := &fakePagedIterator{pages: pages, failAt: tc.failAt, failures: &failures, err: tc.err, pageInfo: &iterator.PageInfo{}}
+			got, err := Collect[int](it, tc.limit)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Collect() error = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !slices.Equal(got.Items, tc.want) {
+				t.Errorf("Collect() items = %v, want %v", got.Items, tc.want)
+			}
+			if got.Truncated != tc.wantTruncated {
+				t.Errorf("Collect() truncated = %v, want %v", got.Truncated, tc.wantTruncated)
+			}
+			if !errors.Is(got.Err, tc.wantPartial) {
+				t.Errorf("Collect() partial error = %v, want %v", got.Err, tc.wantPartial)
+			}
+			warning := got.PartialWarning()
+			if tc.wantWarning == "" && warning != "" || !strings.Contains(warning, tc.wantWarning) {
+				t.Errorf("PartialWarning() = %q, want it to contain %q", warning, tc.wantWarning)
+			}
+		})
+	}
+}
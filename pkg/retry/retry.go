@@ -0,0 +1,141 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retry retries GCP API calls that fail with quota or transient
+// availability errors.
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Policy describes how often and how fast a call is retried.
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultPolicy makes up to 3 attempts with exponential backoff starting at
+// half a second.
+var DefaultPolicy = Policy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+}
+
+// IsRetryable reports whether err is a quota or transient availability error
+// worth retrying.
+func IsRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.ResourceExhausted, codes.Unavailable:
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry (1-based), using
+// exponential backoff with full jitter.
+func (p Policy) backoff(retry int) time.Duration {
+	d := p.InitialBackoff << (retry - 1)
+	if d <= 0 || d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d) + 1
+}
+
+// wait sleeps for the backoff of the given retry or until ctx is done.
+func (p Policy) wait(ctx context.Context, retry int) error {
+	t := time.NewTimer(p.backoff(retry))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Do calls fn until it succeeds, fails with an error that isn't retryable,
+// the policy's attempts are used up, or ctx is done. The last error from fn is
+// returned.
+func Do(ctx context.Context, p Policy, fn func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || !IsRetryable(err) || attempt >= p.MaxAttempts {
+			return err
+		}
+		if waitErr := p.wait(ctx, attempt); waitErr != nil {
+			return err
+		}
+	}
+}
+
+// PagedIterator is implemented by the iterators returned by the GCP client
+// libraries' List methods.
+type PagedIterator[T any] interface {
+	Next() (T, error)
+	PageInfo() *iterator.PageInfo
+}
+
+// Iterator wraps a PagedIterator and, when Next fails with a retryable error,
+// recreates it starting from the page that failed to load.
+type Iterator[T any] struct {
+	ctx     context.Context
+	policy  Policy
+	newIter func(pageToken string) PagedIterator[T]
+	it      PagedIterator[T]
+}
+
+// NewIterator returns an Iterator over the iterator returned by
+// newIter(""). newIter is called again with the page token to resume from
+// whenever a page fails to load with a retryable error.
+func NewIterator[T any](ctx context.Context, p Policy, newIter func(pageToken string) PagedIterator[T]) *Iterator[T] {
+	return &Iterator[T]{
+		ctx:     ctx,
+		policy:  p,
+		newIter: newIter,
+		it:      newIter(""),
+	}
+}
+
+// Next returns the next item, retrying failed page loads according to the
+// policy. Once the retries are used up, the last error is returned.
+func (r *Iterator[T]) Next() (T, error) {
+	for attempt := 1; ; attempt++ {
+		v, err := r.it.Next()
+		if err == nil || err == iterator.Done || !IsRetryable(err) || attempt >= r.policy.MaxAttempts {
+			return v, err
+		}
+		// Pages are only fetched once the previous one is fully consumed, and a
+		// failed fetch leaves the page token untouched, so nothing is skipped or
+		// returned twice by resuming from it.
+		token := r.it.PageInfo().Token
+		if waitErr := r.policy.wait(r.ctx, attempt); waitErr != nil {
+			return v, err
+		}
+		r.it = r.newIter(token)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var testPolicy = Policy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     2 * time.Millisecond,
+}
+
+func TestDo(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	tests := []struct {
+		name         string
+		errs         []error
+		wantErr      error
+		wantAttempts int
+	}{
+		{
+			name:         "success on first attempt",
+			errs:         []error{nil},
+			wantAttempts: 1,
+		},
+		{
+			name:         "success after transient errors",
+			errs:         []error{unavailable, status.Error(codes.ResourceExhausted, "quota"), nil},
+			wantAttempts: 3,
+		},
+		{
+			name:         "attempts used up",
+			errs:         []error{unavailable, unavailable, unavailable, nil},
+			wantErr:      unavailable,
+			wantAttempts: 3,
+		},
+		{
+			name:         "non-retryable error",
+			errs:         []error{status.Error(codes.InvalidArgument, "bad filter"), nil},
+			wantErr:      status.Error(codes.InvalidArgument, "bad filter"),
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := Do(context.Background(), testPolicy, func() error {
+				err := tt.errs[attempts]
+				attempts++
+				return err
+			})
+			if status.Code(err) != status.Code(tt.wantErr) {
+				t.Errorf("Do() error = %v, want %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("Do() made %d attempts, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestDoStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	attempts := 0
+	err := Do(ctx, Policy{MaxAttempts: 3, InitialBackoff: time.Hour, MaxBackoff: time.Hour}, func() error {
+		attempts++
+		return status.Error(codes.Unavailable, "unavailable")
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("Do() = %v after %d attempts, want error after 1 attempt", err, attempts)
+	}
+}
+
+// fakePagedIterator serves pages of ints. Loading the page at index failAt
+// fails with err for the first failures loads across all iterators sharing the
+// same counter.
+type fakePagedIterator struct {
+	pages    [][]int
+	failAt   int
+	failures *int
+	err      error
+
+	pageInfo *iterator.PageInfo
+	page     int
+	buf      []int
+}
+
+func (it *fakePagedIterator) PageInfo() *iterator.PageInfo {
+	return it.pageInfo
+}
+
+func (it *fakePagedIterator) Next() (int, error) {
+	if len(it.buf) == 0 {
+		if it.page >= len(it.pages) {
+			return 0, iterator.Done
+		}
+		if it.page == it.failAt && *it.failures > 0 {
+			*it.failures--
+			return 0, it.err
+		}
+		it.buf = it.pages[it.page]
+		it.page++
+		it.pageInfo.Token = string(rune('0' + it.page))
+	}
+	v := it.buf[0]
+	it.buf = it.buf[1:]
+	return v, nil
+}
+
+func TestIterator(t *testing.T) {
+	pages := [][]int{{1, 2}, {3, 4}, {5}}
+	tests := []struct {
+		name     string
+		failures int
+		err      error
+		want     []int
+		wantErr  bool
+	}{
+		{
+			name: "no failures",
+			want: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:     "resumes from the failed page",
+			failures: 2,
+			err:      status.Error(codes.ResourceExhausted, "quota"),
+			want:     []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:     "gives up after max attempts",
+			failures: 3,
+			err:      status.Error(codes.Unavailable, "unavailable"),
+			want:     []int{1, 2},
+			wantErr:  true,
+		},
+		{
+			name:     "does not retry other errors",
+			failures: 1,
+			err:      errors.New("boom"),
+			want:     []int{1, 2},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := tt.failures
+			var tokens []string
+			it := NewIterator(context.Background(), testPolicy, func(pageToken string) PagedIterator[int] {
+				tokens = append(tokens, pageToken)
+				page := 0
+				if pageToken != "" {
+					page = int(pageToken[0] - '0')
+				}
+				return &fakePagedIterator{
+					pages:    pages,
+					failAt:   1,
+					failures: &failures,
+					err:      tt.err,
+					pageInfo: &iterator.PageInfo{Token: pageToken},
+					page:     page,
+				}
+			})
+
+			var got []int
+			var err error
+			for {
+				var v int
+				v, err = it.Next()
+				if err != nil {
+					break
+				}
+				got = append(got, v)
+			}
+			if (err != iterator.Done) != tt.wantErr {
+				t.Errorf("Next() final error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Next() returned %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Next() returned %v, want %v", got, tt.want)
+				}
+			}
+			for _, token := range tokens[1:] {
+				if token != "1" {
+					t.Errorf("iterator recreated with page token %q, want %q", token, "1")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+)
+
+// applyFieldManager is the field manager of the server-side applies.
+const applyFieldManager = "gke-mcp"
+
+type applyManifestArgs struct {
+	ProjectID       string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location        string `json:"location" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Name            string `json:"name" jsonschema:"GKE cluster name. Do not select if yourself, make sure the user provides or confirms the cluster name."`
+	Namespace       string `json:"namespace,omitempty" jsonschema:"Namespace of the namespaced objects of the manifest that don't set one. Defaults to default."`
+	Manifest        string `json:"manifest" jsonschema:"The YAML manifest to apply, e.g. the output of giq_generate_manifest. Several objects are separated by ---."`
+	DryRun          bool   `json:"dry_run,omitempty" jsonschema:"Only do a server-side dry run of the apply, without changing the cluster."`
+	CreateNamespace bool   `json:"create_namespace,omitempty" jsonschema:"Create the namespaces of the objects if they don't exist. Without it the apply is refused if a namespace is missing."`
+	ForceConflicts  bool   `json:"force_conflicts,omitempty" jsonschema:"Take over fields managed by others, e.g. replicas set by a HorizontalPodAutoscaler or fields changed with kubectl. Without it the apply fails on such conflicts. Only set it after the user confirmed overriding them."`
+}
+
+// applyResult is the outcome of applying one object.
+type applyResult struct {
+	object string
+	action string
+}
+
+func installApplyManifestTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "apply_manifest",
+		Description: "Apply a Kubernetes YAML manifest, e.g. one generated by giq_generate_manifest, to a GKE cluster with server-side apply, and report the created and updated objects. Confirm the cluster and namespace with the user, and prefer a dry_run first.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   false,
+			IdempotentHint: true,
+		},
+	}, h.applyManifest)
+}
+
+func (h *handlers) applyManifest(ctx context.Context, _ *mcp.CallToolRequest, args *applyManifestArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Namespace == "" {
+		args.Namespace = corev1.NamespaceDefault
+	}
+	if args.Name == "" {
+		return nil, nil, fmt.Errorf("name argument cannot be empty")
+	}
+	location, err := h.c.ResolveClusterLocation(ctx, args.ProjectID, args.Location, args.Name, h.clusterLocations)
+	if err != nil {
+		return nil, nil, err
+	}
+	args.Location = location
+	if strings.TrimSpace(args.Manifest) == "" {
+		return nil, nil, fmt.Errorf("manifest argument cannot be empty")
+	}
+	objs, err := parseManifestObjects(args.Manifest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg, err := h.restConfig(ctx, args.ProjectID, args.Location, args.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	kc, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	dc, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	disc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(disc))
+
+	mappings := make([]*meta.RESTMapping, len(objs))
+	for i, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		mappings[i], err = mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to find the resource of %s: %w", describeObject(obj), err)
+		}
+		if mappings[i].Scope.Name() == meta.RESTScopeNameNamespace && obj.GetNamespace() == "" {
+			obj.SetNamespace(args.Namespace)
+		}
+	}
+
+	var dryRun []string
+	if args.DryRun {
+		dryRun = []string{metav1.DryRunAll}
+	}
+	var results []applyResult
+	// missing holds the namespaces that don't exist yet. A dry run cannot
+	// validate the objects in them.
+	missing := map[string]bool{}
+	for _, ns := range objectNamespaces(objs) {
+		_, err := kc.CoreV1().Namespaces().Get(ctx, ns, metav1.GetOptions{})
+		if err == nil {
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("failed to get namespace %s: %w", ns, err)
+		}
+		missing[ns] = true
+		if slices.ContainsFunc(objs, func(obj *unstructured.Unstructured) bool { return isNamespace(obj) && obj.GetName() == ns }) {
+			continue
+		}
+		if !args.CreateNamespace {
+			return nil, nil, fmt.Errorf("namespace %s does not exist; confirm with the user and set create_namespace to create it", ns)
+		}
+		_, err = kc.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}, metav1.CreateOptions{DryRun: dryRun, FieldManager: applyFieldManager})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create namespace %s: %w", ns, err)
+		}
+		results = append(results, applyResult{object: "Namespace " + ns, action: "created"})
+	}
+
+	for i, obj := range objs {
+		if args.DryRun && missing[obj.GetNamespace()] {
+			results = append(results, applyResult{object: describeObject(obj), action: "created (not validated, its namespace does not exist yet)"})
+			continue
+		}
+		var ri dynamic.ResourceInterface = dc.Resource(mappings[i].Resource)
+		if mappings[i].Scope.Name() == meta.RESTScopeNameNamespace {
+			ri = dc.Resource(mappings[i].Resource).Namespace(obj.GetNamespace())
+		}
+		action := "configured"
+		if _, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{}); apierrors.IsNotFound(err) {
+			action = "created"
+		}
+		if _, err := ri.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: applyFieldManager, Force: args.ForceConflicts, DryRun: dryRun}); err != nil {
+			if apierrors.IsConflict(err) {
+				err = fmt.Errorf("%w; the fields are managed by others, confirm with the user and set force_conflicts to take them over", err)
+			}
+			return nil, nil, fmt.Errorf("failed to apply %s: %w\n\n%s", describeObject(obj), err, formatApplyResults(results, args.DryRun))
+		}
+		results = append(results, applyResult{object: describeObject(obj), action: action})
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatApplyResults(results, args.DryRun)},
+		},
+	}, nil, nil
+}
+
+// parseManifestObjects decodes the YAML or JSON documents of a manifest. The
+// Namespaces are moved first so that the other objects can be created in them.
+func parseManifestObjects(manifest string) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+	var objs []*unstructured.Unstructured
+	for {
+		obj := &unstructured.Unstructured{}
+		err := decoder.Decode(&obj.Object)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetAPIVersion() == "" || obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, fmt.Errorf("failed to parse manifest: object without apiVersion, kind or metadata.name: %v", obj.Object)
+		}
+		objs = append(objs, obj)
+	}
+	if len(objs) == 0 {
+		return nil, fmt.Errorf("the manifest has no objects")
+	}
+	slices.SortStableFunc(objs, func(a, b *unstructured.Unstructured) int {
+		return boolToInt(!isNamespace(a)) - boolToInt(!isNamespace(b))
+	})
+	return objs, nil
+}
+
+// objectNamespaces returns the namespaces of objs, in order.
+func objectNamespaces(objs []*unstructured.Unstructured) []string {
+	var namespaces []string
+	for _, obj := range objs {
+		if ns := obj.GetNamespace(); ns != "" && !slices.Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+func isNamespace(obj *unstructured.Unstructured) bool {
+	return obj.GetAPIVersion() == "v1" && obj.GetKind() == "Namespace"
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func describeObject(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() != "" {
+		return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	}
+	return fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+}
+
+func formatApplyResults(results []applyResult, dryRun bool) string {
+	builder := new(strings.Builder)
+	if dryRun {
+		builder.WriteString("Dry run, the cluster was not changed.\n")
+	}
+	if len(results) == 0 {
+		builder.WriteString("No objects applied.\n")
+		return builder.String()
+	}
+	for _, r := range results {
+		fmt.Fprintf(builder, "%s %s\n", r.object, r.action)
+	}
+	return builder.String()
+}
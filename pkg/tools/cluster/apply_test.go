@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseManifestObjects(t *testing.T) {
+	manifest := `# Generated by GIQ.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: vllm
+  namespace: llm
+---
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: vllm
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: llm
+`
+	objs, err := parseManifestObjects(manifest)
+	if err != nil {
+		t.Fatalf("parseManifestObjects() error = %v", err)
+	}
+	var got []string
+	for _, obj := range objs {
+		got = append(got, describeObject(obj))
+	}
+	want := []string{"Namespace llm", "Deployment llm/vllm", "Service vllm"}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseManifestObjects() = %v, want %v", got, want)
+	}
+	if got, want := objectNamespaces(objs), []string{"llm"}; !slices.Equal(got, want) {
+		t.Errorf("objectNamespaces() = %v, want %v", got, want)
+	}
+}
+
+func TestParseManifestObjectsErrors(t *testing.T) {
+	for name, manifest := range map[string]string{
+		"no objects":   "# nothing to apply\n---\n",
+		"without kind": "apiVersion: v1\nmetadata:\n  name: x\n",
+		"without name": "apiVersion: v1\nkind: ConfigMap\n",
+		"invalid yaml": "apiVersion: v1\nkind: [ConfigMap\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseManifestObjects(manifest); err == nil {
+				t.Errorf("parseManifestObjects() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestFormatApplyResults(t *testing.T) {
+	results := []applyResult{
+		{object: "Namespace llm", action: "created"},
+		{object: "Deployment llm/vllm", action: "configured"},
+	}
+	if got, want := formatApplyResults(results, false), "Namespace llm created\nDeployment llm/vllm configured\n"; got != want {
+		t.Errorf("formatApplyResults() = %q, want %q", got, want)
+	}
+	if got, want := formatApplyResults(nil, true), "Dry run, the cluster was not changed.\nNo objects applied.\n"; got != want {
+		t.Errorf("formatApplyResults() = %q, want %q", got, want)
+	}
+}
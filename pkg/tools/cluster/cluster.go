@@ -29,17 +29,19 @@ import (
 
 	container "cloud.google.com/go/container/apiv1"
 	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/command"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
-	"google.golang.org/api/option"
 	"google.golang.org/protobuf/encoding/protojson"
 	"k8s.io/client-go/tools/clientcmd"
 	k8sClientApi "k8s.io/client-go/tools/clientcmd/api"
 )
 
 type handlers struct {
-	c        *config.Config
-	cmClient *container.ClusterManagerClient
+	c            *config.Config
+	cmClient     *container.ClusterManagerClient
+	metricClient *monitoring.MetricClient
 }
 
 type listClustersArgs struct {
@@ -65,18 +67,27 @@ type getNodeSosReportArgs struct {
 	Destination    string `json:"destination,omitempty" jsonschema:"Local directory to download the SOS report to. Defaults to /tmp/sos-report if not specified."`
 	Method         string `json:"method,omitempty" jsonschema:"Method to get sos report. Can be 'pod', 'ssh' or 'any'. Defaults to 'any'. When the node is unhealthy from api server, use ssh only."`
 	TimeoutSeconds int    `json:"timeout,omitempty" jsonschema:"Timeout in seconds for the report collection (applies to both pod and ssh methods). Defaults to 180 (3 minutes)."`
+	ProjectID      string `json:"project_id,omitempty" jsonschema:"GCP project ID of the node, used by the ssh method. Use the default if the user doesn't provide it."`
 }
 
 func Install(ctx context.Context, s *mcp.Server, c *config.Config) error {
 
-	cmClient, err := container.NewClusterManagerClient(ctx, option.WithUserAgent(c.UserAgent()))
+	cmClient, err := container.NewClusterManagerClient(ctx, c.ClientOptions()...)
 	if err != nil {
 		return fmt.Errorf("failed to create cluster manager client: %w", err)
 	}
+	c.OnClose(cmClient.Close)
+
+	metricClient, err := monitoring.NewMetricClient(ctx, c.ClientOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to create metric client: %w", err)
+	}
+	c.OnClose(metricClient.Close)
 
 	h := &handlers{
-		c:        c,
-		cmClient: cmClient,
+		c:            c,
+		cmClient:     cmClient,
+		metricClient: metricClient,
 	}
 
 	mcp.AddTool(s, &mcp.Tool{
@@ -95,6 +106,14 @@ func Install(ctx context.Context, s *mcp.Server, c *config.Config) error {
 		},
 	}, h.getCluster)
 
+	installClusterResourceUtilizationTool(s, h)
+	installHPAInspectionTool(s, h)
+
+	// The tools that write the kubeconfig, run commands on nodes or change
+	// the cluster are left out in read-only mode.
+	if c.ReadOnly() {
+		return nil
+	}
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "get_kubeconfig",
 		Description: "Get the kubeconfig for a GKE cluster by calling the GKE API and extracting necessary details (clusterCaCertificate and endpoint). This tool appends/updates the kubeconfig in ~/.kube/config.",
@@ -108,6 +127,8 @@ func Install(ctx context.Context, s *mcp.Server, c *config.Config) error {
 		Description: "Generate and download an SOS report from a GKE node. Can use 'pod', 'ssh' or 'any' methods. Defaults to 'any' (pod with fallback to ssh). Use 'ssh' if node is API-unhealthy.",
 	}, h.getNodeSosReport)
 
+	installApplyManifestTool(s, h)
+
 	return nil
 }
 
@@ -141,12 +162,14 @@ func (h *handlers) getCluster(ctx context.Context, _ *mcp.CallToolRequest, args
 	if args.ProjectID == "" {
 		args.ProjectID = h.c.DefaultProjectID()
 	}
-	if args.Location == "" {
-		args.Location = h.c.DefaultLocation()
-	}
 	if args.Name == "" {
 		return nil, nil, fmt.Errorf("name argument cannot be empty")
 	}
+	location, err := h.c.ResolveClusterLocation(ctx, args.ProjectID, args.Location, args.Name, h.clusterLocations)
+	if err != nil {
+		return nil, nil, err
+	}
+	args.Location = location
 
 	req := &containerpb.GetClusterRequest{
 		Name: fmt.Sprintf("projects/%s/locations/%s/clusters/%s", args.ProjectID, args.Location, args.Name),
@@ -163,18 +186,38 @@ func (h *handlers) getCluster(ctx context.Context, _ *mcp.CallToolRequest, args
 	}, nil, nil
 }
 
+// clusterLocations returns the locations of the clusters named name in
+// projectID.
+func (h *handlers) clusterLocations(ctx context.Context, projectID, name string) ([]string, error) {
+	resp, err := h.cmClient.ListClusters(ctx, &containerpb.ListClustersRequest{
+		Parent: fmt.Sprintf("projects/%s/locations/-", projectID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var locations []string
+	for _, c := range resp.GetClusters() {
+		if c.GetName() == name {
+			locations = append(locations, c.GetLocation())
+		}
+	}
+	return locations, nil
+}
+
 // getKubeconfig retrieves GKE cluster details and constructs a kubeconfig file.
 // It appends/updates the configuration in the user's ~/.kube/config file.
 func (h *handlers) getKubeconfig(ctx context.Context, _ *mcp.CallToolRequest, args *getKubeconfigArgs) (*mcp.CallToolResult, any, error) {
 	if args.ProjectID == "" {
 		args.ProjectID = h.c.DefaultProjectID()
 	}
-	if args.Location == "" {
-		args.Location = h.c.DefaultLocation()
-	}
 	if args.Name == "" {
 		return nil, nil, fmt.Errorf("name argument cannot be empty")
 	}
+	location, err := h.c.ResolveClusterLocation(ctx, args.ProjectID, args.Location, args.Name, h.clusterLocations)
+	if err != nil {
+		return nil, nil, err
+	}
+	args.Location = location
 
 	req := &containerpb.GetClusterRequest{
 		Name: fmt.Sprintf("projects/%s/locations/%s/clusters/%s", args.ProjectID, args.Location, args.Name),
@@ -429,10 +472,25 @@ func (h *handlers) getNodeSosReportWithPod(ctx context.Context, args *getNodeSos
 }
 
 func (h *handlers) getNodeSosReportWithSSH(ctx context.Context, args *getNodeSosReportArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	// ssh returns the gcloud compute ssh command running remoteCommand on the
+	// node.
+	ssh := func(zone, remoteCommand string) *exec.Cmd {
+		return command.Gcloud{
+			Args:    []string{"compute", "ssh", "--zone", zone, args.Node, "--command", remoteCommand},
+			Project: args.ProjectID,
+		}.Command(ctx)
+	}
+
 	// 1. Find the zone of the VM
 	// gcloud compute instances list --filter="name=NODE_NAME" --format="value(zone)"
-	findZoneCmd := exec.CommandContext(ctx, "gcloud", "compute", "instances", "list", fmt.Sprintf("--filter=name=%s", args.Node), "--format=value(zone)")
-	zoneOut, err := findZoneCmd.Output()
+	zoneOut, err := command.Gcloud{
+		Args:    []string{"compute", "instances", "list", fmt.Sprintf("--filter=name=%s", args.Node)},
+		Project: args.ProjectID,
+		Format:  "value(zone)",
+	}.Run(ctx, 0)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to find zone for node %s using gcloud: %w", args.Node, err)
 	}
@@ -443,7 +501,7 @@ func (h *handlers) getNodeSosReportWithSSH(ctx context.Context, args *getNodeSos
 
 	// 2. Generate SOS report via SSH
 	// gcloud compute ssh --zone "ZONE" "NODE_NAME" --command "sudo sos report --all-logs --batch --tmp-dir=/var"
-	sshCmd := exec.CommandContext(ctx, "gcloud", "compute", "ssh", "--zone", zone, args.Node, "--command", "sudo sos report --all-logs --batch --tmp-dir=/var")
+	sshCmd := ssh(zone, "sudo sos report --all-logs --batch --tmp-dir=/var")
 	outBytes, err := sshCmd.CombinedOutput()
 	output := string(outBytes)
 	if err != nil {
@@ -461,7 +519,7 @@ func (h *handlers) getNodeSosReportWithSSH(ctx context.Context, args *getNodeSos
 
 	// 4. Change ownership of the file
 	// gcloud compute ssh ... --command "sudo chown $USER REMOTE_PATH"
-	chownCmd := exec.CommandContext(ctx, "gcloud", "compute", "ssh", "--zone", zone, args.Node, "--command", fmt.Sprintf("sudo chown $USER %s", remotePath))
+	chownCmd := ssh(zone, fmt.Sprintf("sudo chown $USER %s", remotePath))
 	if out, err := chownCmd.CombinedOutput(); err != nil {
 		return nil, nil, fmt.Errorf("failed to chown remote file: %s, %w", string(out), err)
 	}
@@ -470,13 +528,16 @@ func (h *handlers) getNodeSosReportWithSSH(ctx context.Context, args *getNodeSos
 	// gcloud compute scp --zone "ZONE" "NODE_NAME:REMOTE_PATH" LOCAL_DESTINATION
 	localFilename := fmt.Sprintf("sosreport-%s-%s.tar.xz", args.Node, time.Now().Format("2006-01-02-15-04-05"))
 	localPath := filepath.Join(args.Destination, localFilename)
-	scpCmd := exec.CommandContext(ctx, "gcloud", "compute", "scp", "--zone", zone, fmt.Sprintf("%s:%s", args.Node, remotePath), localPath)
+	scpCmd := command.Gcloud{
+		Args:    []string{"compute", "scp", "--zone", zone, fmt.Sprintf("%s:%s", args.Node, remotePath), localPath},
+		Project: args.ProjectID,
+	}.Command(ctx)
 	if out, err := scpCmd.CombinedOutput(); err != nil {
 		return nil, nil, fmt.Errorf("failed to scp file: %s, %w", string(out), err)
 	}
 
 	// 6. Cleanup remote files on host
-	rmCmd := exec.CommandContext(ctx, "gcloud", "compute", "ssh", "--zone", zone, args.Node, "--command", fmt.Sprintf("sudo rm %s", remotePath))
+	rmCmd := ssh(zone, fmt.Sprintf("sudo rm %s", remotePath))
 	rmCmd.Run()
 
 	return &mcp.CallToolResult{
@@ -0,0 +1,71 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+)
+
+func TestSosReportWithSSHGcloudCommands(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "gcloud.log")
+	script := `#!/bin/sh
+echo "$CLOUDSDK_CORE_DISABLE_PROMPTS $*" >> ` + log + `
+case "$*" in
+*"instances list"*) echo us-central1-a ;;
+*"sos report"*) echo "Your sosreport has been generated and saved in: /var/sosreport-node-1.tar.xz" ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "gcloud"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.ProjectIDEnv, "default-project")
+	c, err := config.New("test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &handlers{c: c}
+	args := &getNodeSosReportArgs{Node: "node-1", Destination: dir}
+	if _, _, err := h.getNodeSosReportWithSSH(context.Background(), args); err != nil {
+		t.Fatalf("getNodeSosReportWithSSH() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"1 compute instances list --filter=name=node-1 --project=default-project --format=value(zone)",
+		"1 compute ssh --zone us-central1-a node-1 --command sudo sos report --all-logs --batch --tmp-dir=/var --project=default-project",
+		"1 compute ssh --zone us-central1-a node-1 --command sudo chown $USER /var/sosreport-node-1.tar.xz --project=default-project",
+		"1 compute scp --zone us-central1-a node-1:/var/sosreport-node-1.tar.xz " + filepath.Join(dir, "sosreport-node-1-TIME.tar.xz") + " --project=default-project",
+		"1 compute ssh --zone us-central1-a node-1 --command sudo rm /var/sosreport-node-1.tar.xz --project=default-project",
+	}
+	// The local file name has the time of the download.
+	got := regexp.MustCompile(`sosreport-node-1-[0-9-]+\.tar`).ReplaceAllString(strings.TrimSpace(string(data)), "sosreport-node-1-TIME.tar")
+	if got != strings.Join(want, "\n") {
+		t.Errorf("getNodeSosReportWithSSH() ran gcloud with:\n%s\nwant:\n%s", got, strings.Join(want, "\n"))
+	}
+}
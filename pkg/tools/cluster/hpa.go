@@ -0,0 +1,413 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	hpaMetricWindow = 30 * time.Minute
+	// maxHPAPods bounds the pod_name one_of() filter of pod metrics.
+	maxHPAPods = 100
+)
+
+type hpaInspectionArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location  string `json:"location" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Name      string `json:"name" jsonschema:"GKE cluster name. Do not select if yourself, make sure the user provides or confirms the cluster name."`
+	Namespace string `json:"namespace" jsonschema:"Namespace of the HorizontalPodAutoscaler."`
+	HPAName   string `json:"hpa_name" jsonschema:"Name of the HorizontalPodAutoscaler."`
+}
+
+// hpaMetricQuery is the Cloud Monitoring query backing one HPA metric.
+type hpaMetricQuery struct {
+	// index of the metric in the HPA spec.
+	index       int
+	description string
+	filter      string
+	reducer     monitoringpb.Aggregation_Reducer
+}
+
+func installHPAInspectionTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "hpa_inspection",
+		Description: "Inspect a HorizontalPodAutoscaler of a GKE cluster to find out why it does or doesn't scale: replicas, conditions such as ScalingLimited or FailedGetResourceMetric, metric targets and current values, and the last 30 minutes of the underlying Cloud Monitoring metrics.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.hpaInspection)
+}
+
+func (h *handlers) hpaInspection(ctx context.Context, _ *mcp.CallToolRequest, args *hpaInspectionArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Name == "" {
+		return nil, nil, fmt.Errorf("name argument cannot be empty")
+	}
+	location, err := h.c.ResolveClusterLocation(ctx, args.ProjectID, args.Location, args.Name, h.clusterLocations)
+	if err != nil {
+		return nil, nil, err
+	}
+	args.Location = location
+	if args.Namespace == "" {
+		return nil, nil, fmt.Errorf("namespace argument cannot be empty")
+	}
+	if args.HPAName == "" {
+		return nil, nil, fmt.Errorf("hpa_name argument cannot be empty")
+	}
+
+	kc, err := h.kubernetesClient(ctx, args.ProjectID, args.Location, args.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	hpa, err := kc.AutoscalingV2().HorizontalPodAutoscalers(args.Namespace).Get(ctx, args.HPAName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get HorizontalPodAutoscaler %s/%s: %w", args.Namespace, args.HPAName, err)
+	}
+
+	builder := new(strings.Builder)
+	builder.WriteString(formatHPAStatus(hpa))
+
+	var notes []string
+	pods, err := scaleTargetPods(ctx, kc, hpa)
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("Pods of the scale target could not be listed, pod metrics are not shown: %v", err))
+	}
+	queries, skipped := hpaMetricQueries(hpa, args.Name, pods)
+	notes = append(notes, skipped...)
+	if len(queries) > 0 {
+		fmt.Fprintf(builder, "\nCloud Monitoring values over the last %s (1m steps, oldest first):\n", hpaMetricWindow)
+		values, err := h.hpaMetricValues(ctx, args.ProjectID, queries)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("Cloud Monitoring could not be queried: %v", err))
+		}
+		for _, q := range queries {
+			fmt.Fprintf(builder, "- Metric %d, %s:\n  filter: %s\n", q.index+1, q.description, q.filter)
+			series := values[q.index]
+			if len(series) == 0 {
+				builder.WriteString("  no data\n")
+			}
+			for _, s := range series {
+				fmt.Fprintf(builder, "  %s\n", s)
+			}
+		}
+	}
+	for _, note := range notes {
+		fmt.Fprintf(builder, "\nNote: %s\n", note)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: builder.String()},
+		},
+	}, nil, nil
+}
+
+// scaleTargetPods returns the names of the pods selected by the scale target
+// of the HPA.
+func scaleTargetPods(ctx context.Context, kc kubernetes.Interface, hpa *autoscalingv2.HorizontalPodAutoscaler) ([]string, error) {
+	ref := hpa.Spec.ScaleTargetRef
+	var scale *autoscalingv1.Scale
+	var err error
+	switch ref.Kind {
+	case "Deployment":
+		scale, err = kc.AppsV1().Deployments(hpa.Namespace).GetScale(ctx, ref.Name, metav1.GetOptions{})
+	case "StatefulSet":
+		scale, err = kc.AppsV1().StatefulSets(hpa.Namespace).GetScale(ctx, ref.Name, metav1.GetOptions{})
+	case "ReplicaSet":
+		scale, err = kc.AppsV1().ReplicaSets(hpa.Namespace).GetScale(ctx, ref.Name, metav1.GetOptions{})
+	default:
+		return nil, fmt.Errorf("scale target kind %s is not supported", ref.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	list, err := kc.CoreV1().Pods(hpa.Namespace).List(ctx, metav1.ListOptions{LabelSelector: scale.Status.Selector})
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]string, 0, len(list.Items))
+	for _, p := range list.Items {
+		pods = append(pods, p.Name)
+	}
+	sort.Strings(pods)
+	return pods, nil
+}
+
+// hpaMetricQueries maps the metrics of the HPA to Cloud Monitoring filters.
+// Metrics which cannot be mapped are reported as notes.
+func hpaMetricQueries(hpa *autoscalingv2.HorizontalPodAutoscaler, clusterName string, pods []string) ([]hpaMetricQuery, []string) {
+	var queries []hpaMetricQuery
+	var notes []string
+
+	podFilter := ""
+	if len(pods) > 0 {
+		quoted := make([]string, 0, min(len(pods), maxHPAPods))
+		for _, p := range pods[:min(len(pods), maxHPAPods)] {
+			quoted = append(quoted, strconv.Quote(p))
+		}
+		podFilter = fmt.Sprintf(`resource.label.cluster_name=%s AND resource.label.namespace_name=%s AND resource.label.pod_name=one_of(%s)`,
+			strconv.Quote(clusterName), strconv.Quote(hpa.Namespace), strings.Join(quoted, ","))
+		if len(pods) > maxHPAPods {
+			notes = append(notes, fmt.Sprintf("Pod metrics only cover the first %d of %d pods.", maxHPAPods, len(pods)))
+		}
+	}
+
+	for i, m := range hpa.Spec.Metrics {
+		switch m.Type {
+		case autoscalingv2.ResourceMetricSourceType, autoscalingv2.ContainerResourceMetricSourceType:
+			name, container := corev1.ResourceName(""), ""
+			if m.Resource != nil {
+				name = m.Resource.Name
+			}
+			if m.ContainerResource != nil {
+				name, container = m.ContainerResource.Name, m.ContainerResource.Container
+			}
+			if podFilter == "" {
+				notes = append(notes, fmt.Sprintf("Metric %d: no pods found for the scale target.", i+1))
+				continue
+			}
+			if name != corev1.ResourceCPU && name != corev1.ResourceMemory {
+				notes = append(notes, fmt.Sprintf("Metric %d: resource %s has no Cloud Monitoring equivalent.", i+1, name))
+				continue
+			}
+			filter := fmt.Sprintf(`metric.type="kubernetes.io/container/%s/request_utilization" AND resource.type="k8s_container" AND %s`, name, podFilter)
+			description := fmt.Sprintf("mean %s request utilization of the pods", name)
+			if container != "" {
+				filter += fmt.Sprintf(" AND resource.label.container_name=%s", strconv.Quote(container))
+				description = fmt.Sprintf("mean %s request utilization of container %s", name, container)
+			}
+			queries = append(queries, hpaMetricQuery{index: i, description: description, filter: filter, reducer: monitoringpb.Aggregation_REDUCE_MEAN})
+		case autoscalingv2.PodsMetricSourceType:
+			if podFilter == "" {
+				notes = append(notes, fmt.Sprintf("Metric %d: no pods found for the scale target.", i+1))
+				continue
+			}
+			queries = append(queries, hpaMetricQuery{
+				index:       i,
+				description: fmt.Sprintf("mean of %s over the pods", m.Pods.Metric.Name),
+				filter:      fmt.Sprintf("metric.type=%s AND %s", strconv.Quote(adapterMetricType(m.Pods.Metric.Name)), podFilter),
+				reducer:     monitoringpb.Aggregation_REDUCE_MEAN,
+			})
+		case autoscalingv2.ExternalMetricSourceType:
+			filter := fmt.Sprintf("metric.type=%s", strconv.Quote(adapterMetricType(m.External.Metric.Name)))
+			if sel := m.External.Metric.Selector; sel != nil {
+				keys := make([]string, 0, len(sel.MatchLabels))
+				for k := range sel.MatchLabels {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					filter += fmt.Sprintf(" AND %s=%s", adapterLabelKey(k), strconv.Quote(sel.MatchLabels[k]))
+				}
+			}
+			queries = append(queries, hpaMetricQuery{index: i, description: fmt.Sprintf("external metric %s", m.External.Metric.Name), filter: filter})
+		default:
+			notes = append(notes, fmt.Sprintf("Metric %d: %s metrics are not looked up in Cloud Monitoring.", i+1, m.Type))
+		}
+	}
+	return queries, notes
+}
+
+// adapterMetricType converts a metric name as used by the Custom Metrics
+// Stackdriver Adapter, e.g. "pubsub.googleapis.com|subscription|num_undelivered_messages",
+// into a Cloud Monitoring metric type. Names without a domain are custom
+// metrics.
+func adapterMetricType(name string) string {
+	if !strings.Contains(name, "|") {
+		return "custom.googleapis.com/" + name
+	}
+	return strings.ReplaceAll(name, "|", "/")
+}
+
+// adapterLabelKey converts a selector key of the adapter, e.g.
+// "resource.labels.subscription_id", into a monitoring filter label.
+func adapterLabelKey(key string) string {
+	key = strings.Replace(key, "resource.labels.", "resource.label.", 1)
+	return strings.Replace(key, "metric.labels.", "metric.label.", 1)
+}
+
+// hpaMetricValues queries the series of every query, keyed by metric index,
+// formatted as one line per series.
+func (h *handlers) hpaMetricValues(ctx context.Context, projectID string, queries []hpaMetricQuery) (map[int][]string, error) {
+	now := time.Now()
+	values := map[int][]string{}
+	for _, q := range queries {
+		req := &monitoringpb.ListTimeSeriesRequest{
+			Name:   fmt.Sprintf("projects/%s", projectID),
+			Filter: q.filter,
+			Interval: &monitoringpb.TimeInterval{
+				StartTime: timestamppb.New(now.Add(-hpaMetricWindow)),
+				EndTime:   timestamppb.New(now),
+			},
+			Aggregation: &monitoringpb.Aggregation{
+				AlignmentPeriod:    durationpb.New(time.Minute),
+				PerSeriesAligner:   monitoringpb.Aggregation_ALIGN_MEAN,
+				CrossSeriesReducer: q.reducer,
+			},
+			View: monitoringpb.ListTimeSeriesRequest_FULL,
+		}
+		it := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*monitoringpb.TimeSeries] {
+			req.PageToken = pageToken
+			return h.metricClient.ListTimeSeries(ctx, req)
+		})
+		for {
+			ts, err := it.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return values, err
+			}
+			values[q.index] = append(values[q.index], formatHPASeries(ts))
+		}
+	}
+	return values, nil
+}
+
+func formatHPASeries(ts *monitoringpb.TimeSeries) string {
+	points := make([]string, 0, len(ts.GetPoints()))
+	// Points are returned newest first.
+	for _, p := range slices.Backward(ts.GetPoints()) {
+		v := p.GetValue()
+		f := v.GetDoubleValue()
+		if _, ok := v.GetValue().(*monitoringpb.TypedValue_Int64Value); ok {
+			f = float64(v.GetInt64Value())
+		}
+		points = append(points, strconv.FormatFloat(f, 'g', 4, 64))
+	}
+	labels := make([]string, 0, len(ts.GetMetric().GetLabels()))
+	for k, v := range ts.GetMetric().GetLabels() {
+		labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(labels)
+	prefix := ""
+	if len(labels) > 0 {
+		prefix = "{" + strings.Join(labels, ",") + "} "
+	}
+	return prefix + strings.Join(points, " ")
+}
+
+func formatHPAStatus(hpa *autoscalingv2.HorizontalPodAutoscaler) string {
+	builder := new(strings.Builder)
+	ref := hpa.Spec.ScaleTargetRef
+	fmt.Fprintf(builder, "HorizontalPodAutoscaler %s/%s scales %s %s.\n", hpa.Namespace, hpa.Name, ref.Kind, ref.Name)
+	minReplicas := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		minReplicas = *hpa.Spec.MinReplicas
+	}
+	fmt.Fprintf(builder, "Replicas: current %d, desired %d, min %d, max %d.\n", hpa.Status.CurrentReplicas, hpa.Status.DesiredReplicas, minReplicas, hpa.Spec.MaxReplicas)
+	if hpa.Status.LastScaleTime != nil {
+		fmt.Fprintf(builder, "Last scaled at %s.\n", hpa.Status.LastScaleTime.UTC().Format(time.RFC3339))
+	} else {
+		builder.WriteString("Never scaled.\n")
+	}
+
+	builder.WriteString("\nConditions:\n\n| Type | Status | Reason | Message |\n|---|---|---|---|\n")
+	for _, c := range hpa.Status.Conditions {
+		fmt.Fprintf(builder, "| %s | %s | %s | %s |\n", c.Type, c.Status, c.Reason, c.Message)
+	}
+
+	builder.WriteString("\nMetrics:\n\n| # | Type | Metric | Target | Current |\n|---|---|---|---|---|\n")
+	for i, m := range hpa.Spec.Metrics {
+		name, target := describeMetricSpec(m)
+		current := "-"
+		if i < len(hpa.Status.CurrentMetrics) {
+			current = describeMetricStatus(hpa.Status.CurrentMetrics[i])
+		}
+		fmt.Fprintf(builder, "| %d | %s | %s | %s | %s |\n", i+1, m.Type, name, target, current)
+	}
+	return builder.String()
+}
+
+func describeMetricSpec(m autoscalingv2.MetricSpec) (string, string) {
+	switch {
+	case m.Resource != nil:
+		return string(m.Resource.Name), describeTarget(m.Resource.Target)
+	case m.ContainerResource != nil:
+		return fmt.Sprintf("%s of container %s", m.ContainerResource.Name, m.ContainerResource.Container), describeTarget(m.ContainerResource.Target)
+	case m.Pods != nil:
+		return m.Pods.Metric.Name, describeTarget(m.Pods.Target)
+	case m.Object != nil:
+		return fmt.Sprintf("%s of %s %s", m.Object.Metric.Name, m.Object.DescribedObject.Kind, m.Object.DescribedObject.Name), describeTarget(m.Object.Target)
+	case m.External != nil:
+		return m.External.Metric.Name, describeTarget(m.External.Target)
+	}
+	return "-", "-"
+}
+
+func describeTarget(t autoscalingv2.MetricTarget) string {
+	switch {
+	case t.AverageUtilization != nil:
+		return fmt.Sprintf("average utilization %d%%", *t.AverageUtilization)
+	case t.AverageValue != nil:
+		return fmt.Sprintf("average value %s", t.AverageValue.String())
+	case t.Value != nil:
+		return fmt.Sprintf("value %s", t.Value.String())
+	}
+	return "-"
+}
+
+func describeMetricStatus(s autoscalingv2.MetricStatus) string {
+	var v autoscalingv2.MetricValueStatus
+	switch {
+	case s.Resource != nil:
+		v = s.Resource.Current
+	case s.ContainerResource != nil:
+		v = s.ContainerResource.Current
+	case s.Pods != nil:
+		v = s.Pods.Current
+	case s.Object != nil:
+		v = s.Object.Current
+	case s.External != nil:
+		v = s.External.Current
+	default:
+		return "-"
+	}
+	var parts []string
+	if v.AverageUtilization != nil {
+		parts = append(parts, fmt.Sprintf("average utilization %d%%", *v.AverageUtilization))
+	}
+	if v.AverageValue != nil {
+		parts = append(parts, fmt.Sprintf("average value %s", v.AverageValue.String()))
+	}
+	if v.Value != nil {
+		parts = append(parts, fmt.Sprintf("value %s", v.Value.String()))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"strings"
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func testHPA(metrics ...autoscalingv2.MetricSpec) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "web"},
+			MinReplicas:    ptr(int32(2)),
+			MaxReplicas:    5,
+			Metrics:        metrics,
+		},
+	}
+}
+
+func TestHPAMetricQueries(t *testing.T) {
+	cpu := autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name:   corev1.ResourceCPU,
+			Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: ptr(int32(60))},
+		},
+	}
+	external := autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ExternalMetricSourceType,
+		External: &autoscalingv2.ExternalMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{
+				Name:     "pubsub.googleapis.com|subscription|num_undelivered_messages",
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"resource.labels.subscription_id": "orders"}},
+			},
+		},
+	}
+	object := autoscalingv2.MetricSpec{Type: autoscalingv2.ObjectMetricSourceType, Object: &autoscalingv2.ObjectMetricSource{}}
+
+	tests := []struct {
+		name        string
+		hpa         *autoscalingv2.HorizontalPodAutoscaler
+		pods        []string
+		wantFilters []string
+		wantNotes   int
+	}{
+		{
+			name: "resource",
+			hpa:  testHPA(cpu),
+			pods: []string{"web-1", "web-2"},
+			wantFilters: []string{
+				`metric.type="kubernetes.io/container/cpu/request_utilization" AND resource.type="k8s_container" AND resource.label.cluster_name="c1" AND resource.label.namespace_name="shop" AND resource.label.pod_name=one_of("web-1","web-2")`,
+			},
+		},
+		{
+			name:      "resource without pods",
+			hpa:       testHPA(cpu),
+			wantNotes: 1,
+		},
+		{
+			name: "external",
+			hpa:  testHPA(external),
+			wantFilters: []string{
+				`metric.type="pubsub.googleapis.com/subscription/num_undelivered_messages" AND resource.label.subscription_id="orders"`,
+			},
+		},
+		{
+			name:      "object",
+			hpa:       testHPA(object),
+			pods:      []string{"web-1"},
+			wantNotes: 1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			queries, notes := hpaMetricQueries(tc.hpa, "c1", tc.pods)
+			var filters []string
+			for _, q := range queries {
+				filters = append(filters, q.filter)
+			}
+			if strings.Join(filters, "\n") != strings.Join(tc.wantFilters, "\n") {
+				t.Errorf("filters = %q, want %q", filters, tc.wantFilters)
+			}
+			if len(notes) != tc.wantNotes {
+				t.Errorf("notes = %q, want %d notes", notes, tc.wantNotes)
+			}
+		})
+	}
+}
+
+func TestAdapterMetricType(t *testing.T) {
+	tests := map[string]string{
+		"pubsub.googleapis.com|subscription|num_undelivered_messages": "pubsub.googleapis.com/subscription/num_undelivered_messages",
+		"requests_per_second": "custom.googleapis.com/requests_per_second",
+	}
+	for in, want := range tests {
+		if got := adapterMetricType(in); got != want {
+			t.Errorf("adapterMetricType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFormatHPAStatus(t *testing.T) {
+	hpa := testHPA(autoscalingv2.MetricSpec{
+		Type: autoscalingv2.PodsMetricSourceType,
+		Pods: &autoscalingv2.PodsMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{Name: "requests_per_second"},
+			Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: ptr(resource.MustParse("100"))},
+		},
+	})
+	hpa.Status = autoscalingv2.HorizontalPodAutoscalerStatus{
+		CurrentReplicas: 5,
+		DesiredReplicas: 5,
+		Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+			{Type: autoscalingv2.ScalingLimited, Status: corev1.ConditionTrue, Reason: "TooManyReplicas", Message: "the desired replica count is more than the maximum replica count"},
+		},
+		CurrentMetrics: []autoscalingv2.MetricStatus{
+			{Type: autoscalingv2.PodsMetricSourceType, Pods: &autoscalingv2.PodsMetricStatus{Current: autoscalingv2.MetricValueStatus{AverageValue: ptr(resource.MustParse("250"))}}},
+		},
+	}
+
+	got := formatHPAStatus(hpa)
+	for _, want := range []string{
+		"HorizontalPodAutoscaler shop/web scales Deployment web.",
+		"Replicas: current 5, desired 5, min 2, max 5.",
+		"Never scaled.",
+		"| ScalingLimited | True | TooManyReplicas | the desired replica count is more than the maximum replica count |",
+		"| 1 | Pods | requests_per_second | average value 100 | average value 250 |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatHPAStatus() = %q, want it to contain %q", got, want)
+		}
+	}
+}
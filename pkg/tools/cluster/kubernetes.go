@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// kubernetesClient returns a client for the Kubernetes API of a GKE cluster,
+// authenticated with the application default credentials. Unlike
+// get_kubeconfig it does not depend on gke-gcloud-auth-plugin or modify the
+// user's kubeconfig.
+func (h *handlers) kubernetesClient(ctx context.Context, projectID, location, name string) (kubernetes.Interface, error) {
+	cfg, err := h.restConfig(ctx, projectID, location, name)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(cfg)
+}
+
+// restConfig returns the configuration of the clients of kubernetesClient.
+func (h *handlers) restConfig(ctx context.Context, projectID, location, name string) (*rest.Config, error) {
+	resp, err := h.cmClient.GetCluster(ctx, &containerpb.GetClusterRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/clusters/%s", projectID, location, name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s: %w", name, err)
+	}
+
+	endpoint := resp.GetEndpoint()
+	if endpoint == "" {
+		return nil, fmt.Errorf("endpoint not found for cluster %s", name)
+	}
+	if !strings.HasPrefix(endpoint, "https://") {
+		endpoint = "https://" + endpoint
+	}
+	// Accept the certificate with or without base64 padding.
+	caData, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(resp.GetMasterAuth().GetClusterCaCertificate(), "="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode clusterCaCertificate: %w", err)
+	}
+
+	ts, err := google.DefaultTokenSource(ctx, cloudPlatformScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get default credentials: %w", err)
+	}
+	return &rest.Config{
+		Host:            endpoint,
+		TLSClientConfig: rest.TLSClientConfig{CAData: caData},
+		UserAgent:       h.c.UserAgent(),
+		WrapTransport: func(rt http.RoundTripper) http.RoundTripper {
+			return &oauth2.Transport{Source: ts, Base: rt}
+		},
+	}, nil
+}
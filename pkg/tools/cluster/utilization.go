@@ -0,0 +1,354 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	nodePoolLabel      = "cloud.google.com/gke-nodepool"
+	topNamespacesCount = 10
+	usageWindow        = 5 * time.Minute
+)
+
+type clusterResourceUtilizationArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location  string `json:"location" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Name      string `json:"name" jsonschema:"GKE cluster name. Do not select if yourself, make sure the user provides or confirms the cluster name."`
+}
+
+// resources holds CPU in millicores and memory in bytes.
+type resources struct {
+	cpuMilli int64
+	memBytes int64
+}
+
+func (r *resources) add(o resources) {
+	r.cpuMilli += o.cpuMilli
+	r.memBytes += o.memBytes
+}
+
+type nodePoolUtilization struct {
+	name        string
+	nodes       int
+	allocatable resources
+	requested   resources
+	// used is the actual usage of the usageNodes nodes with usage metrics.
+	used       resources
+	usageNodes int
+}
+
+type namespaceRequests struct {
+	name      string
+	pods      int
+	requested resources
+}
+
+type clusterUtilization struct {
+	pools      []*nodePoolUtilization
+	namespaces []*namespaceRequests
+	// pending counts pods which are not scheduled to a node yet.
+	pending          int
+	pendingRequested resources
+}
+
+func installClusterResourceUtilizationTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "cluster_resource_utilization",
+		Description: "Report CPU and memory allocatable, requested and actually used per node pool of a GKE cluster, plus the top namespaces by requests. Use it for capacity questions such as whether a deployment fits.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.clusterResourceUtilization)
+}
+
+func (h *handlers) clusterResourceUtilization(ctx context.Context, _ *mcp.CallToolRequest, args *clusterResourceUtilizationArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Name == "" {
+		return nil, nil, fmt.Errorf("name argument cannot be empty")
+	}
+	location, err := h.c.ResolveClusterLocation(ctx, args.ProjectID, args.Location, args.Name, h.clusterLocations)
+	if err != nil {
+		return nil, nil, err
+	}
+	args.Location = location
+
+	kc, err := h.kubernetesClient(ctx, args.ProjectID, args.Location, args.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes, err := kc.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := kc.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	usage, usageErr := h.nodeUsage(ctx, args)
+	u := summarizeUtilization(nodes.Items, pods.Items, usage)
+
+	text := formatUtilization(args.Name, u)
+	if usageErr != nil {
+		text += fmt.Sprintf("\nNote: Actual usage is unavailable, only requests are shown: %v\n", usageErr)
+	} else if len(usage) == 0 {
+		text += "\nNote: No node usage metrics were found in Cloud Monitoring, only requests are shown. Check that system metrics are enabled for the cluster.\n"
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// nodeUsage returns the most recent CPU and non-evictable memory usage of
+// every node of the cluster, keyed by node name.
+func (h *handlers) nodeUsage(ctx context.Context, args *clusterResourceUtilizationArgs) (map[string]resources, error) {
+	resourceFilter := fmt.Sprintf(`resource.type="k8s_node" AND resource.label.project_id=%q AND resource.label.location=%q AND resource.label.cluster_name=%q`, args.ProjectID, args.Location, args.Name)
+	usage := map[string]resources{}
+	queries := []struct {
+		filter  string
+		aligner monitoringpb.Aggregation_Aligner
+		apply   func(r *resources, v float64)
+	}{
+		{
+			filter:  `metric.type="kubernetes.io/node/cpu/core_usage_time" AND ` + resourceFilter,
+			aligner: monitoringpb.Aggregation_ALIGN_RATE,
+			apply:   func(r *resources, v float64) { r.cpuMilli = int64(v * 1000) },
+		},
+		{
+			filter:  `metric.type="kubernetes.io/node/memory/used_bytes" AND metric.label.memory_type="non-evictable" AND ` + resourceFilter,
+			aligner: monitoringpb.Aggregation_ALIGN_MEAN,
+			apply:   func(r *resources, v float64) { r.memBytes = int64(v) },
+		},
+	}
+	now := time.Now()
+	for _, q := range queries {
+		req := &monitoringpb.ListTimeSeriesRequest{
+			Name:   fmt.Sprintf("projects/%s", args.ProjectID),
+			Filter: q.filter,
+			Interval: &monitoringpb.TimeInterval{
+				StartTime: timestamppb.New(now.Add(-2 * usageWindow)),
+				EndTime:   timestamppb.New(now),
+			},
+			Aggregation: &monitoringpb.Aggregation{
+				AlignmentPeriod:    durationpb.New(usageWindow),
+				PerSeriesAligner:   q.aligner,
+				CrossSeriesReducer: monitoringpb.Aggregation_REDUCE_SUM,
+				GroupByFields:      []string{"resource.label.node_name"},
+			},
+			View: monitoringpb.ListTimeSeriesRequest_FULL,
+		}
+		it := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*monitoringpb.TimeSeries] {
+			req.PageToken = pageToken
+			return h.metricClient.ListTimeSeries(ctx, req)
+		})
+		for {
+			ts, err := it.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if len(ts.GetPoints()) == 0 {
+				continue
+			}
+			// Points are returned newest first.
+			node := ts.GetResource().GetLabels()["node_name"]
+			r := usage[node]
+			q.apply(&r, ts.GetPoints()[0].GetValue().GetDoubleValue())
+			usage[node] = r
+		}
+	}
+	return usage, nil
+}
+
+// podRequests returns the effective resource requests of a pod as used by the
+// scheduler: the larger of the sum over its containers and the largest init
+// container, plus the pod overhead.
+func podRequests(pod *corev1.Pod) resources {
+	var sum, initMax resources
+	for _, c := range pod.Spec.Containers {
+		sum.add(containerRequests(&c))
+	}
+	for _, c := range pod.Spec.InitContainers {
+		r := containerRequests(&c)
+		initMax.cpuMilli = max(initMax.cpuMilli, r.cpuMilli)
+		initMax.memBytes = max(initMax.memBytes, r.memBytes)
+	}
+	total := resources{
+		cpuMilli: max(sum.cpuMilli, initMax.cpuMilli),
+		memBytes: max(sum.memBytes, initMax.memBytes),
+	}
+	total.add(resourceListToResources(pod.Spec.Overhead))
+	return total
+}
+
+func containerRequests(c *corev1.Container) resources {
+	return resourceListToResources(c.Resources.Requests)
+}
+
+func resourceListToResources(l corev1.ResourceList) resources {
+	var r resources
+	if q, ok := l[corev1.ResourceCPU]; ok {
+		r.cpuMilli = q.MilliValue()
+	}
+	if q, ok := l[corev1.ResourceMemory]; ok {
+		r.memBytes = q.Value()
+	}
+	return r
+}
+
+// summarizeUtilization aggregates allocatable, requested and used resources
+// per node pool and requested resources per namespace.
+func summarizeUtilization(nodes []corev1.Node, pods []corev1.Pod, usage map[string]resources) *clusterUtilization {
+	u := &clusterUtilization{}
+	pools := map[string]*nodePoolUtilization{}
+	nodePool := map[string]*nodePoolUtilization{}
+	for _, n := range nodes {
+		name := n.Labels[nodePoolLabel]
+		if name == "" {
+			name = "(none)"
+		}
+		p, ok := pools[name]
+		if !ok {
+			p = &nodePoolUtilization{name: name}
+			pools[name] = p
+			u.pools = append(u.pools, p)
+		}
+		p.nodes++
+		p.allocatable.add(resourceListToResources(n.Status.Allocatable))
+		if r, ok := usage[n.Name]; ok {
+			p.used.add(r)
+			p.usageNodes++
+		}
+		nodePool[n.Name] = p
+	}
+
+	namespaces := map[string]*namespaceRequests{}
+	for i := range pods {
+		pod := &pods[i]
+		r := podRequests(pod)
+		if p, ok := nodePool[pod.Spec.NodeName]; ok {
+			p.requested.add(r)
+		} else if pod.Spec.NodeName == "" {
+			u.pending++
+			u.pendingRequested.add(r)
+		}
+		ns, ok := namespaces[pod.Namespace]
+		if !ok {
+			ns = &namespaceRequests{name: pod.Namespace}
+			namespaces[pod.Namespace] = ns
+			u.namespaces = append(u.namespaces, ns)
+		}
+		ns.pods++
+		ns.requested.add(r)
+	}
+
+	sort.Slice(u.pools, func(i, j int) bool { return u.pools[i].name < u.pools[j].name })
+	sort.Slice(u.namespaces, func(i, j int) bool {
+		a, b := u.namespaces[i], u.namespaces[j]
+		if a.requested.cpuMilli != b.requested.cpuMilli {
+			return a.requested.cpuMilli > b.requested.cpuMilli
+		}
+		if a.requested.memBytes != b.requested.memBytes {
+			return a.requested.memBytes > b.requested.memBytes
+		}
+		return a.name < b.name
+	})
+	if len(u.namespaces) > topNamespacesCount {
+		u.namespaces = u.namespaces[:topNamespacesCount]
+	}
+	return u
+}
+
+func formatUtilization(clusterName string, u *clusterUtilization) string {
+	builder := new(strings.Builder)
+	nodes := 0
+	for _, p := range u.pools {
+		nodes += p.nodes
+	}
+	fmt.Fprintf(builder, "Cluster %s: %d nodes in %d node pools.\n\n", clusterName, nodes, len(u.pools))
+
+	builder.WriteString("| Node pool | Nodes | CPU allocatable (cores) | CPU requested | CPU used | Memory allocatable | Memory requested | Memory used |\n")
+	builder.WriteString("|---|---|---|---|---|---|---|---|\n")
+	for _, p := range u.pools {
+		cpuUsed, memUsed := "n/a", "n/a"
+		// Only report usage when every node of the pool has metrics, a
+		// partial sum would understate it.
+		if p.usageNodes == p.nodes && p.nodes > 0 {
+			cpuUsed = formatShare(formatCores(p.used.cpuMilli), p.used.cpuMilli, p.allocatable.cpuMilli)
+			memUsed = formatShare(formatBytes(p.used.memBytes), p.used.memBytes, p.allocatable.memBytes)
+		}
+		fmt.Fprintf(builder, "| %s | %d | %s | %s | %s | %s | %s | %s |\n",
+			p.name, p.nodes,
+			formatCores(p.allocatable.cpuMilli),
+			formatShare(formatCores(p.requested.cpuMilli), p.requested.cpuMilli, p.allocatable.cpuMilli),
+			cpuUsed,
+			formatBytes(p.allocatable.memBytes),
+			formatShare(formatBytes(p.requested.memBytes), p.requested.memBytes, p.allocatable.memBytes),
+			memUsed)
+	}
+
+	if u.pending > 0 {
+		fmt.Fprintf(builder, "\n%d pods are not scheduled yet, requesting %s CPU and %s memory.\n", u.pending, formatCores(u.pendingRequested.cpuMilli), formatBytes(u.pendingRequested.memBytes))
+	}
+
+	fmt.Fprintf(builder, "\nTop %d namespaces by requests:\n\n", len(u.namespaces))
+	builder.WriteString("| Namespace | Pods | CPU requested (cores) | Memory requested |\n")
+	builder.WriteString("|---|---|---|---|\n")
+	for _, ns := range u.namespaces {
+		fmt.Fprintf(builder, "| %s | %d | %s | %s |\n", ns.name, ns.pods, formatCores(ns.requested.cpuMilli), formatBytes(ns.requested.memBytes))
+	}
+	return builder.String()
+}
+
+func formatCores(milli int64) string {
+	return strconv.FormatFloat(float64(milli)/1000, 'f', 2, 64)
+}
+
+func formatBytes(b int64) string {
+	return strconv.FormatFloat(float64(b)/(1<<30), 'f', 2, 64) + "Gi"
+}
+
+// formatShare appends the percentage of part in total to value.
+func formatShare(value string, part, total int64) string {
+	if total == 0 {
+		return value
+	}
+	return fmt.Sprintf("%s (%.0f%%)", value, float64(part)*100/float64(total))
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testNode(name, pool, cpu, mem string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{nodePoolLabel: pool}},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(mem),
+		}},
+	}
+}
+
+func testPod(namespace, node string, containers ...corev1.ResourceList) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+		Spec:       corev1.PodSpec{NodeName: node},
+	}
+	for _, r := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Resources: corev1.ResourceRequirements{Requests: r}})
+	}
+	return pod
+}
+
+func requests(cpu, mem string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(mem),
+	}
+}
+
+func TestPodRequests(t *testing.T) {
+	pod := testPod("default", "n1", requests("100m", "128Mi"), requests("200m", "128Mi"))
+	pod.Spec.InitContainers = []corev1.Container{
+		{Resources: corev1.ResourceRequirements{Requests: requests("500m", "64Mi")}},
+	}
+	pod.Spec.Overhead = requests("10m", "1Mi")
+
+	got := podRequests(&pod)
+	want := resources{cpuMilli: 510, memBytes: 257 << 20}
+	if got != want {
+		t.Errorf("podRequests() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSummarizeUtilization(t *testing.T) {
+	nodes := []corev1.Node{
+		testNode("n1", "default-pool", "2", "4Gi"),
+		testNode("n2", "default-pool", "2", "4Gi"),
+		testNode("g1", "gpu-pool", "8", "32Gi"),
+	}
+	pods := []corev1.Pod{
+		testPod("web", "n1", requests("500m", "1Gi")),
+		testPod("web", "n2", requests("500m", "1Gi")),
+		testPod("ml", "g1", requests("4", "16Gi")),
+		testPod("web", "", requests("1", "1Gi")),
+	}
+	usage := map[string]resources{
+		"n1": {cpuMilli: 300, memBytes: 1 << 30},
+		"n2": {cpuMilli: 700, memBytes: 1 << 30},
+	}
+
+	u := summarizeUtilization(nodes, pods, usage)
+
+	if len(u.pools) != 2 {
+		t.Fatalf("summarizeUtilization() returned %d pools, want 2", len(u.pools))
+	}
+	def := u.pools[0]
+	if def.name != "default-pool" || def.nodes != 2 || def.usageNodes != 2 {
+		t.Errorf("default-pool = %+v, want 2 nodes with usage", def)
+	}
+	if def.allocatable != (resources{cpuMilli: 4000, memBytes: 8 << 30}) {
+		t.Errorf("default-pool allocatable = %+v", def.allocatable)
+	}
+	if def.requested != (resources{cpuMilli: 1000, memBytes: 2 << 30}) {
+		t.Errorf("default-pool requested = %+v", def.requested)
+	}
+	if def.used != (resources{cpuMilli: 1000, memBytes: 2 << 30}) {
+		t.Errorf("default-pool used = %+v", def.used)
+	}
+	if gpu := u.pools[1]; gpu.usageNodes != 0 || gpu.requested.cpuMilli != 4000 {
+		t.Errorf("gpu-pool = %+v, want 4 cores requested and no usage", gpu)
+	}
+	if u.pending != 1 || u.pendingRequested.cpuMilli != 1000 {
+		t.Errorf("pending = %d (%+v), want 1 pod requesting 1 core", u.pending, u.pendingRequested)
+	}
+	if len(u.namespaces) != 2 || u.namespaces[0].name != "ml" || u.namespaces[1].pods != 3 {
+		t.Errorf("namespaces = %+v, want ml first and 3 pods in web", u.namespaces)
+	}
+
+	got := formatUtilization("prod", u)
+	for _, want := range []string{
+		"Cluster prod: 3 nodes in 2 node pools.",
+		"| default-pool | 2 | 4.00 | 1.00 (25%) | 1.00 (25%) | 8.00Gi | 2.00Gi (25%) | 2.00Gi (25%) |",
+		"| gpu-pool | 1 | 8.00 | 4.00 (50%) | n/a |",
+		"1 pods are not scheduled yet",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatUtilization() = %q, want it to contain %q", got, want)
+		}
+	}
+}
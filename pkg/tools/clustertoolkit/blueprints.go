@@ -0,0 +1,264 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertoolkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"sigs.k8s.io/yaml"
+)
+
+// blueprintDirs are the directories of a Cluster Toolkit checkout holding
+// blueprints.
+var blueprintDirs = []string{"examples", filepath.Join("community", "examples")}
+
+type listBlueprintsArgs struct {
+	Path    string `json:"path" jsonschema:"Path of the Cluster Toolkit checkout, e.g. as downloaded by cluster_toolkit_download, or of the directory it was downloaded to."`
+	Keyword string `json:"keyword,omitempty" jsonschema:"Only list the blueprints whose name, path, description or modules contain this keyword, e.g. gke, a3 or slurm. Lists all blueprints if empty."`
+}
+
+// blueprint is a Cluster Toolkit blueprint of a checkout.
+type blueprint struct {
+	name string
+	// path is relative to the checkout.
+	path        string
+	description string
+	// requiredVars are the deployment variables without a usable value, e.g.
+	// project_id.
+	requiredVars []string
+	// modules are the sources of the modules of the blueprint.
+	modules []string
+}
+
+// blueprintFile is the part of a blueprint YAML file the catalog needs.
+type blueprintFile struct {
+	BlueprintName    string         `json:"blueprint_name"`
+	Vars             map[string]any `json:"vars"`
+	DeploymentGroups []struct {
+		Modules []struct {
+			Source string `json:"source"`
+		} `json:"modules"`
+	} `json:"deployment_groups"`
+}
+
+func installListBlueprintsTool(s *mcp.Server) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "cluster_toolkit_list_blueprints",
+		Description: "List the blueprints of a Cluster Toolkit checkout, e.g. downloaded with cluster_toolkit_download, with their description and the deployment variables to set, optionally filtered by a keyword. Use it to pick a blueprint instead of guessing blueprint names.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+		},
+	}, listBlueprints)
+}
+
+func listBlueprints(_ context.Context, _ *mcp.CallToolRequest, args *listBlueprintsArgs) (*mcp.CallToolResult, any, error) {
+	if args.Path == "" {
+		return nil, nil, fmt.Errorf("path argument cannot be empty")
+	}
+	root := args.Path
+	// Accept the download directory too.
+	if _, err := os.Stat(filepath.Join(root, "examples")); err != nil {
+		if _, err := os.Stat(filepath.Join(root, "cluster-toolkit", "examples")); err == nil {
+			root = filepath.Join(root, "cluster-toolkit")
+		}
+	}
+	blueprints, err := findBlueprints(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	if args.Keyword != "" {
+		blueprints = slices.DeleteFunc(blueprints, func(b blueprint) bool { return !b.matches(args.Keyword) })
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatBlueprints(root, blueprints, args.Keyword)},
+		},
+	}, nil, nil
+}
+
+// findBlueprints returns the blueprints of the checkout at root, sorted by
+// path. The YAML files without a blueprint_name, e.g. deployment files, are
+// skipped.
+func findBlueprints(root string) ([]blueprint, error) {
+	var blueprints []blueprint
+	found := false
+	for _, dir := range blueprintDirs {
+		err := filepath.WalkDir(filepath.Join(root, dir), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || (filepath.Ext(path) != ".yaml" && filepath.Ext(path) != ".yml") {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			b, ok, err := parseBlueprint(rel, data)
+			if err != nil {
+				slog.Warn("Skipping blueprint", "blueprint", rel, "err", err)
+				return nil
+			}
+			if ok {
+				blueprints = append(blueprints, b)
+			}
+			return nil
+		})
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the blueprints of %s: %w", root, err)
+		}
+		found = true
+	}
+	if !found {
+		return nil, fmt.Errorf("%s is not a Cluster Toolkit checkout, it has no examples directory; download it with cluster_toolkit_download", root)
+	}
+	slices.SortFunc(blueprints, func(a, b blueprint) int { return strings.Compare(a.path, b.path) })
+	return blueprints, nil
+}
+
+// parseBlueprint parses the blueprint YAML file at path. It returns false if
+// the file is not a blueprint.
+func parseBlueprint(path string, data []byte) (blueprint, bool, error) {
+	var f blueprintFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return blueprint{}, false, err
+	}
+	if f.BlueprintName == "" {
+		return blueprint{}, false, nil
+	}
+	b := blueprint{
+		name:        f.BlueprintName,
+		path:        filepath.ToSlash(path),
+		description: leadingComment(string(data)),
+	}
+	for name, value := range f.Vars {
+		if isPlaceholder(value) {
+			b.requiredVars = append(b.requiredVars, name)
+		}
+	}
+	slices.Sort(b.requiredVars)
+	for _, g := range f.DeploymentGroups {
+		for _, m := range g.Modules {
+			if m.Source != "" && !slices.Contains(b.modules, m.Source) {
+				b.modules = append(b.modules, m.Source)
+			}
+		}
+	}
+	return b, true, nil
+}
+
+// isPlaceholder reports whether the value of a deployment variable has to be
+// set by the user: it is empty, e.g. project_id with only a comment, or a
+// placeholder such as <your-ip-address>/32.
+func isPlaceholder(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == "" || (strings.Contains(v, "<") && strings.Contains(v, ">"))
+	}
+	return false
+}
+
+// leadingComment returns the comment at the top of a blueprint file, without
+// the license header.
+func leadingComment(data string) string {
+	var paragraphs [][]string
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, current)
+			current = nil
+		}
+	}
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "---" {
+			continue
+		}
+		text, ok := strings.CutPrefix(line, "#")
+		if !ok && line != "" {
+			break
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			flush()
+			continue
+		}
+		current = append(current, text)
+	}
+	flush()
+	var description []string
+	for _, p := range paragraphs {
+		text := strings.Join(p, " ")
+		if lower := strings.ToLower(text); strings.Contains(lower, "copyright") || strings.Contains(lower, "license") {
+			continue
+		}
+		description = append(description, text)
+	}
+	return strings.Join(description, " ")
+}
+
+// matches reports whether keyword is in the name, path, description or
+// modules of the blueprint, ignoring case.
+func (b blueprint) matches(keyword string) bool {
+	keyword = strings.ToLower(keyword)
+	for _, s := range append([]string{b.name, b.path, b.description}, b.modules...) {
+		if strings.Contains(strings.ToLower(s), keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatBlueprints(root string, blueprints []blueprint, keyword string) string {
+	builder := new(strings.Builder)
+	if keyword != "" {
+		fmt.Fprintf(builder, "Cluster Toolkit blueprints of %s matching %q:\n", root, keyword)
+	} else {
+		fmt.Fprintf(builder, "Cluster Toolkit blueprints of %s:\n", root)
+	}
+	if len(blueprints) == 0 {
+		builder.WriteString("\nNo blueprints found.\n")
+		return builder.String()
+	}
+	for _, b := range blueprints {
+		fmt.Fprintf(builder, "\n- %s (%s)\n", b.name, b.path)
+		if b.description != "" {
+			fmt.Fprintf(builder, "  %s\n", b.description)
+		}
+		if len(b.requiredVars) > 0 {
+			fmt.Fprintf(builder, "  Variables to set: %s\n", strings.Join(b.requiredVars, ", "))
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertoolkit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const gkeBlueprint = `# Copyright 2024 Google LLC
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# limitations under the License.
+
+# Creates a GKE cluster with an A3 Mega GPU node pool.
+# Requires a reservation.
+---
+
+blueprint_name: gke-a3-megagpu
+
+vars:
+  project_id:  ## Set GCP Project ID Here ##
+  deployment_name: gke-a3-mega
+  region: us-central1
+  authorized_cidr: <your-ip-address>/32
+  static_node_count: 2
+
+deployment_groups:
+- group: primary
+  modules:
+  - id: network
+    source: modules/network/vpc
+  - id: gke-cluster
+    source: modules/scheduler/gke-cluster
+`
+
+const slurmBlueprint = `blueprint_name: hpc-slurm
+
+vars:
+  project_id: ""
+  deployment_name: hpc-slurm
+
+deployment_groups:
+- group: primary
+  modules:
+  - id: slurm_controller
+    source: community/modules/scheduler/schedmd-slurm-gcp-v6-controller
+`
+
+// deploymentFile is a deployment file, not a blueprint.
+const deploymentFile = `terraform_backend_defaults:
+  type: gcs
+vars:
+  project_id: my-project
+`
+
+func writeCheckout(t *testing.T) string {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "cluster-toolkit")
+	for path, content := range map[string]string{
+		"examples/gke-a3-megagpu/gke-a3-megagpu.yaml":            gkeBlueprint,
+		"examples/gke-a3-megagpu/gke-a3-megagpu-deployment.yaml": deploymentFile,
+		"community/examples/hpc-slurm.yaml":                      slurmBlueprint,
+		"examples/broken.yaml":                                   "blueprint_name: [",
+		"examples/README.md":                                     "# Examples",
+	} {
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestFindBlueprints(t *testing.T) {
+	blueprints, err := findBlueprints(writeCheckout(t))
+	if err != nil {
+		t.Fatalf("findBlueprints() returned unexpected error: %v", err)
+	}
+	if len(blueprints) != 2 {
+		t.Fatalf("findBlueprints() = %+v, want 2 blueprints", blueprints)
+	}
+	slurm, gke := blueprints[0], blueprints[1]
+	if slurm.name != "hpc-slurm" || slurm.path != "community/examples/hpc-slurm.yaml" {
+		t.Errorf("first blueprint = %s (%s), want hpc-slurm (community/examples/hpc-slurm.yaml)", slurm.name, slurm.path)
+	}
+	if gke.name != "gke-a3-megagpu" || gke.path != "examples/gke-a3-megagpu/gke-a3-megagpu.yaml" {
+		t.Errorf("second blueprint = %s (%s), want gke-a3-megagpu", gke.name, gke.path)
+	}
+	if want := "Creates a GKE cluster with an A3 Mega GPU node pool. Requires a reservation."; gke.description != want {
+		t.Errorf("description = %q, want %q", gke.description, want)
+	}
+	if want := []string{"authorized_cidr", "project_id"}; !slices.Equal(gke.requiredVars, want) {
+		t.Errorf("required vars = %v, want %v", gke.requiredVars, want)
+	}
+	if want := []string{"project_id"}; !slices.Equal(slurm.requiredVars, want) {
+		t.Errorf("required vars = %v, want %v", slurm.requiredVars, want)
+	}
+}
+
+func TestListBlueprints(t *testing.T) {
+	root := writeCheckout(t)
+	testCases := []struct {
+		name    string
+		args    listBlueprintsArgs
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "all",
+			args: listBlueprintsArgs{Path: root},
+			want: []string{
+				"- gke-a3-megagpu (examples/gke-a3-megagpu/gke-a3-megagpu.yaml)\n  Creates a GKE cluster",
+				"  Variables to set: authorized_cidr, project_id\n",
+				"- hpc-slurm (community/examples/hpc-slurm.yaml)",
+			},
+		},
+		{
+			name:    "keyword in a module",
+			args:    listBlueprintsArgs{Path: root, Keyword: "SLURM"},
+			want:    []string{"hpc-slurm"},
+			notWant: []string{"gke-a3-megagpu"},
+		},
+		{
+			name:    "keyword in the description",
+			args:    listBlueprintsArgs{Path: filepath.Dir(root), Keyword: "reservation"},
+			want:    []string{"gke-a3-megagpu"},
+			notWant: []string{"hpc-slurm"},
+		},
+		{
+			name: "no match",
+			args: listBlueprintsArgs{Path: root, Keyword: "tpu"},
+			want: []string{"No blueprints found."},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, _, err := listBlueprints(context.Background(), nil, &tc.args)
+			if err != nil {
+				t.Fatalf("listBlueprints() returned unexpected error: %v", err)
+			}
+			got := result.Content[0].(*mcp.TextContent).Text
+			for _, w := range tc.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("listBlueprints() = %q, want it to contain %q", got, w)
+				}
+			}
+			for _, w := range tc.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("listBlueprints() = %q, want it not to contain %q", got, w)
+				}
+			}
+		})
+	}
+
+	if _, _, err := listBlueprints(context.Background(), nil, &listBlueprintsArgs{Path: t.TempDir()}); err == nil || !strings.Contains(err.Error(), "is not a Cluster Toolkit checkout") {
+		t.Errorf("listBlueprints() of an empty directory error = %v, want a not a checkout error", err)
+	}
+}
@@ -15,22 +15,46 @@
 package clustertoolkit
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
-	"log"
-	"os/exec"
+	"log/slog"
+	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/command"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// cloneTimeout bounds the time the clone of the Cluster Toolkit repository may
+// take.
+const cloneTimeout = 10 * time.Minute
+
+var (
+	clusterToolkitRepoUrl = "https://github.com/GoogleCloudPlatform/cluster-toolkit.git"
+	// commitRegexp matches the refs that are commit SHAs rather than branches
+	// or tags.
+	commitRegexp = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+)
+
 type clusterToolkitDownloadArgs struct {
 	DownloadDirectory string `json:"download_directory" jsonschema:"Download directory for the git repo. By default use the absolute path to the current working directory."`
+	Ref               string `json:"ref,omitempty" jsonschema:"Branch, tag, e.g. a release like v1.60.0, or commit SHA to download, for a reproducible deployment. Defaults to the default branch."`
+	Depth             int    `json:"depth,omitempty" jsonschema:"Only download this many commits of history, e.g. 1 for the fastest download. Downloads the whole history by default. A commit ref needs its full 40 character SHA with a depth."`
 }
 
-func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
+func Install(_ context.Context, s *mcp.Server, c *config.Config) error {
+	installListBlueprintsTool(s)
+	// Downloading writes to the local disk.
+	if c.ReadOnly() {
+		return nil
+	}
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "cluster_toolkit_download",
 		Description: "Cluster Toolkit, is open-source software offered by Google Cloud which simplifies the process for you to create Google Kubernetes Engine clusters and deploy high performance computing (HPC), artificial intelligence (AI), and machine learning (ML). It is designed to be highly customizable and extensible, and intends to address the deployment needs of a broad range of use cases. This tool will download the public git repository so that Cluster Toolkit can be used.",
@@ -48,15 +72,209 @@ func clusterToolkitDownload(ctx context.Context, _ *mcp.CallToolRequest, args *c
 	if !strings.HasSuffix(downloadDir, "cluster-toolkit") {
 		downloadDir = filepath.Join(downloadDir, "cluster-toolkit")
 	}
-	out, err := exec.Command("git", "clone", "https://github.com/GoogleCloudPlatform/cluster-toolkit.git", downloadDir).Output()
+	if _, err := lookPath("git"); err != nil {
+		// git is preferred since it can update the download later, but
+		// slim containers often lack it.
+		return clusterToolkitTarball(ctx, args, downloadDir)
+	}
+	existing, err := isExistingCheckout(ctx, downloadDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var commands [][]string
+	if existing {
+		commands, err = updateCommands(ctx, args.Ref, args.Depth, downloadDir)
+	} else {
+		commands, err = cloneCommands(args.Ref, args.Depth, downloadDir)
+	}
 	if err != nil {
-		log.Printf("Failed to download Cluster Toolkit: %v %s", err, out)
 		return nil, nil, err
 	}
+	builder := new(strings.Builder)
+	for _, c := range commands {
+		out, err := command.Run(ctx, cloneTimeout, "git", c...)
+		if err != nil {
+			slog.Warn("Failed to download Cluster Toolkit", "err", err)
+			return nil, nil, err
+		}
+		builder.Write(out)
+	}
+
+	commit, err := command.Run(ctx, 0, "git", "-C", downloadDir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve the downloaded commit: %w", err)
+	}
+	ref := args.Ref
+	if ref == "" {
+		// The default branch the clone checked out.
+		out, err := command.Run(ctx, 0, "git", "-C", downloadDir, "rev-parse", "--abbrev-ref", "HEAD")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve the downloaded branch: %w", err)
+		}
+		ref = strings.TrimSpace(string(out))
+	}
+	if existing {
+		fmt.Fprintf(builder, "Updated existing checkout of Cluster Toolkit at %s with git.\n", downloadDir)
+	} else {
+		fmt.Fprintf(builder, "Downloaded Cluster Toolkit to %s with git.\n", downloadDir)
+	}
+	fmt.Fprintf(builder, "Ref: %s\nCommit: %s\n", ref, strings.TrimSpace(string(commit)))
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: string(out)},
+			&mcp.TextContent{Text: builder.String()},
 		},
 	}, nil, nil
 }
+
+// clusterToolkitTarball downloads Cluster Toolkit to downloadDir as a tarball,
+// for when git is not installed.
+func clusterToolkitTarball(ctx context.Context, args *clusterToolkitDownloadArgs, downloadDir string) (*mcp.CallToolResult, any, error) {
+	if args.Depth < 0 {
+		return nil, nil, fmt.Errorf("depth must be positive")
+	}
+	commit, err := tarballDownload(ctx, args.Ref, downloadDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	builder := new(strings.Builder)
+	fmt.Fprintf(builder, "Downloaded Cluster Toolkit to %s as a tarball, since git is not installed. The download has no git history and is replaced by the next download; install git to update it in place.\n", downloadDir)
+	fmt.Fprintf(builder, "Ref: %s\n", cmp.Or(args.Ref, "default branch"))
+	if commit != "" {
+		fmt.Fprintf(builder, "Commit: %s\n", commit)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: builder.String()},
+		},
+	}, nil, nil
+}
+
+// isExistingCheckout reports whether dir is a checkout of the Cluster Toolkit
+// repository to update. It is false if dir doesn't exist or is empty, and an
+// error if dir holds anything else.
+func isExistingCheckout(ctx context.Context, dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(entries) == 0) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	// The prefix is empty at the top of a repository, and git fails outside
+	// of one.
+	prefix, err := command.Run(ctx, 0, "git", "-C", dir, "rev-parse", "--show-prefix")
+	if err != nil || strings.TrimSpace(string(prefix)) != "" {
+		return false, fmt.Errorf("%s already exists and is not a git checkout of Cluster Toolkit; remove it or choose another download_directory", dir)
+	}
+	remote, err := command.Run(ctx, 0, "git", "-C", dir, "remote", "get-url", "origin")
+	if err != nil || normalizeRepoUrl(string(remote)) != normalizeRepoUrl(clusterToolkitRepoUrl) {
+		return false, fmt.Errorf("%s already exists and is a git checkout of %s, not of Cluster Toolkit; remove it or choose another download_directory", dir, cmp.Or(strings.TrimSpace(string(remote)), "another repository"))
+	}
+	return true, nil
+}
+
+// normalizeRepoUrl strips what may differ between the URLs of the same
+// repository.
+func normalizeRepoUrl(url string) string {
+	url = strings.TrimSuffix(strings.TrimSpace(url), "/")
+	return strings.TrimSuffix(url, ".git")
+}
+
+// updateCommands returns the arguments of the git commands updating the
+// checkout in dir to ref, the default branch if empty. Branches are checked out
+// as local branches, tags and commits detached.
+func updateCommands(ctx context.Context, ref string, depth int, dir string) ([][]string, error) {
+	if depth < 0 {
+		return nil, fmt.Errorf("depth must be positive")
+	}
+	var depthArgs []string
+	if depth > 0 {
+		depthArgs = []string{"--depth", strconv.Itoa(depth)}
+	}
+	if commitRegexp.MatchString(ref) && len(ref) != 40 {
+		// Only full SHAs can be fetched, look the commit up in all the
+		// branches.
+		if depth > 0 {
+			return nil, fmt.Errorf("ref %s must be a full 40 character commit SHA with a depth", ref)
+		}
+		return [][]string{
+			{"-C", dir, "fetch", "--tags", "origin"},
+			{"-C", dir, "checkout", "--detach", ref},
+		}, nil
+	}
+
+	branch := ""
+	if ref == "" {
+		out, err := command.Run(ctx, 0, "git", "-C", dir, "ls-remote", "--symref", "origin", "HEAD")
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the default branch of Cluster Toolkit: %w", err)
+		}
+		branch = parseSymref(string(out))
+		if branch == "" {
+			return nil, fmt.Errorf("failed to find the default branch of Cluster Toolkit in %q", out)
+		}
+		ref = branch
+	} else if !commitRegexp.MatchString(ref) {
+		out, err := command.Run(ctx, 0, "git", "-C", dir, "ls-remote", "--heads", "origin", ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up ref %s of Cluster Toolkit: %w", ref, err)
+		}
+		if strings.TrimSpace(string(out)) != "" {
+			branch = ref
+		}
+	}
+	commands := [][]string{append(append([]string{"-C", dir, "fetch", "--tags"}, depthArgs...), "origin", ref)}
+	if branch != "" {
+		return append(commands, []string{"-C", dir, "checkout", "-B", branch, "FETCH_HEAD"}), nil
+	}
+	return append(commands, []string{"-C", dir, "checkout", "--detach", "FETCH_HEAD"}), nil
+}
+
+// parseSymref returns the branch of the ref: line of git ls-remote --symref
+// origin HEAD.
+func parseSymref(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		target, ok := strings.CutPrefix(line, "ref: refs/heads/")
+		if !ok {
+			continue
+		}
+		if branch, _, ok := strings.Cut(target, "\t"); ok {
+			return branch
+		}
+	}
+	return ""
+}
+
+// cloneCommands returns the arguments of the git commands downloading ref of
+// the Cluster Toolkit repository to dir. A branch or tag is cloned directly,
+// a commit is checked out after the clone.
+func cloneCommands(ref string, depth int, dir string) ([][]string, error) {
+	if depth < 0 {
+		return nil, fmt.Errorf("depth must be positive")
+	}
+	var depthArgs []string
+	if depth > 0 {
+		depthArgs = []string{"--depth", strconv.Itoa(depth)}
+	}
+	if !commitRegexp.MatchString(ref) {
+		clone := []string{"clone"}
+		if ref != "" {
+			clone = append(clone, "--branch", ref)
+		}
+		clone = append(clone, depthArgs...)
+		return [][]string{append(clone, clusterToolkitRepoUrl, dir)}, nil
+	}
+
+	clone := append(append([]string{"clone", "--no-checkout"}, depthArgs...), clusterToolkitRepoUrl, dir)
+	commands := [][]string{clone}
+	if depth > 0 {
+		// A shallow clone only has the history of the default branch, the
+		// commit has to be fetched by its full SHA.
+		if len(ref) != 40 {
+			return nil, fmt.Errorf("ref %s must be a full 40 character commit SHA with a depth", ref)
+		}
+		commands = append(commands, append(append([]string{"-C", dir, "fetch"}, depthArgs...), "origin", ref))
+	}
+	return append(commands, []string{"-C", dir, "checkout", "--detach", ref}), nil
+}
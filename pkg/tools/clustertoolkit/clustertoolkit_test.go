@@ -0,0 +1,331 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertoolkit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/command"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCloneCommands(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	testCases := []struct {
+		name    string
+		ref     string
+		depth   int
+		want    [][]string
+		wantErr string
+	}{
+		{
+			name: "default branch",
+			want: [][]string{{"clone", clusterToolkitRepoUrl, "dir"}},
+		},
+		{
+			name:  "tag with depth",
+			ref:   "v1.60.0",
+			depth: 1,
+			want:  [][]string{{"clone", "--branch", "v1.60.0", "--depth", "1", clusterToolkitRepoUrl, "dir"}},
+		},
+		{
+			name: "commit",
+			ref:  "0123abc",
+			want: [][]string{
+				{"clone", "--no-checkout", clusterToolkitRepoUrl, "dir"},
+				{"-C", "dir", "checkout", "--detach", "0123abc"},
+			},
+		},
+		{
+			name:  "commit with depth",
+			ref:   sha,
+			depth: 1,
+			want: [][]string{
+				{"clone", "--no-checkout", "--depth", "1", clusterToolkitRepoUrl, "dir"},
+				{"-C", "dir", "fetch", "--depth", "1", "origin", sha},
+				{"-C", "dir", "checkout", "--detach", sha},
+			},
+		},
+		{
+			name:    "short commit with depth",
+			ref:     "0123abc",
+			depth:   1,
+			wantErr: "full 40 character commit SHA",
+		},
+		{
+			name:    "negative depth",
+			depth:   -1,
+			wantErr: "depth must be positive",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := cloneCommands(tc.ref, tc.depth, "dir")
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("cloneCommands() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("cloneCommands() returned unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("cloneCommands() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClusterToolkitDownload(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	// A local repository with a commit, a tag and a second commit stands in
+	// for the Cluster Toolkit repository.
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "--initial-branch", "main")
+	git("commit", "--allow-empty", "-m", "first")
+	first := git("rev-parse", "HEAD")
+	git("tag", "v1.0.0")
+	git("commit", "--allow-empty", "-m", "second")
+	second := git("rev-parse", "HEAD")
+
+	original := clusterToolkitRepoUrl
+	clusterToolkitRepoUrl = "file://" + repo
+	defer func() { clusterToolkitRepoUrl = original }()
+
+	testCases := []struct {
+		name       string
+		ref        string
+		depth      int
+		wantRef    string
+		wantCommit string
+	}{
+		{name: "default branch", wantRef: "main", wantCommit: second},
+		{name: "tag", ref: "v1.0.0", depth: 1, wantRef: "v1.0.0", wantCommit: first},
+		{name: "commit", ref: first, wantRef: first, wantCommit: first},
+		{name: "shallow commit", ref: first, depth: 1, wantRef: first, wantCommit: first},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := &clusterToolkitDownloadArgs{DownloadDirectory: t.TempDir(), Ref: tc.ref, Depth: tc.depth}
+			result, _, err := clusterToolkitDownload(context.Background(), nil, args)
+			if err != nil {
+				t.Fatalf("clusterToolkitDownload() returned unexpected error: %v", err)
+			}
+			got := result.Content[0].(*mcp.TextContent).Text
+			for _, want := range []string{
+				"Downloaded Cluster Toolkit to " + filepath.Join(args.DownloadDirectory, "cluster-toolkit"),
+				"Ref: " + tc.wantRef + "\n",
+				"Commit: " + tc.wantCommit + "\n",
+			} {
+				if !strings.Contains(got, want) {
+					t.Errorf("clusterToolkitDownload() = %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSymref(t *testing.T) {
+	out := "ref: refs/heads/develop\tHEAD\n0123456789abcdef0123456789abcdef01234567\tHEAD\n"
+	if got := parseSymref(out); got != "develop" {
+		t.Errorf("parseSymref() = %q, want develop", got)
+	}
+	if got := parseSymref("0123456789abcdef0123456789abcdef01234567\tHEAD\n"); got != "" {
+		t.Errorf("parseSymref() without a symref = %q, want none", got)
+	}
+}
+
+func TestClusterToolkitDownloadExistingCheckout(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	// A work repository pushing to a local bare repository stands in for
+	// the Cluster Toolkit repository.
+	work, remote := t.TempDir(), t.TempDir()
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git(remote, "init", "--bare", "--initial-branch", "main")
+	git(work, "init", "--initial-branch", "main")
+	git(work, "remote", "add", "origin", remote)
+	git(work, "commit", "--allow-empty", "-m", "first")
+	first := git(work, "rev-parse", "HEAD")
+	git(work, "tag", "v1.0.0")
+	git(work, "push", "--tags", "origin", "main")
+
+	original := clusterToolkitRepoUrl
+	clusterToolkitRepoUrl = "file://" + remote
+	defer func() { clusterToolkitRepoUrl = original }()
+
+	parent := t.TempDir()
+	download := func(ref string) string {
+		t.Helper()
+		result, _, err := clusterToolkitDownload(context.Background(), nil, &clusterToolkitDownloadArgs{DownloadDirectory: parent, Ref: ref})
+		if err != nil {
+			t.Fatalf("clusterToolkitDownload(%q) returned unexpected error: %v", ref, err)
+		}
+		return result.Content[0].(*mcp.TextContent).Text
+	}
+	if got := download(""); !strings.Contains(got, "Downloaded Cluster Toolkit") {
+		t.Errorf("clusterToolkitDownload() = %q, want a new download", got)
+	}
+
+	git(work, "commit", "--allow-empty", "-m", "second")
+	second := git(work, "rev-parse", "HEAD")
+	git(work, "push", "origin", "main")
+	for _, tc := range []struct {
+		ref        string
+		wantRef    string
+		wantCommit string
+	}{
+		{ref: "", wantRef: "main", wantCommit: second},
+		{ref: "v1.0.0", wantRef: "v1.0.0", wantCommit: first},
+		{ref: first[:10], wantRef: first[:10], wantCommit: first},
+		{ref: "main", wantRef: "main", wantCommit: second},
+	} {
+		got := download(tc.ref)
+		for _, want := range []string{
+			"Updated existing checkout of Cluster Toolkit at " + filepath.Join(parent, "cluster-toolkit"),
+			"Ref: " + tc.wantRef + "\n",
+			"Commit: " + tc.wantCommit + "\n",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("clusterToolkitDownload(%q) = %q, want it to contain %q", tc.ref, got, want)
+			}
+		}
+	}
+}
+
+func TestClusterToolkitDownloadConflictingDirectory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	notRepo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(notRepo, "cluster-toolkit"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(notRepo, "cluster-toolkit", "file"), []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	otherRepo := t.TempDir()
+	dir := filepath.Join(otherRepo, "cluster-toolkit")
+	for _, args := range [][]string{{"init", dir}, {"-C", dir, "remote", "add", "origin", "https://example.com/other.git"}, {"-C", dir, "commit", "--allow-empty", "-m", "first"}} {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+
+	for _, tc := range []struct {
+		parent  string
+		wantErr string
+	}{
+		{notRepo, filepath.Join(notRepo, "cluster-toolkit") + " already exists and is not a git checkout of Cluster Toolkit"},
+		{otherRepo, dir + " already exists and is a git checkout of https://example.com/other.git, not of Cluster Toolkit"},
+	} {
+		_, _, err := clusterToolkitDownload(context.Background(), nil, &clusterToolkitDownloadArgs{DownloadDirectory: tc.parent})
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("clusterToolkitDownload() error = %v, want it to contain %q", err, tc.wantErr)
+		}
+	}
+}
+
+func TestClusterToolkitDownloadDirectory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	remote := t.TempDir()
+	cmd := exec.Command("git", "-C", remote, "-c", "user.name=test", "-c", "user.email=test@example.com", "init", "--initial-branch", "main")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v: %s", err, out)
+	}
+	cmd = exec.Command("git", "-C", remote, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "first")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit failed: %v: %s", err, out)
+	}
+	original := clusterToolkitRepoUrl
+	clusterToolkitRepoUrl = "file://" + remote
+	defer func() { clusterToolkitRepoUrl = original }()
+
+	parent := t.TempDir()
+	testCases := []struct {
+		name string
+		dir  string
+		want string
+	}{
+		{name: "parent directory", dir: filepath.Join(parent, "a"), want: filepath.Join(parent, "a", "cluster-toolkit")},
+		{name: "toolkit directory", dir: filepath.Join(parent, "b", "cluster-toolkit"), want: filepath.Join(parent, "b", "cluster-toolkit")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, _, err := clusterToolkitDownload(context.Background(), nil, &clusterToolkitDownloadArgs{DownloadDirectory: tc.dir}); err != nil {
+				t.Fatalf("clusterToolkitDownload() returned unexpected error: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(tc.want, ".git")); err != nil {
+				t.Errorf("clusterToolkitDownload(%s) did not download to %s: %v", tc.dir, tc.want, err)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(parent, "b", "cluster-toolkit", "cluster-toolkit")); !os.IsNotExist(err) {
+		t.Errorf("clusterToolkitDownload() downloaded to a nested cluster-toolkit directory")
+	}
+
+	if _, _, err := clusterToolkitDownload(context.Background(), nil, &clusterToolkitDownloadArgs{}); err == nil || !strings.Contains(err.Error(), "download_directory argument cannot be empty") {
+		t.Errorf("clusterToolkitDownload() without a directory error = %v, want an empty argument error", err)
+	}
+}
+
+func TestClusterToolkitDownloadGitError(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	original := clusterToolkitRepoUrl
+	clusterToolkitRepoUrl = "file://" + filepath.Join(t.TempDir(), "missing")
+	defer func() { clusterToolkitRepoUrl = original }()
+
+	_, _, err := clusterToolkitDownload(context.Background(), nil, &clusterToolkitDownloadArgs{DownloadDirectory: t.TempDir()})
+	var cmdErr *command.Error
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("clusterToolkitDownload() error = %v, want the git error", err)
+	}
+	if !strings.HasPrefix(cmdErr.Command, "git clone") || cmdErr.ExitCode == 0 || cmdErr.Stderr == "" {
+		t.Errorf("clusterToolkitDownload() error = %+v, want the failed git clone with its stderr", cmdErr)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertoolkit
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// refMarkerFile records the ref of a Cluster Toolkit downloaded as a tarball,
+// which has no git metadata.
+const refMarkerFile = ".cluster-toolkit-ref"
+
+var (
+	// clusterToolkitTarballUrl is the URL of the tarball of a ref of the
+	// Cluster Toolkit repository.
+	clusterToolkitTarballUrl = "https://github.com/GoogleCloudPlatform/cluster-toolkit/archive/%s.tar.gz"
+	// lookPath finds the git binary, tests replace it to fall back to the
+	// tarball.
+	lookPath = exec.LookPath
+)
+
+// tarballDownload downloads ref of the Cluster Toolkit repository, the
+// default branch if empty, as a tarball and extracts it to dir. It returns the
+// commit of the tarball. A previous tarball download in dir is replaced.
+func tarballDownload(ctx context.Context, ref, dir string) (string, error) {
+	if err := checkTarballDir(dir); err != nil {
+		return "", err
+	}
+	tarballRef := ref
+	if tarballRef == "" {
+		tarballRef = "HEAD"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(clusterToolkitTarballUrl, url.PathEscape(tarballRef)), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		slog.Warn("Failed to download Cluster Toolkit tarball", "err", err)
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("failed to download the Cluster Toolkit tarball of %s with status code: %d", tarballRef, resp.StatusCode)
+		slog.Warn("Failed to download Cluster Toolkit tarball", "err", err)
+		return "", err
+	}
+
+	// Extract next to dir first, so that a failed download leaves a previous
+	// one in place.
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return "", err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), ".cluster-toolkit-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+	if err := os.Chmod(tmp, 0o755); err != nil {
+		return "", err
+	}
+	commit, err := extractTarball(resp.Body, tmp)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract the Cluster Toolkit tarball: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, refMarkerFile), []byte(tarballRef+"\n"), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return "", err
+	}
+	return commit, nil
+}
+
+// checkTarballDir returns an error if dir holds anything else than a previous
+// tarball download.
+func checkTarballDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(entries) == 0) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, refMarkerFile)); err != nil {
+		return fmt.Errorf("%s already exists and is not a download of Cluster Toolkit; remove it or choose another download_directory", dir)
+	}
+	return nil
+}
+
+// extractTarball extracts the gzipped tarball r to dir, without the top-level
+// directory GitHub puts the files in. It returns the commit git archive
+// records in the pax header, or an empty string if there is none.
+func extractTarball(r io.Reader, dir string) (string, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return "", err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	commit := ""
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return commit, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if hdr.Typeflag == tar.TypeXGlobalHeader {
+			commit = hdr.PAXRecords["comment"]
+			continue
+		}
+		_, name, _ := strings.Cut(strings.TrimPrefix(hdr.Name, "./"), "/")
+		if name == "" {
+			continue
+		}
+		if !filepath.IsLocal(name) {
+			return "", fmt.Errorf("tarball entry %s is outside of the repository", hdr.Name)
+		}
+		target := filepath.Join(dir, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0o755)
+		case tar.TypeReg:
+			err = writeFile(target, tr, hdr.FileInfo().Mode().Perm())
+		case tar.TypeSymlink:
+			if filepath.IsAbs(hdr.Linkname) || !filepath.IsLocal(filepath.Join(filepath.Dir(name), hdr.Linkname)) {
+				return "", fmt.Errorf("tarball symlink %s points outside of the repository", hdr.Name)
+			}
+			if err = os.MkdirAll(filepath.Dir(target), 0o755); err == nil {
+				err = os.Symlink(hdr.Linkname, target)
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// writeFile writes the contents of r to a new file name with mode perm.
+func writeFile(name string, r io.Reader, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertoolkit
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const tarballCommit = "0123456789abcdef0123456789abcdef01234567"
+
+// makeTarball returns a gzipped tarball laid out like the GitHub archives,
+// with the files in a top-level directory and the commit in a pax header.
+func makeTarball(t *testing.T, files map[string]string, symlinks map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	write := func(hdr *tar.Header, content string) {
+		t.Helper()
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("WriteHeader(%s) failed: %v", hdr.Name, err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("Write(%s) failed: %v", hdr.Name, err)
+		}
+	}
+	write(&tar.Header{Typeflag: tar.TypeXGlobalHeader, Name: "pax_global_header", PAXRecords: map[string]string{"comment": tarballCommit}}, "")
+	write(&tar.Header{Typeflag: tar.TypeDir, Name: "cluster-toolkit-main/", Mode: 0o755}, "")
+	for name, content := range files {
+		write(&tar.Header{Typeflag: tar.TypeReg, Name: "cluster-toolkit-main/" + name, Mode: 0o644, Size: int64(len(content))}, content)
+	}
+	for name, target := range symlinks {
+		write(&tar.Header{Typeflag: tar.TypeSymlink, Name: "cluster-toolkit-main/" + name, Linkname: target}, "")
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractTarball(t *testing.T) {
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		symlinks map[string]string
+		wantErr  string
+	}{
+		{
+			name:     "repository",
+			files:    map[string]string{"README.md": "readme", "examples/gke.yaml": "blueprint_name: gke"},
+			symlinks: map[string]string{"examples/link.yaml": "gke.yaml"},
+		},
+		{name: "entry outside", files: map[string]string{"../escape": "x"}, wantErr: "outside of the repository"},
+		{name: "symlink outside", symlinks: map[string]string{"link": "../../etc/passwd"}, wantErr: "points outside of the repository"},
+		{name: "absolute symlink", symlinks: map[string]string{"link": "/etc/passwd"}, wantErr: "points outside of the repository"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			commit, err := extractTarball(bytes.NewReader(makeTarball(t, tc.files, tc.symlinks)), dir)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("extractTarball() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extractTarball() returned unexpected error: %v", err)
+			}
+			if commit != tarballCommit {
+				t.Errorf("extractTarball() commit = %q, want %q", commit, tarballCommit)
+			}
+			for name, want := range tc.files {
+				got, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil || string(got) != want {
+					t.Errorf("extracted %s = %q, %v, want %q", name, got, err, want)
+				}
+			}
+			if got, err := os.ReadFile(filepath.Join(dir, "examples", "link.yaml")); err != nil || string(got) != "blueprint_name: gke" {
+				t.Errorf("extracted symlink = %q, %v, want the contents of its target", got, err)
+			}
+		})
+	}
+}
+
+func TestClusterToolkitDownloadTarball(t *testing.T) {
+	tarball := makeTarball(t, map[string]string{"README.md": "readme"}, nil)
+	var gotPaths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.URL.Path)
+		if r.URL.Path == "/missing.tar.gz" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(tarball)
+	}))
+	defer server.Close()
+	originalUrl, originalLookPath := clusterToolkitTarballUrl, lookPath
+	clusterToolkitTarballUrl = server.URL + "/%s.tar.gz"
+	lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+	defer func() { clusterToolkitTarballUrl, lookPath = originalUrl, originalLookPath }()
+
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "cluster-toolkit")
+	download := func(ref string) (string, error) {
+		t.Helper()
+		result, _, err := clusterToolkitDownload(context.Background(), nil, &clusterToolkitDownloadArgs{DownloadDirectory: parent, Ref: ref})
+		if err != nil {
+			return "", err
+		}
+		return result.Content[0].(*mcp.TextContent).Text, nil
+	}
+
+	got, err := download("")
+	if err != nil {
+		t.Fatalf("clusterToolkitDownload() returned unexpected error: %v", err)
+	}
+	for _, want := range []string{"Downloaded Cluster Toolkit to " + dir + " as a tarball", "Ref: default branch\n", "Commit: " + tarballCommit + "\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("clusterToolkitDownload() = %q, want it to contain %q", got, want)
+		}
+	}
+	if marker, err := os.ReadFile(filepath.Join(dir, refMarkerFile)); err != nil || string(marker) != "HEAD\n" {
+		t.Errorf("ref marker = %q, %v, want %q", marker, err, "HEAD\n")
+	}
+
+	// A later download replaces the previous one.
+	if _, err := download("v1.60.0"); err != nil {
+		t.Fatalf("clusterToolkitDownload() of a tag returned unexpected error: %v", err)
+	}
+	if marker, err := os.ReadFile(filepath.Join(dir, refMarkerFile)); err != nil || string(marker) != "v1.60.0\n" {
+		t.Errorf("ref marker = %q, %v, want %q", marker, err, "v1.60.0\n")
+	}
+	if want := []string{"/HEAD.tar.gz", "/v1.60.0.tar.gz"}; strings.Join(gotPaths, ",") != strings.Join(want, ",") {
+		t.Errorf("downloaded %v, want %v", gotPaths, want)
+	}
+
+	// A failed download keeps the previous one.
+	if _, err := download("missing"); err == nil || !strings.Contains(err.Error(), "status code: 404") {
+		t.Errorf("clusterToolkitDownload() of a missing ref error = %v, want a status code error", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "README.md")); err != nil {
+		t.Errorf("failed download removed the previous download: %v", err)
+	}
+
+	other := filepath.Join(t.TempDir(), "cluster-toolkit")
+	if err := os.MkdirAll(other, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(other, "notes.txt"), []byte("mine"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := clusterToolkitDownload(context.Background(), nil, &clusterToolkitDownloadArgs{DownloadDirectory: other}); err == nil || !strings.Contains(err.Error(), "is not a download of Cluster Toolkit") {
+		t.Errorf("clusterToolkitDownload() into an unrelated directory error = %v, want a conflict error", err)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type checkCostAllocationArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID of the cluster. Use the default if the user doesn't provide it."`
+	Location  string `json:"location,omitempty" jsonschema:"GKE cluster location. Use the default if the user doesn't provide it."`
+	Name      string `json:"name" jsonschema:"GKE cluster name. Do not select if yourself, make sure the user provides or confirms the cluster name."`
+}
+
+func installCheckCostAllocationTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "check_cost_allocation_enabled",
+		Description: "Check whether GKE Cost Allocation is enabled on a cluster. Without it the billing export has no namespace or workload labels and the per namespace and per workload cost tools return no rows. Returns the command to enable it when it is disabled.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.checkCostAllocation)
+}
+
+func (h *handlers) checkCostAllocation(ctx context.Context, _ *mcp.CallToolRequest, args *checkCostAllocationArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = h.c.DefaultLocation()
+	}
+	if args.ProjectID == "" {
+		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
+	}
+	if args.Location == "" {
+		return nil, nil, fmt.Errorf("location argument cannot be empty")
+	}
+	if args.Name == "" {
+		return nil, nil, fmt.Errorf("name argument cannot be empty")
+	}
+	enabled, err := h.costAllocationEnabled(ctx, args.ProjectID, args.Location, args.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatCostAllocation(args.ProjectID, args.Location, args.Name, enabled)},
+		},
+	}, nil, nil
+}
+
+// costAllocationEnabled gets the cluster and returns whether GKE Cost
+// Allocation is enabled on it.
+func (h *handlers) costAllocationEnabled(ctx context.Context, projectID, location, name string) (bool, error) {
+	cluster, err := h.getCluster(ctx, projectID, location, name)
+	if err != nil {
+		return false, err
+	}
+	return cluster.GetCostManagementConfig().GetEnabled(), nil
+}
+
+func (h *handlers) getCluster(ctx context.Context, projectID, location, name string) (*containerpb.Cluster, error) {
+	var cluster *containerpb.Cluster
+	err := retry.Do(ctx, retry.DefaultPolicy, func() error {
+		var err error
+		cluster, err = h.cmClient.GetCluster(ctx, &containerpb.GetClusterRequest{
+			Name: fmt.Sprintf("projects/%s/locations/%s/clusters/%s", projectID, location, name),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s: %w", name, err)
+	}
+	return cluster, nil
+}
+
+// costAllocationWarning returns a warning for the per namespace cost tools if
+// cost allocation is disabled on the cluster. The check is skipped if the
+// cluster cannot be read, e.g. without credentials.
+func (h *handlers) costAllocationWarning(ctx context.Context, projectID, location, name string) string {
+	enabled, err := h.costAllocationEnabled(ctx, projectID, location, name)
+	if err != nil {
+		slog.Info("Skipping the cost allocation check", "err", err)
+		return ""
+	}
+	if enabled {
+		return ""
+	}
+	return fmt.Sprintf("GKE Cost Allocation is disabled on cluster %s, so the billing export has no namespace labels for it and the results will be empty or unallocated. Enable it with:\n\n%s", name, enableCostAllocationCommand(projectID, location, name))
+}
+
+func enableCostAllocationCommand(projectID, location, name string) string {
+	return fmt.Sprintf("gcloud container clusters update %s --location %s --project %s --enable-cost-allocation", name, location, projectID)
+}
+
+func formatCostAllocation(projectID, location, name string, enabled bool) string {
+	if enabled {
+		return fmt.Sprintf("GKE Cost Allocation is enabled on cluster %s.\n\nCosts are labeled with their namespace and workload from the time it was enabled on. Costs incurred before are not labeled, and new costs take up to a day to show up in the billing export. If it was enabled recently, expect namespace data from about a day after enabling it, and set start_date of the cost tools to that day.\n", name)
+	}
+	return fmt.Sprintf("GKE Cost Allocation is disabled on cluster %s.\n\nThe billing export has no namespace or workload labels for the cluster, so the per namespace and per workload cost tools return no rows for it. Enable it with:\n\n%s\n\nOnly costs incurred after enabling it are labeled, and they take up to a day to show up in the billing export.\n", name, enableCostAllocationCommand(projectID, location, name))
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatCostAllocation(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		want      []string
+		wantNoCmd bool
+	}{
+		{
+			name:    "disabled",
+			enabled: false,
+			want: []string{
+				"GKE Cost Allocation is disabled on cluster prod.",
+				"gcloud container clusters update prod --location us-central1 --project p --enable-cost-allocation",
+			},
+		},
+		{
+			name:      "enabled",
+			enabled:   true,
+			want:      []string{"GKE Cost Allocation is enabled on cluster prod.", "about a day after enabling it"},
+			wantNoCmd: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := formatCostAllocation("p", "us-central1", "prod", tc.enabled)
+			for _, want := range tc.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("formatCostAllocation() = %s, want it to contain %q", got, want)
+				}
+			}
+			if tc.wantNoCmd && strings.Contains(got, "--enable-cost-allocation") {
+				t.Errorf("formatCostAllocation() = %s, want no enable command", got)
+			}
+		})
+	}
+}
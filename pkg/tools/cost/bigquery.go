@@ -0,0 +1,201 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	// defaultMaxScanBytes is the largest scan an executed query may do
+	// without allow_large_scan.
+	defaultMaxScanBytes int64 = 10 << 30
+
+	// maxResultRows is the number of rows read from an executed query.
+	maxResultRows = 1000
+
+	// queryJobTimeout bounds an executed query job. Its API calls are bounded
+	// by the call timeout.
+	queryJobTimeout = 10 * time.Minute
+
+	// costColumn is the column summed up into the totals of a result.
+	costColumn = "cost"
+	// currencyColumn is the column the totals of a result are split by.
+	currencyColumn = "currency"
+)
+
+// queryResult holds the rows of an executed query.
+type queryResult struct {
+	columns      []string
+	rows         [][]bigquery.Value
+	bytesScanned int64
+	truncated    bool
+	// limit is the LIMIT of the query, 0 if it has none. Reaching it means
+	// the rows may be the top ones of more.
+	limit int
+}
+
+// bigQueryClient returns a BigQuery client running jobs in projectID.
+func (h *handlers) bigQueryClient(ctx context.Context, projectID string) (*bigquery.Client, error) {
+	opts, err := h.c.RESTClientOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return bigquery.NewClient(ctx, projectID, opts...)
+}
+
+// runQuery dry-runs sql to check how many bytes it scans, then runs it in
+// projectID, the project of the billing export dataset.
+func (h *handlers) runQuery(ctx context.Context, projectID, sql string, allowLargeScan bool, maxScanBytes int64) (*queryResult, error) {
+	c, err := h.bigQueryClient(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	defer c.Close()
+
+	dryRun := c.Query(sql)
+	dryRun.DryRun = true
+	job, err := dryRun.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dry run query: %w", err)
+	}
+	var scanned int64
+	if stats := job.LastStatus().Statistics; stats != nil {
+		scanned = stats.TotalBytesProcessed
+	}
+	if err := checkScan(scanned, maxScanBytes, allowLargeScan); err != nil {
+		return nil, err
+	}
+
+	q := c.Query(sql)
+	q.JobTimeout = queryJobTimeout
+	it, err := q.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run query: %w", err)
+	}
+	res := &queryResult{bytesScanned: scanned}
+	for {
+		var row []bigquery.Value
+		err := it.Next(&row)
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read query results: %w", err)
+		}
+		if len(res.rows) == maxResultRows {
+			res.truncated = true
+			break
+		}
+		res.rows = append(res.rows, row)
+	}
+	for _, f := range it.Schema {
+		res.columns = append(res.columns, f.Name)
+	}
+	return res, nil
+}
+
+// checkScan refuses queries scanning more than maxScanBytes unless
+// allowLargeScan is set.
+func checkScan(scanned, maxScanBytes int64, allowLargeScan bool) error {
+	if maxScanBytes <= 0 {
+		maxScanBytes = defaultMaxScanBytes
+	}
+	if scanned > maxScanBytes && !allowLargeScan {
+		return fmt.Errorf("the query would scan %s, more than the limit of %s; narrow start_date, or confirm with the user and set allow_large_scan to run it anyway", formatBytes(scanned), formatBytes(maxScanBytes))
+	}
+	return nil
+}
+
+func formatBytes(b int64) string {
+	return strconv.FormatFloat(float64(b)/(1<<30), 'f', 2, 64) + " GiB"
+}
+
+// formatQueryResult renders the rows as a markdown table, followed by the
+// total of the cost column per currency. If the rows may not be all of them,
+// the total is labeled as the one of the rows shown.
+func formatQueryResult(res *queryResult) string {
+	builder := new(strings.Builder)
+	fmt.Fprintf(builder, "Query scanned %s and returned %d rows.\n", formatBytes(res.bytesScanned), len(res.rows))
+	if len(res.rows) == 0 {
+		return builder.String()
+	}
+
+	builder.WriteString("\n| " + strings.Join(res.columns, " | ") + " |\n")
+	builder.WriteString("|" + strings.Repeat("---|", len(res.columns)) + "\n")
+	costIndex := slices.Index(res.columns, costColumn)
+	currencyIndex := slices.Index(res.columns, currencyColumn)
+	totals := map[string]float64{}
+	for _, row := range res.rows {
+		values := make([]string, len(row))
+		for i, v := range row {
+			values[i] = formatValue(v)
+		}
+		builder.WriteString("| " + strings.Join(values, " | ") + " |\n")
+		if costIndex < 0 || costIndex >= len(row) {
+			continue
+		}
+		var currency string
+		if currencyIndex >= 0 && currencyIndex < len(row) {
+			currency, _ = row[currencyIndex].(string)
+		}
+		if f, ok := row[costIndex].(float64); ok {
+			totals[currency] += f
+		}
+	}
+	label := "Total cost"
+	if res.truncated || res.limit > 0 && len(res.rows) >= res.limit {
+		label = "Total of the rows shown"
+	}
+	currencies := slices.Sorted(maps.Keys(totals))
+	for _, c := range currencies {
+		fmt.Fprintf(builder, "\n%s: %s", label, strconv.FormatFloat(totals[c], 'f', 2, 64))
+		if c != "" {
+			builder.WriteString(" " + c)
+		}
+	}
+	if len(currencies) > 0 {
+		builder.WriteString("\n")
+	}
+	if len(currencies) > 1 {
+		fmt.Fprintf(builder, "\n\nWarning: The results contain more than one currency (%s). Costs in different currencies cannot be added up.", strings.Join(currencies, ", "))
+	}
+	if res.truncated {
+		fmt.Fprintf(builder, "\n\nWarning: Results limited to %d rows.", maxResultRows)
+	}
+	return builder.String()
+}
+
+// formatValue renders a result value. Floats are costs and are rounded to
+// cents.
+func formatValue(v bigquery.Value) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+	return fmt.Sprint(v)
+}
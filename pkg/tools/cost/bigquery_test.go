@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestCheckScan(t *testing.T) {
+	tests := []struct {
+		name           string
+		scanned, max   int64
+		allowLargeScan bool
+		wantErr        bool
+	}{
+		{name: "below default", scanned: 1 << 30},
+		{name: "above default", scanned: 11 << 30, wantErr: true},
+		{name: "above default allowed", scanned: 11 << 30, allowLargeScan: true},
+		{name: "above custom", scanned: 2 << 30, max: 1 << 30, wantErr: true},
+		{name: "below custom", scanned: 20 << 30, max: 50 << 30},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := checkScan(tc.scanned, tc.max, tc.allowLargeScan); (err != nil) != tc.wantErr {
+				t.Errorf("checkScan() error = %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatQueryResult(t *testing.T) {
+	tests := []struct {
+		name string
+		res  *queryResult
+		want string
+	}{
+		{
+			name: "one currency",
+			res: &queryResult{
+				columns:      []string{"currency", "namespace", "cost", "cost_before_credits"},
+				rows:         [][]bigquery.Value{{"USD", "default", 12.504, 13.0}, {"USD", nil, 0.25, 0.25}},
+				bytesScanned: 3 << 29,
+			},
+			want: `Query scanned 1.50 GiB and returned 2 rows.
+
+| currency | namespace | cost | cost_before_credits |
+|---|---|---|---|
+| USD | default | 12.50 | 13.00 |
+| USD | NULL | 0.25 | 0.25 |
+
+Total cost: 12.75 USD
+`,
+		},
+		{
+			name: "several currencies",
+			res: &queryResult{
+				columns: []string{"project_id", "currency", "cost"},
+				rows:    [][]bigquery.Value{{"p", "USD", 10.0}, {"q", "EUR", 5.0}},
+			},
+			want: `Query scanned 0.00 GiB and returned 2 rows.
+
+| project_id | currency | cost |
+|---|---|---|
+| p | USD | 10.00 |
+| q | EUR | 5.00 |
+
+Total cost: 5.00 EUR
+Total cost: 10.00 USD
+
+
+Warning: The results contain more than one currency (EUR, USD). Costs in different currencies cannot be added up.`,
+		},
+		{
+			name: "limit reached",
+			res: &queryResult{
+				columns: []string{"namespace", "currency", "cost"},
+				rows:    [][]bigquery.Value{{"default", "USD", 10.0}, {"kube-system", "USD", 5.0}},
+				limit:   2,
+			},
+			want: `Query scanned 0.00 GiB and returned 2 rows.
+
+| namespace | currency | cost |
+|---|---|---|
+| default | USD | 10.00 |
+| kube-system | USD | 5.00 |
+
+Total of the rows shown: 15.00 USD
+`,
+		},
+		{
+			name: "below limit",
+			res: &queryResult{
+				columns: []string{"namespace", "currency", "cost"},
+				rows:    [][]bigquery.Value{{"default", "USD", 10.0}},
+				limit:   2,
+			},
+			want: `Query scanned 0.00 GiB and returned 1 rows.
+
+| namespace | currency | cost |
+|---|---|---|
+| default | USD | 10.00 |
+
+Total cost: 10.00 USD
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := formatQueryResult(tc.res); got != tc.want {
+				t.Errorf("formatQueryResult() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	container "cloud.google.com/go/container/apiv1"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var (
+	// billingAccountRE matches billing account IDs, e.g. 012345-6789AB-CDEF01.
+	billingAccountRE = regexp.MustCompile(`^[0-9A-Fa-f]{6}-[0-9A-Fa-f]{6}-[0-9A-Fa-f]{6}$`)
+	// datasetProjectRE and datasetNameRE match the project and the name of a
+	// BigQuery dataset. Both end up in a quoted identifier of the queries.
+	datasetProjectRE = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+	datasetNameRE    = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+)
+
+const (
+	defaultCostDays     = 30
+	defaultCostRowLimit = 10
+	maxCostRowLimit     = 1000
+)
+
+// costFromTemplate selects the detailed export rows of a cluster since the
+// start date. It is shared by all cost queries, which group their results by
+// currency since a billing account can export several. User provided strings
+// must go through quote, identifiers are validated by newCostQuery.
+const costFromTemplate = `{{define "from"}}FROM ` + "`{{.DatasetProjectID}}.{{.DatasetName}}.{{.Table}}`" + ` AS bqe
+WHERE _PARTITIONTIME >= TIMESTAMP("{{.StartDate}}")
+  AND project.id IN ({{range $i, $p := .ProjectIDs}}{{if $i}}, {{end}}{{quote $p}}{{end}})
+  AND EXISTS(SELECT * FROM bqe.labels AS l WHERE l.key = "goog-k8s-cluster-location" AND l.value = {{quote .Location}})
+  AND EXISTS(SELECT * FROM bqe.labels AS l WHERE l.key = "goog-k8s-cluster-name" AND l.value = {{quote .Name}})
+{{- end}}`
+
+// clusterCostTemplate sums the cost of a cluster, with and without credits.
+const clusterCostTemplate = `SELECT
+  project.id AS project_id,
+  currency,
+  SUM(cost) + SUM(IFNULL((SELECT SUM(c.amount) FROM UNNEST(credits) c), 0)) AS cost,
+  SUM(cost) AS cost_before_credits,
+{{template "from" .}}
+GROUP BY 1, 2
+ORDER BY 3 DESC
+`
+
+// clusterCostByNamespaceTemplate breaks the cost of a cluster down by
+// Kubernetes namespace, which requires GKE Cost Allocation.
+const clusterCostByNamespaceTemplate = `SELECT
+  project.id AS project_id,
+  currency,
+  (SELECT l.value FROM bqe.labels AS l WHERE l.key = "k8s-namespace") AS namespace,
+  SUM(cost) + SUM(IFNULL((SELECT SUM(c.amount) FROM UNNEST(credits) c), 0)) AS cost,
+  SUM(cost) AS cost_before_credits,
+{{template "from" .}}
+GROUP BY 1, 2, 3
+ORDER BY 4 DESC
+LIMIT {{.Limit}}
+`
+
+var (
+	clusterCostTmpl            = newCostTemplate("cluster-cost", clusterCostTemplate)
+	clusterCostByNamespaceTmpl = newCostTemplate("cluster-cost-by-namespace", clusterCostByNamespaceTemplate)
+)
+
+type handlers struct {
+	c        *config.Config
+	cmClient *container.ClusterManagerClient
+}
+
+type clusterCostArgs struct {
+	ProjectID        string   `json:"project_id,omitempty" jsonschema:"GCP project ID of the cluster. Use the default if the user doesn't provide it."`
+	ProjectIDs       []string `json:"project_ids,omitempty" jsonschema:"Other projects to include, e.g. to compare clusters across the projects of a shared billing account. Results are grouped by project."`
+	Location         string   `json:"location,omitempty" jsonschema:"GKE cluster location. Use the default if the user doesn't provide it."`
+	Name             string   `json:"name" jsonschema:"GKE cluster name. Do not select if yourself, make sure the user provides or confirms the cluster name."`
+	BQDatasetID      string   `json:"bq_dataset_id,omitempty" jsonschema:"BigQuery dataset of the detailed billing export, as project.dataset or project:dataset. Discovered from the billing account of the project if omitted."`
+	BillingAccountID string   `json:"billing_account_id,omitempty" jsonschema:"Billing account ID, e.g. 012345-6789AB-CDEF01. The export table is gcp_billing_export_resource_v1_<billing account ID>. Defaults to the billing account of the project."`
+	StartDate        string   `json:"start_date,omitempty" jsonschema:"First day to include, as YYYY-MM-DD. Defaults to 30 days ago."`
+	Execute          bool     `json:"execute,omitempty" jsonschema:"Run the query in BigQuery and return the results instead of only returning the query."`
+	AllowLargeScan   bool     `json:"allow_large_scan,omitempty" jsonschema:"Run the query even if the dry run shows it scans more than max_scan_bytes. Only set this when the user confirms."`
+	MaxScanBytes     int64    `json:"max_scan_bytes,omitempty" jsonschema:"Largest number of bytes an executed query may scan without allow_large_scan. Defaults to 10 GiB."`
+	SkipTableCheck   bool     `json:"skip_table_check,omitempty" jsonschema:"Don't check that the billing export table exists and is a detailed export, e.g. to only generate the query without access to BigQuery."`
+}
+
+type clusterCostByNamespaceArgs struct {
+	ProjectID        string   `json:"project_id,omitempty" jsonschema:"GCP project ID of the cluster. Use the default if the user doesn't provide it."`
+	ProjectIDs       []string `json:"project_ids,omitempty" jsonschema:"Other projects to include, e.g. to compare clusters across the projects of a shared billing account. Results are grouped by project."`
+	Location         string   `json:"location,omitempty" jsonschema:"GKE cluster location. Use the default if the user doesn't provide it."`
+	Name             string   `json:"name" jsonschema:"GKE cluster name. Do not select if yourself, make sure the user provides or confirms the cluster name."`
+	BQDatasetID      string   `json:"bq_dataset_id,omitempty" jsonschema:"BigQuery dataset of the detailed billing export, as project.dataset or project:dataset. Discovered from the billing account of the project if omitted."`
+	BillingAccountID string   `json:"billing_account_id,omitempty" jsonschema:"Billing account ID, e.g. 012345-6789AB-CDEF01. The export table is gcp_billing_export_resource_v1_<billing account ID>. Defaults to the billing account of the project."`
+	StartDate        string   `json:"start_date,omitempty" jsonschema:"First day to include, as YYYY-MM-DD. Defaults to 30 days ago."`
+	Limit            int      `json:"limit,omitempty" jsonschema:"Maximum number of namespaces to return. Defaults to 10."`
+	Execute          bool     `json:"execute,omitempty" jsonschema:"Run the query in BigQuery and return the results instead of only returning the query."`
+	AllowLargeScan   bool     `json:"allow_large_scan,omitempty" jsonschema:"Run the query even if the dry run shows it scans more than max_scan_bytes. Only set this when the user confirms."`
+	MaxScanBytes     int64    `json:"max_scan_bytes,omitempty" jsonschema:"Largest number of bytes an executed query may scan without allow_large_scan. Defaults to 10 GiB."`
+	SkipTableCheck   bool     `json:"skip_table_check,omitempty" jsonschema:"Don't check that the billing export table exists and is a detailed export, e.g. to only generate the query without access to BigQuery."`
+}
+
+// Install adds the GKE cost tools to an MCP server.
+func Install(ctx context.Context, s *mcp.Server, c *config.Config) error {
+	cmClient, err := container.NewClusterManagerClient(ctx, c.ClientOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to create cluster manager client: %w", err)
+	}
+	c.OnClose(cmClient.Close)
+
+	h := &handlers{
+		c:        c,
+		cmClient: cmClient,
+	}
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "cluster_cost",
+		Description: "Get the cost of a GKE cluster from the GCP Billing Detailed BigQuery Export. Returns the BigQuery query, or runs it when execute is set. Prefer to use this tool instead of bq",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.clusterCost)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "cluster_cost_by_namespace",
+		Description: "Get the cost of a GKE cluster per Kubernetes namespace from the GCP Billing Detailed BigQuery Export. Requires GKE Cost Allocation to be enabled on the cluster, which is checked first. Returns the BigQuery query, or runs it when execute is set. Prefer to use this tool instead of bq",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.clusterCostByNamespace)
+
+	installClusterCostByWorkloadTool(s, h)
+	installClusterCostTrendTool(s, h)
+	installClusterCostSavingsTool(s, h)
+	installDiscoverBillingExportTool(s, h)
+	installCheckCostAllocationTool(s, h)
+
+	return nil
+}
+
+func (h *handlers) clusterCost(ctx context.Context, _ *mcp.CallToolRequest, args *clusterCostArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = h.c.DefaultLocation()
+	}
+	note, err := h.fillBillingExport(ctx, args.ProjectID, &args.BQDatasetID, &args.BillingAccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	q, err := newCostQuery(args.ProjectID, args.Location, args.Name, args.BQDatasetID, args.BillingAccountID, args.StartDate, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	q.addProjects(args.ProjectIDs)
+	if note != "" {
+		q.notes = append(q.notes, note)
+	}
+	sql, err := q.render(clusterCostTmpl)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.costResult(ctx, q, sql, runOptions{
+		execute:        args.Execute,
+		allowLargeScan: args.AllowLargeScan,
+		maxScanBytes:   args.MaxScanBytes,
+		skipTableCheck: args.SkipTableCheck,
+	})
+}
+
+func (h *handlers) clusterCostByNamespace(ctx context.Context, _ *mcp.CallToolRequest, args *clusterCostByNamespaceArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = h.c.DefaultLocation()
+	}
+	note, err := h.fillBillingExport(ctx, args.ProjectID, &args.BQDatasetID, &args.BillingAccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	q, err := newCostQuery(args.ProjectID, args.Location, args.Name, args.BQDatasetID, args.BillingAccountID, args.StartDate, args.Limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	q.addProjects(args.ProjectIDs)
+	if note != "" {
+		q.notes = append(q.notes, note)
+	}
+	if w := h.costAllocationWarning(ctx, args.ProjectID, args.Location, args.Name); w != "" {
+		q.warnings = append(q.warnings, w)
+	}
+	sql, err := q.render(clusterCostByNamespaceTmpl)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.costResult(ctx, q, sql, runOptions{
+		execute:        args.Execute,
+		allowLargeScan: args.AllowLargeScan,
+		maxScanBytes:   args.MaxScanBytes,
+		skipTableCheck: args.SkipTableCheck,
+		rowLimit:       q.Limit,
+	})
+}
+
+// runOptions are the arguments shared by the cost tools that control how
+// their query is checked and run.
+type runOptions struct {
+	execute        bool
+	allowLargeScan bool
+	maxScanBytes   int64
+	skipTableCheck bool
+	// rowLimit is the LIMIT of the query, 0 if it has none.
+	rowLimit int
+	// render renders the results of an executed query. Defaults to
+	// formatQueryResult.
+	render func(*queryResult) string
+}
+
+// costResult returns the query as a bq command, or its results if execute is
+// set.
+func (h *handlers) costResult(ctx context.Context, q *costQuery, sql string, opts runOptions) (*mcp.CallToolResult, any, error) {
+	if !opts.skipTableCheck {
+		if err := h.checkExportTable(ctx, q); err != nil {
+			return nil, nil, err
+		}
+	}
+	var text string
+	if opts.execute {
+		res, err := h.runQuery(ctx, q.DatasetProjectID, sql, opts.allowLargeScan, opts.maxScanBytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		res.limit = opts.rowLimit
+		render := opts.render
+		if render == nil {
+			render = formatQueryResult
+		}
+		text = render(res) + "\nQuery:\n" + sql
+	} else {
+		text = fmt.Sprintf("Run this query with the bq CLI, or call this tool again with execute set to run it:\n\nbq query --nouse_legacy_sql '\n%s'\n", sql)
+	}
+	for i := len(q.warnings) - 1; i >= 0; i-- {
+		text = fmt.Sprintf("Warning: %s\n\n", q.warnings[i]) + text
+	}
+	for _, note := range q.notes {
+		text += fmt.Sprintf("\nNote: %s\n", note)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// costQuery holds the values interpolated into the cost query templates.
+type costQuery struct {
+	DatasetProjectID string
+	DatasetName      string
+	Table            string
+	StartDate        string
+	ProjectIDs       []string
+	Location         string
+	Name             string
+	Namespace        string
+	Limit            int
+
+	// notes are shown along with the query or its results.
+	notes []string
+	// warnings are shown before the query or its results.
+	warnings []string
+}
+
+// newCostQuery validates the arguments of the cost tools and applies their
+// defaults.
+func newCostQuery(projectID, location, name, datasetID, billingAccountID, startDate string, limit int) (*costQuery, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("project_id argument cannot be empty")
+	}
+	if location == "" {
+		return nil, fmt.Errorf("location argument cannot be empty")
+	}
+	if name == "" {
+		return nil, fmt.Errorf("name argument cannot be empty")
+	}
+	if billingAccountID == "" {
+		return nil, fmt.Errorf("billing_account_id argument cannot be empty")
+	}
+	if !billingAccountRE.MatchString(billingAccountID) {
+		return nil, fmt.Errorf("billing_account_id must be of the form 012345-6789AB-CDEF01, got %q", billingAccountID)
+	}
+	// The bq CLI separates the project and the dataset with a colon, SQL with
+	// a dot.
+	datasetProject, datasetName, ok := strings.Cut(strings.Replace(datasetID, ":", ".", 1), ".")
+	if !ok || !datasetProjectRE.MatchString(datasetProject) || !datasetNameRE.MatchString(datasetName) {
+		return nil, fmt.Errorf("bq_dataset_id must be of the form project.dataset, got %q", datasetID)
+	}
+	if startDate == "" {
+		startDate = time.Now().AddDate(0, 0, -defaultCostDays).Format(time.DateOnly)
+	}
+	if _, err := time.Parse(time.DateOnly, startDate); err != nil {
+		return nil, fmt.Errorf("start_date must be a date of the form YYYY-MM-DD, got %q", startDate)
+	}
+	if limit == 0 {
+		limit = defaultCostRowLimit
+	}
+	if limit < 1 || limit > maxCostRowLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxCostRowLimit)
+	}
+	return &costQuery{
+		DatasetProjectID: datasetProject,
+		DatasetName:      datasetName,
+		Table:            exportTable(billingAccountID),
+		StartDate:        startDate,
+		ProjectIDs:       []string{projectID},
+		Location:         location,
+		Name:             name,
+		Limit:            limit,
+	}, nil
+}
+
+// newCostTemplate parses a cost query template, which can use the "from"
+// template for the rows of the cluster and quote for string literals.
+func newCostTemplate(name, text string) *template.Template {
+	t := template.New(name).Funcs(template.FuncMap{"quote": quote})
+	return template.Must(template.Must(t.Parse(costFromTemplate)).Parse(text))
+}
+
+// quote returns s as a BigQuery string literal. Besides the characters SQL
+// requires to be escaped, single quotes are escaped too since the queries are
+// shown wrapped in single quotes for the shell.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\'' || r == '`' || r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// addProjects adds more projects to the one of the cluster.
+func (q *costQuery) addProjects(projectIDs []string) {
+	for _, p := range projectIDs {
+		if p != "" && !slices.Contains(q.ProjectIDs, p) {
+			q.ProjectIDs = append(q.ProjectIDs, p)
+		}
+	}
+}
+
+func (q *costQuery) render(tmpl *template.Template) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, q); err != nil {
+		return "", fmt.Errorf("failed to execute query template: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,289 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestNewCostQuery(t *testing.T) {
+	const account = "012345-6789AB-CDEF01"
+	tests := []struct {
+		name      string
+		datasetID string
+		account   string
+		startDate string
+		limit     int
+		want      costQuery
+		wantErr   bool
+	}{
+		{
+			name:      "dot separated dataset",
+			datasetID: "billing-project.billing_export",
+			account:   "012345-6789AB-CDEF01",
+			startDate: "2025-06-01",
+			want: costQuery{
+				DatasetProjectID: "billing-project",
+				DatasetName:      "billing_export",
+				Table:            "gcp_billing_export_resource_v1_012345_6789AB_CDEF01",
+				StartDate:        "2025-06-01",
+				ProjectIDs:       []string{"p"},
+				Location:         "us-central1",
+				Name:             "prod",
+				Limit:            defaultCostRowLimit,
+			},
+		},
+		{
+			name:      "colon separated dataset",
+			datasetID: "billing-project:billing_export",
+			account:   "012345-6789AB-CDEF01",
+			startDate: "2025-06-01",
+			limit:     25,
+			want: costQuery{
+				DatasetProjectID: "billing-project",
+				DatasetName:      "billing_export",
+				Table:            "gcp_billing_export_resource_v1_012345_6789AB_CDEF01",
+				StartDate:        "2025-06-01",
+				ProjectIDs:       []string{"p"},
+				Location:         "us-central1",
+				Name:             "prod",
+				Limit:            25,
+			},
+		},
+		{name: "dataset without project", datasetID: "billing_export", account: account, wantErr: true},
+		{name: "missing billing account", datasetID: "p.d", wantErr: true},
+		{name: "invalid start date", datasetID: "p.d", account: account, startDate: "June 1st", wantErr: true},
+		{name: "limit too large", datasetID: "p.d", account: account, limit: maxCostRowLimit + 1, wantErr: true},
+		{name: "quote in billing account", datasetID: "p.d", account: `012345-6789AB-CDEF01" OR "1"="1`, wantErr: true},
+		{name: "backtick in dataset project", datasetID: "p`.d", account: account, wantErr: true},
+		{name: "backtick in dataset name", datasetID: "p.d` AS bqe; DROP TABLE x; --", account: account, wantErr: true},
+		{name: "table in dataset", datasetID: "p.d.t", account: account, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := newCostQuery("p", "us-central1", "prod", tc.datasetID, tc.account, tc.startDate, tc.limit)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("newCostQuery() error = %v, want error %v", err, tc.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(*got, tc.want) {
+				t.Errorf("newCostQuery() = %+v, want %+v", *got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewCostQueryDefaultStartDate(t *testing.T) {
+	got, err := newCostQuery("p", "us-central1", "prod", "p.d", "012345-6789AB-CDEF01", "", 0)
+	if err != nil {
+		t.Fatalf("newCostQuery() error = %v", err)
+	}
+	want := time.Now().AddDate(0, 0, -defaultCostDays).Format(time.DateOnly)
+	if got.StartDate != want {
+		t.Errorf("newCostQuery() start date = %q, want %q", got.StartDate, want)
+	}
+}
+
+func TestRenderClusterCostByNamespace(t *testing.T) {
+	q, err := newCostQuery("p", "us-central1", "prod", "billing-project.export", "012345-6789AB-CDEF01", "2025-06-01", 5)
+	if err != nil {
+		t.Fatalf("newCostQuery() error = %v", err)
+	}
+	got, err := q.render(clusterCostByNamespaceTmpl)
+	if err != nil {
+		t.Fatalf("render() error = %v", err)
+	}
+	for _, want := range []string{
+		"FROM `billing-project.export.gcp_billing_export_resource_v1_012345_6789AB_CDEF01` AS bqe",
+		`_PARTITIONTIME >= TIMESTAMP("2025-06-01")`,
+		`project.id IN ("p")`,
+		`l.key = "goog-k8s-cluster-location" AND l.value = "us-central1"`,
+		`l.key = "goog-k8s-cluster-name" AND l.value = "prod"`,
+		`l.key = "k8s-namespace"`,
+		"GROUP BY 1, 2, 3\nORDER BY 4 DESC\nLIMIT 5",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("render() = %s, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestHandlerArgumentValidation(t *testing.T) {
+	h := &handlers{c: &config.Config{}}
+	ctx := context.Background()
+	const dataset, account = "b.export", "012345-6789AB-CDEF01"
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr string
+	}{
+		{
+			name: "cluster_cost without project",
+			call: func() error {
+				_, _, err := h.clusterCost(ctx, nil, &clusterCostArgs{Location: "us-central1", Name: "prod", BQDatasetID: dataset, BillingAccountID: account})
+				return err
+			},
+			wantErr: "project_id argument cannot be empty",
+		},
+		{
+			name: "cluster_cost without name",
+			call: func() error {
+				_, _, err := h.clusterCost(ctx, nil, &clusterCostArgs{ProjectID: "p", Location: "us-central1", BQDatasetID: dataset, BillingAccountID: account})
+				return err
+			},
+			wantErr: "name argument cannot be empty",
+		},
+		{
+			name: "cluster_cost_by_namespace with invalid dataset",
+			call: func() error {
+				_, _, err := h.clusterCostByNamespace(ctx, nil, &clusterCostByNamespaceArgs{ProjectID: "p", Location: "us-central1", Name: "prod", BQDatasetID: "export", BillingAccountID: account})
+				return err
+			},
+			wantErr: "bq_dataset_id must be of the form project.dataset",
+		},
+		{
+			name: "cluster_cost_by_workload with invalid limit",
+			call: func() error {
+				_, _, err := h.clusterCostByWorkload(ctx, nil, &clusterCostByWorkloadArgs{ProjectID: "p", Location: "us-central1", Name: "prod", BQDatasetID: dataset, BillingAccountID: account, Limit: -1})
+				return err
+			},
+			wantErr: "limit must be between 1 and",
+		},
+		{
+			name: "cluster_cost_trend with invalid start date",
+			call: func() error {
+				_, _, err := h.clusterCostTrend(ctx, nil, &clusterCostTrendArgs{ProjectID: "p", Location: "us-central1", Name: "prod", BQDatasetID: dataset, BillingAccountID: account, StartDate: "2025-13-01"})
+				return err
+			},
+			wantErr: "start_date must be a date",
+		},
+		{
+			name: "cluster_cost_trend with negative threshold",
+			call: func() error {
+				_, _, err := h.clusterCostTrend(ctx, nil, &clusterCostTrendArgs{ProjectID: "p", Location: "us-central1", Name: "prod", JumpThresholdPercent: -5})
+				return err
+			},
+			wantErr: "jump_threshold_percent must be positive",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call()
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestClusterCostReturnsQuery(t *testing.T) {
+	h := &handlers{c: &config.Config{}}
+	res, _, err := h.clusterCost(context.Background(), nil, &clusterCostArgs{
+		ProjectID:        "p",
+		Location:         "us-central1",
+		Name:             "prod",
+		BQDatasetID:      "b:export",
+		BillingAccountID: "012345-6789AB-CDEF01",
+		StartDate:        "2025-06-01",
+		SkipTableCheck:   true,
+	})
+	if err != nil {
+		t.Fatalf("clusterCost() error = %v", err)
+	}
+	text := res.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"bq query --nouse_legacy_sql '", "FROM `b.export.gcp_billing_export_resource_v1_012345_6789AB_CDEF01` AS bqe"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("clusterCost() = %s, want it to contain %q", text, want)
+		}
+	}
+}
+
+func TestRenderWithProjects(t *testing.T) {
+	q, err := newCostQuery("p", "us-central1", "prod", "b.export", "012345-6789AB-CDEF01", "2025-06-01", 0)
+	if err != nil {
+		t.Fatalf("newCostQuery() error = %v", err)
+	}
+	q.addProjects([]string{"q", "p", "", "r"})
+	got, err := q.render(clusterCostTmpl)
+	if err != nil {
+		t.Fatalf("render() error = %v", err)
+	}
+	for _, want := range []string{
+		"project.id AS project_id,\n  currency,",
+		`AND project.id IN ("p", "q", "r")`,
+		"GROUP BY 1, 2\nORDER BY 3 DESC",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("render() = %s, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "us-central1", want: `"us-central1"`},
+		{in: `prod" OR "1"="1`, want: `"prod\" OR \"1\"=\"1"`},
+		{in: `a\`, want: `"a\\"`},
+		{in: "it's", want: `"it\x27s"`},
+		{in: "a`b", want: `"a\x60b"`},
+		{in: "a\nb", want: `"a\x0ab"`},
+	}
+	for _, tc := range tests {
+		if got := quote(tc.in); got != tc.want {
+			t.Errorf("quote(%q) = %s, want %s", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestRenderAdversarialValues(t *testing.T) {
+	const name = `prod") OR TRUE --`
+	q, err := newCostQuery(`p" OR "1"="1`, "us-central1'; rm -rf ~; '", name, "b.export", "012345-6789AB-CDEF01", "2025-06-01", 0)
+	if err != nil {
+		t.Fatalf("newCostQuery() error = %v", err)
+	}
+	q.Namespace = "default`"
+	got, err := q.render(clusterCostByWorkloadTmpl)
+	if err != nil {
+		t.Fatalf("render() error = %v", err)
+	}
+	for _, want := range []string{
+		`AND project.id IN ("p\" OR \"1\"=\"1")`,
+		`l.value = "us-central1\x27; rm -rf ~; \x27"`,
+		`l.value = "prod\") OR TRUE --"`,
+		`l.value = "default\x60"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("render() = %s, want it to contain %q", got, want)
+		}
+	}
+	// The query is shown in single quotes for the shell and the table in
+	// backticks, so neither may appear in a value.
+	if strings.Contains(got, "'") {
+		t.Errorf("render() = %s, want no single quotes", got)
+	}
+	if n := strings.Count(got, "`"); n != 2 {
+		t.Errorf("render() = %s, want 2 backticks around the table, got %d", got, n)
+	}
+}
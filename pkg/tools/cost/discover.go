@@ -0,0 +1,195 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+	billing "cloud.google.com/go/billing/apiv1"
+	billingpb "cloud.google.com/go/billing/apiv1/billingpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/iterator"
+)
+
+// maxSearchedDatasets is the number of datasets per project searched for the
+// billing export table.
+const maxSearchedDatasets = 100
+
+type discoverBillingExportArgs struct {
+	ProjectID        string   `json:"project_id,omitempty" jsonschema:"GCP project ID of the clusters. Use the default if the user doesn't provide it."`
+	SearchProjectIDs []string `json:"search_project_ids,omitempty" jsonschema:"Other projects to search for the billing export dataset, e.g. a central billing project. The project of the clusters is always searched."`
+}
+
+func installDiscoverBillingExportTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "discover_billing_export",
+		Description: "Find the billing account of a project and the BigQuery dataset holding its detailed billing export, for use as billing_account_id and bq_dataset_id of the cost tools. The result is remembered for the session and filled in by the cost tools automatically.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.discoverBillingExportTool)
+}
+
+func (h *handlers) discoverBillingExportTool(ctx context.Context, _ *mcp.CallToolRequest, args *discoverBillingExportArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.ProjectID == "" {
+		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
+	}
+	e, err := h.discoverBillingExport(ctx, args.ProjectID, "", args.SearchProjectIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	text := fmt.Sprintf("Billing account: %s\nDataset: %s\nTable: %s\n", e.BillingAccountID, e.DatasetID, exportTable(e.BillingAccountID))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// fillBillingExport discovers the dataset and billing account arguments the
+// user left empty. It returns a note naming the inferred values.
+func (h *handlers) fillBillingExport(ctx context.Context, projectID string, datasetID, billingAccountID *string) (string, error) {
+	if projectID == "" || (*datasetID != "" && *billingAccountID != "") {
+		return "", nil
+	}
+	if *datasetID != "" {
+		// The dataset is often in another project than the clusters, so it
+		// is not searched for; only the billing account is looked up.
+		account, err := h.projectBillingAccount(ctx, projectID)
+		if err != nil {
+			return "", fmt.Errorf("billing_account_id was not provided and could not be discovered: %w", err)
+		}
+		*billingAccountID = account
+		return fmt.Sprintf("Using the inferred billing account %s of project %s.", account, projectID), nil
+	}
+	e, err := h.discoverBillingExport(ctx, projectID, *billingAccountID, nil)
+	if err != nil {
+		return "", fmt.Errorf("bq_dataset_id and billing_account_id were not provided and could not be discovered: %w", err)
+	}
+	var inferred []string
+	if *billingAccountID == "" {
+		*billingAccountID = e.BillingAccountID
+		inferred = append(inferred, "billing account "+e.BillingAccountID)
+	}
+	if *datasetID == "" {
+		*datasetID = e.DatasetID
+		inferred = append(inferred, "dataset "+e.DatasetID)
+	}
+	return fmt.Sprintf("Using the inferred %s of project %s.", strings.Join(inferred, " and "), projectID), nil
+}
+
+// discoverBillingExport returns the billing export of projectID, from the
+// session cache or by looking up the billing account of the project, unless
+// given, and searching the datasets of the project and of searchProjects for
+// its detailed export table.
+func (h *handlers) discoverBillingExport(ctx context.Context, projectID, billingAccountID string, searchProjects []string) (config.BillingExport, error) {
+	if e, ok := h.c.BillingExport(projectID); ok && (billingAccountID == "" || billingAccountID == e.BillingAccountID) {
+		return e, nil
+	}
+	if billingAccountID == "" {
+		var err error
+		billingAccountID, err = h.projectBillingAccount(ctx, projectID)
+		if err != nil {
+			return config.BillingExport{}, err
+		}
+	}
+
+	c, err := h.bigQueryClient(ctx, projectID)
+	if err != nil {
+		return config.BillingExport{}, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	defer c.Close()
+
+	table := exportTable(billingAccountID)
+	projects := []string{projectID}
+	for _, p := range searchProjects {
+		if p != "" && !slices.Contains(projects, p) {
+			projects = append(projects, p)
+		}
+	}
+	for _, p := range projects {
+		datasetID, err := findExportDataset(ctx, c, p, table)
+		if err != nil {
+			return config.BillingExport{}, err
+		}
+		if datasetID != "" {
+			e := config.BillingExport{DatasetID: datasetID, BillingAccountID: billingAccountID}
+			h.c.SetBillingExport(projectID, e)
+			return e, nil
+		}
+	}
+	return config.BillingExport{}, fmt.Errorf("no dataset with table %s found in projects %s; enable the detailed billing export or pass the project of its dataset in search_project_ids", table, strings.Join(projects, ", "))
+}
+
+// projectBillingAccount returns the ID of the billing account of projectID.
+func (h *handlers) projectBillingAccount(ctx context.Context, projectID string) (string, error) {
+	c, err := billing.NewCloudBillingClient(ctx, h.c.ClientOptions()...)
+	if err != nil {
+		return "", fmt.Errorf("failed to create billing client: %w", err)
+	}
+	defer c.Close()
+
+	var info *billingpb.ProjectBillingInfo
+	err = retry.Do(ctx, retry.DefaultPolicy, func() error {
+		info, err = c.GetProjectBillingInfo(ctx, &billingpb.GetProjectBillingInfoRequest{Name: "projects/" + projectID})
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the billing account of project %s: %w", projectID, err)
+	}
+	if !info.GetBillingEnabled() || info.GetBillingAccountName() == "" {
+		return "", fmt.Errorf("project %s has no billing account", projectID)
+	}
+	return strings.TrimPrefix(info.GetBillingAccountName(), "billingAccounts/"), nil
+}
+
+// findExportDataset returns the first dataset of projectID with the table, as
+// project.dataset, or "" if there is none.
+func findExportDataset(ctx context.Context, c *bigquery.Client, projectID, table string) (string, error) {
+	it := c.DatasetsInProject(ctx, projectID)
+	for i := 0; i < maxSearchedDatasets; i++ {
+		ds, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to list the datasets of project %s: %w", projectID, err)
+		}
+		_, err = ds.Table(table).Metadata(ctx)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to get table %s.%s.%s: %w", ds.ProjectID, ds.DatasetID, table, err)
+		}
+		return ds.ProjectID + "." + ds.DatasetID, nil
+	}
+	return "", nil
+}
+
+// exportTable returns the detailed export table of a billing account.
+func exportTable(billingAccountID string) string {
+	return detailedExportPrefix + strings.ReplaceAll(billingAccountID, "-", "_")
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	billingpb "cloud.google.com/go/billing/apiv1/billingpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/grpc"
+)
+
+type fakeCloudBilling struct {
+	billingpb.UnimplementedCloudBillingServer
+}
+
+func (f *fakeCloudBilling) GetProjectBillingInfo(_ context.Context, req *billingpb.GetProjectBillingInfoRequest) (*billingpb.ProjectBillingInfo, error) {
+	return &billingpb.ProjectBillingInfo{
+		Name:               req.GetName() + "/billingInfo",
+		BillingAccountName: "billingAccounts/012345-6789AB-CDEF01",
+		BillingEnabled:     true,
+	}, nil
+}
+
+func TestClusterCostWithDatasetInOtherProject(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	billingpb.RegisterCloudBillingServer(srv, &fakeCloudBilling{})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	// Only the billing account is looked up; searching the datasets of the
+	// cluster project would fail against the fake.
+	c := &config.Config{}
+	c.SetEndpoint(lis.Addr().String())
+	h := &handlers{c: c}
+	res, _, err := h.clusterCost(context.Background(), nil, &clusterCostArgs{
+		ProjectID:      "p",
+		Location:       "us-central1",
+		Name:           "c",
+		BQDatasetID:    "billing-project.export",
+		SkipTableCheck: true,
+	})
+	if err != nil {
+		t.Fatalf("clusterCost() failed: %v", err)
+	}
+	text := res.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{
+		"Using the inferred billing account 012345-6789AB-CDEF01 of project p.",
+		"billing-project.export.gcp_billing_export_resource_v1_012345_6789AB_CDEF01",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("clusterCost() = %q, want it to contain %q", text, want)
+		}
+	}
+}
+
+func TestFillBillingExportFromCache(t *testing.T) {
+	c := &config.Config{}
+	c.SetBillingExport("p", config.BillingExport{DatasetID: "b.export", BillingAccountID: "0123-4567"})
+	h := &handlers{c: c}
+
+	tests := []struct {
+		name                  string
+		datasetID, account    string
+		wantDataset, wantAcct string
+		wantNote              string
+	}{
+		{
+			name:        "both inferred",
+			wantDataset: "b.export",
+			wantAcct:    "0123-4567",
+			wantNote:    "Using the inferred billing account 0123-4567 and dataset b.export of project p.",
+		},
+		{
+			name:        "dataset inferred",
+			account:     "0123-4567",
+			wantDataset: "b.export",
+			wantAcct:    "0123-4567",
+			wantNote:    "Using the inferred dataset b.export of project p.",
+		},
+		{
+			name:        "nothing inferred",
+			datasetID:   "other.export",
+			account:     "89AB",
+			wantDataset: "other.export",
+			wantAcct:    "89AB",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			datasetID, account := tc.datasetID, tc.account
+			note, err := h.fillBillingExport(context.Background(), "p", &datasetID, &account)
+			if err != nil {
+				t.Fatalf("fillBillingExport() error = %v", err)
+			}
+			if datasetID != tc.wantDataset || account != tc.wantAcct || note != tc.wantNote {
+				t.Errorf("fillBillingExport() = %q, %q, %q, want %q, %q, %q", datasetID, account, note, tc.wantDataset, tc.wantAcct, tc.wantNote)
+			}
+		})
+	}
+}
+
+func TestExportTable(t *testing.T) {
+	if got, want := exportTable("012345-6789AB-CDEF01"), "gcp_billing_export_resource_v1_012345_6789AB_CDEF01"; got != want {
+		t.Errorf("exportTable() = %q, want %q", got, want)
+	}
+}
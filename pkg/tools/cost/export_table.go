@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// detailedExportPrefix prefixes the tables of the detailed, resource
+	// level, billing export. The cost tools need its labels per resource.
+	detailedExportPrefix = "gcp_billing_export_resource_v1_"
+	// standardExportPrefix prefixes the tables of the standard billing
+	// export.
+	standardExportPrefix = "gcp_billing_export_v1_"
+)
+
+// detailedExportColumns are the columns of the detailed export the cost
+// queries rely on. The standard export has no resource column.
+var detailedExportColumns = []string{"labels", "credits", "resource"}
+
+// checkExportTable verifies that the table of q exists and is a detailed
+// billing export. The check is skipped if no BigQuery client can be created,
+// e.g. without credentials.
+func (h *handlers) checkExportTable(ctx context.Context, q *costQuery) error {
+	c, err := h.bigQueryClient(ctx, q.DatasetProjectID)
+	if err != nil {
+		slog.Info("Skipping the billing export table check", "err", err)
+		return nil
+	}
+	defer c.Close()
+
+	dataset := c.Dataset(q.DatasetName)
+	md, err := dataset.Table(q.Table).Metadata(ctx)
+	if isNotFound(err) {
+		var hasStandard bool
+		if _, err := dataset.Table(standardExportTable(q.Table)).Metadata(ctx); err == nil {
+			hasStandard = true
+		}
+		return missingTableError(q, hasStandard)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get billing export table %s.%s.%s: %w", q.DatasetProjectID, q.DatasetName, q.Table, err)
+	}
+	return validateExportSchema(q, md.Schema)
+}
+
+func isNotFound(err error) bool {
+	var e *googleapi.Error
+	return errors.As(err, &e) && e.Code == http.StatusNotFound
+}
+
+// standardExportTable returns the standard export table of the same billing
+// account as the detailed export table.
+func standardExportTable(detailedTable string) string {
+	return standardExportPrefix + strings.TrimPrefix(detailedTable, detailedExportPrefix)
+}
+
+func missingTableError(q *costQuery, hasStandard bool) error {
+	if hasStandard {
+		return fmt.Errorf("dataset %s.%s has no table %s but has %s: this looks like the standard export, detailed export is required. See https://cloud.google.com/billing/docs/how-to/export-data-bigquery-setup", q.DatasetProjectID, q.DatasetName, q.Table, standardExportTable(q.Table))
+	}
+	return fmt.Errorf("dataset %s.%s has no table %s: check bq_dataset_id and billing_account_id, and that the detailed billing export is enabled", q.DatasetProjectID, q.DatasetName, q.Table)
+}
+
+// validateExportSchema returns an error if schema lacks a column of the
+// detailed export.
+func validateExportSchema(q *costQuery, schema bigquery.Schema) error {
+	columns := map[string]bool{}
+	for _, f := range schema {
+		columns[f.Name] = true
+	}
+	var missing []string
+	for _, c := range detailedExportColumns {
+		if !columns[c] {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("table %s.%s.%s has no %s column: this looks like the standard export, detailed export is required", q.DatasetProjectID, q.DatasetName, q.Table, strings.Join(missing, ", "))
+	}
+	return nil
+}
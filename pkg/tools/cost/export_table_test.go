@@ -0,0 +1,63 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestValidateExportSchema(t *testing.T) {
+	q := &costQuery{DatasetProjectID: "b", DatasetName: "export", Table: "gcp_billing_export_resource_v1_0123"}
+	tests := []struct {
+		name    string
+		columns []string
+		wantErr string
+	}{
+		{name: "detailed export", columns: []string{"billing_account_id", "labels", "credits", "resource", "cost"}},
+		{name: "standard export", columns: []string{"billing_account_id", "labels", "credits", "cost"}, wantErr: "has no resource column: this looks like the standard export"},
+		{name: "unrelated table", columns: []string{"id"}, wantErr: "has no labels, credits, resource column"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var schema bigquery.Schema
+			for _, c := range tc.columns {
+				schema = append(schema, &bigquery.FieldSchema{Name: c})
+			}
+			err := validateExportSchema(q, schema)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateExportSchema() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("validateExportSchema() error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestMissingTableError(t *testing.T) {
+	q := &costQuery{DatasetProjectID: "b", DatasetName: "export", Table: "gcp_billing_export_resource_v1_0123"}
+	if got := missingTableError(q, true).Error(); !strings.Contains(got, "has gcp_billing_export_v1_0123: this looks like the standard export, detailed export is required") {
+		t.Errorf("missingTableError(q, true) = %q, want the standard export hint", got)
+	}
+	if got := missingTableError(q, false).Error(); strings.Contains(got, "standard export") {
+		t.Errorf("missingTableError(q, false) = %q, want no standard export hint", got)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// clusterCostSavingsTemplate splits the cost of a cluster into on-demand and
+// Spot usage, by the SKU description, and sums its credits by type. Spot
+// discounts are part of the SKU price, so they show up in the Spot cost rather
+// than as credits.
+const clusterCostSavingsTemplate = `SELECT
+  project.id AS project_id,
+  currency,
+  IF(REGEXP_CONTAINS(LOWER(sku.description), r"spot|preemptible"), "spot", "on-demand") AS pricing,
+  SUM(cost) AS cost_before_credits,
+  SUM(IFNULL((SELECT SUM(c.amount) FROM UNNEST(credits) c WHERE c.type IN ("COMMITTED_USAGE_DISCOUNT", "COMMITTED_USAGE_DISCOUNT_DOLLAR_BASE")), 0)) AS cud_credits,
+  SUM(IFNULL((SELECT SUM(c.amount) FROM UNNEST(credits) c WHERE c.type = "SUSTAINED_USAGE_DISCOUNT"), 0)) AS sud_credits,
+  SUM(IFNULL((SELECT SUM(c.amount) FROM UNNEST(credits) c WHERE c.type NOT IN ("COMMITTED_USAGE_DISCOUNT", "COMMITTED_USAGE_DISCOUNT_DOLLAR_BASE", "SUSTAINED_USAGE_DISCOUNT")), 0)) AS other_credits,
+  SUM(cost) + SUM(IFNULL((SELECT SUM(c.amount) FROM UNNEST(credits) c), 0)) AS cost,
+{{template "from" .}}
+GROUP BY 1, 2, 3
+ORDER BY 1, 2, 3
+`
+
+var clusterCostSavingsTmpl = newCostTemplate("cluster-cost-savings", clusterCostSavingsTemplate)
+
+type clusterCostSavingsArgs struct {
+	ProjectID        string   `json:"project_id,omitempty" jsonschema:"GCP project ID of the cluster. Use the default if the user doesn't provide it."`
+	ProjectIDs       []string `json:"project_ids,omitempty" jsonschema:"Other projects to include in the breakdown, e.g. the other projects of a shared billing account."`
+	Location         string   `json:"location,omitempty" jsonschema:"GKE cluster location. Use the default if the user doesn't provide it."`
+	Name             string   `json:"name" jsonschema:"GKE cluster name. Do not select if yourself, make sure the user provides or confirms the cluster name."`
+	BQDatasetID      string   `json:"bq_dataset_id,omitempty" jsonschema:"BigQuery dataset of the detailed billing export, as project.dataset or project:dataset. Discovered from the billing account of the project if omitted."`
+	BillingAccountID string   `json:"billing_account_id,omitempty" jsonschema:"Billing account ID, e.g. 012345-6789AB-CDEF01. The export table is gcp_billing_export_resource_v1_<billing account ID>. Defaults to the billing account of the project."`
+	StartDate        string   `json:"start_date,omitempty" jsonschema:"First day to include, as YYYY-MM-DD. Defaults to 30 days ago."`
+	Execute          bool     `json:"execute,omitempty" jsonschema:"Run the query in BigQuery and return the breakdown with percentages of the total instead of only returning the query."`
+	AllowLargeScan   bool     `json:"allow_large_scan,omitempty" jsonschema:"Run the query even if the dry run shows it scans more than max_scan_bytes. Only set this when the user confirms."`
+	MaxScanBytes     int64    `json:"max_scan_bytes,omitempty" jsonschema:"Largest number of bytes an executed query may scan without allow_large_scan. Defaults to 10 GiB."`
+	SkipTableCheck   bool     `json:"skip_table_check,omitempty" jsonschema:"Don't check that the billing export table exists and is a detailed export, e.g. to only generate the query without access to BigQuery."`
+}
+
+func installClusterCostSavingsTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "cluster_cost_savings",
+		Description: "Break the cost of a GKE cluster down into on-demand and Spot usage, and sum its committed use (CUD), sustained use (SUD) and other credits, from the GCP Billing Detailed BigQuery Export. Also lists the node pools not using Spot VMs. Prefer to use this tool instead of bq",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.clusterCostSavings)
+}
+
+func (h *handlers) clusterCostSavings(ctx context.Context, _ *mcp.CallToolRequest, args *clusterCostSavingsArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = h.c.DefaultLocation()
+	}
+	note, err := h.fillBillingExport(ctx, args.ProjectID, &args.BQDatasetID, &args.BillingAccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	q, err := newCostQuery(args.ProjectID, args.Location, args.Name, args.BQDatasetID, args.BillingAccountID, args.StartDate, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	q.addProjects(args.ProjectIDs)
+	if note != "" {
+		q.notes = append(q.notes, note)
+	}
+	if hint := h.spotHint(ctx, args.ProjectID, args.Location, args.Name); hint != "" {
+		q.notes = append(q.notes, hint)
+	}
+	sql, err := q.render(clusterCostSavingsTmpl)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.costResult(ctx, q, sql, runOptions{
+		execute:        args.Execute,
+		allowLargeScan: args.AllowLargeScan,
+		maxScanBytes:   args.MaxScanBytes,
+		skipTableCheck: args.SkipTableCheck,
+		render:         formatCostSavings,
+	})
+}
+
+// spotHint names the node pools of the cluster not using Spot VMs. The hint is
+// left out if the cluster cannot be read, e.g. without credentials.
+func (h *handlers) spotHint(ctx context.Context, projectID, location, name string) string {
+	cluster, err := h.getCluster(ctx, projectID, location, name)
+	if err != nil {
+		slog.Info("Skipping the Spot node pool hint", "err", err)
+		return ""
+	}
+	pools := nonSpotNodePools(cluster)
+	if len(pools) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Node pools not using Spot VMs: %s. Fault tolerant workloads could run on Spot node pools at a lower price.", strings.Join(pools, ", "))
+}
+
+// nonSpotNodePools returns the names of the node pools running neither Spot
+// nor preemptible VMs.
+func nonSpotNodePools(cluster *containerpb.Cluster) []string {
+	var pools []string
+	for _, np := range cluster.GetNodePools() {
+		if !np.GetConfig().GetSpot() && !np.GetConfig().GetPreemptible() {
+			pools = append(pools, np.GetName())
+		}
+	}
+	return pools
+}
+
+// costSavings is the breakdown of the cost of a cluster in one currency.
+type costSavings struct {
+	onDemand, spot, cud, sud, other, net float64
+}
+
+// formatCostSavings renders the savings query results per currency, as
+// amounts and percentages of the cost before credits.
+func formatCostSavings(res *queryResult) string {
+	column := func(row []bigquery.Value, name string) bigquery.Value {
+		if i := slices.Index(res.columns, name); i >= 0 && i < len(row) {
+			return row[i]
+		}
+		return nil
+	}
+	byCurrency := map[string]*costSavings{}
+	for _, row := range res.rows {
+		currency, _ := column(row, currencyColumn).(string)
+		s, ok := byCurrency[currency]
+		if !ok {
+			s = &costSavings{}
+			byCurrency[currency] = s
+		}
+		cost, _ := column(row, "cost_before_credits").(float64)
+		if column(row, "pricing") == "spot" {
+			s.spot += cost
+		} else {
+			s.onDemand += cost
+		}
+		for name, sum := range map[string]*float64{"cud_credits": &s.cud, "sud_credits": &s.sud, "other_credits": &s.other, costColumn: &s.net} {
+			f, _ := column(row, name).(float64)
+			*sum += f
+		}
+	}
+	if len(byCurrency) == 0 {
+		return "No costs found for the cluster in the selected window.\n"
+	}
+
+	builder := new(strings.Builder)
+	currencies := slices.Sorted(maps.Keys(byCurrency))
+	for _, c := range currencies {
+		s := byCurrency[c]
+		total := s.onDemand + s.spot
+		if len(currencies) > 1 {
+			fmt.Fprintf(builder, "## %s\n\n", c)
+		}
+		fmt.Fprintf(builder, "| Category | Cost (%s) | Share of cost before credits |\n|---|---|---|\n", c)
+		for _, line := range []struct {
+			name   string
+			amount float64
+		}{
+			{"On-demand usage", s.onDemand},
+			{"Spot usage", s.spot},
+			{"Committed use discount credits", s.cud},
+			{"Sustained use discount credits", s.sud},
+			{"Other credits", s.other},
+			{"Net cost", s.net},
+		} {
+			fmt.Fprintf(builder, "| %s | %.2f | %s |\n", line.name, line.amount, formatShare(line.amount, total))
+		}
+		builder.WriteString("\n")
+	}
+	if len(currencies) > 1 {
+		fmt.Fprintf(builder, "Warning: The results contain more than one currency (%s). Costs in different currencies cannot be added up.\n", strings.Join(currencies, ", "))
+	}
+	return builder.String()
+}
+
+func formatShare(amount, total float64) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", amount/total*100)
+}
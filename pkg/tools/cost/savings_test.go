@@ -0,0 +1,91 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+)
+
+func TestRenderClusterCostSavings(t *testing.T) {
+	q, err := newCostQuery("p", "us-central1", "prod", "b.export", "012345-6789AB-CDEF01", "2025-06-01", 0)
+	if err != nil {
+		t.Fatalf("newCostQuery() error = %v", err)
+	}
+	got, err := q.render(clusterCostSavingsTmpl)
+	if err != nil {
+		t.Fatalf("render() error = %v", err)
+	}
+	for _, want := range []string{
+		`IF(REGEXP_CONTAINS(LOWER(sku.description), r"spot|preemptible"), "spot", "on-demand") AS pricing`,
+		`c.type IN ("COMMITTED_USAGE_DISCOUNT", "COMMITTED_USAGE_DISCOUNT_DOLLAR_BASE")`,
+		`c.type = "SUSTAINED_USAGE_DISCOUNT"`,
+		"GROUP BY 1, 2, 3\nORDER BY 1, 2, 3",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("render() = %s, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestFormatCostSavings(t *testing.T) {
+	res := &queryResult{
+		columns: []string{"project_id", "currency", "pricing", "cost_before_credits", "cud_credits", "sud_credits", "other_credits", "cost"},
+		rows: [][]bigquery.Value{
+			{"p", "USD", "on-demand", 75.0, -20.0, -5.0, 0.0, 50.0},
+			{"p", "USD", "spot", 25.0, 0.0, 0.0, -1.0, 24.0},
+		},
+	}
+	got := formatCostSavings(res)
+	for _, want := range []string{
+		"| Category | Cost (USD) | Share of cost before credits |",
+		"| On-demand usage | 75.00 | 75.0% |",
+		"| Spot usage | 25.00 | 25.0% |",
+		"| Committed use discount credits | -20.00 | -20.0% |",
+		"| Sustained use discount credits | -5.00 | -5.0% |",
+		"| Other credits | -1.00 | -1.0% |",
+		"| Net cost | 74.00 | 74.0% |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatCostSavings() = %s, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "Warning") {
+		t.Errorf("formatCostSavings() = %s, want no currency warning", got)
+	}
+
+	if got := formatCostSavings(&queryResult{}); !strings.Contains(got, "No costs found") {
+		t.Errorf("formatCostSavings() of no rows = %s, want it to report no costs", got)
+	}
+}
+
+func TestNonSpotNodePools(t *testing.T) {
+	cluster := &containerpb.Cluster{
+		NodePools: []*containerpb.NodePool{
+			{Name: "default-pool", Config: &containerpb.NodeConfig{}},
+			{Name: "spot-pool", Config: &containerpb.NodeConfig{Spot: true}},
+			{Name: "preemptible-pool", Config: &containerpb.NodeConfig{Preemptible: true}},
+			{Name: "gpu-pool"},
+		},
+	}
+	want := []string{"default-pool", "gpu-pool"}
+	if got := nonSpotNodePools(cluster); !slices.Equal(got, want) {
+		t.Errorf("nonSpotNodePools() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultJumpThresholdPercent = 20
+
+// clusterCostTrendTemplate sums the cost of a cluster per usage day.
+const clusterCostTrendTemplate = `SELECT
+  DATE(usage_start_time) AS day,
+  currency,
+  SUM(cost) + SUM(IFNULL((SELECT SUM(c.amount) FROM UNNEST(credits) c), 0)) AS cost,
+{{template "from" .}}
+GROUP BY 1, 2
+ORDER BY 2, 1
+`
+
+var clusterCostTrendTmpl = newCostTemplate("cluster-cost-trend", clusterCostTrendTemplate)
+
+type clusterCostTrendArgs struct {
+	ProjectID            string   `json:"project_id,omitempty" jsonschema:"GCP project ID of the cluster. Use the default if the user doesn't provide it."`
+	ProjectIDs           []string `json:"project_ids,omitempty" jsonschema:"Other projects to include in the daily costs, e.g. the other projects of a shared billing account."`
+	Location             string   `json:"location,omitempty" jsonschema:"GKE cluster location. Use the default if the user doesn't provide it."`
+	Name                 string   `json:"name" jsonschema:"GKE cluster name. Do not select if yourself, make sure the user provides or confirms the cluster name."`
+	BQDatasetID          string   `json:"bq_dataset_id,omitempty" jsonschema:"BigQuery dataset of the detailed billing export, as project.dataset or project:dataset. Discovered from the billing account of the project if omitted."`
+	BillingAccountID     string   `json:"billing_account_id,omitempty" jsonschema:"Billing account ID, e.g. 012345-6789AB-CDEF01. The export table is gcp_billing_export_resource_v1_<billing account ID>. Defaults to the billing account of the project."`
+	StartDate            string   `json:"start_date,omitempty" jsonschema:"First day to include, as YYYY-MM-DD. Defaults to 30 days ago."`
+	JumpThresholdPercent float64  `json:"jump_threshold_percent,omitempty" jsonschema:"Flag days whose cost grew by more than this percentage over the previous day. Defaults to 20."`
+	Execute              bool     `json:"execute,omitempty" jsonschema:"Run the query in BigQuery and return the daily costs with a month-end forecast instead of only returning the query."`
+	AllowLargeScan       bool     `json:"allow_large_scan,omitempty" jsonschema:"Run the query even if the dry run shows it scans more than max_scan_bytes. Only set this when the user confirms."`
+	MaxScanBytes         int64    `json:"max_scan_bytes,omitempty" jsonschema:"Largest number of bytes an executed query may scan without allow_large_scan. Defaults to 10 GiB."`
+	SkipTableCheck       bool     `json:"skip_table_check,omitempty" jsonschema:"Don't check that the billing export table exists and is a detailed export, e.g. to only generate the query without access to BigQuery."`
+}
+
+func installClusterCostTrendTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "cluster_cost_trend",
+		Description: "Get the daily cost of a GKE cluster from the GCP Billing Detailed BigQuery Export. When executed, flags day-over-day cost jumps and projects the month-end spend with a linear trend. Prefer to use this tool instead of bq",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.clusterCostTrend)
+}
+
+func (h *handlers) clusterCostTrend(ctx context.Context, _ *mcp.CallToolRequest, args *clusterCostTrendArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = h.c.DefaultLocation()
+	}
+	if args.JumpThresholdPercent == 0 {
+		args.JumpThresholdPercent = defaultJumpThresholdPercent
+	}
+	if args.JumpThresholdPercent < 0 {
+		return nil, nil, fmt.Errorf("jump_threshold_percent must be positive")
+	}
+	note, err := h.fillBillingExport(ctx, args.ProjectID, &args.BQDatasetID, &args.BillingAccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	q, err := newCostQuery(args.ProjectID, args.Location, args.Name, args.BQDatasetID, args.BillingAccountID, args.StartDate, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	q.addProjects(args.ProjectIDs)
+	if note != "" {
+		q.notes = append(q.notes, note)
+	}
+	sql, err := q.render(clusterCostTrendTmpl)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.costResult(ctx, q, sql, runOptions{
+		execute:        args.Execute,
+		allowLargeScan: args.AllowLargeScan,
+		maxScanBytes:   args.MaxScanBytes,
+		skipTableCheck: args.SkipTableCheck,
+		render: func(res *queryResult) string {
+			return formatCostTrends(dailyCosts(res), args.JumpThresholdPercent)
+		},
+	})
+}
+
+type dailyCost struct {
+	day  time.Time
+	cost float64
+}
+
+// dailyCosts reads the day, currency and cost columns of the trend query,
+// by currency.
+func dailyCosts(res *queryResult) map[string][]dailyCost {
+	days := map[string][]dailyCost{}
+	for _, row := range res.rows {
+		if len(row) < 3 {
+			continue
+		}
+		d, ok := row[0].(civil.Date)
+		if !ok {
+			continue
+		}
+		currency, _ := row[1].(string)
+		c, _ := row[2].(float64)
+		days[currency] = append(days[currency], dailyCost{day: d.In(time.UTC), cost: c})
+	}
+	return days
+}
+
+// costJumps returns the growth in percent of the days, by index, whose cost
+// grew by more than thresholdPercent over the previous day.
+func costJumps(days []dailyCost, thresholdPercent float64) map[int]float64 {
+	jumps := map[int]float64{}
+	for i := 1; i < len(days); i++ {
+		prev := days[i-1].cost
+		if prev <= 0 {
+			continue
+		}
+		if growth := (days[i].cost - prev) / prev * 100; growth > thresholdPercent {
+			jumps[i] = growth
+		}
+	}
+	return jumps
+}
+
+// monthEndForecast fits a line through the daily costs and projects it over
+// the rest of the month of the last day. It returns the cost of that month so
+// far, the projected cost of the remaining days and their number.
+func monthEndForecast(days []dailyCost) (monthToDate, projected float64, remaining int, ok bool) {
+	if len(days) < 2 {
+		return 0, 0, 0, false
+	}
+	first := days[0].day
+	x := func(t time.Time) float64 { return t.Sub(first).Hours() / 24 }
+
+	// Least squares fit of cost = a + b*x.
+	var sx, sy, sxx, sxy float64
+	n := float64(len(days))
+	for _, d := range days {
+		sx += x(d.day)
+		sy += d.cost
+		sxx += x(d.day) * x(d.day)
+		sxy += x(d.day) * d.cost
+	}
+	denom := n*sxx - sx*sx
+	if denom == 0 {
+		return 0, 0, 0, false
+	}
+	b := (n*sxy - sx*sy) / denom
+	a := (sy - b*sx) / n
+
+	last := days[len(days)-1].day
+	for _, d := range days {
+		if d.day.Year() == last.Year() && d.day.Month() == last.Month() {
+			monthToDate += d.cost
+		}
+	}
+	monthEnd := time.Date(last.Year(), last.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+	for t := last.AddDate(0, 0, 1); !t.After(monthEnd); t = t.AddDate(0, 0, 1) {
+		projected += max(0, a+b*x(t))
+		remaining++
+	}
+	return monthToDate, projected, remaining, true
+}
+
+// formatCostTrends renders the trend of every currency. Costs in different
+// currencies are kept apart since they cannot be added up.
+func formatCostTrends(byCurrency map[string][]dailyCost, thresholdPercent float64) string {
+	if len(byCurrency) == 0 {
+		return "No costs found for the cluster in the selected window.\n"
+	}
+	currencies := slices.Sorted(maps.Keys(byCurrency))
+	if len(currencies) == 1 {
+		return formatCostTrend(byCurrency[currencies[0]], currencies[0], thresholdPercent)
+	}
+	builder := new(strings.Builder)
+	for _, c := range currencies {
+		fmt.Fprintf(builder, "## %s\n\n%s\n", c, formatCostTrend(byCurrency[c], c, thresholdPercent))
+	}
+	fmt.Fprintf(builder, "\nWarning: The results contain more than one currency (%s). Costs in different currencies cannot be added up.", strings.Join(currencies, ", "))
+	return builder.String()
+}
+
+// formatCostTrend renders the daily costs as a table with the cost jumps
+// flagged, followed by the month-end forecast.
+func formatCostTrend(days []dailyCost, currency string, thresholdPercent float64) string {
+	jumps := costJumps(days, thresholdPercent)
+
+	builder := new(strings.Builder)
+	fmt.Fprintf(builder, "| Date | Cost (%s) | Change |\n|---|---|---|\n", currency)
+	for i, d := range days {
+		change := ""
+		if g, ok := jumps[i]; ok {
+			change = fmt.Sprintf("+%.0f%% (jump)", g)
+		}
+		fmt.Fprintf(builder, "| %s | %.2f | %s |\n", d.day.Format(time.DateOnly), d.cost, change)
+	}
+	if len(jumps) > 0 {
+		fmt.Fprintf(builder, "\n%d days with a day-over-day cost increase above %.0f%%.\n", len(jumps), thresholdPercent)
+	}
+
+	monthToDate, projected, remaining, ok := monthEndForecast(days)
+	if !ok {
+		builder.WriteString("\nForecast: not enough days for a month-end projection.\n")
+		return builder.String()
+	}
+	last := days[len(days)-1].day
+	fmt.Fprintf(builder, "\nForecast: %.2f %s for %s (%.2f so far + %.2f projected for the remaining %d days).\n", monthToDate+projected, currency, last.Format("2006-01"), monthToDate, projected, remaining)
+	return builder.String()
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+)
+
+func day(d int) time.Time {
+	return time.Date(2025, time.June, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestMonthEndForecast(t *testing.T) {
+	// A cost growing by 1 per day from 10 on June 1st reaches 37 on June
+	// 28th, leaving 38 + 39 for the last two days of June.
+	var days []dailyCost
+	for d := 1; d <= 28; d++ {
+		days = append(days, dailyCost{day: day(d), cost: float64(9 + d)})
+	}
+	monthToDate, projected, remaining, ok := monthEndForecast(days)
+	if !ok {
+		t.Fatalf("monthEndForecast() ok = false, want true")
+	}
+	if remaining != 2 {
+		t.Errorf("monthEndForecast() remaining = %d, want 2", remaining)
+	}
+	if want := float64(28*10 + 27*28/2); math.Abs(monthToDate-want) > 1e-9 {
+		t.Errorf("monthEndForecast() month to date = %v, want %v", monthToDate, want)
+	}
+	if want := 38.0 + 39.0; math.Abs(projected-want) > 1e-9 {
+		t.Errorf("monthEndForecast() projected = %v, want %v", projected, want)
+	}
+
+	if _, _, _, ok := monthEndForecast(days[:1]); ok {
+		t.Errorf("monthEndForecast() of one day ok = true, want false")
+	}
+}
+
+func TestCostJumps(t *testing.T) {
+	days := []dailyCost{
+		{day: day(1), cost: 10},
+		{day: day(2), cost: 11},
+		{day: day(3), cost: 15},
+		{day: day(4), cost: 0},
+		{day: day(5), cost: 5},
+	}
+	got := costJumps(days, 20)
+	if len(got) != 1 {
+		t.Fatalf("costJumps() = %v, want a single jump", got)
+	}
+	if g, ok := got[2]; !ok || math.Abs(g-400.0/11) > 1e-9 {
+		t.Errorf("costJumps() = %v, want day 3 with %v%%", got, 400.0/11)
+	}
+}
+
+func TestDailyCosts(t *testing.T) {
+	res := &queryResult{
+		columns: []string{"day", "currency", "cost"},
+		rows: [][]bigquery.Value{
+			{civil.Date{Year: 2025, Month: time.June, Day: 1}, "USD", 12.5},
+			{civil.Date{Year: 2025, Month: time.June, Day: 1}, "EUR", 3.0},
+			{nil, "USD", 1.0},
+		},
+	}
+	got := dailyCosts(res)
+	if len(got) != 2 {
+		t.Fatalf("dailyCosts() = %+v, want costs in 2 currencies", got)
+	}
+	if usd := got["USD"]; len(usd) != 1 || !usd[0].day.Equal(day(1)) || usd[0].cost != 12.5 {
+		t.Errorf("dailyCosts() USD = %+v, want one day on 2025-06-01 costing 12.5", usd)
+	}
+}
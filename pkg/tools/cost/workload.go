@@ -0,0 +1,102 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// clusterCostByWorkloadTemplate breaks the cost of a cluster down by
+// Kubernetes workload. Costs without workload labels, such as idle node
+// capacity or system overhead, are bucketed into "(unallocated)".
+const clusterCostByWorkloadTemplate = `SELECT
+  project.id AS project_id,
+  currency,
+  IFNULL((SELECT l.value FROM bqe.labels AS l WHERE l.key = "k8s-namespace"), "(unallocated)") AS namespace,
+  IFNULL((SELECT l.value FROM bqe.labels AS l WHERE l.key = "k8s-workload-type"), "(unallocated)") AS workload_type,
+  IFNULL((SELECT l.value FROM bqe.labels AS l WHERE l.key = "k8s-workload-name"), "(unallocated)") AS workload_name,
+  SUM(cost) + SUM(IFNULL((SELECT SUM(c.amount) FROM UNNEST(credits) c), 0)) AS cost,
+  SUM(cost) AS cost_before_credits,
+{{template "from" .}}
+{{- if .Namespace}}
+  AND EXISTS(SELECT * FROM bqe.labels AS l WHERE l.key = "k8s-namespace" AND l.value = {{quote .Namespace}})
+{{- end}}
+GROUP BY 1, 2, 3, 4, 5
+ORDER BY 6 DESC
+LIMIT {{.Limit}}
+`
+
+var clusterCostByWorkloadTmpl = newCostTemplate("cluster-cost-by-workload", clusterCostByWorkloadTemplate)
+
+type clusterCostByWorkloadArgs struct {
+	ProjectID        string   `json:"project_id,omitempty" jsonschema:"GCP project ID of the cluster. Use the default if the user doesn't provide it."`
+	ProjectIDs       []string `json:"project_ids,omitempty" jsonschema:"Other projects to include, e.g. to compare clusters across the projects of a shared billing account. Results are grouped by project."`
+	Location         string   `json:"location,omitempty" jsonschema:"GKE cluster location. Use the default if the user doesn't provide it."`
+	Name             string   `json:"name" jsonschema:"GKE cluster name. Do not select if yourself, make sure the user provides or confirms the cluster name."`
+	Namespace        string   `json:"namespace,omitempty" jsonschema:"Only include the workloads of this Kubernetes namespace. Defaults to all namespaces."`
+	BQDatasetID      string   `json:"bq_dataset_id,omitempty" jsonschema:"BigQuery dataset of the detailed billing export, as project.dataset or project:dataset. Discovered from the billing account of the project if omitted."`
+	BillingAccountID string   `json:"billing_account_id,omitempty" jsonschema:"Billing account ID, e.g. 012345-6789AB-CDEF01. The export table is gcp_billing_export_resource_v1_<billing account ID>. Defaults to the billing account of the project."`
+	StartDate        string   `json:"start_date,omitempty" jsonschema:"First day to include, as YYYY-MM-DD. Defaults to 30 days ago."`
+	Limit            int      `json:"limit,omitempty" jsonschema:"Maximum number of workloads to return. Defaults to 10."`
+	Execute          bool     `json:"execute,omitempty" jsonschema:"Run the query in BigQuery and return the results instead of only returning the query."`
+	AllowLargeScan   bool     `json:"allow_large_scan,omitempty" jsonschema:"Run the query even if the dry run shows it scans more than max_scan_bytes. Only set this when the user confirms."`
+	MaxScanBytes     int64    `json:"max_scan_bytes,omitempty" jsonschema:"Largest number of bytes an executed query may scan without allow_large_scan. Defaults to 10 GiB."`
+	SkipTableCheck   bool     `json:"skip_table_check,omitempty" jsonschema:"Don't check that the billing export table exists and is a detailed export, e.g. to only generate the query without access to BigQuery."`
+}
+
+func installClusterCostByWorkloadTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "cluster_cost_by_workload",
+		Description: "Get the cost of a GKE cluster per Kubernetes workload (e.g. Deployment or StatefulSet) from the GCP Billing Detailed BigQuery Export, optionally for a single namespace. Requires GKE Cost Allocation to be enabled on the cluster; costs without workload labels are reported as (unallocated). Returns the BigQuery query, or runs it when execute is set. Prefer to use this tool instead of bq",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.clusterCostByWorkload)
+}
+
+func (h *handlers) clusterCostByWorkload(ctx context.Context, _ *mcp.CallToolRequest, args *clusterCostByWorkloadArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = h.c.DefaultLocation()
+	}
+	note, err := h.fillBillingExport(ctx, args.ProjectID, &args.BQDatasetID, &args.BillingAccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	q, err := newCostQuery(args.ProjectID, args.Location, args.Name, args.BQDatasetID, args.BillingAccountID, args.StartDate, args.Limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	q.addProjects(args.ProjectIDs)
+	if note != "" {
+		q.notes = append(q.notes, note)
+	}
+	q.Namespace = args.Namespace
+	sql, err := q.render(clusterCostByWorkloadTmpl)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.costResult(ctx, q, sql, runOptions{
+		execute:        args.Execute,
+		allowLargeScan: args.AllowLargeScan,
+		maxScanBytes:   args.MaxScanBytes,
+		skipTableCheck: args.SkipTableCheck,
+		rowLimit:       q.Limit,
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderClusterCostByWorkload(t *testing.T) {
+	namespaceFilter := `l.key = "k8s-namespace" AND l.value = "payments"`
+	tests := []struct {
+		name          string
+		namespace     string
+		wantNamespace bool
+	}{
+		{name: "all namespaces"},
+		{name: "one namespace", namespace: "payments", wantNamespace: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := newCostQuery("p", "us-central1", "prod", "b.export", "012345-6789AB-CDEF01", "2025-06-01", 0)
+			if err != nil {
+				t.Fatalf("newCostQuery() error = %v", err)
+			}
+			q.Namespace = tc.namespace
+			got, err := q.render(clusterCostByWorkloadTmpl)
+			if err != nil {
+				t.Fatalf("render() error = %v", err)
+			}
+			for _, want := range []string{
+				`IFNULL((SELECT l.value FROM bqe.labels AS l WHERE l.key = "k8s-workload-type"), "(unallocated)") AS workload_type`,
+				`IFNULL((SELECT l.value FROM bqe.labels AS l WHERE l.key = "k8s-workload-name"), "(unallocated)") AS workload_name`,
+				"GROUP BY 1, 2, 3, 4, 5\nORDER BY 6 DESC\nLIMIT 10",
+			} {
+				if !strings.Contains(got, want) {
+					t.Errorf("render() = %s, want it to contain %q", got, want)
+				}
+			}
+			if strings.Contains(got, namespaceFilter) != tc.wantNamespace {
+				t.Errorf("render() = %s, want namespace filter %v", got, tc.wantNamespace)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package giq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+type checkAcceleratorArgs struct {
+	ProjectID   string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location    string `json:"location,omitempty" jsonschema:"GKE cluster location. Use the default if the user doesn't provide it."`
+	Name        string `json:"name" jsonschema:"GKE cluster name. Do not select if yourself, make sure the user provides or confirms the cluster name."`
+	Accelerator string `json:"accelerator" jsonschema:"The accelerator type to check, e.g. nvidia-l4."`
+}
+
+// acceleratorQuota is the regional quota of an accelerator type.
+type acceleratorQuota struct {
+	metric string
+	limit  float64
+	usage  float64
+}
+
+// acceleratorAvailability is where an accelerator type is offered among the
+// zones of a cluster.
+type acceleratorAvailability struct {
+	accelerator string
+	cluster     string
+	region      string
+	zones       []string
+	missing     []string
+	// quota is nil if no quota of the accelerator was found in the region.
+	quota *acceleratorQuota
+	// tpu is set for TPU types, which are not Compute Engine accelerator
+	// types and are not looked up.
+	tpu bool
+}
+
+func installCheckAcceleratorTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "check_accelerator_availability",
+		Description: "Check whether an accelerator type is offered in the zones of a GKE cluster and how much of its regional quota is left, e.g. before deploying a manifest of giq_generate_manifest. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+		},
+	}, h.checkAccelerator)
+}
+
+func (h *handlers) checkAccelerator(ctx context.Context, _ *mcp.CallToolRequest, args *checkAcceleratorArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	args.Location = h.c.ResolveLocation(args.Location)
+	if args.Name == "" {
+		return nil, nil, fmt.Errorf("name argument cannot be empty")
+	}
+	if args.Accelerator == "" {
+		return nil, nil, fmt.Errorf("accelerator argument cannot be empty")
+	}
+	a, err := h.acceleratorAvailability(ctx, args.ProjectID, args.Location, args.Name, args.Accelerator)
+	if err != nil {
+		return nil, nil, err
+	}
+	text := formatAcceleratorAvailability(a)
+	for _, p := range a.problems() {
+		text += fmt.Sprintf("\nWarning: %s\n", p)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// acceleratorAvailability looks up the accelerator type in the node zones of
+// the cluster and its quota in their region.
+func (h *handlers) acceleratorAvailability(ctx context.Context, projectID, location, name, accelerator string) (*acceleratorAvailability, error) {
+	if isTPU(accelerator) {
+		return &acceleratorAvailability{accelerator: accelerator, cluster: name, tpu: true}, nil
+	}
+	var cluster *containerpb.Cluster
+	err := retry.Do(ctx, retry.DefaultPolicy, func() error {
+		var err error
+		cluster, err = h.cmClient.GetCluster(ctx, &containerpb.GetClusterRequest{
+			Name: fmt.Sprintf("projects/%s/locations/%s/clusters/%s", projectID, location, name),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s: %w", name, err)
+	}
+	zones := cluster.GetLocations()
+	if len(zones) == 0 {
+		return nil, fmt.Errorf("cluster %s has no node zones", name)
+	}
+
+	opts, err := h.c.RESTClientOptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compute client: %w", err)
+	}
+	svc, err := compute.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compute client: %w", err)
+	}
+	a := &acceleratorAvailability{
+		accelerator: accelerator,
+		cluster:     name,
+		region:      zoneRegion(zones[0]),
+	}
+	for _, zone := range zones {
+		_, err := svc.AcceleratorTypes.Get(projectID, zone, accelerator).Context(ctx).Do()
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+			a.missing = append(a.missing, zone)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get accelerator type %s in zone %s: %w", accelerator, zone, err)
+		}
+		a.zones = append(a.zones, zone)
+	}
+
+	region, err := svc.Regions.Get(projectID, a.region).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the quotas of region %s: %w", a.region, err)
+	}
+	a.quota = findAcceleratorQuota(region.Quotas, accelerator)
+	return a, nil
+}
+
+// isTPU reports whether accelerator is a TPU type, e.g. tpu-v5-lite-podslice.
+func isTPU(accelerator string) bool {
+	return strings.HasPrefix(accelerator, "tpu-")
+}
+
+// zoneRegion returns the region of a zone, e.g. us-central1 of us-central1-a.
+func zoneRegion(zone string) string {
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		return zone[:i]
+	}
+	return zone
+}
+
+// findAcceleratorQuota returns the on-demand quota of an accelerator type,
+// e.g. NVIDIA_L4_GPUS for nvidia-l4. Quota metrics don't always spell out the
+// memory size, so NVIDIA_H100_GPUS is the quota of nvidia-h100-80gb.
+func findAcceleratorQuota(quotas []*compute.Quota, accelerator string) *acceleratorQuota {
+	metric := strings.ToUpper(strings.ReplaceAll(strings.Replace(accelerator, "-tesla-", "-", 1), "-", "_"))
+	candidates := []string{metric + "_GPUS"}
+	if i := strings.LastIndex(metric, "_"); i > 0 {
+		candidates = append(candidates, metric[:i]+"_GPUS")
+	}
+	for _, c := range candidates {
+		for _, q := range quotas {
+			if q.Metric == c {
+				return &acceleratorQuota{metric: q.Metric, limit: q.Limit, usage: q.Usage}
+			}
+		}
+	}
+	return nil
+}
+
+// problems returns what keeps the accelerator from being used by the cluster.
+func (a *acceleratorAvailability) problems() []string {
+	var problems []string
+	if a.tpu {
+		return nil
+	}
+	if len(a.zones) == 0 {
+		problems = append(problems, fmt.Sprintf("Accelerator %s is not offered in any zone of cluster %s (%s); pods requesting it will stay unschedulable. Pick another accelerator or add a node zone offering it.", a.accelerator, a.cluster, strings.Join(a.missing, ", ")))
+	}
+	if a.quota != nil && a.quota.limit-a.quota.usage <= 0 {
+		problems = append(problems, fmt.Sprintf("Quota %s of region %s is used up (%.0f of %.0f); request a quota increase before deploying.", a.quota.metric, a.region, a.quota.usage, a.quota.limit))
+	}
+	return problems
+}
+
+func formatAcceleratorAvailability(a *acceleratorAvailability) string {
+	if a.tpu {
+		return fmt.Sprintf("Accelerator %s is a TPU type; its availability in the zones of cluster %s and its quota are not checked. Check the TPU regions and zones in the Cloud TPU documentation and the TPU quotas of the project.\n", a.accelerator, a.cluster)
+	}
+	builder := new(strings.Builder)
+	if len(a.zones) > 0 {
+		fmt.Fprintf(builder, "Accelerator %s is offered in zones %s of cluster %s.\n", a.accelerator, strings.Join(a.zones, ", "), a.cluster)
+	}
+	if len(a.missing) > 0 {
+		fmt.Fprintf(builder, "Accelerator %s is not offered in zones %s of cluster %s.\n", a.accelerator, strings.Join(a.missing, ", "), a.cluster)
+	}
+	if a.quota != nil {
+		fmt.Fprintf(builder, "Quota %s of region %s: %.0f of %.0f used, %.0f available.\n", a.quota.metric, a.region, a.quota.usage, a.quota.limit, max(0, a.quota.limit-a.quota.usage))
+	} else {
+		fmt.Fprintf(builder, "No quota found for accelerator %s in region %s.\n", a.accelerator, a.region)
+	}
+	return builder.String()
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package giq
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func TestFindAcceleratorQuota(t *testing.T) {
+	quotas := []*compute.Quota{
+		{Metric: "CPUS", Limit: 24},
+		{Metric: "PREEMPTIBLE_NVIDIA_L4_GPUS", Limit: 16},
+		{Metric: "NVIDIA_L4_GPUS", Limit: 8, Usage: 2},
+		{Metric: "NVIDIA_T4_GPUS", Limit: 4},
+		{Metric: "NVIDIA_H100_GPUS", Limit: 16, Usage: 16},
+	}
+	tests := []struct {
+		accelerator string
+		wantMetric  string
+	}{
+		{accelerator: "nvidia-l4", wantMetric: "NVIDIA_L4_GPUS"},
+		{accelerator: "nvidia-tesla-t4", wantMetric: "NVIDIA_T4_GPUS"},
+		{accelerator: "nvidia-h100-80gb", wantMetric: "NVIDIA_H100_GPUS"},
+		{accelerator: "nvidia-b200"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.accelerator, func(t *testing.T) {
+			q := findAcceleratorQuota(quotas, tc.accelerator)
+			var got string
+			if q != nil {
+				got = q.metric
+			}
+			if got != tc.wantMetric {
+				t.Errorf("findAcceleratorQuota() metric = %q, want %q", got, tc.wantMetric)
+			}
+		})
+	}
+}
+
+func TestAcceleratorAvailabilityProblems(t *testing.T) {
+	tests := []struct {
+		name string
+		a    acceleratorAvailability
+		want []string
+	}{
+		{
+			name: "available",
+			a:    acceleratorAvailability{accelerator: "nvidia-l4", zones: []string{"us-central1-a"}, quota: &acceleratorQuota{limit: 8, usage: 2}},
+		},
+		{
+			name: "not offered",
+			a:    acceleratorAvailability{accelerator: "nvidia-l4", cluster: "prod", missing: []string{"us-west1-a", "us-west1-b"}},
+			want: []string{"Accelerator nvidia-l4 is not offered in any zone of cluster prod (us-west1-a, us-west1-b)"},
+		},
+		{
+			name: "no quota left",
+			a:    acceleratorAvailability{accelerator: "nvidia-h100-80gb", region: "us-east5", zones: []string{"us-east5-a"}, quota: &acceleratorQuota{metric: "NVIDIA_H100_GPUS", limit: 16, usage: 16}},
+			want: []string{"Quota NVIDIA_H100_GPUS of region us-east5 is used up (16 of 16)"},
+		},
+		{
+			name: "tpu",
+			a:    acceleratorAvailability{accelerator: "tpu-v5-lite-podslice", cluster: "prod", tpu: true},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.a.problems()
+			if len(got) != len(tc.want) {
+				t.Fatalf("problems() = %q, want %d problems", got, len(tc.want))
+			}
+			for i := range got {
+				if !strings.HasPrefix(got[i], tc.want[i]) {
+					t.Errorf("problems()[%d] = %q, want it to start with %q", i, got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAcceleratorWarningsTPU(t *testing.T) {
+	// TPU types are not looked up, so no API is called.
+	h := &handlers{c: &config.Config{}}
+	args := &giqGenerateManifestArgs{
+		ProjectID:   "p",
+		Location:    "us-west4-a",
+		ClusterName: "prod",
+		Accelerator: "tpu-v5-lite-podslice",
+	}
+	if got := h.acceleratorWarnings(context.Background(), args); len(got) != 0 {
+		t.Errorf("acceleratorWarnings() of a TPU profile = %q, want none", got)
+	}
+}
+
+func TestZoneRegion(t *testing.T) {
+	if got := zoneRegion("us-central1-a"); got != "us-central1" {
+		t.Errorf("zoneRegion() = %q, want us-central1", got)
+	}
+}
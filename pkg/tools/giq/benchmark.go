@@ -0,0 +1,196 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package giq
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/command"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	priorityCost    = "cost"
+	priorityLatency = "latency"
+)
+
+type benchmarkProfilesArgs struct {
+	Model                  string  `json:"model" jsonschema:"The model to compare the profiles of. Get the list of valid models with the giq_list_models tool if the user doesn't provide it."`
+	ModelServer            string  `json:"model_server,omitempty" jsonschema:"Only compare the profiles of this model server. Compares all model servers if empty."`
+	Priority               string  `json:"priority" jsonschema:"What the user wants to optimize for, which sets the order of the profiles. One of: cost, latency. Ask the user if they don't say."`
+	TargetQueriesPerSecond float64 `json:"target_queries_per_second,omitempty" jsonschema:"The load the user expects, in queries per second, to compute the number of replicas each profile needs. Leave it empty if the user doesn't provide it."`
+}
+
+// profile is a GIQ benchmark profile: the performance of a model on a model
+// server and accelerator at increasing load.
+type profile struct {
+	ModelServerInfo struct {
+		Model              string `json:"model"`
+		ModelServer        string `json:"modelServer"`
+		ModelServerVersion string `json:"modelServerVersion"`
+	} `json:"modelServerInfo"`
+	AcceleratorType string `json:"acceleratorType"`
+	ResourcesUsed   struct {
+		AcceleratorCount int `json:"acceleratorCount"`
+	} `json:"resourcesUsed"`
+	PerformanceStats []performanceStats `json:"performanceStats"`
+}
+
+type performanceStats struct {
+	QueriesPerSecond  float64 `json:"queriesPerSecond"`
+	NTPOTMilliseconds float64 `json:"ntpotMilliseconds"`
+	TTFTMilliseconds  float64 `json:"ttftMilliseconds"`
+	Cost              []struct {
+		CostPerMillionOutputTokens float64 `json:"costPerMillionOutputTokens"`
+	} `json:"cost"`
+}
+
+// profileSummary is a profile at saturation, i.e. at its highest benchmarked
+// load.
+type profileSummary struct {
+	accelerator      string
+	modelServer      string
+	acceleratorCount int
+	maxQPS           float64
+	ntpot            float64
+	// costPerMillion is the cost per million output tokens, or 0 if unknown.
+	costPerMillion float64
+	// replicas is the number of replicas for the target load, or 0 if
+	// there is no target.
+	replicas int
+}
+
+func installBenchmarkProfilesTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "giq_benchmark_profiles",
+		Description: "Compare the GKE Inference Quickstart (GIQ) benchmark profiles of a model across accelerators and model servers: cost per million output tokens, NTPOT at saturation and, given a target load, the number of replicas. Use it to pick an accelerator before giq_generate_manifest. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+		},
+	}, h.benchmarkProfiles)
+}
+
+func (h *handlers) benchmarkProfiles(ctx context.Context, _ *mcp.CallToolRequest, args *benchmarkProfilesArgs) (*mcp.CallToolResult, any, error) {
+	if args.Model == "" {
+		return nil, nil, fmt.Errorf("model argument cannot be empty")
+	}
+	if args.Priority != priorityCost && args.Priority != priorityLatency {
+		return nil, nil, fmt.Errorf("priority must be one of %s, %s, got %q", priorityCost, priorityLatency, args.Priority)
+	}
+	if args.TargetQueriesPerSecond < 0 {
+		return nil, nil, fmt.Errorf("target_queries_per_second must be positive")
+	}
+	gcloudArgs := []string{"container", "ai", "profiles", "list", "--model", args.Model}
+	if args.ModelServer != "" {
+		gcloudArgs = append(gcloudArgs, "--model-server", args.ModelServer)
+	}
+	out, err := command.Gcloud{Args: gcloudArgs, Project: h.c.DefaultProjectID(), Format: "json"}.Run(ctx, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list the GIQ profiles of %s: %w", args.Model, err)
+	}
+	var profiles []profile
+	if err := json.Unmarshal(out, &profiles); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the GIQ profiles: %w", err)
+	}
+	summaries := summarizeProfiles(profiles, args.TargetQueriesPerSecond)
+	sortProfiles(summaries, args.Priority)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatProfiles(args.Model, summaries, args.Priority, args.TargetQueriesPerSecond)},
+		},
+	}, nil, nil
+}
+
+// summarizeProfiles reduces the profiles to their performance at saturation.
+// Profiles without performance stats are left out.
+func summarizeProfiles(profiles []profile, targetQPS float64) []profileSummary {
+	var summaries []profileSummary
+	for _, p := range profiles {
+		if len(p.PerformanceStats) == 0 {
+			continue
+		}
+		saturation := slices.MaxFunc(p.PerformanceStats, func(a, b performanceStats) int {
+			return cmp.Compare(a.QueriesPerSecond, b.QueriesPerSecond)
+		})
+		s := profileSummary{
+			accelerator:      p.AcceleratorType,
+			modelServer:      p.ModelServerInfo.ModelServer,
+			acceleratorCount: p.ResourcesUsed.AcceleratorCount,
+			maxQPS:           saturation.QueriesPerSecond,
+			ntpot:            saturation.NTPOTMilliseconds,
+		}
+		if p.ModelServerInfo.ModelServerVersion != "" {
+			s.modelServer += " " + p.ModelServerInfo.ModelServerVersion
+		}
+		if len(saturation.Cost) > 0 {
+			s.costPerMillion = saturation.Cost[0].CostPerMillionOutputTokens
+		}
+		if targetQPS > 0 && s.maxQPS > 0 {
+			s.replicas = int(math.Ceil(targetQPS / s.maxQPS))
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries
+}
+
+// sortProfiles orders the profiles by cost or by NTPOT, the other one breaking
+// ties. Unknown values go last.
+func sortProfiles(summaries []profileSummary, priority string) {
+	known := func(v float64) float64 {
+		if v <= 0 {
+			return math.Inf(1)
+		}
+		return v
+	}
+	slices.SortStableFunc(summaries, func(a, b profileSummary) int {
+		if priority == priorityLatency {
+			return cmp.Or(cmp.Compare(known(a.ntpot), known(b.ntpot)), cmp.Compare(known(a.costPerMillion), known(b.costPerMillion)))
+		}
+		return cmp.Or(cmp.Compare(known(a.costPerMillion), known(b.costPerMillion)), cmp.Compare(known(a.ntpot), known(b.ntpot)))
+	})
+}
+
+func formatProfiles(model string, summaries []profileSummary, priority string, targetQPS float64) string {
+	if len(summaries) == 0 {
+		return fmt.Sprintf("No GIQ benchmark profiles found for model %s.\n", model)
+	}
+	builder := new(strings.Builder)
+	fmt.Fprintf(builder, "GIQ benchmark profiles of model %s, by %s:\n\n", model, priority)
+	builder.WriteString("| Accelerator | Model server | Accelerators per replica | Max QPS per replica | NTPOT at saturation (ms) | Cost per million output tokens | Replicas |\n")
+	builder.WriteString("|---|---|---|---|---|---|---|\n")
+	for _, s := range summaries {
+		cost, replicas := "-", "-"
+		if s.costPerMillion > 0 {
+			cost = fmt.Sprintf("%.2f", s.costPerMillion)
+		}
+		if s.replicas > 0 {
+			replicas = fmt.Sprint(s.replicas)
+		}
+		fmt.Fprintf(builder, "| %s | %s | %d | %.2f | %.0f | %s | %s |\n", s.accelerator, s.modelServer, s.acceleratorCount, s.maxQPS, s.ntpot, cost, replicas)
+	}
+	if targetQPS > 0 {
+		fmt.Fprintf(builder, "\nReplicas are for a load of %.2f queries per second at saturation; plan for headroom.\n", targetQPS)
+	} else {
+		builder.WriteString("\nSet target_queries_per_second to get the number of replicas each profile needs.\n")
+	}
+	return builder.String()
+}
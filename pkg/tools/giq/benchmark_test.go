@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package giq
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testProfiles = `[
+  {
+    "modelServerInfo": {"model": "m", "modelServer": "vllm", "modelServerVersion": "v0.8.5"},
+    "acceleratorType": "nvidia-l4",
+    "resourcesUsed": {"acceleratorCount": 2},
+    "performanceStats": [
+      {"queriesPerSecond": 1, "ntpotMilliseconds": 40, "cost": [{"costPerMillionOutputTokens": 3.5}]},
+      {"queriesPerSecond": 4, "ntpotMilliseconds": 90, "cost": [{"costPerMillionOutputTokens": 1.25}]}
+    ]
+  },
+  {
+    "modelServerInfo": {"model": "m", "modelServer": "vllm"},
+    "acceleratorType": "nvidia-h100-80gb",
+    "resourcesUsed": {"acceleratorCount": 1},
+    "performanceStats": [
+      {"queriesPerSecond": 10, "ntpotMilliseconds": 30, "cost": [{"costPerMillionOutputTokens": 2}]}
+    ]
+  },
+  {"acceleratorType": "tpu-v6e-slice"}
+]`
+
+func TestSummarizeProfiles(t *testing.T) {
+	var profiles []profile
+	if err := json.Unmarshal([]byte(testProfiles), &profiles); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	got := summarizeProfiles(profiles, 9)
+	want := []profileSummary{
+		{accelerator: "nvidia-l4", modelServer: "vllm v0.8.5", acceleratorCount: 2, maxQPS: 4, ntpot: 90, costPerMillion: 1.25, replicas: 3},
+		{accelerator: "nvidia-h100-80gb", modelServer: "vllm", acceleratorCount: 1, maxQPS: 10, ntpot: 30, costPerMillion: 2, replicas: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("summarizeProfiles() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSortProfiles(t *testing.T) {
+	summaries := []profileSummary{
+		{accelerator: "unknown"},
+		{accelerator: "cheap", costPerMillion: 1, ntpot: 90},
+		{accelerator: "fast", costPerMillion: 2, ntpot: 30},
+	}
+	tests := []struct {
+		priority string
+		want     []string
+	}{
+		{priority: priorityCost, want: []string{"cheap", "fast", "unknown"}},
+		{priority: priorityLatency, want: []string{"fast", "cheap", "unknown"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.priority, func(t *testing.T) {
+			s := append([]profileSummary(nil), summaries...)
+			sortProfiles(s, tc.priority)
+			var got []string
+			for _, p := range s {
+				got = append(got, p.accelerator)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("sortProfiles() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormatProfiles(t *testing.T) {
+	got := formatProfiles("m", []profileSummary{
+		{accelerator: "nvidia-l4", modelServer: "vllm", acceleratorCount: 2, maxQPS: 4, ntpot: 90, costPerMillion: 1.25},
+	}, priorityCost, 0)
+	for _, want := range []string{
+		"GIQ benchmark profiles of model m, by cost:",
+		"| nvidia-l4 | vllm | 2 | 4.00 | 90 | 1.25 | - |",
+		"Set target_queries_per_second",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatProfiles() = %s, want it to contain %q", got, want)
+		}
+	}
+}
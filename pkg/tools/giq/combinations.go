@@ -0,0 +1,182 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package giq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/command"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var (
+	// modelServerNameKeys and acceleratorNameKeys are the fields holding the
+	// name of a model server and an accelerator when gcloud lists objects.
+	modelServerNameKeys = []string{"modelServerName", "modelServer", "name"}
+	acceleratorNameKeys = []string{"acceleratorType", "accelerator", "name"}
+)
+
+type listCombinationsArgs struct {
+	Model string `json:"model" jsonschema:"The model to list the model servers and accelerators of. Get the list of valid models with the giq_list_models tool if the user doesn't provide it."`
+}
+
+// combination is a model server and accelerator GIQ supports for a model.
+type combination struct {
+	modelServer string
+	accelerator string
+}
+
+func installListCombinationsTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "giq_list_model_servers_and_accelerators",
+		Description: "List the model server and accelerator combinations GKE Inference Quickstart (GIQ) supports for a model, with the regions each accelerator is available in, for use as the model_server and accelerator of giq_generate_manifest. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+		},
+	}, h.listCombinations)
+}
+
+func (h *handlers) listCombinations(ctx context.Context, _ *mcp.CallToolRequest, args *listCombinationsArgs) (*mcp.CallToolResult, any, error) {
+	if args.Model == "" {
+		return nil, nil, fmt.Errorf("model argument cannot be empty")
+	}
+	project := h.c.DefaultProjectID()
+	out, err := command.Gcloud{
+		Args:    []string{"container", "ai", "profiles", "model-servers", "list", "--model", args.Model},
+		Project: project,
+		Format:  "json",
+	}.Run(ctx, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list the GIQ model servers of %s: %w", args.Model, err)
+	}
+	servers, err := parseListItems(out, "model servers", modelServerNameKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var combinations []combination
+	for _, server := range servers {
+		out, err := command.Gcloud{
+			Args:    []string{"container", "ai", "profiles", "accelerators", "list", "--model", args.Model, "--model-server", server.name},
+			Project: project,
+			Format:  "json",
+		}.Run(ctx, 0)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list the GIQ accelerators of %s on %s: %w", args.Model, server.name, err)
+		}
+		accelerators, err := parseListItems(out, "accelerators", acceleratorNameKeys)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, a := range accelerators {
+			c := combination{modelServer: server.name, accelerator: a.name}
+			if !slices.Contains(combinations, c) {
+				combinations = append(combinations, c)
+			}
+		}
+	}
+
+	var warnings []string
+	regions, err := acceleratorRegions(ctx, project, combinations)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Could not look up the regions of the accelerators: %v", err))
+	}
+	text := formatCombinations(args.Model, combinations, regions)
+	for _, w := range warnings {
+		text += fmt.Sprintf("\nWarning: %s\n", w)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// acceleratorRegions returns the regions the accelerators of combinations are
+// available in, by accelerator, as listed in project.
+func acceleratorRegions(ctx context.Context, project string, combinations []combination) (map[string][]string, error) {
+	var names []string
+	for _, c := range combinations {
+		if !slices.Contains(names, c.accelerator) {
+			names = append(names, c.accelerator)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	out, err := command.Gcloud{
+		Args:    []string{"compute", "accelerator-types", "list", "--filter", fmt.Sprintf("name:(%s)", strings.Join(names, " "))},
+		Project: project,
+		Format:  "json",
+	}.Run(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseAcceleratorZones(out)
+}
+
+// parseAcceleratorZones parses the accelerator types listed by gcloud into the
+// regions of each accelerator.
+func parseAcceleratorZones(out []byte) (map[string][]string, error) {
+	var types []struct {
+		Name string `json:"name"`
+		Zone string `json:"zone"`
+	}
+	if err := json.Unmarshal(out, &types); err != nil {
+		return nil, fmt.Errorf("failed to parse the accelerator types: %w", err)
+	}
+	regions := map[string][]string{}
+	for _, t := range types {
+		// The zone is a URL, e.g. .../zones/us-central1-a.
+		zone := path.Base(t.Zone)
+		i := strings.LastIndex(zone, "-")
+		if t.Name == "" || i < 0 {
+			continue
+		}
+		if region := zone[:i]; !slices.Contains(regions[t.Name], region) {
+			regions[t.Name] = append(regions[t.Name], region)
+		}
+	}
+	for _, r := range regions {
+		slices.Sort(r)
+	}
+	return regions, nil
+}
+
+func formatCombinations(model string, combinations []combination, regions map[string][]string) string {
+	if len(combinations) == 0 {
+		return fmt.Sprintf("GIQ supports no model server and accelerator combinations for model %s.\n", model)
+	}
+	builder := new(strings.Builder)
+	fmt.Fprintf(builder, "Model server and accelerator combinations for model %s:\n", model)
+	for _, c := range combinations {
+		fmt.Fprintf(builder, "- model_server: %s, accelerator: %s", c.modelServer, c.accelerator)
+		if regions != nil {
+			if r := regions[c.accelerator]; len(r) > 0 {
+				fmt.Fprintf(builder, " (regions: %s)", strings.Join(r, ", "))
+			} else {
+				builder.WriteString(" (not available in any region)")
+			}
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package giq
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceleratorZones(t *testing.T) {
+	out := `[
+		{"name": "nvidia-l4", "zone": "https://www.googleapis.com/compute/v1/projects/p/zones/us-central1-a"},
+		{"name": "nvidia-l4", "zone": "https://www.googleapis.com/compute/v1/projects/p/zones/us-central1-b"},
+		{"name": "nvidia-l4", "zone": "europe-west4-a"},
+		{"name": "nvidia-h100-80gb", "zone": "us-east5-a"}
+	]`
+	got, err := parseAcceleratorZones([]byte(out))
+	if err != nil {
+		t.Fatalf("parseAcceleratorZones() error = %v", err)
+	}
+	want := map[string][]string{
+		"nvidia-l4":        {"europe-west4", "us-central1"},
+		"nvidia-h100-80gb": {"us-east5"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAcceleratorZones() = %v, want %v", got, want)
+	}
+
+	if _, err := parseAcceleratorZones([]byte("Listed 0 items.")); err == nil {
+		t.Errorf("parseAcceleratorZones() of invalid JSON succeeded, want error")
+	}
+}
+
+func TestFormatCombinations(t *testing.T) {
+	combinations := []combination{
+		{modelServer: "vllm", accelerator: "nvidia-l4"},
+		{modelServer: "vllm", accelerator: "nvidia-a100-80gb"},
+	}
+	tests := []struct {
+		name    string
+		regions map[string][]string
+		want    string
+	}{
+		{
+			name:    "with regions",
+			regions: map[string][]string{"nvidia-l4": {"us-central1", "us-east1"}},
+			want: "Model server and accelerator combinations for model m:\n" +
+				"- model_server: vllm, accelerator: nvidia-l4 (regions: us-central1, us-east1)\n" +
+				"- model_server: vllm, accelerator: nvidia-a100-80gb (not available in any region)\n",
+		},
+		{
+			name: "without regions",
+			want: "Model server and accelerator combinations for model m:\n" +
+				"- model_server: vllm, accelerator: nvidia-l4\n" +
+				"- model_server: vllm, accelerator: nvidia-a100-80gb\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := formatCombinations("m", combinations, tc.regions); got != tc.want {
+				t.Errorf("formatCombinations() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -17,34 +17,74 @@ package giq
 import (
 	"context"
 	"fmt"
-	"log"
-	"os/exec"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	container "cloud.google.com/go/container/apiv1"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/command"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"sigs.k8s.io/yaml"
 )
 
+// manifestTimeout bounds the time gcloud may take to generate a manifest.
+const manifestTimeout = 5 * time.Minute
+
 type giqGenerateManifestArgs struct {
-	Model                   string `json:"model" jsonschema:"The model to use. Get the list of valid models from 'gcloud container ai profiles model-and-server-combinations list' if the user doesn't provide it."`
-	ModelServer             string `json:"model_server" jsonschema:"The model server to use. Get the list of valid models from 'gcloud container ai profiles model-and-server-combinations list' if the user doesn't provide it."`
-	Accelerator             string `json:"accelerator" jsonschema:"The accelerator to use. Get the list of valid accelerators from 'gcloud container ai profiles list --model=<model>' if the user doesn't provide it."`
-	TargetNTPOTMilliseconds string `json:"target_ntpot_milliseconds,omitempty" jsonschema:"The maximum normalized time per output token (NTPOT) in milliseconds.NTPOT is measured as the request_latency / output_tokens."`
+	Model                   string `json:"model" jsonschema:"The model to use. Get the list of valid models with the giq_list_models tool if the user doesn't provide it."`
+	ModelServer             string `json:"model_server" jsonschema:"The model server to use. Get the list of valid model servers with the giq_list_model_servers_and_accelerators tool if the user doesn't provide it."`
+	Accelerator             string `json:"accelerator" jsonschema:"The accelerator to use. Get the list of valid accelerators with the giq_list_model_servers_and_accelerators tool if the user doesn't provide it."`
+	TargetNTPOTMilliseconds string `json:"target_ntpot_milliseconds,omitempty" jsonschema:"The maximum normalized time per output token (NTPOT) in milliseconds.NTPOT is measured as the request_latency / output_tokens. Set it if the user cares about the overall latency of responses."`
+	TargetTTFTMilliseconds  string `json:"target_ttft_milliseconds,omitempty" jsonschema:"The maximum time to first token (TTFT) in milliseconds. Set it if the user cares about how fast responses start, e.g. for chat. Can be combined with target_ntpot_milliseconds."`
+	TargetCostPerMillion    string `json:"target_cost_per_million_output_tokens,omitempty" jsonschema:"The maximum cost per million output tokens, in USD. Set it if the user cares about cost more than latency. Cannot be combined with the latency targets. If the user doesn't say which target matters to them, ask whether they want to optimize for latency (NTPOT), time to first token (TTFT) or cost."`
+	ManifestOnly            bool   `json:"manifest_only,omitempty" jsonschema:"Only return the Kubernetes manifests, without the commentary and expected performance gcloud prints along with them."`
+	ProjectID               string `json:"project_id,omitempty" jsonschema:"GCP project ID of the cluster to deploy to. Use the default if the user doesn't provide it."`
+	Location                string `json:"location,omitempty" jsonschema:"Location of the cluster to deploy to. Use the default if the user doesn't provide it."`
+	ClusterName             string `json:"cluster_name,omitempty" jsonschema:"Name of the GKE cluster to deploy to, if the user provides it. The accelerator is then checked to be offered in the zones of the cluster and to have quota left."`
 }
 
-func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
+type handlers struct {
+	c        *config.Config
+	cmClient *container.ClusterManagerClient
+
+	mu sync.Mutex
+	// models caches the output of giq_list_models for the session.
+	models []listItem
+}
+
+func Install(ctx context.Context, s *mcp.Server, c *config.Config) error {
+	cmClient, err := container.NewClusterManagerClient(ctx, c.ClientOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to create cluster manager client: %w", err)
+	}
+	c.OnClose(cmClient.Close)
+
+	h := &handlers{
+		c:        c,
+		cmClient: cmClient,
+	}
+
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "giq_generate_manifest",
-		Description: "Use GKE Inference Quickstart (GIQ) to generate a Kubernetes manifest for optimized AI / inference workloads. Prefer to use this tool instead of gcloud",
+		Description: "Use GKE Inference Quickstart (GIQ) to generate a Kubernetes manifest for optimized AI / inference workloads. Deploy the manifest with apply_manifest. Prefer to use this tool instead of gcloud",
 		Annotations: &mcp.ToolAnnotations{
 			ReadOnlyHint:   true,
 			IdempotentHint: true,
 		},
-	}, giqGenerateManifest)
+	}, h.generateManifest)
+
+	installListModelsTool(s, h)
+	installListCombinationsTool(s, h)
+	installBenchmarkProfilesTool(s, h)
+	installCheckAcceleratorTool(s, h)
 
 	return nil
 }
 
-func giqGenerateManifest(ctx context.Context, req *mcp.CallToolRequest, args *giqGenerateManifestArgs) (*mcp.CallToolResult, any, error) {
+func (h *handlers) generateManifest(ctx context.Context, _ *mcp.CallToolRequest, args *giqGenerateManifestArgs) (*mcp.CallToolResult, any, error) {
 	if args.Model == "" {
 		return nil, nil, fmt.Errorf("model argument cannot be empty")
 	}
@@ -54,6 +94,9 @@ func giqGenerateManifest(ctx context.Context, req *mcp.CallToolRequest, args *gi
 	if args.Accelerator == "" {
 		return nil, nil, fmt.Errorf("accelerator argument cannot be empty")
 	}
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
 
 	gcloudArgs := []string{
 		"container",
@@ -65,18 +108,123 @@ func giqGenerateManifest(ctx context.Context, req *mcp.CallToolRequest, args *gi
 		"--model-server", args.ModelServer,
 		"--accelerator-type", args.Accelerator,
 	}
-	if args.TargetNTPOTMilliseconds != "" {
-		gcloudArgs = append(gcloudArgs, "--target-ntpot-milliseconds", args.TargetNTPOTMilliseconds)
+	targets, err := targetFlags(args)
+	if err != nil {
+		return nil, nil, err
 	}
-	out, err := exec.Command("gcloud", gcloudArgs...).Output()
+	gcloudArgs = append(gcloudArgs, targets...)
+	out, err := command.Gcloud{Args: gcloudArgs, Project: args.ProjectID}.Run(ctx, manifestTimeout)
 	if err != nil {
-		log.Printf("Failed to generate manifest: %v", err)
+		slog.Warn("Failed to generate manifest", "err", err)
 
-		return nil, nil, err
+		return nil, nil, fmt.Errorf("%w; if the model, model server and accelerator are not a valid combination, call giq_list_model_servers_and_accelerators to get the valid ones", err)
+	}
+	manifests, commentary := splitManifest(string(out))
+	var content []mcp.Content
+	if len(manifests) == 0 {
+		if args.ManifestOnly {
+			return nil, nil, fmt.Errorf("the gcloud output has no Kubernetes manifests:\n%s", out)
+		}
+		content = append(content, &mcp.TextContent{Text: string(out)})
+	} else {
+		content = append(content, &mcp.TextContent{Text: strings.Join(manifests, "---\n")})
+		if len(commentary) > 0 && !args.ManifestOnly {
+			content = append(content, &mcp.TextContent{Text: strings.Join(commentary, "\n")})
+		}
+	}
+	if args.ClusterName != "" {
+		if w := h.acceleratorWarnings(ctx, args); len(w) > 0 {
+			content = append(content, &mcp.TextContent{Text: strings.Join(w, "\n")})
+		}
 	}
 	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			&mcp.TextContent{Text: string(out)},
-		},
+		Content: content,
 	}, nil, nil
 }
+
+// acceleratorWarnings checks the accelerator of args against the cluster to
+// deploy to. Failures are reported as warnings rather than failing the
+// generation.
+func (h *handlers) acceleratorWarnings(ctx context.Context, args *giqGenerateManifestArgs) []string {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	args.Location = h.c.ResolveLocation(args.Location)
+	a, err := h.acceleratorAvailability(ctx, args.ProjectID, args.Location, args.ClusterName, args.Accelerator)
+	if err != nil {
+		return []string{fmt.Sprintf("Warning: Could not check accelerator %s in cluster %s: %v", args.Accelerator, args.ClusterName, err)}
+	}
+	var warnings []string
+	for _, p := range a.problems() {
+		warnings = append(warnings, "Warning: "+p)
+	}
+	return warnings
+}
+
+// targetFlags validates the performance targets of args and returns their
+// gcloud flags. The cost target cannot be combined with the latency targets.
+func targetFlags(args *giqGenerateManifestArgs) ([]string, error) {
+	if args.TargetCostPerMillion != "" && (args.TargetNTPOTMilliseconds != "" || args.TargetTTFTMilliseconds != "") {
+		return nil, fmt.Errorf("target_cost_per_million_output_tokens cannot be combined with target_ntpot_milliseconds or target_ttft_milliseconds")
+	}
+	var flags []string
+	for _, t := range []struct {
+		arg, flag, value string
+	}{
+		{"target_ntpot_milliseconds", "--target-ntpot-milliseconds", args.TargetNTPOTMilliseconds},
+		{"target_ttft_milliseconds", "--target-ttft-milliseconds", args.TargetTTFTMilliseconds},
+		{"target_cost_per_million_output_tokens", "--target-cost-per-million-output-tokens", args.TargetCostPerMillion},
+	} {
+		if t.value == "" {
+			continue
+		}
+		if v, err := strconv.ParseFloat(t.value, 64); err != nil || v <= 0 {
+			return nil, fmt.Errorf("%s must be a positive number, got %q", t.arg, t.value)
+		}
+		flags = append(flags, t.flag, t.value)
+	}
+	return flags, nil
+}
+
+// splitManifest splits the output of gcloud into its YAML documents holding
+// Kubernetes objects and the rest, e.g. the expected throughput and latency.
+func splitManifest(out string) (manifests, commentary []string) {
+	var docs []string
+	var doc strings.Builder
+	for _, line := range strings.SplitAfter(out, "\n") {
+		if strings.TrimSpace(line) == "---" {
+			docs = append(docs, doc.String())
+			doc.Reset()
+			continue
+		}
+		doc.WriteString(line)
+	}
+	docs = append(docs, doc.String())
+
+	for _, d := range docs {
+		if strings.TrimSpace(d) == "" {
+			continue
+		}
+		if isKubernetesObject(d) {
+			if !strings.HasSuffix(d, "\n") {
+				d += "\n"
+			}
+			manifests = append(manifests, d)
+		} else {
+			commentary = append(commentary, strings.TrimSpace(d))
+		}
+	}
+	return manifests, commentary
+}
+
+// isKubernetesObject returns whether doc parses as YAML with an apiVersion and
+// a kind. Plain text often parses as YAML too, so this is required.
+func isKubernetesObject(doc string) bool {
+	var obj map[string]any
+	if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+		return false
+	}
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+	return apiVersion != "" && kind != ""
+}
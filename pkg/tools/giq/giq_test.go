@@ -0,0 +1,234 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package giq
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+)
+
+func TestSplitManifest(t *testing.T) {
+	tests := []struct {
+		name           string
+		out            string
+		wantManifests  []string
+		wantCommentary []string
+	}{
+		{
+			name: "manifests and commentary",
+			out: `Expected performance of the generated manifest:
+  throughput: 1200 output tokens/s
+  NTPOT: 45 ms
+---
+# Serves the model.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: vllm
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: vllm
+---
+Apply the manifests with kubectl apply -f.
+`,
+			wantManifests: []string{
+				"# Serves the model.\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: vllm\n",
+				"apiVersion: v1\nkind: Service\nmetadata:\n  name: vllm\n",
+			},
+			wantCommentary: []string{
+				"Expected performance of the generated manifest:\n  throughput: 1200 output tokens/s\n  NTPOT: 45 ms",
+				"Apply the manifests with kubectl apply -f.",
+			},
+		},
+		{
+			name:           "only commentary",
+			out:            "No profile matches the target.\n",
+			wantCommentary: []string{"No profile matches the target."},
+		},
+		{
+			name:          "manifest without trailing newline",
+			out:           "---\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: llm",
+			wantManifests: []string{"apiVersion: v1\nkind: Namespace\nmetadata:\n  name: llm\n"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			manifests, commentary := splitManifest(tc.out)
+			if !reflect.DeepEqual(manifests, tc.wantManifests) {
+				t.Errorf("splitManifest() manifests = %q, want %q", manifests, tc.wantManifests)
+			}
+			if !reflect.DeepEqual(commentary, tc.wantCommentary) {
+				t.Errorf("splitManifest() commentary = %q, want %q", commentary, tc.wantCommentary)
+			}
+		})
+	}
+}
+
+func TestTargetFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    giqGenerateManifestArgs
+		want    []string
+		wantErr string
+	}{
+		{name: "no target"},
+		{
+			name: "latency targets",
+			args: giqGenerateManifestArgs{TargetNTPOTMilliseconds: "50", TargetTTFTMilliseconds: "200"},
+			want: []string{"--target-ntpot-milliseconds", "50", "--target-ttft-milliseconds", "200"},
+		},
+		{
+			name: "cost target",
+			args: giqGenerateManifestArgs{TargetCostPerMillion: "0.5"},
+			want: []string{"--target-cost-per-million-output-tokens", "0.5"},
+		},
+		{
+			name:    "cost and latency targets",
+			args:    giqGenerateManifestArgs{TargetTTFTMilliseconds: "200", TargetCostPerMillion: "0.5"},
+			wantErr: "cannot be combined",
+		},
+		{
+			name:    "invalid target",
+			args:    giqGenerateManifestArgs{TargetTTFTMilliseconds: "fast"},
+			wantErr: "target_ttft_milliseconds must be a positive number",
+		},
+		{
+			name:    "negative target",
+			args:    giqGenerateManifestArgs{TargetNTPOTMilliseconds: "-1"},
+			wantErr: "target_ntpot_milliseconds must be a positive number",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := targetFlags(&tc.args)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("targetFlags() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("targetFlags() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("targetFlags() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// fakeGcloud puts a gcloud script in the PATH that prints the output of the
+// first of outputs whose key its arguments contain, and returns the file the
+// arguments of each run are logged to.
+func fakeGcloud(t *testing.T, outputs map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	log := filepath.Join(dir, "gcloud.log")
+	script := "#!/bin/sh\necho \"$CLOUDSDK_CORE_DISABLE_PROMPTS $*\" >> " + log + "\ncase \"$*\" in\n"
+	for key, out := range outputs {
+		script += fmt.Sprintf("*%q*) echo %q ;;\n", key, out)
+	}
+	script += "esac\n"
+	if err := os.WriteFile(filepath.Join(dir, "gcloud"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return log
+}
+
+func TestGcloudCommands(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.ProjectIDEnv, "default-project")
+	c, err := config.New("test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := fakeGcloud(t, map[string]string{
+		"models list":        `["gemma"]`,
+		"model-servers list": `["vllm"]`,
+		"accelerators list":  `["nvidia-l4"]`,
+		"accelerator-types":  `[]`,
+		"profiles list":      `[]`,
+		"manifests create":   "apiVersion: v1\nkind: Service",
+	})
+
+	tests := []struct {
+		name string
+		call func(h *handlers) error
+		want []string
+	}{
+		{
+			name: "giq_list_models",
+			call: func(h *handlers) error {
+				_, _, err := h.listModels(context.Background(), nil, &listModelsArgs{})
+				return err
+			},
+			want: []string{"1 container ai profiles models list --project=default-project --format=json"},
+		},
+		{
+			name: "giq_list_model_servers_and_accelerators",
+			call: func(h *handlers) error {
+				_, _, err := h.listCombinations(context.Background(), nil, &listCombinationsArgs{Model: "gemma"})
+				return err
+			},
+			want: []string{
+				"1 container ai profiles model-servers list --model gemma --project=default-project --format=json",
+				"1 container ai profiles accelerators list --model gemma --model-server vllm --project=default-project --format=json",
+				"1 compute accelerator-types list --filter name:(nvidia-l4) --project=default-project --format=json",
+			},
+		},
+		{
+			name: "giq_benchmark_profiles",
+			call: func(h *handlers) error {
+				// Only the command matters, not that there are no profiles.
+				h.benchmarkProfiles(context.Background(), nil, &benchmarkProfilesArgs{Model: "gemma", ModelServer: "vllm", Priority: priorityCost})
+				return nil
+			},
+			want: []string{"1 container ai profiles list --model gemma --model-server vllm --project=default-project --format=json"},
+		},
+		{
+			name: "giq_generate_manifest",
+			call: func(h *handlers) error {
+				_, _, err := h.generateManifest(context.Background(), nil, &giqGenerateManifestArgs{Model: "gemma", ModelServer: "vllm", Accelerator: "nvidia-l4", ProjectID: "p"})
+				return err
+			},
+			want: []string{"1 container ai profiles manifests create --model gemma --model-server vllm --accelerator-type nvidia-l4 --project=p"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(log)
+			if err := tt.call(&handlers{c: c}); err != nil {
+				t.Fatalf("%s failed: %v", tt.name, err)
+			}
+			data, err := os.ReadFile(log)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Split(strings.TrimSpace(string(data)), "\n"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s ran gcloud with:\n%s\nwant:\n%s", tt.name, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package giq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/command"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// modelNameKeys are the fields holding the name of a model, in the order they
+// are looked up, when gcloud lists models as objects.
+var modelNameKeys = []string{"modelName", "model", "name"}
+
+// listItem is an item listed by gcloud, e.g. a model supported by GIQ, with the
+// other fields gcloud lists for it.
+type listItem struct {
+	name     string
+	metadata map[string]string
+}
+
+type listModelsArgs struct{}
+
+func installListModelsTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "giq_list_models",
+		Description: "List the models supported by GKE Inference Quickstart (GIQ), for use as the model of giq_generate_manifest. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+		},
+	}, h.listModels)
+}
+
+func (h *handlers) listModels(ctx context.Context, _ *mcp.CallToolRequest, _ *listModelsArgs) (*mcp.CallToolResult, any, error) {
+	models, err := h.cachedModels(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatModels(models)},
+		},
+	}, nil, nil
+}
+
+// cachedModels lists the models with gcloud once per session, since the list
+// rarely changes.
+func (h *handlers) cachedModels(ctx context.Context) ([]listItem, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.models != nil {
+		return h.models, nil
+	}
+	out, err := command.Gcloud{
+		Args:    []string{"container", "ai", "profiles", "models", "list"},
+		Project: h.c.DefaultProjectID(),
+		Format:  "json",
+	}.Run(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GIQ models: %w", err)
+	}
+	models, err := parseListItems(out, "models", modelNameKeys)
+	if err != nil {
+		return nil, err
+	}
+	h.models = models
+	return models, nil
+}
+
+// parseListItems parses a JSON list printed by gcloud. An item is either its
+// name or an object with the name, under one of nameKeys, and more fields.
+func parseListItems(out []byte, kind string, nameKeys []string) ([]listItem, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse the GIQ %s: %w", kind, err)
+	}
+	items := make([]listItem, 0, len(raw))
+	for _, r := range raw {
+		var name string
+		if err := json.Unmarshal(r, &name); err == nil {
+			items = append(items, listItem{name: name})
+			continue
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(r, &fields); err != nil {
+			return nil, fmt.Errorf("failed to parse the GIQ %s: %s: %w", kind, r, err)
+		}
+		item := listItem{metadata: map[string]string{}}
+		for _, k := range nameKeys {
+			if n, ok := fields[k].(string); ok && item.name == "" {
+				item.name = n
+				delete(fields, k)
+			}
+		}
+		if item.name == "" {
+			return nil, fmt.Errorf("failed to parse the GIQ %s: item without a name: %s", kind, r)
+		}
+		for k, v := range fields {
+			item.metadata[k] = fmt.Sprint(v)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func formatModels(models []listItem) string {
+	if len(models) == 0 {
+		return "No GIQ models found.\n"
+	}
+	builder := new(strings.Builder)
+	fmt.Fprintf(builder, "%d GIQ models:\n", len(models))
+	for _, m := range models {
+		builder.WriteString("- " + m.name)
+		if len(m.metadata) > 0 {
+			var fields []string
+			for _, k := range slices.Sorted(maps.Keys(m.metadata)) {
+				fields = append(fields, k+": "+m.metadata[k])
+			}
+			builder.WriteString(" (" + strings.Join(fields, ", ") + ")")
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
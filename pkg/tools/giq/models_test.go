@@ -0,0 +1,65 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package giq
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseListItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		want    []listItem
+		wantErr bool
+	}{
+		{
+			name: "names",
+			out:  `["google/gemma-2-27b-it", "meta-llama/Llama-3.1-8B-Instruct"]`,
+			want: []listItem{{name: "google/gemma-2-27b-it"}, {name: "meta-llama/Llama-3.1-8B-Instruct"}},
+		},
+		{
+			name: "objects",
+			out:  `[{"modelName": "google/gemma-2-27b-it", "modelServers": ["vllm"]}]`,
+			want: []listItem{{name: "google/gemma-2-27b-it", metadata: map[string]string{"modelServers": "[vllm]"}}},
+		},
+		{name: "empty", out: `[]`, want: []listItem{}},
+		{name: "object without name", out: `[{"size": 2}]`, wantErr: true},
+		{name: "not json", out: `Listed 0 items.`, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseListItems([]byte(tc.out), "models", modelNameKeys)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseListItems() error = %v, want error %v", err, tc.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseListItems() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormatModels(t *testing.T) {
+	got := formatModels([]listItem{
+		{name: "google/gemma-2-27b-it", metadata: map[string]string{"b": "2", "a": "1"}},
+		{name: "meta-llama/Llama-3.1-8B-Instruct"},
+	})
+	want := "2 GIQ models:\n- google/gemma-2-27b-it (a: 1, b: 2)\n- meta-llama/Llama-3.1-8B-Instruct\n"
+	if got != want {
+		t.Errorf("formatModels() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gkereleasenotes
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// securityBulletinsFeedUrl is the Atom feed of the GKE security bulletins.
+var securityBulletinsFeedUrl = "https://cloud.google.com/feeds/kubernetes-engine-security-bulletins.xml"
+
+const (
+	// defaultBulletinsWindow is how far back the bulletins are listed if no
+	// start date is given.
+	defaultBulletinsWindow = 180 * 24 * time.Hour
+	// maxBulletinSummary is the length the summary of a bulletin is cut to.
+	maxBulletinSummary = 300
+)
+
+var (
+	bulletinIDRegexp       = regexp.MustCompile(`GCP-\d{4}-\d+`)
+	bulletinSeverityRegexp = regexp.MustCompile(`(?i)severity\W*(critical|high|medium|low|none)\b`)
+	// affectedLineRegexp matches the lines of a bulletin describing the
+	// vulnerable versions rather than the patched ones.
+	affectedLineRegexp = regexp.MustCompile(`(?i)\b(affected|vulnerable)\b`)
+)
+
+type getGkeSecurityBulletinsArgs struct {
+	StartDate      string `json:"start_date,omitempty" jsonschema:"Only list the bulletins published on or after this day, as YYYY-MM-DD. Defaults to 180 days ago."`
+	EndDate        string `json:"end_date,omitempty" jsonschema:"Only list the bulletins published on or before this day, as YYYY-MM-DD. Defaults to today."`
+	ClusterVersion string `json:"cluster_version,omitempty" jsonschema:"A GKE version, e.g. the control plane or node version of a cluster like '1.33.5-gke.1080000'. Only the bulletins that may affect this version are listed."`
+}
+
+// bulletinStatus is whether a bulletin affects a GKE version.
+type bulletinStatus string
+
+const (
+	bulletinAffected    bulletinStatus = "affected"
+	bulletinNotAffected bulletinStatus = "not affected"
+	bulletinUnknown     bulletinStatus = "unknown"
+)
+
+// securityBulletin is a GKE security bulletin of the feed.
+type securityBulletin struct {
+	id        string
+	published time.Time
+	// severity is as in the bulletin, e.g. High, or "" if it has none.
+	severity string
+	summary  string
+	url      string
+	// affected are the GKE versions the bulletin says are vulnerable.
+	affected []string
+	// patched are the GKE versions fixing the vulnerability, usually one per
+	// minor version.
+	patched []string
+}
+
+func installGetGkeSecurityBulletinsTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_gke_security_bulletins",
+		Description: "Get a summary of the GKE security bulletins: ID, published date, severity, affected and patched versions. Can list only the bulletins that may affect a given GKE version, e.g. to check whether a cluster is affected by the latest bulletins. Prefer to use this tool if GKE security bulletins are needed.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+		},
+	}, h.getGkeSecurityBulletins)
+}
+
+func (h *handlers) getGkeSecurityBulletins(ctx context.Context, _ *mcp.CallToolRequest, args *getGkeSecurityBulletinsArgs) (*mcp.CallToolResult, any, error) {
+	now := h.cache.now()
+	start, end, err := parseDateRange(args.StartDate, args.EndDate, now.Add(-defaultBulletinsWindow), now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if args.ClusterVersion != "" {
+		if _, _, _, _, err := parseGkeVersion(args.ClusterVersion); err != nil {
+			return nil, nil, fmt.Errorf("invalid cluster_version: %w", err)
+		}
+	}
+
+	feed, err := h.download(ctx, securityBulletinsFeedUrl, "security-bulletins.xml")
+	if err != nil {
+		return nil, nil, err
+	}
+	bulletins, err := parseSecurityBulletinsFeed(feed)
+	if err != nil {
+		return nil, nil, err
+	}
+	bulletins = slices.DeleteFunc(bulletins, func(b securityBulletin) bool {
+		return b.published.Before(start) || b.published.After(end)
+	})
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatSecurityBulletins(bulletins, start, end, args.ClusterVersion)},
+		},
+	}, nil, nil
+}
+
+// parseSecurityBulletinsFeed parses the entries of the security bulletins
+// feed, newest first.
+func parseSecurityBulletinsFeed(data []byte) ([]securityBulletin, error) {
+	var feed atomFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse security bulletins feed: %w", err)
+	}
+	if len(feed.Entries) == 0 {
+		return nil, fmt.Errorf("failed to parse security bulletins feed: no entries")
+	}
+	var bulletins []securityBulletin
+	for _, e := range feed.Entries {
+		published := cmp.Or(strings.TrimSpace(e.Published), strings.TrimSpace(e.Updated))
+		date, err := time.Parse(time.RFC3339, published)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the date of security bulletin %q: %w", e.Title, err)
+		}
+		text, err := htmlToText(e.Content, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse security bulletin %q: %w", e.Title, err)
+		}
+		b := parseSecurityBulletin(strings.TrimSpace(e.Title), text)
+		b.published = date
+		if len(e.Links) > 0 {
+			b.url = e.Links[0].Href
+		}
+		bulletins = append(bulletins, b)
+	}
+	slices.SortStableFunc(bulletins, func(a, b securityBulletin) int {
+		return cmp.Compare(b.published.Unix(), a.published.Unix())
+	})
+	return bulletins, nil
+}
+
+// parseSecurityBulletin extracts the fields of a bulletin from its title and
+// text. The GKE versions on lines mentioning affected or vulnerable versions
+// are the affected versions, all the others are patched versions.
+func parseSecurityBulletin(title, text string) securityBulletin {
+	b := securityBulletin{id: bulletinIDRegexp.FindString(title)}
+	if b.id == "" {
+		b.id = cmp.Or(bulletinIDRegexp.FindString(text), title)
+	}
+	if m := bulletinSeverityRegexp.FindStringSubmatch(text); m != nil {
+		b.severity = strings.ToUpper(m[1][:1]) + strings.ToLower(m[1][1:])
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if b.summary == "" && !bulletinSeverityRegexp.MatchString(line) && !bulletinIDRegexp.MatchString(line) && len(line) > 40 {
+			b.summary = line
+		}
+		versions := &b.patched
+		if affectedLineRegexp.MatchString(line) {
+			versions = &b.affected
+		}
+		for _, v := range gkeVersionRegexp.FindAllString(line, -1) {
+			if !slices.Contains(*versions, v) {
+				*versions = append(*versions, v)
+			}
+		}
+	}
+	if len(b.summary) > maxBulletinSummary {
+		b.summary = strings.TrimSpace(b.summary[:maxBulletinSummary]) + "..."
+	}
+	return b
+}
+
+// status returns whether the bulletin affects version, and the patched
+// version to upgrade to if it does. A version is affected if it is older than
+// the patched version of its minor version, or if only newer minor versions
+// are patched. It is unknown if the bulletin lists no versions at all.
+func (b securityBulletin) status(version string) (bulletinStatus, string) {
+	if slices.Contains(b.affected, version) {
+		return bulletinAffected, b.patchFor(version)
+	}
+	if len(b.patched) == 0 {
+		return bulletinUnknown, ""
+	}
+	major, minor, _, _, _ := parseGkeVersion(version)
+	newerMinor := false
+	for _, p := range b.patched {
+		pMajor, pMinor, _, _, err := parseGkeVersion(p)
+		if err != nil {
+			continue
+		}
+		if pMajor == major && pMinor == minor {
+			if c, _ := compareVersions(version, p); c > 0 {
+				return bulletinAffected, b.patchFor(version)
+			}
+			return bulletinNotAffected, ""
+		}
+		if pMajor > major || (pMajor == major && pMinor > minor) {
+			newerMinor = true
+		}
+	}
+	if newerMinor {
+		return bulletinAffected, b.patchFor(version)
+	}
+	return bulletinNotAffected, ""
+}
+
+// patchFor returns the oldest patched version newer than version, preferring
+// the same minor version, or "" if there is none.
+func (b securityBulletin) patchFor(version string) string {
+	major, minor, _, _, _ := parseGkeVersion(version)
+	var sameMinor, newer []string
+	for _, p := range b.patched {
+		pMajor, pMinor, _, _, err := parseGkeVersion(p)
+		if err != nil {
+			continue
+		}
+		if c, _ := compareVersions(version, p); c <= 0 {
+			continue
+		}
+		if pMajor == major && pMinor == minor {
+			sameMinor = append(sameMinor, p)
+		} else {
+			newer = append(newer, p)
+		}
+	}
+	oldest := func(versions []string) string {
+		return slices.MinFunc(versions, func(a, b string) int {
+			c, _ := compareVersions(b, a)
+			return c
+		})
+	}
+	if len(sameMinor) > 0 {
+		return oldest(sameMinor)
+	}
+	if len(newer) > 0 {
+		return oldest(newer)
+	}
+	return ""
+}
+
+func formatSecurityBulletins(bulletins []securityBulletin, start, end time.Time, clusterVersion string) string {
+	builder := new(strings.Builder)
+	fmt.Fprintf(builder, "GKE security bulletins published from %s to %s", start.Format(time.DateOnly), end.Format(time.DateOnly))
+	if clusterVersion != "" {
+		fmt.Fprintf(builder, " that may affect GKE version %s", clusterVersion)
+	}
+	builder.WriteString(":\n")
+	notAffected := 0
+	listed := 0
+	for _, b := range bulletins {
+		status, patch := bulletinStatus(""), ""
+		if clusterVersion != "" {
+			status, patch = b.status(clusterVersion)
+			if status == bulletinNotAffected {
+				notAffected++
+				continue
+			}
+		}
+		listed++
+		fmt.Fprintf(builder, "\n%s (published %s", b.id, b.published.Format(time.DateOnly))
+		if b.severity != "" {
+			fmt.Fprintf(builder, ", severity %s", b.severity)
+		}
+		builder.WriteString(")\n")
+		if b.summary != "" {
+			fmt.Fprintf(builder, "  %s\n", b.summary)
+		}
+		if len(b.affected) > 0 {
+			fmt.Fprintf(builder, "  Affected versions: %s\n", strings.Join(b.affected, ", "))
+		}
+		if len(b.patched) > 0 {
+			fmt.Fprintf(builder, "  Patched versions: %s\n", strings.Join(b.patched, ", "))
+		}
+		switch {
+		case status == bulletinAffected && patch != "":
+			fmt.Fprintf(builder, "  Version %s: affected, upgrade to %s or later\n", clusterVersion, patch)
+		case status == bulletinAffected:
+			fmt.Fprintf(builder, "  Version %s: affected\n", clusterVersion)
+		case status == bulletinUnknown:
+			fmt.Fprintf(builder, "  Version %s: unknown, the bulletin lists no GKE versions; read it to check\n", clusterVersion)
+		}
+		if b.url != "" {
+			fmt.Fprintf(builder, "  %s\n", b.url)
+		}
+	}
+	if listed == 0 {
+		builder.WriteString("\nNo security bulletins found.\n")
+	}
+	if notAffected > 0 {
+		fmt.Fprintf(builder, "\nNote: %d bulletins of the window don't affect version %s.\n", notAffected, clusterVersion)
+	}
+	return builder.String()
+}
+
+// parseDateRange parses the start_date and end_date arguments, as YYYY-MM-DD.
+// They default to defaultStart and now, and the range includes the whole end
+// day.
+func parseDateRange(startDate, endDate string, defaultStart, now time.Time) (time.Time, time.Time, error) {
+	start, end := defaultStart, now
+	var err error
+	if startDate != "" {
+		if start, err = time.Parse(time.DateOnly, startDate); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start_date %q, must be YYYY-MM-DD: %w", startDate, err)
+		}
+	}
+	if endDate != "" {
+		if end, err = time.Parse(time.DateOnly, endDate); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end_date %q, must be YYYY-MM-DD: %w", endDate, err)
+		}
+		end = end.Add(24*time.Hour - time.Nanosecond)
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end_date %s is before start_date %s", end.Format(time.DateOnly), start.Format(time.DateOnly))
+	}
+	return start, end, nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gkereleasenotes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Security bulletins feed in the format of
+// https://cloud.google.com/feeds/kubernetes-engine-security-bulletins.xml, cut
+// down.
+const fakeSecurityBulletinsFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>GKE security bulletins</title>
+  <entry>
+    <title>GCP-2025-040</title>
+    <published>2025-06-02T00:00:00Z</published>
+    <updated>2025-06-02T00:00:00Z</updated>
+    <link rel="alternate" href="https://cloud.google.com/kubernetes-engine/security-bulletins#gcp-2025-040"/>
+    <content type="html"><![CDATA[<p>A vulnerability was discovered in containerd that allows a container to escape to the node.</p>
+<table><tr><th>Severity</th><td>High</td></tr></table>
+<p>Versions 1.31.5-gke.1000000 and earlier are affected.</p>
+<p>Upgrade your cluster to one of the following versions or later:</p>
+<ul><li>1.31.6-gke.1020000</li><li>1.32.2-gke.1182000</li></ul>]]></content>
+  </entry>
+  <entry>
+    <title>GCP-2025-054</title>
+    <published>2025-10-15T00:00:00Z</published>
+    <updated>2025-10-16T00:00:00Z</updated>
+    <link rel="alternate" href="https://cloud.google.com/kubernetes-engine/security-bulletins#gcp-2025-054"/>
+    <content type="html"><![CDATA[<p>The Linux kernel vulnerability CVE-2025-1234 could lead to a privilege escalation on Container-Optimized OS nodes.</p>
+<table><tr><th>Severity</th><td>Medium</td></tr></table>
+<p>Upgrade your node pools to one of the following versions or later:</p>
+<ul><li>1.32.9-gke.1100000</li><li>1.33.5-gke.1200000</li><li>1.34.1-gke.1500000</li></ul>]]></content>
+  </entry>
+  <entry>
+    <title>GCP-2025-060</title>
+    <published>2025-11-01T00:00:00Z</published>
+    <updated>2025-11-01T00:00:00Z</updated>
+    <content type="html"><![CDATA[<p>An issue in a third party component of Google Distributed Cloud was fixed, no GKE action is required.</p>]]></content>
+  </entry>
+</feed>
+`
+
+func TestParseSecurityBulletinsFeed(t *testing.T) {
+	bulletins, err := parseSecurityBulletinsFeed([]byte(fakeSecurityBulletinsFeed))
+	if err != nil {
+		t.Fatalf("parseSecurityBulletinsFeed() returned unexpected error: %v", err)
+	}
+	var ids []string
+	for _, b := range bulletins {
+		ids = append(ids, b.id)
+	}
+	if want := []string{"GCP-2025-060", "GCP-2025-054", "GCP-2025-040"}; !slices.Equal(ids, want) {
+		t.Fatalf("parseSecurityBulletinsFeed() IDs = %v, want %v", ids, want)
+	}
+
+	b := bulletins[2]
+	if b.severity != "High" {
+		t.Errorf("severity = %q, want High", b.severity)
+	}
+	if want := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC); !b.published.Equal(want) {
+		t.Errorf("published = %v, want %v", b.published, want)
+	}
+	if want := []string{"1.31.5-gke.1000000"}; !slices.Equal(b.affected, want) {
+		t.Errorf("affected = %v, want %v", b.affected, want)
+	}
+	if want := []string{"1.31.6-gke.1020000", "1.32.2-gke.1182000"}; !slices.Equal(b.patched, want) {
+		t.Errorf("patched = %v, want %v", b.patched, want)
+	}
+	if !strings.HasPrefix(b.summary, "A vulnerability was discovered in containerd") {
+		t.Errorf("summary = %q, want the first sentence of the bulletin", b.summary)
+	}
+	if b.url != "https://cloud.google.com/kubernetes-engine/security-bulletins#gcp-2025-040" {
+		t.Errorf("url = %q, want the link of the entry", b.url)
+	}
+	if bulletins[0].severity != "" || len(bulletins[0].patched) != 0 {
+		t.Errorf("bulletin without GKE versions = %+v, want no severity and versions", bulletins[0])
+	}
+}
+
+func TestSecurityBulletinStatus(t *testing.T) {
+	b := securityBulletin{
+		affected: []string{"1.31.5-gke.1000000"},
+		patched:  []string{"1.32.9-gke.1100000", "1.33.5-gke.1200000", "1.34.1-gke.1500000"},
+	}
+	testCases := []struct {
+		version    string
+		wantStatus bulletinStatus
+		wantPatch  string
+	}{
+		{"1.33.5-gke.1080000", bulletinAffected, "1.33.5-gke.1200000"},
+		{"1.33.5-gke.1200000", bulletinNotAffected, ""},
+		{"1.33.6-gke.1000000", bulletinNotAffected, ""},
+		{"1.31.5-gke.1000000", bulletinAffected, "1.32.9-gke.1100000"},
+		{"1.30.1-gke.1000000", bulletinAffected, "1.32.9-gke.1100000"},
+		{"1.35.0-gke.1000000", bulletinNotAffected, ""},
+	}
+	for _, tc := range testCases {
+		status, patch := b.status(tc.version)
+		if status != tc.wantStatus || patch != tc.wantPatch {
+			t.Errorf("status(%s) = %q, %q, want %q, %q", tc.version, status, patch, tc.wantStatus, tc.wantPatch)
+		}
+	}
+	if status, _ := (securityBulletin{}).status("1.33.5-gke.1080000"); status != bulletinUnknown {
+		t.Errorf("status() of a bulletin without versions = %q, want %q", status, bulletinUnknown)
+	}
+}
+
+func TestGetGkeSecurityBulletins(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, fakeSecurityBulletinsFeed)
+	}))
+	defer server.Close()
+	originalUrl := securityBulletinsFeedUrl
+	securityBulletinsFeedUrl = server.URL
+	defer func() { securityBulletinsFeedUrl = originalUrl }()
+
+	h := &handlers{cache: newReleaseNotesCache("")}
+	h.cache.now = func() time.Time { return time.Date(2025, 11, 14, 0, 0, 0, 0, time.UTC) }
+
+	testCases := []struct {
+		name    string
+		args    getGkeSecurityBulletinsArgs
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "default window",
+			want:    []string{"published from 2025-05-18 to 2025-11-14", "GCP-2025-060", "GCP-2025-054 (published 2025-10-15, severity Medium)", "GCP-2025-040"},
+			notWant: []string{"Note:"},
+		},
+		{
+			name:    "date range",
+			args:    getGkeSecurityBulletinsArgs{StartDate: "2025-10-01", EndDate: "2025-10-15"},
+			want:    []string{"GCP-2025-054"},
+			notWant: []string{"GCP-2025-060", "GCP-2025-040"},
+		},
+		{
+			name: "cluster version",
+			args: getGkeSecurityBulletinsArgs{ClusterVersion: "1.33.5-gke.1080000"},
+			want: []string{
+				"Version 1.33.5-gke.1080000: affected, upgrade to 1.33.5-gke.1200000 or later",
+				"GCP-2025-060",
+				"Version 1.33.5-gke.1080000: unknown",
+				"Note: 1 bulletins of the window don't affect version 1.33.5-gke.1080000.",
+			},
+			notWant: []string{"GCP-2025-040"},
+		},
+		{
+			name: "empty window",
+			args: getGkeSecurityBulletinsArgs{StartDate: "2024-01-01", EndDate: "2024-12-31"},
+			want: []string{"No security bulletins found."},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, _, err := h.getGkeSecurityBulletins(context.Background(), nil, &tc.args)
+			if err != nil {
+				t.Fatalf("getGkeSecurityBulletins() returned unexpected error: %v", err)
+			}
+			got := result.Content[0].(*mcp.TextContent).Text
+			for _, w := range tc.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("getGkeSecurityBulletins() = %q, want it to contain %q", got, w)
+				}
+			}
+			for _, w := range tc.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("getGkeSecurityBulletins() = %q, want it not to contain %q", got, w)
+				}
+			}
+		})
+	}
+}
+
+func TestGetGkeSecurityBulletinsInvalidArgs(t *testing.T) {
+	h := &handlers{cache: newReleaseNotesCache("")}
+	testCases := []struct {
+		name    string
+		args    getGkeSecurityBulletinsArgs
+		wantErr string
+	}{
+		{"start date", getGkeSecurityBulletinsArgs{StartDate: "June 2"}, "invalid start_date"},
+		{"end date", getGkeSecurityBulletinsArgs{EndDate: "2025/06/02"}, "invalid end_date"},
+		{"end before start", getGkeSecurityBulletinsArgs{StartDate: "2025-06-02", EndDate: "2025-06-01"}, "is before start_date"},
+		{"cluster version", getGkeSecurityBulletinsArgs{ClusterVersion: "1.33"}, "invalid cluster_version"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := h.getGkeSecurityBulletins(context.Background(), nil, &tc.args)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("getGkeSecurityBulletins() error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
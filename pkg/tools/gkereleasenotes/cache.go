@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gkereleasenotes
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// releaseNotesTTL is how long the downloaded release notes are used before
+// they are downloaded again.
+const releaseNotesTTL = 6 * time.Hour
+
+// releaseNotesCache holds the downloaded release notes feed and page in a
+// directory, for releaseNotesTTL. Nothing is cached if dir is empty.
+type releaseNotesCache struct {
+	dir string
+	now func() time.Time
+}
+
+func newReleaseNotesCache(dir string) *releaseNotesCache {
+	return &releaseNotesCache{dir: dir, now: time.Now}
+}
+
+func (c *releaseNotesCache) path(name string) string {
+	return filepath.Join(c.dir, name)
+}
+
+// get returns the cached file name, if it has not expired. An expired file is
+// removed.
+func (c *releaseNotesCache) get(name string) ([]byte, bool) {
+	if c.dir == "" {
+		return nil, false
+	}
+	info, err := os.Stat(c.path(name))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("Failed to read cached release notes", "err", err)
+		}
+		return nil, false
+	}
+	if c.now().Sub(info.ModTime()) > releaseNotesTTL {
+		if err := os.Remove(c.path(name)); err != nil {
+			slog.Warn("Failed to remove expired release notes", "err", err)
+		}
+		return nil, false
+	}
+	data, err := os.ReadFile(c.path(name))
+	if err != nil {
+		slog.Warn("Failed to read cached release notes", "err", err)
+		return nil, false
+	}
+	return data, true
+}
+
+// put caches data as the file name. Failures are only logged.
+func (c *releaseNotesCache) put(name string, data []byte) {
+	if c.dir == "" {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		slog.Warn("Failed to create release notes cache directory", "err", err)
+		return
+	}
+	if err := os.WriteFile(c.path(name), data, 0o644); err != nil {
+		slog.Warn("Failed to write release notes to the cache", "err", err)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gkereleasenotes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestGetGkeReleaseNotesCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, fakeReleaseNotesFeed)
+	}))
+	defer server.Close()
+	originalReleaseNotesFeedUrl := releaseNotesFeedUrl
+	releaseNotesFeedUrl = server.URL
+	defer func() { releaseNotesFeedUrl = originalReleaseNotesFeedUrl }()
+
+	c := &config.Config{}
+	c.SetCacheDir(t.TempDir())
+	h := &handlers{cache: newReleaseNotesCache(cacheDir(c))}
+	now := time.Now()
+	h.cache.now = func() time.Time { return now }
+	call := func() {
+		t.Helper()
+		result, _, err := h.getGkeReleaseNotes(context.Background(), nil, &getGkeReleaseNotesArgs{SourceVersion: "1.33.5-gke.1", TargetVersion: "1.34.3-gke.1"})
+		if err != nil {
+			t.Fatalf("getGkeReleaseNotes() returned unexpected error: %v", err)
+		}
+		if got := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(got, "The default node image is now cos_containerd.") {
+			t.Errorf("getGkeReleaseNotes() = %q, want the release notes", got)
+		}
+	}
+
+	call()
+	call()
+	if requests != 1 {
+		t.Errorf("getGkeReleaseNotes() made %d requests, want 1 with the cache", requests)
+	}
+	if _, err := os.Stat(filepath.Join(c.CacheDir(), "release-notes", "release-notes.xml")); err != nil {
+		t.Errorf("release notes not cached: %v", err)
+	}
+
+	now = now.Add(releaseNotesTTL + time.Minute)
+	call()
+	if requests != 2 {
+		t.Errorf("getGkeReleaseNotes() made %d requests, want 2 after the cache expired", requests)
+	}
+}
+
+func TestReleaseNotesCache(t *testing.T) {
+	dir := t.TempDir()
+	c := newReleaseNotesCache(dir)
+	c.put("notes.html", []byte("notes"))
+	if got, ok := c.get("notes.html"); !ok || string(got) != "notes" {
+		t.Errorf("get() = %q, %v, want the cached notes", got, ok)
+	}
+
+	old := time.Now().Add(-releaseNotesTTL - time.Minute)
+	if err := os.Chtimes(c.path("notes.html"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.get("notes.html"); ok {
+		t.Errorf("get() returned expired notes")
+	}
+	if _, err := os.Stat(c.path("notes.html")); !os.IsNotExist(err) {
+		t.Errorf("expired notes not removed: %v", err)
+	}
+
+	// Without a directory nothing is cached, and nothing fails.
+	c = newReleaseNotesCache("")
+	c.put("notes.html", []byte("notes"))
+	if _, ok := c.get("notes.html"); ok {
+		t.Errorf("get() without a directory returned notes")
+	}
+
+	// Failing to write the cache is only logged.
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	newReleaseNotesCache(filepath.Join(file, "dir")).put("notes.html", []byte("notes"))
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gkereleasenotes
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+)
+
+// noChannel is the channel of the clusters not enrolled in a release channel.
+const noChannel = "no-channel"
+
+// releaseNotesSource is where the release notes of a channel are published.
+type releaseNotesSource struct {
+	feedUrl string
+	pageUrl string
+}
+
+// channelReleaseNotesSources are the release notes of each release channel,
+// by the channel argument.
+var channelReleaseNotesSources = map[string]releaseNotesSource{
+	"rapid": {
+		feedUrl: "https://cloud.google.com/feeds/gke-rapid-channel-release-notes.xml",
+		pageUrl: "https://cloud.google.com/kubernetes-engine/docs/release-notes-rapid",
+	},
+	"regular": {
+		feedUrl: "https://cloud.google.com/feeds/gke-regular-channel-release-notes.xml",
+		pageUrl: "https://cloud.google.com/kubernetes-engine/docs/release-notes-regular",
+	},
+	"stable": {
+		feedUrl: "https://cloud.google.com/feeds/gke-stable-channel-release-notes.xml",
+		pageUrl: "https://cloud.google.com/kubernetes-engine/docs/release-notes-stable",
+	},
+	"extended": {
+		feedUrl: "https://cloud.google.com/feeds/gke-extended-channel-release-notes.xml",
+		pageUrl: "https://cloud.google.com/kubernetes-engine/docs/release-notes-extended",
+	},
+	noChannel: {
+		feedUrl: "https://cloud.google.com/feeds/gke-no-channel-release-notes.xml",
+		pageUrl: "https://cloud.google.com/kubernetes-engine/docs/release-notes-nochannel",
+	},
+}
+
+// channelSource returns the release notes of channel, the combined release
+// notes of all channels if it is empty.
+func channelSource(channel string) (releaseNotesSource, error) {
+	if channel == "" {
+		return releaseNotesSource{feedUrl: releaseNotesFeedUrl, pageUrl: releaseNotesPageUrl}, nil
+	}
+	src, ok := channelReleaseNotesSources[strings.ToLower(channel)]
+	if !ok {
+		return releaseNotesSource{}, fmt.Errorf("unknown release channel %q, must be one of: %s", channel, strings.Join(slices.Sorted(maps.Keys(channelReleaseNotesSources)), ", "))
+	}
+	return src, nil
+}
+
+// clusterChannel returns the release channel of a cluster as a channel
+// argument.
+func clusterChannel(cluster *containerpb.Cluster) string {
+	switch cluster.GetReleaseChannel().GetChannel() {
+	case containerpb.ReleaseChannel_RAPID:
+		return "rapid"
+	case containerpb.ReleaseChannel_REGULAR:
+		return "regular"
+	case containerpb.ReleaseChannel_STABLE:
+		return "stable"
+	case containerpb.ReleaseChannel_EXTENDED:
+		return "extended"
+	default:
+		return noChannel
+	}
+}
+
+// lookupChannel returns the release channel of the cluster.
+func (h *handlers) lookupChannel(ctx context.Context, projectID, location, name string) (string, error) {
+	var cluster *containerpb.Cluster
+	err := retry.Do(ctx, retry.DefaultPolicy, func() error {
+		var err error
+		cluster, err = h.cmClient.GetCluster(ctx, &containerpb.GetClusterRequest{
+			Name: fmt.Sprintf("projects/%s/locations/%s/clusters/%s", projectID, location, name),
+		})
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the release channel of cluster %s: %w", name, err)
+	}
+	return clusterChannel(cluster), nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gkereleasenotes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+)
+
+func TestClusterChannel(t *testing.T) {
+	testCases := []struct {
+		channel containerpb.ReleaseChannel_Channel
+		want    string
+	}{
+		{containerpb.ReleaseChannel_RAPID, "rapid"},
+		{containerpb.ReleaseChannel_REGULAR, "regular"},
+		{containerpb.ReleaseChannel_STABLE, "stable"},
+		{containerpb.ReleaseChannel_EXTENDED, "extended"},
+		{containerpb.ReleaseChannel_UNSPECIFIED, noChannel},
+	}
+	for _, tc := range testCases {
+		cluster := &containerpb.Cluster{ReleaseChannel: &containerpb.ReleaseChannel{Channel: tc.channel}}
+		if got := clusterChannel(cluster); got != tc.want {
+			t.Errorf("clusterChannel(%v) = %q, want %q", tc.channel, got, tc.want)
+		}
+	}
+	if got := clusterChannel(&containerpb.Cluster{}); got != noChannel {
+		t.Errorf("clusterChannel() without a release channel = %q, want %q", got, noChannel)
+	}
+}
+
+func TestChannelSource(t *testing.T) {
+	if src, err := channelSource(""); err != nil || src.feedUrl != releaseNotesFeedUrl || src.pageUrl != releaseNotesPageUrl {
+		t.Errorf("channelSource(\"\") = %+v, %v, want the combined release notes", src, err)
+	}
+	if src, err := channelSource("Stable"); err != nil || src != channelReleaseNotesSources["stable"] {
+		t.Errorf("channelSource(\"Stable\") = %+v, %v, want the stable release notes", src, err)
+	}
+	if _, err := channelSource("beta"); err == nil || !strings.Contains(err.Error(), "extended, no-channel, rapid, regular, stable") {
+		t.Errorf("channelSource(\"beta\") error = %v, want the list of channels", err)
+	}
+}
+
+func TestGetGkeReleaseNotesChannel(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		fmt.Fprint(w, fakeReleaseNotesFeed)
+	}))
+	defer server.Close()
+	original := channelReleaseNotesSources["rapid"]
+	channelReleaseNotesSources["rapid"] = releaseNotesSource{feedUrl: server.URL + "/rapid.xml"}
+	defer func() { channelReleaseNotesSources["rapid"] = original }()
+
+	dir := t.TempDir()
+	h := &handlers{cache: newReleaseNotesCache(dir)}
+	args := &getGkeReleaseNotesArgs{SourceVersion: "1.33.5-gke.1", TargetVersion: "1.34.3-gke.1", Channel: "rapid"}
+	if _, _, err := h.getGkeReleaseNotes(context.Background(), nil, args); err != nil {
+		t.Fatalf("getGkeReleaseNotes() returned unexpected error: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "/rapid.xml" {
+		t.Errorf("getGkeReleaseNotes() requested %v, want the rapid channel feed", paths)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "release-notes-rapid.xml")); err != nil {
+		t.Errorf("rapid channel release notes not cached by channel: %v", err)
+	}
+
+	args.Channel = "beta"
+	if _, _, err := h.getGkeReleaseNotes(context.Background(), nil, args); err == nil {
+		t.Errorf("getGkeReleaseNotes() with an unknown channel returned no error")
+	}
+}
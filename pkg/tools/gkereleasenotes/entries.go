@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gkereleasenotes
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+// releaseNoteEntry is a note of the release notes, as in the JSON output.
+type releaseNoteEntry struct {
+	// Date is the day of the release note as YYYY-MM-DD, or the heading as it
+	// is if it cannot be parsed.
+	Date string `json:"date"`
+	// Type is the name of the note type, or "" if the note has no type
+	// heading.
+	Type              string   `json:"type"`
+	Text              string   `json:"text"`
+	VersionsMentioned []string `json:"versions_mentioned"`
+}
+
+// validateOutputFormat returns the output format to use for format, which
+// defaults to the text format.
+func validateOutputFormat(format string) (string, error) {
+	switch format {
+	case "":
+		return outputFormatText, nil
+	case outputFormatText, outputFormatJSON:
+		return format, nil
+	}
+	return "", fmt.Errorf("output_format must be one of %q or %q", outputFormatText, outputFormatJSON)
+}
+
+// parseReleaseNoteEntries splits release notes text, as extracted for an
+// upgrade, into one entry per typed note under each date heading.
+func parseReleaseNoteEntries(notes string) []releaseNoteEntry {
+	entries := []releaseNoteEntry{}
+	headings := releaseDateHeadingRegexp.FindAllStringIndex(notes, -1)
+	// Notes before the first date heading, e.g. cut from the previous day by
+	// the extraction, have no date.
+	blocks := []struct{ date, text string }{{text: notes}}
+	if len(headings) > 0 {
+		blocks[0].text = notes[:headings[0][0]]
+	}
+	for i, loc := range headings {
+		end := len(notes)
+		if i+1 < len(headings) {
+			end = headings[i+1][0]
+		}
+		blocks = append(blocks, struct{ date, text string }{
+			date: formatNoteDate(strings.TrimSpace(notes[loc[0]:loc[1]])),
+			text: notes[loc[1]:end],
+		})
+	}
+
+	for _, b := range blocks {
+		var entry *releaseNoteEntry
+		var lines []string
+		flush := func() {
+			if entry == nil && len(lines) == 0 {
+				return
+			}
+			if entry == nil {
+				entry = &releaseNoteEntry{Date: b.date}
+			}
+			entry.Text = strings.Join(lines, "\n")
+			entry.VersionsMentioned = mentionedVersions(entry.Text)
+			entries = append(entries, *entry)
+			entry, lines = nil, nil
+		}
+		for _, line := range strings.Split(b.text, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			if t, ok := headingNoteType(line); ok {
+				flush()
+				entry = &releaseNoteEntry{Date: b.date, Type: t}
+				continue
+			}
+			lines = append(lines, line)
+		}
+		flush()
+	}
+	return entries
+}
+
+// formatNoteDate returns a date heading such as "November 14, 2025" as
+// 2025-11-14, or as it is if it cannot be parsed.
+func formatNoteDate(heading string) string {
+	date, err := time.Parse("January 2, 2006", heading)
+	if err != nil {
+		return heading
+	}
+	return date.Format(time.DateOnly)
+}
+
+// headingNoteType returns the name of the note type of which line is the
+// heading.
+func headingNoteType(line string) (string, bool) {
+	for _, t := range noteTypes {
+		if keepsHeading([]noteType{t}, line) {
+			return t.name, true
+		}
+	}
+	return "", false
+}
+
+// mentionedVersions returns the GKE versions mentioned in text, in order.
+func mentionedVersions(text string) []string {
+	versions := []string{}
+	for _, v := range gkeVersionRegexp.FindAllString(text, -1) {
+		if !slices.Contains(versions, v) {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+func formatReleaseNoteEntriesJSON(notes string) (string, error) {
+	b, err := json.Marshal(parseReleaseNoteEntries(notes))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
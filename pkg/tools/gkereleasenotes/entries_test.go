@@ -0,0 +1,125 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gkereleasenotes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestParseReleaseNoteEntries(t *testing.T) {
+	notes := `
+      The rest of a note cut by the extraction.
+
+November 14, 2025
+
+      Feature
+      In GKE version 1.35.2-gke.3040000 and later, GKE rejects
+anonymous requests to cluster endpoints by default.
+
+November 07, 2025
+
+      Feature
+      In GKE version 1.34.1-gke.2037001 and later, the
+GKE logging agent is faster.
+      Issue
+      Don't use GKE version 1.34.1-gke.1431000 or 1.34.1-gke.1431000-like
+versions with 1.34.0-gke.1662000 node pools.
+`
+	want := []releaseNoteEntry{
+		{
+			Date:              "",
+			Type:              "",
+			Text:              "The rest of a note cut by the extraction.",
+			VersionsMentioned: []string{},
+		},
+		{
+			Date:              "2025-11-14",
+			Type:              "feature",
+			Text:              "In GKE version 1.35.2-gke.3040000 and later, GKE rejects\nanonymous requests to cluster endpoints by default.",
+			VersionsMentioned: []string{"1.35.2-gke.3040000"},
+		},
+		{
+			Date:              "2025-11-07",
+			Type:              "feature",
+			Text:              "In GKE version 1.34.1-gke.2037001 and later, the\nGKE logging agent is faster.",
+			VersionsMentioned: []string{"1.34.1-gke.2037001"},
+		},
+		{
+			Date:              "2025-11-07",
+			Type:              "issue",
+			Text:              "Don't use GKE version 1.34.1-gke.1431000 or 1.34.1-gke.1431000-like\nversions with 1.34.0-gke.1662000 node pools.",
+			VersionsMentioned: []string{"1.34.1-gke.1431000", "1.34.0-gke.1662000"},
+		},
+	}
+	if got := parseReleaseNoteEntries(notes); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseReleaseNoteEntries() = %+v, want %+v", got, want)
+	}
+	if got := parseReleaseNoteEntries(""); got == nil || len(got) != 0 {
+		t.Errorf("parseReleaseNoteEntries(\"\") = %#v, want an empty list", got)
+	}
+}
+
+func TestFormatNoteDate(t *testing.T) {
+	for heading, want := range map[string]string{
+		"November 14, 2025": "2025-11-14",
+		"November 07, 2025": "2025-11-07",
+		"Some day":          "Some day",
+	} {
+		if got := formatNoteDate(heading); got != want {
+			t.Errorf("formatNoteDate(%q) = %q, want %q", heading, got, want)
+		}
+	}
+}
+
+func TestGetGkeReleaseNotesJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, fakeReleaseNotesFeed)
+	}))
+	defer server.Close()
+	originalUrl := releaseNotesFeedUrl
+	releaseNotesFeedUrl = server.URL
+	defer func() { releaseNotesFeedUrl = originalUrl }()
+
+	h := &handlers{cache: newReleaseNotesCache("")}
+	args := &getGkeReleaseNotesArgs{SourceVersion: "1.33.5-gke.1", TargetVersion: "1.35.3-gke.1", OutputFormat: outputFormatJSON}
+	result, _, err := h.getGkeReleaseNotes(context.Background(), nil, args)
+	if err != nil {
+		t.Fatalf("getGkeReleaseNotes() returned unexpected error: %v", err)
+	}
+	var entries []releaseNoteEntry
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &entries); err != nil {
+		t.Fatalf("getGkeReleaseNotes() returned invalid JSON: %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Date+" "+e.Type)
+	}
+	if want := []string{"2025-11-14 feature", "2025-11-14 change", "2025-11-10 fix"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("getGkeReleaseNotes() entries = %v, want %v", got, want)
+	}
+
+	args.OutputFormat = "yaml"
+	if _, _, err := h.getGkeReleaseNotes(context.Background(), nil, args); err == nil {
+		t.Errorf("getGkeReleaseNotes() with output_format yaml returned no error")
+	}
+}
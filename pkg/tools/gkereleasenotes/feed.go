@@ -0,0 +1,138 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gkereleasenotes
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// releaseNotesFeedUrl is the Atom feed of the GKE release notes.
+var releaseNotesFeedUrl = "https://cloud.google.com/feeds/kubernetes-engine-release-notes.xml"
+
+// ignoredNoteSections are the sections of the release notes left out: the
+// lists of versions and of security bulletins.
+var ignoredNoteSections = []string{"Version updates", "Security updates"}
+
+var blankLinesRegexp = regexp.MustCompile(`\n\s*\n+`)
+
+type atomFeed struct {
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title     string     `xml:"title"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Links     []atomLink `xml:"link"`
+	Content   string     `xml:"content"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+// releaseNote is the release notes of one day.
+type releaseNote struct {
+	date time.Time
+	// title is the heading of the note, the date as in "November 14, 2025".
+	title string
+	text  string
+}
+
+// parseReleaseNotesFeed parses the entries of the release notes feed, newest
+// first, with their HTML bodies converted to text. Only the notes of types are
+// kept unless it is empty; the entries left without notes are dropped.
+func parseReleaseNotesFeed(data []byte, types []noteType) ([]releaseNote, error) {
+	var feed atomFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse release notes feed: %w", err)
+	}
+	if len(feed.Entries) == 0 {
+		return nil, fmt.Errorf("failed to parse release notes feed: no entries")
+	}
+	var notes []releaseNote
+	for _, e := range feed.Entries {
+		date, err := time.Parse(time.RFC3339, strings.TrimSpace(e.Updated))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the date of release note %q: %w", e.Title, err)
+		}
+		text, err := htmlToText(e.Content, types)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse release note %q: %w", e.Title, err)
+		}
+		if text == "" {
+			continue
+		}
+		title := strings.TrimSpace(e.Title)
+		// The extraction relies on the date headings.
+		if !releaseDateHeadingRegexp.MatchString(title) {
+			title = date.Format("January 2, 2006")
+		}
+		notes = append(notes, releaseNote{date: date, title: title, text: text})
+	}
+	slices.SortStableFunc(notes, func(a, b releaseNote) int {
+		return cmp.Compare(b.date.Unix(), a.date.Unix())
+	})
+	return notes, nil
+}
+
+// htmlToText converts the HTML body of a release note to text, without the
+// ignored sections. If types is not empty, only the sections under the
+// headings of types are kept.
+func htmlToText(body string, types []noteType) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	headings := doc.Find("h2, h3, h4")
+	if len(types) > 0 {
+		if headings.Length() == 0 {
+			return "", nil
+		}
+		headings.First().PrevAll().Remove()
+	}
+	headings.Each(func(_ int, s *goquery.Selection) {
+		heading := strings.TrimSpace(s.Text())
+		if slices.Contains(ignoredNoteSections, heading) || (len(types) > 0 && !keepsHeading(types, heading)) {
+			s.NextUntil("h2, h3, h4").Remove()
+			s.Remove()
+		}
+	})
+	// Keep block elements on their own lines.
+	doc.Find("p, li, h2, h3, h4, div, br, tr").Each(func(_ int, s *goquery.Selection) {
+		s.AppendHtml("\n")
+	})
+	text := blankLinesRegexp.ReplaceAllString(doc.Text(), "\n\n")
+	return strings.TrimSpace(text), nil
+}
+
+// formatReleaseNotes joins the release notes under their date headings, as
+// the extraction expects them.
+func formatReleaseNotes(notes []releaseNote) string {
+	var b bytes.Buffer
+	for _, n := range notes {
+		fmt.Fprintf(&b, "\n%s\n\n%s\n", n.title, n.text)
+	}
+	return b.String()
+}
@@ -0,0 +1,382 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gkereleasenotes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Release notes feed in the format of
+// https://cloud.google.com/feeds/kubernetes-engine-release-notes.xml, cut down.
+// The entries are out of order on purpose.
+const fakeReleaseNotesFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <id>tag:google.com,2016:kubernetes-engine-release-notes</id>
+  <title>Google Kubernetes Engine (GKE) - Release notes</title>
+  <updated>2025-11-14T00:00:00-08:00</updated>
+  <entry>
+    <title>November 10, 2025</title>
+    <id>tag:google.com,2016:kubernetes-engine-release-notes#November_10_2025</id>
+    <updated>2025-11-10T00:00:00-08:00</updated>
+    <link rel="alternate" href="https://cloud.google.com/kubernetes-engine/docs/release-notes#November_10_2025"/>
+    <content type="html"><![CDATA[<h3>Fixed</h3>
+<p>Version 1.33.5-gke.1080000 fixes an issue with node upgrades.</p>
+<h3>Security updates</h3>
+<p>A security bulletin was published.</p>]]></content>
+  </entry>
+  <entry>
+    <title>November 14, 2025</title>
+    <id>tag:google.com,2016:kubernetes-engine-release-notes#November_14_2025</id>
+    <updated>2025-11-14T00:00:00-08:00</updated>
+    <link rel="alternate" href="https://cloud.google.com/kubernetes-engine/docs/release-notes#November_14_2025"/>
+    <content type="html"><![CDATA[<h3>Feature</h3>
+<p>In GKE version 1.35.2-gke.3040000 and later, GKE rejects
+anonymous requests to cluster endpoints by default.</p>
+<h3>Version updates</h3>
+<p>GKE cluster versions have been updated.</p>
+<ul><li>1.34.1-gke.1000000</li><li>1.34.2-gke.2000000</li></ul>
+<h3>Changed</h3>
+<ul>
+<li>The default node image is now <code>cos_containerd</code>.</li>
+</ul>]]></content>
+  </entry>
+</feed>
+`
+
+func TestParseReleaseNotesFeed(t *testing.T) {
+	notes, err := parseReleaseNotesFeed([]byte(fakeReleaseNotesFeed), nil)
+	if err != nil {
+		t.Fatalf("parseReleaseNotesFeed() returned unexpected error: %v", err)
+	}
+	if len(notes) != 2 {
+		t.Fatalf("parseReleaseNotesFeed() returned %d notes, want 2", len(notes))
+	}
+	want := []struct {
+		title string
+		text  string
+	}{
+		{
+			title: "November 14, 2025",
+			text:  "Feature\n\nIn GKE version 1.35.2-gke.3040000 and later, GKE rejects\nanonymous requests to cluster endpoints by default.\n\nChanged\n\nThe default node image is now cos_containerd.",
+		},
+		{
+			title: "November 10, 2025",
+			text:  "Fixed\n\nVersion 1.33.5-gke.1080000 fixes an issue with node upgrades.",
+		},
+	}
+	for i, w := range want {
+		if notes[i].title != w.title {
+			t.Errorf("note %d title = %q, want %q", i, notes[i].title, w.title)
+		}
+		if notes[i].text != w.text {
+			t.Errorf("note %d text = %q, want %q", i, notes[i].text, w.text)
+		}
+	}
+}
+
+func TestParseReleaseNotesFeedErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		feed    string
+		wantErr string
+	}{
+		{
+			name:    "not xml",
+			feed:    "<html><body>Not a feed",
+			wantErr: "failed to parse release notes feed",
+		},
+		{
+			name:    "no entries",
+			feed:    `<feed xmlns="http://www.w3.org/2005/Atom"></feed>`,
+			wantErr: "no entries",
+		},
+		{
+			name:    "invalid date",
+			feed:    `<feed xmlns="http://www.w3.org/2005/Atom"><entry><title>November 14, 2025</title><updated>yesterday</updated></entry></feed>`,
+			wantErr: `failed to parse the date of release note "November 14, 2025"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseReleaseNotesFeed([]byte(tc.feed), nil)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("parseReleaseNotesFeed() error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseReleaseNotesFeedTitleFallback(t *testing.T) {
+	notes, err := parseReleaseNotesFeed([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"><entry><title>GKE release notes</title><updated>2025-11-14T00:00:00Z</updated><content type="html">&lt;p&gt;Notes.&lt;/p&gt;</content></entry></feed>`), nil)
+	if err != nil {
+		t.Fatalf("parseReleaseNotesFeed() returned unexpected error: %v", err)
+	}
+	if got, want := notes[0].title, "November 14, 2025"; got != want {
+		t.Errorf("parseReleaseNotesFeed() title = %q, want the date %q", got, want)
+	}
+}
+
+func TestGetGkeReleaseNotesHTMLFallback(t *testing.T) {
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer feed.Close()
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><div class="releases">November 14, 2025
+Feature
+A feature of the page.</div></body></html>`)
+	}))
+	defer page.Close()
+	originalFeedUrl, originalPageUrl := releaseNotesFeedUrl, releaseNotesPageUrl
+	releaseNotesFeedUrl, releaseNotesPageUrl = feed.URL, page.URL
+	defer func() { releaseNotesFeedUrl, releaseNotesPageUrl = originalFeedUrl, originalPageUrl }()
+
+	args := &getGkeReleaseNotesArgs{SourceVersion: "1.33.5-gke.1", TargetVersion: "1.34.3-gke.1"}
+	h := &handlers{cache: newReleaseNotesCache(""), htmlFallback: true}
+	result, _, err := h.getGkeReleaseNotes(context.Background(), nil, args)
+	if err != nil {
+		t.Fatalf("getGkeReleaseNotes() returned unexpected error: %v", err)
+	}
+	if got := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(got, "A feature of the page.") {
+		t.Errorf("getGkeReleaseNotes() = %q, want the notes of the page", got)
+	}
+
+	h.htmlFallback = false
+	if _, _, err := h.getGkeReleaseNotes(context.Background(), nil, args); err == nil || !strings.Contains(err.Error(), "status code: 404") {
+		t.Errorf("getGkeReleaseNotes() without the HTML fallback error = %v, want the feed error", err)
+	}
+}
+
+func TestGetGkeReleaseNotesCoverage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, fakeReleaseNotesFeed)
+	}))
+	defer server.Close()
+	originalUrl := releaseNotesFeedUrl
+	releaseNotesFeedUrl = server.URL
+	defer func() { releaseNotesFeedUrl = originalUrl }()
+
+	h := &handlers{cache: newReleaseNotesCache("")}
+	testCases := []struct {
+		name        string
+		args        getGkeReleaseNotesArgs
+		wantNotes   bool
+		wantWarning string
+	}{
+		{
+			name:      "covered",
+			args:      getGkeReleaseNotesArgs{SourceVersion: "1.33.5-gke.1080000", TargetVersion: "1.35.2-gke.3040000"},
+			wantNotes: true,
+		},
+		{
+			name:        "source predates the notes",
+			args:        getGkeReleaseNotesArgs{SourceVersion: "1.30.1-gke.1", TargetVersion: "1.35.2-gke.3040000"},
+			wantNotes:   true,
+			wantWarning: "Warning: Release notes older than 1.33.5-gke.1080000 are not available on this page; only showing back to November 10, 2025",
+		},
+		{
+			name:        "both predate the notes",
+			args:        getGkeReleaseNotesArgs{SourceVersion: "1.30.1-gke.1", TargetVersion: "1.31.1-gke.1"},
+			wantWarning: "both 1.30.1-gke.1 and 1.31.1-gke.1 predate it, so no release notes are shown",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, _, err := h.getGkeReleaseNotes(context.Background(), nil, &tc.args)
+			if err != nil {
+				t.Fatalf("getGkeReleaseNotes() returned unexpected error: %v", err)
+			}
+			notes := result.Content[0].(*mcp.TextContent).Text
+			if got := strings.Contains(notes, "November 14, 2025"); got != tc.wantNotes {
+				t.Errorf("getGkeReleaseNotes() = %q, want notes: %v", notes, tc.wantNotes)
+			}
+			if tc.wantWarning == "" {
+				if len(result.Content) != 1 {
+					t.Errorf("getGkeReleaseNotes() returned %d contents, want no warning", len(result.Content))
+				}
+				return
+			}
+			if len(result.Content) != 2 {
+				t.Fatalf("getGkeReleaseNotes() returned %d contents, want the notes and a warning", len(result.Content))
+			}
+			if got := result.Content[1].(*mcp.TextContent).Text; !strings.Contains(got, tc.wantWarning) {
+				t.Errorf("getGkeReleaseNotes() warning = %q, want it to contain %q", got, tc.wantWarning)
+			}
+		})
+	}
+
+	if _, _, err := h.getGkeReleaseNotes(context.Background(), nil, &getGkeReleaseNotesArgs{SourceVersion: "1.33.x", TargetVersion: "1.35.2-gke.3040000"}); err == nil || !strings.Contains(err.Error(), "invalid SourceVersion") {
+		t.Errorf("getGkeReleaseNotes() with a malformed version error = %v, want an invalid SourceVersion error", err)
+	}
+}
+
+func TestGetGkeReleaseNotesMaxBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, fakeReleaseNotesFeed)
+	}))
+	defer server.Close()
+	originalUrl := releaseNotesFeedUrl
+	releaseNotesFeedUrl = server.URL
+	defer func() { releaseNotesFeedUrl = originalUrl }()
+
+	h := &handlers{cache: newReleaseNotesCache("")}
+	args := &getGkeReleaseNotesArgs{SourceVersion: "1.33.5-gke.1080000", TargetVersion: "1.35.2-gke.3040000", MaxBytes: 250}
+	result, _, err := h.getGkeReleaseNotes(context.Background(), nil, args)
+	if err != nil {
+		t.Fatalf("getGkeReleaseNotes() returned unexpected error: %v", err)
+	}
+	if got := result.Content[0].(*mcp.TextContent).Text; len(got) > args.MaxBytes || strings.Contains(got, "November 10, 2025") {
+		t.Errorf("getGkeReleaseNotes() = %q, want at most %d bytes without the oldest day", got, args.MaxBytes)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("getGkeReleaseNotes() returned %d contents, want the notes and a notice", len(result.Content))
+	}
+	if got, want := result.Content[1].(*mcp.TextContent).Text, "Note: The release notes from November 10, 2025 back to November 10, 2025 were left out"; !strings.HasPrefix(got, want) {
+		t.Errorf("getGkeReleaseNotes() notice = %q, want it to start with %q", got, want)
+	}
+
+	args.MaxBytes = -1
+	if _, _, err := h.getGkeReleaseNotes(context.Background(), nil, args); err == nil {
+		t.Errorf("getGkeReleaseNotes() with a negative max_bytes returned no error")
+	}
+}
+
+func TestGetGkeReleaseNotesDateRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, fakeReleaseNotesFeed)
+	}))
+	defer server.Close()
+	originalUrl := releaseNotesFeedUrl
+	releaseNotesFeedUrl = server.URL
+	defer func() { releaseNotesFeedUrl = originalUrl }()
+
+	h := &handlers{cache: newReleaseNotesCache("")}
+	h.cache.now = func() time.Time { return time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC) }
+	testCases := []struct {
+		name        string
+		args        getGkeReleaseNotesArgs
+		want        []string
+		notWant     []string
+		wantWarning string
+	}{
+		{
+			name:    "newest day",
+			args:    getGkeReleaseNotesArgs{StartDate: "2025-11-12"},
+			want:    []string{"November 14, 2025", "GKE rejects"},
+			notWant: []string{"November 10, 2025"},
+		},
+		{
+			name: "both days",
+			args: getGkeReleaseNotesArgs{StartDate: "2025-11-10", EndDate: "2025-11-14"},
+			want: []string{"November 14, 2025", "November 10, 2025"},
+		},
+		{
+			name:        "before the notes",
+			args:        getGkeReleaseNotesArgs{StartDate: "2025-01-01", EndDate: "2025-01-31"},
+			wantWarning: "Warning: No release notes were published from 2025-01-01 to 2025-01-31; the available release notes span 2025-11-10 to 2025-11-14.",
+		},
+		{
+			name:        "after the notes",
+			args:        getGkeReleaseNotesArgs{StartDate: "2025-11-15"},
+			wantWarning: "Warning: No release notes were published from 2025-11-15 to 2025-11-20",
+		},
+		{
+			name:        "starting before the notes",
+			args:        getGkeReleaseNotesArgs{StartDate: "2025-10-01", EndDate: "2025-11-10"},
+			want:        []string{"November 10, 2025"},
+			notWant:     []string{"November 14, 2025"},
+			wantWarning: "Warning: Release notes older than 2025-11-10 are not available on this page",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, _, err := h.getGkeReleaseNotes(context.Background(), nil, &tc.args)
+			if err != nil {
+				t.Fatalf("getGkeReleaseNotes() returned unexpected error: %v", err)
+			}
+			got := result.Content[0].(*mcp.TextContent).Text
+			for _, w := range tc.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("getGkeReleaseNotes() = %q, want it to contain %q", got, w)
+				}
+			}
+			for _, w := range tc.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("getGkeReleaseNotes() = %q, want it not to contain %q", got, w)
+				}
+			}
+			if tc.want == nil && got != "" {
+				t.Errorf("getGkeReleaseNotes() = %q, want no release notes", got)
+			}
+			var warning string
+			if len(result.Content) > 1 {
+				warning = result.Content[1].(*mcp.TextContent).Text
+			}
+			if (tc.wantWarning == "") != (warning == "") || !strings.Contains(warning, tc.wantWarning) {
+				t.Errorf("getGkeReleaseNotes() warning = %q, want %q", warning, tc.wantWarning)
+			}
+		})
+	}
+}
+
+func TestGetGkeReleaseNotesWindowArgs(t *testing.T) {
+	h := &handlers{cache: newReleaseNotesCache("")}
+	testCases := []struct {
+		name    string
+		args    getGkeReleaseNotesArgs
+		wantErr string
+	}{
+		{
+			name:    "versions and dates",
+			args:    getGkeReleaseNotesArgs{SourceVersion: "1.33", TargetVersion: "1.34", StartDate: "2025-11-01"},
+			wantErr: "not both",
+		},
+		{
+			name:    "end date only",
+			args:    getGkeReleaseNotesArgs{EndDate: "2025-11-01"},
+			wantErr: "start_date argument cannot be empty",
+		},
+		{
+			name:    "invalid date",
+			args:    getGkeReleaseNotesArgs{StartDate: "last month"},
+			wantErr: "invalid start_date",
+		},
+		{
+			name:    "nothing",
+			wantErr: "set either SourceVersion and TargetVersion, or start_date",
+		},
+		{
+			name:    "source version only",
+			args:    getGkeReleaseNotesArgs{SourceVersion: "1.33"},
+			wantErr: "invalid TargetVersion",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := h.getGkeReleaseNotes(context.Background(), nil, &tc.args)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("getGkeReleaseNotes() error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
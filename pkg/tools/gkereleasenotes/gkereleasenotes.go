@@ -19,30 +19,69 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"math"
 	"net/http"
-	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	container "cloud.google.com/go/container/apiv1"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// defaultMaxBytes is the default size limit of the returned release notes.
+const defaultMaxBytes = 200 * 1024
+
 var (
+	releaseNotesPageUrl      = "https://cloud.google.com/kubernetes-engine/docs/release-notes"
 	gkeVersionRegexp         = regexp.MustCompile(`\d+\.\d+\.\d+-gke\.\d+`)
+	partialVersionRegexp     = regexp.MustCompile(`^(\d+\.\d+)(?:\.(\d+))?$`)
 	releaseDateHeadingRegexp = regexp.MustCompile(`(^|\n)\s*[A-Za-z]+\s+\d+,\s+\d+\s*(\n|$)`)
 )
 
 type getGkeReleaseNotesArgs struct {
-	SourceVersion string `json:"SourceVersion" jsonschema:"A source GKE version an upgrade happens from. For example, '1.33.5-gke.120000'."`
-	TargetVersion string `json:"TargetVersion" jsonschema:"A target GKE version an upgrade happens from. For example, '1.34.3-gke.240500'."`
+	SourceVersion string   `json:"SourceVersion,omitempty" jsonschema:"A source GKE version an upgrade happens from. For example, '1.33.5-gke.120000'. A minor version such as '1.33', or a patch version such as '1.33.5', stands for its oldest GKE version."`
+	TargetVersion string   `json:"TargetVersion,omitempty" jsonschema:"A target GKE version an upgrade happens from. For example, '1.34.3-gke.240500'. A minor version such as '1.34', or a patch version such as '1.34.3', stands for its newest GKE version."`
+	StartDate     string   `json:"start_date,omitempty" jsonschema:"Instead of SourceVersion and TargetVersion, return the release notes published on or after this day, as YYYY-MM-DD, e.g. to see what changed in GKE in the last month."`
+	EndDate       string   `json:"end_date,omitempty" jsonschema:"With start_date, only return the release notes published on or before this day, as YYYY-MM-DD. Defaults to today."`
+	Channel       string   `json:"channel,omitempty" jsonschema:"Release channel to get the release notes of. One of: rapid, regular, stable, extended, no-channel. Defaults to the channel of the cluster if one is given, else to the release notes of all channels."`
+	ProjectID     string   `json:"project_id,omitempty" jsonschema:"GCP project ID of the cluster to get the release channel of. Use the default if the user doesn't provide it."`
+	Location      string   `json:"location,omitempty" jsonschema:"GKE cluster location of the cluster to get the release channel of. Use the default if the user doesn't provide it."`
+	Name          string   `json:"name,omitempty" jsonschema:"GKE cluster name to get the release channel of, if channel is not set."`
+	Types         []string `json:"types,omitempty" jsonschema:"Only return the release notes of these types, e.g. [breaking, deprecation, issue] for the risks of an upgrade. Any of: feature, change, fix, issue, deprecation, breaking, announcement. Returns all types if empty."`
+	MaxBytes      int      `json:"max_bytes,omitempty" jsonschema:"Largest size of the returned release notes in bytes. The oldest days beyond it are left out. Defaults to 204800."`
+	OutputFormat  string   `json:"output_format,omitempty" jsonschema:"Output format. 'text' (default) is the release notes as they are published. 'json' is an array of the notes with their date as YYYY-MM-DD, type, text and the GKE versions they mention."`
+}
+
+type handlers struct {
+	c        *config.Config
+	cmClient *container.ClusterManagerClient
+	cache    *releaseNotesCache
+	// htmlFallback scrapes the release notes page if the feed cannot be
+	// read.
+	htmlFallback bool
 }
 
-func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
+func Install(ctx context.Context, s *mcp.Server, c *config.Config) error {
+	cmClient, err := container.NewClusterManagerClient(ctx, c.ClientOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to create cluster manager client: %w", err)
+	}
+	c.OnClose(cmClient.Close)
+
+	h := &handlers{
+		c:            c,
+		cmClient:     cmClient,
+		cache:        newReleaseNotesCache(cacheDir(c)),
+		htmlFallback: c.ReleaseNotesHTMLFallback(),
+	}
+
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "get_gke_release_notes",
 		Description: "Get GKE release notes. Prefer to use this tool if GKE release notes are needed.",
@@ -50,68 +89,342 @@ func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
 			ReadOnlyHint:   true,
 			IdempotentHint: true,
 		},
-	}, getGkeReleaseNotes)
+	}, h.getGkeReleaseNotes)
+	installGetGkeSecurityBulletinsTool(s, h)
 
 	return nil
 }
 
-func getGkeReleaseNotes(ctx context.Context, req *mcp.CallToolRequest, args *getGkeReleaseNotesArgs) (*mcp.CallToolResult, any, error) {
-	releaseNotesFilePath := fmt.Sprintf("release-notes-%s.html", time.Now().Format("2006-01-02"))
+// cacheDir returns the directory of the release notes cache under the cache
+// directory of the config, or "" if there is none.
+func cacheDir(c *config.Config) string {
+	if c.CacheDir() == "" {
+		return ""
+	}
+	return filepath.Join(c.CacheDir(), "release-notes")
+}
 
-	var out []byte
+func (h *handlers) getGkeReleaseNotes(ctx context.Context, req *mcp.CallToolRequest, args *getGkeReleaseNotesArgs) (*mcp.CallToolResult, any, error) {
+	byDate := args.StartDate != "" || args.EndDate != ""
+	if byDate && (args.SourceVersion != "" || args.TargetVersion != "") {
+		return nil, nil, fmt.Errorf("set either SourceVersion and TargetVersion, or start_date and end_date, not both")
+	}
+	if !byDate && args.SourceVersion == "" && args.TargetVersion == "" {
+		return nil, nil, fmt.Errorf("set either SourceVersion and TargetVersion, or start_date")
+	}
+	var sourceVersion, targetVersion string
+	var start, end time.Time
 	var err error
-
-	if _, err = os.Stat(releaseNotesFilePath); err == nil {
-		log.Printf("Reading release notes from cached file: %s", releaseNotesFilePath)
-		out, err = os.ReadFile(releaseNotesFilePath)
+	if byDate {
+		if args.StartDate == "" {
+			return nil, nil, fmt.Errorf("start_date argument cannot be empty with end_date")
+		}
+		start, end, err = parseDateRange(args.StartDate, args.EndDate, time.Time{}, h.cache.now())
 		if err != nil {
-			log.Printf("Failed to read cached release notes file: %v", err)
 			return nil, nil, err
 		}
 	} else {
-		log.Printf("Fetching release notes from web")
-		const releaseNotesPageUrl = "https://cloud.google.com/kubernetes-engine/docs/release-notes"
-		resp, err := http.Get(releaseNotesPageUrl)
+		sourceVersion, err = expandGkeVersion("SourceVersion", args.SourceVersion, false)
 		if err != nil {
-			log.Printf("Failed to get release notes: %v", err)
 			return nil, nil, err
 		}
-		defer resp.Body.Close()
-		out, err = io.ReadAll(resp.Body)
+		targetVersion, err = expandGkeVersion("TargetVersion", args.TargetVersion, true)
 		if err != nil {
-			log.Printf("Failed to read release notes response body: %v", err)
 			return nil, nil, err
 		}
-		if err = os.WriteFile(releaseNotesFilePath, out, 0644); err != nil {
-			log.Printf("Failed to write release notes to file: %v", err)
+	}
+	if args.MaxBytes < 0 {
+		return nil, nil, fmt.Errorf("max_bytes must be positive")
+	}
+	if args.MaxBytes == 0 {
+		args.MaxBytes = defaultMaxBytes
+	}
+	types, err := parseNoteTypes(args.Types)
+	if err != nil {
+		return nil, nil, err
+	}
+	format, err := validateOutputFormat(args.OutputFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+	if args.Channel == "" && args.Name != "" {
+		if args.ProjectID == "" {
+			args.ProjectID = h.c.DefaultProjectID()
+		}
+		args.Location = h.c.ResolveLocation(args.Location)
+		channel, err := h.lookupChannel(ctx, args.ProjectID, args.Location, args.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		args.Channel = channel
+	}
+	fullReleaseNotesContentText, err := h.releaseNotesText(ctx, args.Channel, types)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var reducedReleaseNotes string
+	var notices []string
+	if byDate {
+		reducedReleaseNotes, notices = extractReleaseNotesBetweenDates(fullReleaseNotesContentText, start, end)
+	} else {
+		reducedReleaseNotes, notices, err = h.extractReleaseNotesBetweenVersions(fullReleaseNotesContentText, sourceVersion, targetVersion, args)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	reducedReleaseNotes, omitted := truncateReleaseNotes(reducedReleaseNotes, args.MaxBytes)
+	if len(omitted) > 0 {
+		notices = append(notices, fmt.Sprintf("Note: The release notes from %s back to %s were left out to stay under max_bytes (%d). Raise max_bytes or narrow the versions or dates to get them.", omitted[0], omitted[len(omitted)-1], args.MaxBytes))
+	}
+	if format == outputFormatJSON {
+		reducedReleaseNotes, err = formatReleaseNoteEntriesJSON(reducedReleaseNotes)
+		if err != nil {
+			return nil, nil, err
 		}
 	}
 
-	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(out))
+	content := []mcp.Content{
+		&mcp.TextContent{Text: reducedReleaseNotes},
+	}
+	for _, n := range notices {
+		// The notices are apart from the notes to keep the JSON output
+		// valid.
+		content = append(content, &mcp.TextContent{Text: n})
+	}
+	return &mcp.CallToolResult{
+		Content: content,
+	}, nil, nil
+}
+
+// extractReleaseNotesBetweenVersions returns the release notes of an upgrade
+// from sourceVersion to targetVersion, expanded from the arguments, with
+// notices if the release notes don't go back far enough.
+func (h *handlers) extractReleaseNotesBetweenVersions(fullReleaseNotes, sourceVersion, targetVersion string, args *getGkeReleaseNotesArgs) (string, []string, error) {
+	reducedReleaseNotes, err := extractReleaseNotesRelevantForUpgrade(fullReleaseNotes, sourceVersion, targetVersion)
 	if err != nil {
-		log.Printf("Failed to parse release notes html content: %v", err)
+		return "", nil, err
+	}
+	oldestVersion, oldestDate := releaseNotesCoverage(fullReleaseNotes)
+	var notices []string
+	switch {
+	case oldestVersion == "":
+		notices = append(notices, "Warning: The release notes mention no GKE versions; showing all of them.")
+	case isOlderVersion(targetVersion, oldestVersion):
+		// The extraction found no border and would return all the notes.
+		reducedReleaseNotes = ""
+		notices = append(notices, fmt.Sprintf("Warning: Release notes older than %s are not available on this page, which only goes back to %s; both %s and %s predate it, so no release notes are shown.", oldestVersion, oldestDate, args.SourceVersion, args.TargetVersion))
+	case isOlderVersion(sourceVersion, oldestVersion):
+		notices = append(notices, fmt.Sprintf("Warning: Release notes older than %s are not available on this page; only showing back to %s, the release notes between %s and that date are missing.", oldestVersion, oldestDate, args.SourceVersion))
+	}
+	return reducedReleaseNotes, notices, nil
+}
 
-		return nil, nil, err
+// extractReleaseNotesBetweenDates returns the whole days of the release notes
+// published from start to end, with notices if there are none or if the
+// release notes don't go back to start.
+func extractReleaseNotesBetweenDates(fullReleaseNotes string, start, end time.Time) (string, []string) {
+	headings := releaseDateHeadingRegexp.FindAllStringIndex(fullReleaseNotes, -1)
+	var reduced strings.Builder
+	var newest, oldest time.Time
+	for i, loc := range headings {
+		date, err := time.Parse("January 2, 2006", strings.TrimSpace(fullReleaseNotes[loc[0]:loc[1]]))
+		if err != nil {
+			continue
+		}
+		if newest.IsZero() || date.After(newest) {
+			newest = date
+		}
+		if oldest.IsZero() || date.Before(oldest) {
+			oldest = date
+		}
+		if date.Before(start) || date.After(end) {
+			continue
+		}
+		sectionEnd := len(fullReleaseNotes)
+		if i+1 < len(headings) {
+			sectionEnd = headings[i+1][0]
+		}
+		reduced.WriteString(fullReleaseNotes[loc[0]:sectionEnd])
+	}
+	var notices []string
+	switch {
+	case oldest.IsZero():
+		notices = append(notices, "Warning: The release notes have no date headings, no release notes are shown.")
+	case reduced.Len() == 0:
+		notices = append(notices, fmt.Sprintf("Warning: No release notes were published from %s to %s; the available release notes span %s to %s.", start.Format(time.DateOnly), end.Format(time.DateOnly), oldest.Format(time.DateOnly), newest.Format(time.DateOnly)))
+	case start.Before(oldest):
+		notices = append(notices, fmt.Sprintf("Warning: Release notes older than %s are not available on this page; only showing back to that date.", oldest.Format(time.DateOnly)))
+	}
+	return reduced.String(), notices
+}
+
+// releaseNotesCoverage returns the oldest GKE version mentioned in the release
+// notes and the date of the oldest release note, or "" if there are none.
+func releaseNotesCoverage(fullReleaseNotes string) (oldestVersion, oldestDate string) {
+	for _, v := range gkeVersionRegexp.FindAllString(fullReleaseNotes, -1) {
+		if oldestVersion == "" || isOlderVersion(v, oldestVersion) {
+			oldestVersion = v
+		}
+	}
+	if headings := releaseDateHeadingRegexp.FindAllString(fullReleaseNotes, -1); len(headings) > 0 {
+		oldestDate = strings.TrimSpace(headings[len(headings)-1])
+	}
+	return oldestVersion, oldestDate
+}
+
+// isOlderVersion reports whether GKE version a is older than b.
+func isOlderVersion(a, b string) bool {
+	cmp, err := compareVersions(a, b)
+	return err == nil && cmp > 0
+}
+
+// expandGkeVersion returns version, a GKE version such as 1.33.5-gke.120000,
+// a patch version such as 1.33.5 or a minor version such as 1.33, as a GKE
+// version. A patch or minor version is expanded to its oldest GKE version, or
+// to its newest one if highest is set.
+func expandGkeVersion(name, version string, highest bool) (string, error) {
+	if version != "" && gkeVersionRegexp.FindString(version) == version {
+		return version, nil
+	}
+	m := partialVersionRegexp.FindStringSubmatch(version)
+	if m == nil {
+		return "", fmt.Errorf("invalid %s %q, must be a GKE version such as 1.33.5-gke.120000, or a minor version such as 1.33", name, version)
+	}
+	patch, gkePatch := "0", "0"
+	if highest {
+		patch, gkePatch = strconv.Itoa(math.MaxInt32), strconv.Itoa(math.MaxInt32)
+	}
+	if m[2] != "" {
+		patch = m[2]
+	}
+	return fmt.Sprintf("%s.%s-gke.%s", m[1], patch, gkePatch), nil
+}
+
+// releaseNotesText returns the text of all the release notes of channel,
+// newest first, from the feed or, if it cannot be read and htmlFallback is
+// set, from the release notes page. An empty channel selects the release notes
+// of all channels. Only the notes of types are kept unless it is empty.
+func (h *handlers) releaseNotesText(ctx context.Context, channel string, types []noteType) (string, error) {
+	src, err := channelSource(channel)
+	if err != nil {
+		return "", err
+	}
+	name := "release-notes"
+	if channel != "" {
+		name += "-" + strings.ToLower(channel)
+	}
+	feed, err := h.download(ctx, src.feedUrl, name+".xml")
+	if err == nil {
+		var notes []releaseNote
+		notes, err = parseReleaseNotesFeed(feed, types)
+		if err == nil {
+			return formatReleaseNotes(notes), nil
+		}
+	}
+	if !h.htmlFallback {
+		return "", err
+	}
+	slog.Warn("Failed to read release notes feed, scraping the release notes page", "err", err)
+	page, err := h.download(ctx, src.pageUrl, name+".html")
+	if err != nil {
+		return "", err
+	}
+	return scrapeReleaseNotesPage(page, types)
+}
+
+// download returns the content of url, from the cache file name if it has
+// not expired.
+func (h *handlers) download(ctx context.Context, url, name string) ([]byte, error) {
+	if out, ok := h.cache.get(name); ok {
+		return out, nil
+	}
+	slog.Info("Fetching release notes from web")
+	out, err := fetchReleaseNotes(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	h.cache.put(name, out)
+	return out, nil
+}
+
+// scrapeReleaseNotesPage returns the text of the release notes of the HTML
+// release notes page. Only the notes of types are kept unless it is empty.
+func scrapeReleaseNotesPage(page []byte, types []noteType) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page))
+	if err != nil {
+		slog.Warn("Failed to parse release notes html content", "err", err)
+		return "", err
 	}
 
 	var fullReleaseNotesContent strings.Builder
-	doc.Find("[data-text$=\"Version updates\"]").Parent().Parent().Remove()
-	doc.Find("[data-text$=\"Security updates\"]").Parent().Parent().Remove()
+	for _, section := range ignoredNoteSections {
+		doc.Find(fmt.Sprintf("[data-text$=%q]", section)).Parent().Parent().Remove()
+	}
+	if len(types) > 0 {
+		doc.Find("[class^='release-']").Each(func(_ int, s *goquery.Selection) {
+			if !slices.ContainsFunc(types, func(t noteType) bool { return s.HasClass(t.class) }) {
+				s.Remove()
+			}
+		})
+	}
 	doc.Find(".releases").Each(func(i int, s *goquery.Selection) {
 		fullReleaseNotesContent.WriteString(s.Text())
 	})
-	fullReleaseNotesContentText := fullReleaseNotesContent.String()
+	return fullReleaseNotesContent.String(), nil
+}
 
-	reducedReleaseNotes, err := extractReleaseNotesRelevantForUpgrade(fullReleaseNotesContentText, args.SourceVersion, args.TargetVersion)
+// fetchReleaseNotes downloads the release notes feed or page at url.
+func fetchReleaseNotes(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		slog.Warn("Failed to get release notes", "err", err)
+		return nil, err
 	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("failed to get release notes with status code: %d", resp.StatusCode)
+		slog.Warn("Failed to get release notes", "err", err)
+		return nil, err
+	}
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		slog.Warn("Failed to read release notes response body", "err", err)
+		return nil, err
+	}
+	return out, nil
+}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			&mcp.TextContent{Text: reducedReleaseNotes},
-		},
-	}, nil, nil
+// truncateReleaseNotes cuts notes to at most maxBytes at a date heading, and
+// returns the date headings of the days left out. The first day is cut at a
+// line if it is larger than maxBytes on its own.
+func truncateReleaseNotes(notes string, maxBytes int) (string, []string) {
+	if len(notes) <= maxBytes {
+		return notes, nil
+	}
+	headings := releaseDateHeadingRegexp.FindAllStringIndex(notes, -1)
+	end := 0
+	for _, loc := range headings {
+		if loc[0] > maxBytes {
+			break
+		}
+		end = loc[0]
+	}
+	if end == 0 {
+		// Not even the first day fits.
+		end = strings.LastIndex(notes[:maxBytes], "\n") + 1
+	}
+	var omitted []string
+	for _, loc := range headings {
+		if loc[0] >= end {
+			omitted = append(omitted, strings.TrimSpace(notes[loc[0]:loc[1]]))
+		}
+	}
+	return notes[:end], omitted
 }
 
 func extractReleaseNotesRelevantForUpgrade(fullReleaseNotes string, sourceVersion string, targetVersion string) (string, error) {
@@ -128,7 +441,7 @@ func extractReleaseNotesRelevantForUpgrade(fullReleaseNotes string, sourceVersio
 			if err != nil {
 				continue // Skip invalid versions
 			}
-			fmt.Printf("cmp%d + %v vs %v\n", cmp, version, targetVersion)
+			slog.Debug("Comparing release note version with target version", "version", version, "target", targetVersion, "cmp", cmp)
 			// cmp >= 0 means targetVersion >= version
 			if cmp == 0 {
 				leftBorderVersionLocation = loc
@@ -152,6 +465,7 @@ func extractReleaseNotesRelevantForUpgrade(fullReleaseNotes string, sourceVersio
 			if err != nil {
 				continue // Skip invalid versions
 			}
+			slog.Debug("Comparing release note version with source version", "version", version, "source", sourceVersion, "cmp", cmp)
 			if cmp == 0 {
 				rightBorderVersionLocation = loc
 				break
@@ -217,12 +531,12 @@ func extractReleaseNotesRelevantForUpgrade(fullReleaseNotes string, sourceVersio
 func compareVersions(a, b string) (int, error) {
 	a_major, a_minor, a_patch, a_gke, err := parseGkeVersion(a)
 	if err != nil {
-		log.Printf("Failed to parse version A '%s': %v", a, err)
+		slog.Warn("Failed to parse version A", "version", a, "err", err)
 		return 0, err
 	}
 	b_major, b_minor, b_patch, b_gke, err := parseGkeVersion(b)
 	if err != nil {
-		log.Printf("Failed to parse version B '%s': %v", b, err)
+		slog.Warn("Failed to parse version B", "version", b, "err", err)
 		return 0, err
 	}
 
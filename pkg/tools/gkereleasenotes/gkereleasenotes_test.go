@@ -15,6 +15,12 @@
 package gkereleasenotes
 
 import (
+	"bytes"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -205,3 +211,230 @@ August 28, 2025
 		})
 	}
 }
+
+func TestExpandGkeVersion(t *testing.T) {
+	testCases := []struct {
+		version string
+		highest bool
+		want    string
+	}{
+		{"1.33.5-gke.120000", false, "1.33.5-gke.120000"},
+		{"1.33.5-gke.120000", true, "1.33.5-gke.120000"},
+		{"1.33", false, "1.33.0-gke.0"},
+		{"1.33", true, "1.33.2147483647-gke.2147483647"},
+		{"1.33.5", false, "1.33.5-gke.0"},
+		{"1.33.5", true, "1.33.5-gke.2147483647"},
+	}
+	for _, tc := range testCases {
+		got, err := expandGkeVersion("SourceVersion", tc.version, tc.highest)
+		if err != nil || got != tc.want {
+			t.Errorf("expandGkeVersion(%q, %v) = %q, %v, want %q", tc.version, tc.highest, got, err, tc.want)
+		}
+	}
+	for _, version := range []string{"", "1", "v1.33", "1.33.x", "v1.33.5-gke.120000", "1.33.5-gke.120000 and later"} {
+		if _, err := expandGkeVersion("SourceVersion", version, false); err == nil || !strings.Contains(err.Error(), "invalid SourceVersion") {
+			t.Errorf("expandGkeVersion(%q) error = %v, want an invalid SourceVersion error", version, err)
+		}
+	}
+}
+
+func TestReleaseNotesCoverage(t *testing.T) {
+	notes := `
+November 14, 2025
+
+      Feature
+      In GKE version 1.35.2-gke.3040000 and later, GKE rejects anonymous requests.
+
+October 21, 2025
+
+      Feature
+      For GKE Standard, use GKE version 1.34.0-gke.1662000 or later.
+`
+	version, date := releaseNotesCoverage(notes)
+	if version != "1.34.0-gke.1662000" || date != "October 21, 2025" {
+		t.Errorf("releaseNotesCoverage() = %q, %q, want 1.34.0-gke.1662000, October 21, 2025", version, date)
+	}
+	if version, _ := releaseNotesCoverage("No versions."); version != "" {
+		t.Errorf("releaseNotesCoverage() without versions = %q, want none", version)
+	}
+}
+
+func TestExtractReleaseNotesWritesNothingToStdout(t *testing.T) {
+	notes := `
+November 14, 2025
+
+      Feature
+      In GKE version 1.35.2-gke.3040000 and later, GKE rejects anonymous requests.
+
+October 21, 2025
+
+      Feature
+      For GKE Standard, use GKE version 1.34.0-gke.1662000 or later.
+`
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() returned unexpected error: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	logs := new(bytes.Buffer)
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer func() {
+		slog.SetDefault(defaultLogger)
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+	}()
+
+	if _, err := extractReleaseNotesRelevantForUpgrade(notes, "1.34.0-gke.1", "1.35.2-gke.3040000"); err != nil {
+		t.Errorf("extractReleaseNotesRelevantForUpgrade() returned unexpected error: %v", err)
+	}
+	os.Stdout = stdout
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to read stdout: %v", err)
+	}
+	if len(out) != 0 {
+		t.Errorf("extractReleaseNotesRelevantForUpgrade() wrote %q to stdout, want nothing as it corrupts the stdio transport", out)
+	}
+	if !strings.Contains(logs.String(), `msg="Comparing release note version with target version" version=1.35.2-gke.3040000`) {
+		t.Errorf("extractReleaseNotesRelevantForUpgrade() logged %q, want the comparisons at debug level", logs.String())
+	}
+}
+
+func TestTruncateReleaseNotes(t *testing.T) {
+	notes := "\nNovember 14, 2025\n\nFirst note.\n\nNovember 10, 2025\n\nSecond note.\n\nNovember 07, 2025\n\nThird note.\n"
+	testCases := []struct {
+		name        string
+		maxBytes    int
+		want        string
+		wantOmitted []string
+	}{
+		{
+			name:     "fits",
+			maxBytes: len(notes),
+			want:     notes,
+		},
+		{
+			name:        "cut at a date heading",
+			maxBytes:    len(notes) - 1,
+			want:        "\nNovember 14, 2025\n\nFirst note.\n\nNovember 10, 2025\n\nSecond note.",
+			wantOmitted: []string{"November 07, 2025"},
+		},
+		{
+			name:        "first day too large",
+			maxBytes:    25,
+			want:        "\nNovember 14, 2025\n\n",
+			wantOmitted: []string{"November 10, 2025", "November 07, 2025"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, omitted := truncateReleaseNotes(notes, tc.maxBytes)
+			if got != tc.want || !slices.Equal(omitted, tc.wantOmitted) {
+				t.Errorf("truncateReleaseNotes(%d) = %q, %v, want %q, %v", tc.maxBytes, got, omitted, tc.want, tc.wantOmitted)
+			}
+			if len(got) > tc.maxBytes {
+				t.Errorf("truncateReleaseNotes(%d) returned %d bytes", tc.maxBytes, len(got))
+			}
+		})
+	}
+}
+
+func TestExtractReleaseNotesMixedPrecision(t *testing.T) {
+	notes := `
+November 14, 2025
+
+      Feature
+      Note for 1.35.2-gke.3040000.
+
+November 10, 2025
+
+      Feature
+      Note for 1.34.2-gke.1000000.
+
+November 07, 2025
+
+      Feature
+      Note for 1.34.1-gke.2037001.
+
+October 28, 2025
+
+      Feature
+      Note for 1.33.5-gke.1080000.
+
+October 21, 2025
+
+      Feature
+      Note for 1.32.4-gke.1029000.
+
+October 14, 2025
+
+      Feature
+      Note for 1.31.9-gke.1000000.
+`
+	testCases := []struct {
+		name          string
+		sourceVersion string
+		targetVersion string
+		want          []string
+		notWant       []string
+	}{
+		{
+			name:          "full source, minor target",
+			sourceVersion: "1.33.5-gke.1080000",
+			targetVersion: "1.34",
+			want:          []string{"1.34.2-gke.1000000", "1.34.1-gke.2037001", "1.33.5-gke.1080000"},
+			notWant:       []string{"1.32.4-gke.1029000", "1.31.9-gke.1000000"},
+		},
+		{
+			name:          "minor source, full target",
+			sourceVersion: "1.33",
+			targetVersion: "1.34.1-gke.2037001",
+			want:          []string{"1.34.1-gke.2037001", "1.33.5-gke.1080000"},
+			notWant:       []string{"1.35.2-gke.3040000", "1.31.9-gke.1000000"},
+		},
+		{
+			name:          "minor source and target",
+			sourceVersion: "1.33",
+			targetVersion: "1.34",
+			want:          []string{"1.34.2-gke.1000000", "1.33.5-gke.1080000"},
+			notWant:       []string{"1.31.9-gke.1000000"},
+		},
+		{
+			name:          "patch target",
+			sourceVersion: "1.33.5-gke.1080000",
+			targetVersion: "1.34.1",
+			want:          []string{"1.34.1-gke.2037001", "1.33.5-gke.1080000"},
+			notWant:       []string{"1.35.2-gke.3040000", "1.32.4-gke.1029000"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source, err := expandGkeVersion("SourceVersion", tc.sourceVersion, false)
+			if err != nil {
+				t.Fatalf("expandGkeVersion() returned unexpected error: %v", err)
+			}
+			target, err := expandGkeVersion("TargetVersion", tc.targetVersion, true)
+			if err != nil {
+				t.Fatalf("expandGkeVersion() returned unexpected error: %v", err)
+			}
+			got, err := extractReleaseNotesRelevantForUpgrade(notes, source, target)
+			if err != nil {
+				t.Fatalf("extractReleaseNotesRelevantForUpgrade() returned unexpected error: %v", err)
+			}
+			for _, w := range tc.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("extractReleaseNotesRelevantForUpgrade() = %q, want it to contain %q", got, w)
+				}
+			}
+			for _, w := range tc.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("extractReleaseNotesRelevantForUpgrade() = %q, want it not to contain %q", got, w)
+				}
+			}
+		})
+	}
+}
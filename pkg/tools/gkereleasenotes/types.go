@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gkereleasenotes
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// noteType is a type of release note, e.g. a feature or a fixed issue.
+type noteType struct {
+	// name is the value of the types argument.
+	name string
+	// headings are the headings of the notes of the type in the feed.
+	headings []string
+	// class is the class of the notes of the type on the release notes page.
+	class string
+}
+
+// noteTypes are the types of release notes, in the order of the types
+// argument description.
+var noteTypes = []noteType{
+	{name: "feature", headings: []string{"Feature"}, class: "release-feature"},
+	{name: "change", headings: []string{"Changed", "Change"}, class: "release-changed"},
+	{name: "fix", headings: []string{"Fixed", "Fix"}, class: "release-fixed"},
+	{name: "issue", headings: []string{"Issue"}, class: "release-issue"},
+	{name: "deprecation", headings: []string{"Deprecated", "Deprecation"}, class: "release-deprecated"},
+	{name: "breaking", headings: []string{"Breaking"}, class: "release-breaking"},
+	{name: "announcement", headings: []string{"Announcement"}, class: "release-announcement"},
+}
+
+// parseNoteTypes returns the note types named by the types argument, or nil
+// to keep all the notes.
+func parseNoteTypes(names []string) ([]noteType, error) {
+	var types []noteType
+	for _, name := range names {
+		i := slices.IndexFunc(noteTypes, func(t noteType) bool { return t.name == strings.ToLower(strings.TrimSpace(name)) })
+		if i < 0 {
+			var valid []string
+			for _, t := range noteTypes {
+				valid = append(valid, t.name)
+			}
+			return nil, fmt.Errorf("unknown release note type %q, must be one of: %s", name, strings.Join(valid, ", "))
+		}
+		if !slices.ContainsFunc(types, func(t noteType) bool { return t.name == noteTypes[i].name }) {
+			types = append(types, noteTypes[i])
+		}
+	}
+	return types, nil
+}
+
+// keepsHeading reports whether the section under heading is of one of types.
+func keepsHeading(types []noteType, heading string) bool {
+	return slices.ContainsFunc(types, func(t noteType) bool {
+		return slices.ContainsFunc(t.headings, func(h string) bool { return strings.EqualFold(h, heading) })
+	})
+}
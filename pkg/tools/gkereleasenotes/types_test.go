@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gkereleasenotes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Release notes feed with a note of each type.
+const fakeTypedReleaseNotesFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <title>November 14, 2025</title>
+    <updated>2025-11-14T00:00:00Z</updated>
+    <content type="html"><![CDATA[<h3>Feature</h3>
+<p>Feature note for 1.34.2-gke.1000000.</p>
+<h3>Changed</h3>
+<p>Change note.</p>
+<h3>Fixed</h3>
+<p>Fix note.</p>
+<h3>Issue</h3>
+<p>Issue note.</p>
+<h3>Deprecated</h3>
+<p>Deprecation note.</p>
+<h3>Breaking</h3>
+<p>Breaking note.</p>
+<h3>Announcement</h3>
+<p>Announcement note.</p>
+<h3>Version updates</h3>
+<p>Version updates note.</p>]]></content>
+  </entry>
+  <entry>
+    <title>November 10, 2025</title>
+    <updated>2025-11-10T00:00:00Z</updated>
+    <content type="html"><![CDATA[<h3>Feature</h3>
+<p>Older feature note.</p>]]></content>
+  </entry>
+</feed>
+`
+
+// Release notes page with a note of each type, in the structure of
+// https://cloud.google.com/kubernetes-engine/docs/release-notes.
+const fakeTypedReleaseNotesPage = `<html><body><div class="releases">
+<h2>November 14, 2025</h2>
+<div class="release-feature"><strong>Feature</strong><p>Feature note.</p></div>
+<div class="release-changed"><strong>Changed</strong><p>Change note.</p></div>
+<div class="release-fixed"><strong>Fixed</strong><p>Fix note.</p></div>
+<div class="release-issue"><strong>Issue</strong><p>Issue note.</p></div>
+<div class="release-deprecated"><strong>Deprecated</strong><p>Deprecation note.</p></div>
+<div class="release-breaking"><strong>Breaking</strong><p>Breaking note.</p></div>
+<div class="release-announcement"><strong>Announcement</strong><p>Announcement note.</p></div>
+</div></body></html>`
+
+// allNoteTexts are the notes of each type of the fixtures.
+var allNoteTexts = []string{"Feature note", "Change note.", "Fix note.", "Issue note.", "Deprecation note.", "Breaking note.", "Announcement note."}
+
+func TestParseNoteTypes(t *testing.T) {
+	types, err := parseNoteTypes([]string{"Breaking", "issue", "breaking"})
+	if err != nil {
+		t.Fatalf("parseNoteTypes() returned unexpected error: %v", err)
+	}
+	if len(types) != 2 || types[0].name != "breaking" || types[1].name != "issue" {
+		t.Errorf("parseNoteTypes() = %v, want breaking and issue", types)
+	}
+	if types, err := parseNoteTypes(nil); err != nil || types != nil {
+		t.Errorf("parseNoteTypes(nil) = %v, %v, want all types", types, err)
+	}
+	if _, err := parseNoteTypes([]string{"bugfix"}); err == nil || !strings.Contains(err.Error(), "feature, change, fix, issue, deprecation, breaking, announcement") {
+		t.Errorf("parseNoteTypes() error = %v, want the list of types", err)
+	}
+}
+
+func TestReleaseNoteTypes(t *testing.T) {
+	for _, tc := range []struct {
+		source string
+		text   func(t *testing.T, types []noteType) string
+	}{
+		{
+			source: "feed",
+			text: func(t *testing.T, types []noteType) string {
+				notes, err := parseReleaseNotesFeed([]byte(fakeTypedReleaseNotesFeed), types)
+				if err != nil {
+					t.Fatalf("parseReleaseNotesFeed() returned unexpected error: %v", err)
+				}
+				return formatReleaseNotes(notes)
+			},
+		},
+		{
+			source: "page",
+			text: func(t *testing.T, types []noteType) string {
+				text, err := scrapeReleaseNotesPage([]byte(fakeTypedReleaseNotesPage), types)
+				if err != nil {
+					t.Fatalf("scrapeReleaseNotesPage() returned unexpected error: %v", err)
+				}
+				return text
+			},
+		},
+	} {
+		for _, nt := range noteTypes {
+			t.Run(tc.source+"/"+nt.name, func(t *testing.T) {
+				got := tc.text(t, []noteType{nt})
+				for i, want := range allNoteTexts {
+					if keep := noteTypes[i].name == nt.name; strings.Contains(got, want) != keep {
+						t.Errorf("release notes of type %s = %q, contain %q: %v, want %v", nt.name, got, want, !keep, keep)
+					}
+				}
+				if strings.Contains(got, "Version updates") {
+					t.Errorf("release notes of type %s = %q, want no version updates", nt.name, got)
+				}
+			})
+		}
+		t.Run(tc.source+"/all", func(t *testing.T) {
+			got := tc.text(t, nil)
+			for _, want := range allNoteTexts {
+				if !strings.Contains(got, want) {
+					t.Errorf("release notes of all types = %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReleaseNoteTypesDropEmptyEntries(t *testing.T) {
+	notes, err := parseReleaseNotesFeed([]byte(fakeTypedReleaseNotesFeed), []noteType{noteTypes[5]})
+	if err != nil {
+		t.Fatalf("parseReleaseNotesFeed() returned unexpected error: %v", err)
+	}
+	if len(notes) != 1 || notes[0].title != "November 14, 2025" {
+		t.Errorf("parseReleaseNotesFeed() = %v, want only the entry with a breaking note", notes)
+	}
+}
+
+func TestGetGkeReleaseNotesTypes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, fakeTypedReleaseNotesFeed)
+	}))
+	defer server.Close()
+	originalUrl := releaseNotesFeedUrl
+	releaseNotesFeedUrl = server.URL
+	defer func() { releaseNotesFeedUrl = originalUrl }()
+
+	h := &handlers{cache: newReleaseNotesCache("")}
+	args := &getGkeReleaseNotesArgs{SourceVersion: "1.34.1-gke.1", TargetVersion: "1.34.3-gke.1", Types: []string{"breaking", "deprecation", "issue"}}
+	result, _, err := h.getGkeReleaseNotes(context.Background(), nil, args)
+	if err != nil {
+		t.Fatalf("getGkeReleaseNotes() returned unexpected error: %v", err)
+	}
+	got := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"Breaking note.", "Deprecation note.", "Issue note."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("getGkeReleaseNotes() = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "Feature") {
+		t.Errorf("getGkeReleaseNotes() = %q, want no features", got)
+	}
+
+	args.Types = []string{"bugfix"}
+	if _, _, err := h.getGkeReleaseNotes(context.Background(), nil, args); err == nil {
+		t.Errorf("getGkeReleaseNotes() with an unknown type returned no error")
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8schangelog
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+)
+
+// processingFormat is the version of the processing of the cached changelogs.
+// It is bumped when keepOnlyChanges changes, so that the changelogs processed
+// before are not served from the cache.
+const processingFormat = 2
+
+// cacheEntry is a processed changelog with the validators of the download it
+// was processed from.
+type cacheEntry struct {
+	Format       int    `json:"format"`
+	Content      string `json:"content"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// changelogCache holds the processed changelogs by key, e.g. the minor version, in memory
+// and, if dir is set, on disk to survive restarts.
+type changelogCache struct {
+	dir string
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// newChangelogCache returns a cache stored in dir, or only in memory if dir
+// is empty.
+func newChangelogCache(dir string) *changelogCache {
+	return &changelogCache{
+		dir:     dir,
+		entries: map[string]*cacheEntry{},
+	}
+}
+
+// cacheDir returns the directory of the on-disk cache under the cache
+// directory of the config, or "" if there is none.
+func cacheDir(c *config.Config) string {
+	if c.CacheDir() == "" {
+		return ""
+	}
+	return filepath.Join(c.CacheDir(), "k8s-changelog")
+}
+
+func (c *changelogCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		return e, true
+	}
+	if c.dir == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("Failed to read cached changelog", "err", err)
+		}
+		return nil, false
+	}
+	e := &cacheEntry{}
+	if err := json.Unmarshal(data, e); err != nil {
+		slog.Warn("Failed to parse cached changelog", "path", c.path(key), "err", err)
+		return nil, false
+	}
+	if e.Format != processingFormat {
+		return nil, false
+	}
+	c.entries[key] = e
+	return e, true
+}
+
+func (c *changelogCache) put(key string, e *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = e
+	if c.dir == "" {
+		return
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		slog.Warn("Failed to encode changelog for the cache", "err", err)
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		slog.Warn("Failed to create changelog cache directory", "err", err)
+		return
+	}
+	if err := os.WriteFile(c.path(key), data, 0o644); err != nil {
+		slog.Warn("Failed to write cached changelog", "err", err)
+	}
+}
+
+func (c *changelogCache) path(key string) string {
+	return filepath.Join(c.dir, "CHANGELOG-"+key+".json")
+}
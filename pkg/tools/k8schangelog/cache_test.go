@@ -0,0 +1,39 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8schangelog
+
+import (
+	"os"
+	"testing"
+)
+
+func TestChangelogCacheFormat(t *testing.T) {
+	dir := t.TempDir()
+	newChangelogCache(dir).put("1.31", &cacheEntry{Format: processingFormat, Content: "changes", ETag: `"v1"`})
+
+	e, ok := newChangelogCache(dir).get("1.31")
+	if !ok || e.Content != "changes" || e.ETag != `"v1"` {
+		t.Errorf("get() = %+v, %v, want the entry put before", e, ok)
+	}
+
+	// Changelogs processed by an older version are not served.
+	c := newChangelogCache(dir)
+	if err := os.WriteFile(c.path("1.30"), []byte(`{"content":"old changes","etag":"\"v0\""}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := c.get("1.30"); ok {
+		t.Errorf("get() = %+v, want no entry of an older processing format", e)
+	}
+}
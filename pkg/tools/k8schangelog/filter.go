@@ -0,0 +1,177 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8schangelog
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// changeKinds maps the kinds of the kinds filter to their "### <Kind>"
+// headings in the changelog.
+var changeKinds = map[string]string{
+	"deprecation":   "Deprecation",
+	"api-change":    "API Change",
+	"feature":       "Feature",
+	"bug":           "Bug or Regression",
+	"failing-test":  "Failing Test",
+	"cleanup":       "Other (Cleanup or Flake)",
+	"documentation": "Documentation",
+}
+
+// changeSIGsRegexp matches the SIGs tag ending a change, e.g.
+// "[SIG Network and Windows]".
+var changeSIGsRegexp = regexp.MustCompile(`\[SIG ([^\]]+)\]\s*$`)
+
+// changeFilter keeps the changes of some kinds or SIGs. An empty filter keeps
+// all changes.
+type changeFilter struct {
+	// headings are the "### <Kind>" headings to keep, or nil for all kinds.
+	headings []string
+	// sigs are the lowercase SIGs to keep, or nil for all SIGs.
+	sigs []string
+}
+
+// newChangeFilter validates the kinds and sigs filter arguments.
+func newChangeFilter(kinds, sigs []string) (*changeFilter, error) {
+	f := &changeFilter{}
+	for _, k := range kinds {
+		heading, ok := changeKinds[strings.ToLower(strings.TrimSpace(k))]
+		if !ok {
+			return nil, fmt.Errorf("unknown change kind %q, must be one of: deprecation, api-change, feature, bug, failing-test, cleanup, documentation", k)
+		}
+		f.headings = append(f.headings, heading)
+	}
+	for _, s := range sigs {
+		s = strings.ToLower(strings.TrimSpace(s))
+		s = strings.TrimSpace(strings.TrimPrefix(s, "sig "))
+		if s == "" {
+			return nil, fmt.Errorf("sigs cannot contain an empty SIG")
+		}
+		f.sigs = append(f.sigs, s)
+	}
+	return f, nil
+}
+
+func (f *changeFilter) empty() bool {
+	return len(f.headings) == 0 && len(f.sigs) == 0
+}
+
+func (f *changeFilter) keepKind(heading string) bool {
+	return len(f.headings) == 0 || slices.Contains(f.headings, heading)
+}
+
+func (f *changeFilter) keepChange(change []string) bool {
+	if len(f.sigs) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(changeSIGs(change[len(change)-1]), func(sig string) bool {
+		return slices.Contains(f.sigs, strings.ToLower(sig))
+	})
+}
+
+// changeSIGs returns the SIGs tagged on the last line of a change, e.g.
+// Network and Windows of "... [SIG Network and Windows]".
+func changeSIGs(line string) []string {
+	m := changeSIGsRegexp.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	var sigs []string
+	for _, part := range strings.Split(m[1], ", ") {
+		for _, sig := range strings.Split(part, " and ") {
+			if sig = strings.TrimSpace(sig); sig != "" {
+				sigs = append(sigs, sig)
+			}
+		}
+	}
+	return sigs
+}
+
+// filterChanges keeps the changes of a processed changelog matching f. A
+// change is a "- " bullet line with its indented continuation lines, under a
+// "### <Kind>" heading. The version headings, the urgent upgrade notes and the
+// other "#" and "##" sections are kept, the kind headings only if some of
+// their changes are.
+func filterChanges(changes string, f *changeFilter) string {
+	if f.empty() {
+		return changes
+	}
+	var result strings.Builder
+	var (
+		inUrgent    bool
+		inKind      bool
+		keepKind    bool
+		heading     string
+		headingDone bool
+		change      []string
+	)
+	flushChange := func() {
+		if len(change) == 0 {
+			return
+		}
+		if keepKind && f.keepChange(change) {
+			if !headingDone {
+				result.WriteString(heading + "\n\n")
+				headingDone = true
+			}
+			for _, l := range change {
+				result.WriteString(l + "\n")
+			}
+		}
+		change = nil
+	}
+	endKind := func() {
+		flushChange()
+		if headingDone {
+			result.WriteString("\n")
+		}
+		inKind, headingDone = false, false
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(changes, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "# "), strings.HasPrefix(line, "## "):
+			endKind()
+			inUrgent = strings.HasPrefix(line, urgentNotesHeading)
+			result.WriteString(line + "\n")
+		case inUrgent:
+			result.WriteString(line + "\n")
+		case strings.HasPrefix(line, "### "):
+			endKind()
+			inKind = true
+			heading = line
+			keepKind = f.keepKind(strings.TrimSpace(strings.TrimPrefix(line, "### ")))
+		case !inKind:
+			result.WriteString(line + "\n")
+		case strings.HasPrefix(line, "- "):
+			flushChange()
+			change = []string{line}
+		case strings.TrimSpace(line) == "":
+			flushChange()
+		case len(change) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")):
+			change = append(change, line)
+		default:
+			// Text that is not part of a bullet is filtered as a change of
+			// its own.
+			flushChange()
+			change = []string{line}
+		}
+	}
+	endKind()
+	return result.String()
+}
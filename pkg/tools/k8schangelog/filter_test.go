@@ -0,0 +1,275 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8schangelog
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestChangeSIGs(t *testing.T) {
+	testCases := []struct {
+		line string
+		want []string
+	}{
+		{line: "- Fix. ([#1](https://x), [@a](https://y)) [SIG Network]", want: []string{"Network"}},
+		{line: "- Fix. [SIG Network and Windows]", want: []string{"Network", "Windows"}},
+		{line: "- Fix. [SIG API Machinery, Auth, Cloud Provider, Cluster Lifecycle and Testing]", want: []string{"API Machinery", "Auth", "Cloud Provider", "Cluster Lifecycle", "Testing"}},
+		{line: "- Fix without SIGs.", want: nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			if got := changeSIGs(tc.line); !slices.Equal(got, tc.want) {
+				t.Errorf("changeSIGs() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewChangeFilter(t *testing.T) {
+	testCases := []struct {
+		name    string
+		kinds   []string
+		sigs    []string
+		want    *changeFilter
+		wantErr string
+	}{
+		{name: "empty", want: &changeFilter{}},
+		{name: "kinds", kinds: []string{"feature", " API-Change"}, want: &changeFilter{headings: []string{"Feature", "API Change"}}},
+		{name: "sigs", sigs: []string{"Network", "SIG Node"}, want: &changeFilter{sigs: []string{"network", "node"}}},
+		{name: "unknown kind", kinds: []string{"security"}, wantErr: `unknown change kind "security"`},
+		{name: "empty sig", sigs: []string{" "}, wantErr: "empty SIG"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := newChangeFilter(tc.kinds, tc.sigs)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("newChangeFilter() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newChangeFilter() returned unexpected error: %v", err)
+			}
+			if !slices.Equal(got.headings, tc.want.headings) || !slices.Equal(got.sigs, tc.want.sigs) {
+				t.Errorf("newChangeFilter() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFilterChanges(t *testing.T) {
+	const changes = `# v1.2.3
+
+## Changes by Kind
+
+### Feature
+
+- New feature. [SIG Node]
+
+### Bug or Regression
+
+- Network fix
+  over two lines. [SIG Network and Windows]
+- Node fix. [SIG Node]
+
+# v1.2.2
+
+## Changes by Kind
+
+### Bug or Regression
+
+- Storage fix. [SIG Storage]
+`
+	testCases := []struct {
+		name  string
+		kinds []string
+		sigs  []string
+		want  string
+	}{
+		{
+			name: "no filter",
+			want: changes,
+		},
+		{
+			name:  "kind",
+			kinds: []string{"feature"},
+			want: `# v1.2.3
+
+## Changes by Kind
+
+### Feature
+
+- New feature. [SIG Node]
+
+# v1.2.2
+
+## Changes by Kind
+
+`,
+		},
+		{
+			name: "sig",
+			sigs: []string{"windows"},
+			want: `# v1.2.3
+
+## Changes by Kind
+
+### Bug or Regression
+
+- Network fix
+  over two lines. [SIG Network and Windows]
+
+# v1.2.2
+
+## Changes by Kind
+
+`,
+		},
+		{
+			name:  "kind and sig",
+			kinds: []string{"bug"},
+			sigs:  []string{"Node", "Storage"},
+			want: `# v1.2.3
+
+## Changes by Kind
+
+### Bug or Regression
+
+- Node fix. [SIG Node]
+
+# v1.2.2
+
+## Changes by Kind
+
+### Bug or Regression
+
+- Storage fix. [SIG Storage]
+
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := newChangeFilter(tc.kinds, tc.sigs)
+			if err != nil {
+				t.Fatalf("newChangeFilter() returned unexpected error: %v", err)
+			}
+			if got := filterChanges(changes, f); got != tc.want {
+				t.Errorf("filterChanges() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFilterChangesFakeChangelog(t *testing.T) {
+	testCases := []struct {
+		name     string
+		kinds    []string
+		sigs     []string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:  "feature",
+			kinds: []string{"feature"},
+			contains: []string{
+				"# v1.33.6\n",
+				"# v1.33.5\n",
+				"### Feature\n\n- Kubernetes is now built using Go 1.24.9\n  - update setcap and debian-base to bookworm-v1.0.6",
+				"- Kubernetes is now built using Go 1.24.6",
+			},
+			excludes: []string{"### Bug or Regression", "### Other (Cleanup or Flake)", "Kubeadm:"},
+		},
+		{
+			name: "network",
+			sigs: []string{"Network"},
+			contains: []string{
+				"### Bug or Regression\n\n- Fix Windows kube-proxy (winkernel) issue where stale RemoteEndpoints remained\n  when a Deployment was referenced",
+				"- Adjusted the conformance test for the ServiceCIDR API",
+			},
+			excludes: []string{"### Feature", "Kubeadm:", "Portworx", "thermal interrupt"},
+		},
+		{
+			name:     "node cleanup",
+			kinds:    []string{"cleanup"},
+			sigs:     []string{"node"},
+			contains: []string{"### Other (Cleanup or Flake)\n\n- Masked off access to Linux thermal interrupt info"},
+			excludes: []string{"etcd version", "ResourceClaim", "anti-affinity"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := newChangeFilter(tc.kinds, tc.sigs)
+			if err != nil {
+				t.Fatalf("newChangeFilter() returned unexpected error: %v", err)
+			}
+			got := filterChanges(keepOnlyChanges(fakeChangelogContent, sectionRules(false)), f)
+			for _, want := range tc.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("filterChanges() = %q, want it to contain %q", got, want)
+				}
+			}
+			for _, unwanted := range tc.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("filterChanges() = %q, want it not to contain %q", got, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterChangesKeepsUrgentNotes(t *testing.T) {
+	changes := keepOnlyChanges(`# v1.32.0
+
+## Changelog since v1.31.0
+
+## Urgent Upgrade Notes
+
+### (No, really, you MUST read this before you upgrade)
+
+- Urgent change. [SIG Storage]
+
+## Changes by Kind
+
+### Feature
+
+- New feature. [SIG Node]
+- Storage feature. [SIG Storage]
+`, sectionRules(false))
+	f, err := newChangeFilter([]string{"bug"}, []string{"Node"})
+	if err != nil {
+		t.Fatalf("newChangeFilter() returned unexpected error: %v", err)
+	}
+	want := `# v1.32.0
+
+## Urgent Upgrade Notes (action required before upgrading)
+
+### (No, really, you MUST read this before you upgrade)
+
+- Urgent change. [SIG Storage]
+
+
+## Changelog since v1.31.0
+
+## Changes by Kind
+
+`
+	if got := filterChanges(changes, f); got != want {
+		t.Errorf("filterChanges() = %q, want %q", got, want)
+	}
+}
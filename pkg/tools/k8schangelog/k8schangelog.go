@@ -16,27 +16,77 @@ package k8schangelog
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"maps"
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-var (
-	kubernetesMinorVersionRegexp = regexp.MustCompile(`^\d+\.\d+$`)
-	changelogHostUrl             = "https://raw.githubusercontent.com"
-)
+var kubernetesMinorVersionRegexp = regexp.MustCompile(`^\d+\.\d+$`)
+
+// changelogSource is where changelogs are downloaded from.
+type changelogSource struct {
+	host string
+	// path is the format of the path of a changelog, given its minor version.
+	path string
+	// accept is the Accept header of the requests, if any.
+	accept string
+}
+
+// changelogSources are tried in order until one returns the changelog. The
+// GitHub API is a fallback for networks blocking raw.githubusercontent.com.
+var changelogSources = []changelogSource{
+	{
+		host: "https://raw.githubusercontent.com",
+		path: "/kubernetes/kubernetes/refs/heads/master/CHANGELOG/CHANGELOG-%s.md",
+	},
+	{
+		host:   "https://api.github.com",
+		path:   "/repos/kubernetes/kubernetes/contents/CHANGELOG/CHANGELOG-%s.md?ref=master",
+		accept: "application/vnd.github.raw+json",
+	},
+}
+
+// statusError is a changelog download failing with an HTTP status.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("failed to get changelog with status code: %d", e.code)
+}
 
 type getK8sChangelogArgs struct {
-	KubernetesMinorVersion string `json:"KubernetesMinorVersion" jsonschema:"The kubernetes minor version to get changelog for. For example, '1.33'."`
+	KubernetesMinorVersion string   `json:"KubernetesMinorVersion" jsonschema:"The kubernetes minor version to get changelog for. For example, '1.33'."`
+	Refresh                bool     `json:"refresh,omitempty" jsonschema:"Download the changelog again instead of using the cached copy."`
+	Kinds                  []string `json:"kinds,omitempty" jsonschema:"Only keep the changes of these kinds. Each one of: deprecation, api-change, feature, bug, failing-test, cleanup, documentation. Keeps all kinds if empty."`
+	Sigs                   []string `json:"sigs,omitempty" jsonschema:"Only keep the changes tagged with one of these SIGs, e.g. Network or Node. Keeps the changes of all SIGs if empty."`
+	MaxBytes               int      `json:"max_bytes,omitempty" jsonschema:"Largest size of the returned changes in bytes. Whole patch versions beyond it are left out. Defaults to 204800."`
+	PatchVersionsLimit     int      `json:"patch_versions_limit,omitempty" jsonschema:"Largest number of patch versions to return, newest first. Returns all of them if empty."`
+	OffsetVersion          string   `json:"offset_version,omitempty" jsonschema:"Patch version to start from, e.g. v1.33.4, to continue after a response that left out patch versions. Starts from the newest version if empty."`
+	FromPatch              string   `json:"from_patch,omitempty" jsonschema:"Only keep the patch versions after this one, e.g. 1.29.3 for the current version of a cluster. It is not included itself."`
+	ToPatch                string   `json:"to_patch,omitempty" jsonschema:"Only keep the patch versions up to and including this one, e.g. 1.29.8 for the target version of an upgrade."`
+	IncludeDependencies    bool     `json:"include_dependencies,omitempty" jsonschema:"Keep the Dependencies sections listing the dependency bumps, which are left out by default."`
+}
+
+type handlers struct {
+	cache *changelogCache
 }
 
-func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
+func Install(_ context.Context, s *mcp.Server, c *config.Config) error {
+	h := &handlers{
+		cache: newChangelogCache(cacheDir(c)),
+	}
+
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "get_k8s_changelog",
 		Description: "Get changelog file for a specific kubernetes minor version and keep only changes content. Prefer to use this tool if kubernetes minor version changelog is needed.",
@@ -44,57 +94,271 @@ func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
 			ReadOnlyHint:   true,
 			IdempotentHint: true,
 		},
-	}, getK8sChangelog)
+	}, h.getK8sChangelog)
+
+	installGetK8sChangelogRangeTool(s, h)
 
 	return nil
 }
 
-func getK8sChangelog(ctx context.Context, req *mcp.CallToolRequest, args *getK8sChangelogArgs) (*mcp.CallToolResult, any, error) {
+func (h *handlers) getK8sChangelog(ctx context.Context, req *mcp.CallToolRequest, args *getK8sChangelogArgs) (*mcp.CallToolResult, any, error) {
 	version := strings.TrimSpace(args.KubernetesMinorVersion)
 	if !kubernetesMinorVersionRegexp.MatchString(version) {
 		return nil, nil, fmt.Errorf("invalid kubernetes minor version: %s", version)
 	}
-
-	changelogUrl := fmt.Sprintf("%s/kubernetes/kubernetes/refs/heads/master/CHANGELOG/CHANGELOG-%s.md", changelogHostUrl, version)
-	resp, err := http.Get(changelogUrl)
+	filter, err := newChangeFilter(args.Kinds, args.Sigs)
 	if err != nil {
-		log.Printf("Failed to get changelog: %v", err)
 		return nil, nil, err
 	}
-	defer resp.Body.Close()
+	if args.MaxBytes < 0 {
+		return nil, nil, fmt.Errorf("max_bytes must be positive")
+	}
+	if args.MaxBytes == 0 {
+		args.MaxBytes = defaultMaxBytes
+	}
+	if args.PatchVersionsLimit < 0 {
+		return nil, nil, fmt.Errorf("patch_versions_limit must be positive")
+	}
 
-	if resp.StatusCode != http.StatusOK {
-		err := fmt.Errorf("failed to get changelog with status code: %d", resp.StatusCode)
-		log.Printf("Failed to get changelog: %v", err)
+	changes, note, err := h.changelog(ctx, version, args.IncludeDependencies, args.Refresh)
+	if err != nil {
 		return nil, nil, err
 	}
-
-	body, err := io.ReadAll(resp.Body)
+	changes, err = patchRange(changes, args.FromPatch, args.ToPatch)
+	if err != nil {
+		return nil, nil, err
+	}
+	if changes == "" {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatNote(note) + fmt.Sprintf("No patch versions after %s up to %s.\n", args.FromPatch, args.ToPatch)},
+			},
+		}, nil, nil
+	}
+	changes = filterChanges(changes, filter)
+	page, err := paginateChanges(changes, args.OffsetVersion, args.PatchVersionsLimit, args.MaxBytes)
 	if err != nil {
-		log.Printf("Failed to read changelog response body: %v", err)
 		return nil, nil, err
 	}
-	changelogFileContent := string(body)
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: keepOnlyChanges(changelogFileContent)},
+			&mcp.TextContent{Text: formatNote(note) + formatChangelogPage(page, args.MaxBytes)},
 		},
 	}, nil, nil
 }
 
+// changelog returns the changes of a minor version, trying the sources in
+// order. If none of them can be reached, the cached copy or else the embedded
+// snapshot is returned, with a note saying so. includeDependencies keeps the
+// dependency bumps.
+func (h *handlers) changelog(ctx context.Context, version string, includeDependencies, refresh bool) (string, string, error) {
+	rules := sectionRules(includeDependencies)
+	// The changes are cached as processed, so with and without dependencies
+	// are cached apart.
+	key := version
+	if includeDependencies {
+		key += "+dependencies"
+	}
+	cached, ok := h.cache.get(key)
+	var errs []error
+	for _, src := range changelogSources {
+		changes, err := h.download(ctx, src, version, rules, key, cached, ok && !refresh)
+		if err == nil {
+			return changes, "", nil
+		}
+		// The version doesn't exist, the other sources won't have it either.
+		if se := (*statusError)(nil); errors.As(err, &se) && se.code == http.StatusNotFound {
+			return "", "", err
+		}
+		errs = append(errs, err)
+	}
+	err := errors.Join(errs...)
+	if ok {
+		return cached.Content, fmt.Sprintf("The changelog could not be downloaded (%v). These are the changes of the cached copy, which may be out of date.", err), nil
+	}
+	changes, generatedAt, snapshotErr := snapshotChangelog(version, rules)
+	if snapshotErr != nil {
+		slog.Warn("Failed to read changelog snapshot", "err", snapshotErr)
+		return "", "", err
+	}
+	return changes, fmt.Sprintf("The changelog could not be downloaded (%v). These are the changes of the snapshot embedded in gke-mcp, generated on %s, %d days ago; changes released since then are missing.", err, generatedAt.Format(time.DateOnly), int(time.Since(generatedAt).Hours()/24)), nil
+}
+
+// download gets the changes of a minor version from src and caches them under
+// key. A cached copy is revalidated with its ETag and Last-Modified headers, so
+// that an unchanged changelog is not downloaded again.
+func (h *handlers) download(ctx context.Context, src changelogSource, version string, rules []sectionRule, key string, cached *cacheEntry, revalidate bool) (string, error) {
+	changelogUrl := src.host + fmt.Sprintf(src.path, version)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, changelogUrl, nil)
+	if err != nil {
+		return "", err
+	}
+	if src.accept != "" {
+		req.Header.Set("Accept", src.accept)
+	}
+	if revalidate {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		slog.Warn("Failed to get changelog", "err", err)
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && revalidate {
+		return cached.Content, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := &statusError{code: resp.StatusCode}
+		slog.Warn("Failed to get changelog", "host", src.host, "err", err)
+		return "", err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		slog.Warn("Failed to read changelog response body", "err", err)
+		return "", err
+	}
+	e := &cacheEntry{
+		Format:       processingFormat,
+		Content:      keepOnlyChanges(string(body), rules),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	h.cache.put(key, e)
+	return e.Content, nil
+}
+
+var changelogVersionLineRegexp = regexp.MustCompile(`^# v\d\.\d+\.\d+`)
+
+// sectionAction is what keepOnlyChanges does with a section of a changelog.
+type sectionAction int
+
+const (
+	// keepSection keeps the section. A kept heading also ends an ignored
+	// section it is nested in, for changelogs missing a heading level.
+	keepSection sectionAction = iota
+	// ignoreSection drops the section with its nested sections.
+	ignoreSection
+	// urgentSection hoists the section to the top of its version.
+	urgentSection
+)
+
+// sectionRule applies an action to the sections whose heading text starts
+// with prefix, at any heading level.
+type sectionRule struct {
+	prefix string
+	action sectionAction
+}
+
 var (
-	changelogVersionLineRegexp = regexp.MustCompile(`^# v\d\.\d+\.\d+`)
-	ignoredSectionPrefixes     = []string{"## Dependencies", "## Downloads for"}
+	// ignoredSectionRules are the sections of release artifacts. The tables
+	// of their SHAs are usually under "## Downloads for", but not always.
+	ignoredSectionRules = []sectionRule{
+		{prefix: "Downloads for", action: ignoreSection},
+		{prefix: "Source Code", action: ignoreSection},
+		{prefix: "Client Binaries", action: ignoreSection},
+		{prefix: "Server Binaries", action: ignoreSection},
+		{prefix: "Node Binaries", action: ignoreSection},
+		{prefix: "Container Images", action: ignoreSection},
+	}
+	// dependenciesSectionRule drops the dependency bumps, unless they are
+	// asked for.
+	dependenciesSectionRule = sectionRule{prefix: "Dependencies", action: ignoreSection}
+	// urgentSectionRules are the sections of the actions required before
+	// upgrading. The second one is the subsection of the first one, and is
+	// also recognized on its own.
+	urgentSectionRules = []sectionRule{
+		{prefix: "Urgent Upgrade Notes", action: urgentSection},
+		{prefix: "(No, really, you MUST read this before you upgrade)", action: urgentSection},
+	}
+	// keptSectionRules are the sections of changes.
+	keptSectionRules = []sectionRule{
+		{prefix: "Changelog since", action: keepSection},
+		{prefix: "Changes by Kind", action: keepSection},
+		{prefix: "Known Issues", action: keepSection},
+	}
 )
 
-func keepOnlyChanges(changelog string) string {
+// sectionRules returns the rules keepOnlyChanges applies, in order.
+func sectionRules(includeDependencies bool) []sectionRule {
+	var rules []sectionRule
+	rules = append(rules, urgentSectionRules...)
+	rules = append(rules, keptSectionRules...)
+	for _, heading := range slices.Sorted(maps.Values(changeKinds)) {
+		rules = append(rules, sectionRule{prefix: heading, action: keepSection})
+	}
+	rules = append(rules, ignoredSectionRules...)
+	if !includeDependencies {
+		rules = append(rules, dependenciesSectionRule)
+	}
+	return rules
+}
+
+// headingLevel returns the level of a markdown heading, e.g. 2 for "## Foo",
+// and its text, or 0 if line is not a heading.
+func headingLevel(line string) (int, string) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level == len(line) || line[level] != ' ' {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(line[level:])
+}
+
+// matchSectionRule returns the rule of the first prefix matching a heading.
+func matchSectionRule(rules []sectionRule, heading string) (sectionRule, bool) {
+	for _, r := range rules {
+		if strings.HasPrefix(heading, r.prefix) {
+			return r, true
+		}
+	}
+	return sectionRule{}, false
+}
+
+// urgentNotesHeading is the heading the urgent upgrade notes of a version are
+// hoisted under.
+const urgentNotesHeading = "## Urgent Upgrade Notes (action required before upgrading)"
+
+// keepOnlyChanges drops the text before the first version and the sections of
+// a changelog ignored by rules. The urgent upgrade notes of each version are
+// hoisted to the top of the version, under urgentNotesHeading.
+//
+// It walks the headings: an ignored or urgent section lasts until the next
+// heading of the same or a higher level, or a kept heading of any level.
+func keepOnlyChanges(changelog string, rules []sectionRule) string {
 	var result strings.Builder
 	hasMetTheFirstVersionHeading := false // it is set to true only once when the first version heading is met and then never change
-	isInIgnoredSection := false
-	lines := strings.Split(changelog, "\n")
+	// state is the action of the current section, and stateLevel the level
+	// of its heading.
+	state, stateLevel := keepSection, 0
+	// versionHeading, urgent and body are the parts of the current version.
+	var versionHeading string
+	var urgent, body strings.Builder
+	flushVersion := func() {
+		if versionHeading == "" {
+			return
+		}
+		result.WriteString(versionHeading + "\n")
+		if strings.TrimSpace(urgent.String()) != "" {
+			result.WriteString("\n" + urgentNotesHeading + "\n")
+			result.WriteString(urgent.String())
+		}
+		result.WriteString(body.String())
+		urgent.Reset()
+		body.Reset()
+	}
 
-	for _, line := range lines {
+	for _, line := range strings.Split(changelog, "\n") {
 		if !hasMetTheFirstVersionHeading {
 			if changelogVersionLineRegexp.MatchString(line) {
 				hasMetTheFirstVersionHeading = true
@@ -103,28 +367,74 @@ func keepOnlyChanges(changelog string) string {
 			}
 		}
 
-		isIgnoredSectionHeader := false
-		for _, prefix := range ignoredSectionPrefixes {
-			if strings.HasPrefix(line, prefix) {
-				isInIgnoredSection = true
-				isIgnoredSectionHeader = true
-				break
+		if level, heading := headingLevel(line); level > 0 {
+			rule, ok := matchSectionRule(rules, heading)
+			nested := state != keepSection && level > stateLevel && !(ok && rule.action == keepSection)
+			switch {
+			case changelogVersionLineRegexp.MatchString(line):
+				flushVersion()
+				versionHeading = line
+				state, stateLevel = keepSection, level
+				continue
+			case nested:
+				// Nested in the current ignored or urgent section.
+			case ok:
+				state, stateLevel = rule.action, level
+				// The hoisted notes get their own heading.
+				if rule.action == urgentSection && level <= 2 {
+					continue
+				}
+			default:
+				state, stateLevel = keepSection, level
 			}
 		}
-		if isIgnoredSectionHeader {
-			continue
+
+		switch state {
+		case urgentSection:
+			urgent.WriteString(line)
+			urgent.WriteString("\n")
+		case keepSection:
+			body.WriteString(line)
+			body.WriteString("\n")
 		}
+	}
+	flushVersion()
+	return result.String()
+}
+
+// urgentNote is the urgent upgrade notes section of a version.
+type urgentNote struct {
+	versionHeading string
+	section        string
+}
 
-		if isInIgnoredSection {
-			if strings.HasPrefix(line, "# ") || strings.HasPrefix(line, "## ") {
-				isInIgnoredSection = false
+// urgentNotes returns the urgent upgrade notes of a processed changelog.
+func urgentNotes(changes string) []urgentNote {
+	var notes []urgentNote
+	var versionHeading string
+	var section *strings.Builder
+	flush := func() {
+		if section != nil {
+			notes = append(notes, urgentNote{versionHeading: versionHeading, section: section.String()})
+			section = nil
+		}
+	}
+	for _, line := range strings.Split(changes, "\n") {
+		switch {
+		case strings.HasPrefix(line, urgentNotesHeading):
+			flush()
+			section = new(strings.Builder)
+		case strings.HasPrefix(line, "# "), strings.HasPrefix(line, "## "):
+			flush()
+			if changelogVersionLineRegexp.MatchString(line) {
+				versionHeading = line
 			}
 		}
-
-		if !isInIgnoredSection {
-			result.WriteString(line)
-			result.WriteString("\n")
+		if section != nil {
+			section.WriteString(line)
+			section.WriteString("\n")
 		}
 	}
-	return result.String()
+	flush()
+	return notes
 }
@@ -15,12 +15,16 @@
 package k8schangelog
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -46,10 +50,7 @@ func TestGetK8sChangelog(t *testing.T) {
 	}))
 	defer server.Close()
 
-	// Temporarily replace the changelog host URL to point to the test server.
-	originalChangelogHostUrl := changelogHostUrl
-	changelogHostUrl = server.URL
-	defer func() { changelogHostUrl = originalChangelogHostUrl }()
+	useChangelogSources(t, server.URL)
 
 	testCases := []struct {
 		name          string
@@ -82,7 +83,8 @@ func TestGetK8sChangelog(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result, _, err := getK8sChangelog(context.Background(), nil, tc.args)
+			h := &handlers{cache: newChangelogCache("")}
+			result, _, err := h.getK8sChangelog(context.Background(), nil, tc.args)
 
 			if tc.wantErr != "" {
 				if err == nil {
@@ -113,6 +115,162 @@ func TestGetK8sChangelog(t *testing.T) {
 	}
 }
 
+func TestGetK8sChangelogCache(t *testing.T) {
+	var downloads, requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		const etag = `"v1"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		fmt.Fprint(w, fakeChangelogContent)
+	}))
+	defer server.Close()
+
+	useChangelogSources(t, server.URL)
+
+	dir := t.TempDir()
+	h := &handlers{cache: newChangelogCache(dir)}
+	call := func(h *handlers, refresh bool) {
+		t.Helper()
+		result, _, err := h.getK8sChangelog(context.Background(), nil, &getK8sChangelogArgs{KubernetesMinorVersion: "1.31", Refresh: refresh})
+		if err != nil {
+			t.Fatalf("getK8sChangelog() returned unexpected error: %v", err)
+		}
+		if got := result.Content[0].(*mcp.TextContent).Text; got != expectedProcessedContent {
+			t.Errorf("getK8sChangelog() returned '%s', want the processed changelog", got)
+		}
+	}
+
+	for range 3 {
+		call(h, false)
+	}
+	if downloads != 1 || requests != 3 {
+		t.Errorf("repeated calls made %d requests with %d downloads, want 3 requests with 1 download", requests, downloads)
+	}
+
+	// A new cache in the same directory, as after a restart, is revalidated
+	// rather than downloaded again.
+	call(&handlers{cache: newChangelogCache(dir)}, false)
+	if downloads != 1 {
+		t.Errorf("call with the on-disk cache made %d downloads, want 1", downloads)
+	}
+
+	call(h, true)
+	if downloads != 2 {
+		t.Errorf("call with refresh made %d downloads, want 2", downloads)
+	}
+}
+
+func TestGetK8sChangelogFallback(t *testing.T) {
+	var requests []string
+	newServer := func(name string, status int) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, name)
+			w.WriteHeader(status)
+			if status == http.StatusOK {
+				fmt.Fprint(w, fakeChangelogContent)
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	blocked := newServer("blocked", http.StatusForbidden)
+	mirror := newServer("mirror", http.StatusOK)
+	notFound := newServer("not found", http.StatusNotFound)
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	fmt.Fprint(w, fakeChangelogContent)
+	w.Close()
+	snapshot := fstest.MapFS{
+		"snapshot/snapshot.json":        {Data: []byte(`{"generated_at": "2025-10-01T00:00:00Z", "versions": ["1.33"]}`)},
+		"snapshot/CHANGELOG-1.33.md.gz": {Data: compressed.Bytes()},
+	}
+
+	testCases := []struct {
+		name         string
+		hosts        []string
+		version      string
+		wantRequests []string
+		wantPrefix   string
+		wantErr      string
+	}{
+		{
+			name:         "mirror",
+			hosts:        []string{blocked.URL, mirror.URL},
+			version:      "1.33",
+			wantRequests: []string{"blocked", "mirror"},
+			wantPrefix:   "# v1.33.6",
+		},
+		{
+			name:         "snapshot",
+			hosts:        []string{blocked.URL, blocked.URL},
+			version:      "1.33",
+			wantRequests: []string{"blocked", "blocked"},
+			wantPrefix:   "Note: The changelog could not be downloaded (failed to get changelog with status code: 403\nfailed to get changelog with status code: 403). These are the changes of the snapshot embedded in gke-mcp, generated on 2025-10-01,",
+		},
+		{
+			name:         "not in snapshot",
+			hosts:        []string{blocked.URL},
+			version:      "1.34",
+			wantRequests: []string{"blocked"},
+			wantErr:      "failed to get changelog with status code: 403",
+		},
+		{
+			name:         "not found",
+			hosts:        []string{notFound.URL, mirror.URL},
+			version:      "1.33",
+			wantRequests: []string{"not found"},
+			wantErr:      "failed to get changelog with status code: 404",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests = nil
+			useChangelogSources(t, tc.hosts...)
+			changelogSnapshot = snapshot
+
+			h := &handlers{cache: newChangelogCache("")}
+			result, _, err := h.getK8sChangelog(context.Background(), nil, &getK8sChangelogArgs{KubernetesMinorVersion: tc.version})
+			if !slices.Equal(requests, tc.wantRequests) {
+				t.Errorf("getK8sChangelog() requested %v, want %v", requests, tc.wantRequests)
+			}
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("getK8sChangelog() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getK8sChangelog() returned unexpected error: %v", err)
+			}
+			if got := result.Content[0].(*mcp.TextContent).Text; !strings.HasPrefix(got, tc.wantPrefix) {
+				t.Errorf("getK8sChangelog() = %q, want it to start with %q", got, tc.wantPrefix)
+			}
+		})
+	}
+}
+
+// useChangelogSources points the changelog sources at test servers, and
+// empties the snapshot, for the duration of a test.
+func useChangelogSources(t *testing.T, hosts ...string) {
+	t.Helper()
+	originalSources, originalSnapshot := changelogSources, changelogSnapshot
+	t.Cleanup(func() { changelogSources, changelogSnapshot = originalSources, originalSnapshot })
+	changelogSources = nil
+	for _, host := range hosts {
+		changelogSources = append(changelogSources, changelogSource{
+			host: host,
+			path: "/kubernetes/kubernetes/refs/heads/master/CHANGELOG/CHANGELOG-%s.md",
+		})
+	}
+	changelogSnapshot = fstest.MapFS{}
+}
+
 func TestKeepOnlyChanges(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -190,6 +348,72 @@ It should be ignored.
 
 ### Changes of Kind B
 - B change.
+
+`,
+		},
+		{
+			name: "urgent upgrade notes",
+			input: `# v1.32.0
+
+## Downloads for v1.32.0
+
+- binary 1
+
+## Changelog since v1.31.0
+
+## Urgent Upgrade Notes
+
+### (No, really, you MUST read this before you upgrade)
+
+- Urgent change. [SIG Node]
+
+## Changes by Kind
+
+### Feature
+- A change.
+
+# v1.32.0-rc.1
+
+## Changelog since v1.32.0-rc.0
+
+### (No, really, you MUST read this before you upgrade)
+
+- Other urgent change.
+
+## Changes by Kind
+
+### Feature
+- B change.
+`,
+			expected: `# v1.32.0
+
+## Urgent Upgrade Notes (action required before upgrading)
+
+### (No, really, you MUST read this before you upgrade)
+
+- Urgent change. [SIG Node]
+
+## Changelog since v1.31.0
+
+## Changes by Kind
+
+### Feature
+- A change.
+
+# v1.32.0-rc.1
+
+## Urgent Upgrade Notes (action required before upgrading)
+### (No, really, you MUST read this before you upgrade)
+
+- Other urgent change.
+
+## Changelog since v1.32.0-rc.0
+
+## Changes by Kind
+
+### Feature
+- B change.
+
 `,
 		},
 	}
@@ -198,7 +422,7 @@ It should be ignored.
 		t.Run(tc.name, func(t *testing.T) {
 			// Normalize newlines for consistent comparison
 			expected := strings.ReplaceAll(tc.expected, "\n", "")
-			actual := strings.ReplaceAll(keepOnlyChanges(tc.input), "\n", "")
+			actual := strings.ReplaceAll(keepOnlyChanges(tc.input, sectionRules(false)), "\n", "")
 			if actual != expected {
 				t.Errorf("keepOnlyChanges() did not return expected string.\nGot:\n%s\n\nWant:\n%s", actual, expected)
 			}
@@ -206,6 +430,150 @@ It should be ignored.
 	}
 }
 
+func TestKeepOnlyChangesNesting(t *testing.T) {
+	testCases := []struct {
+		name                string
+		includeDependencies bool
+		input               string
+		expected            string
+	}{
+		{
+			name: "subsections of ignored sections",
+			input: `# v1.2.3
+## Dependencies
+### Added
+- dep a
+### Changed
+- dep b
+## Changes by Kind
+### Feature
+- A change.
+`,
+			expected: `# v1.2.3
+## Changes by Kind
+### Feature
+- A change.
+
+`,
+		},
+		{
+			name: "kind heading missing its parent after an ignored section",
+			input: `# v1.2.3
+## Downloads for v1.2.3
+| filename | sha512 hash |
+### Feature
+- A change.
+### Bug or Regression
+- A fix.
+`,
+			expected: `# v1.2.3
+### Feature
+- A change.
+### Bug or Regression
+- A fix.
+
+`,
+		},
+		{
+			name: "SHA tables inside changes by kind",
+			input: `# v1.2.3
+## Changes by Kind
+### Source Code
+| filename | sha512 hash |
+| kubernetes.tar.gz | abc |
+### Container Images
+| name | architectures |
+### Feature
+- A change.
+`,
+			expected: `# v1.2.3
+## Changes by Kind
+### Feature
+- A change.
+
+`,
+		},
+		{
+			name: "known issues after ignored sections",
+			input: `# v1.2.3
+## Downloads for v1.2.3
+### Source Code
+| filename | sha512 hash |
+## Known Issues
+### Known issue
+- A known issue.
+`,
+			expected: `# v1.2.3
+## Known Issues
+### Known issue
+- A known issue.
+
+`,
+		},
+		{
+			name: "hashes that are not headings",
+			input: `# v1.2.3
+## Dependencies
+#not-a-heading
+## Changes by Kind
+- A #1 change.
+`,
+			expected: `# v1.2.3
+## Changes by Kind
+- A #1 change.
+
+`,
+		},
+		{
+			name:                "include dependencies",
+			includeDependencies: true,
+			input: `# v1.2.3
+## Downloads for v1.2.3
+| filename | sha512 hash |
+## Dependencies
+### Added
+- dep a
+`,
+			expected: `# v1.2.3
+## Dependencies
+### Added
+- dep a
+
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := keepOnlyChanges(tc.input, sectionRules(tc.includeDependencies)); got != tc.expected {
+				t.Errorf("keepOnlyChanges() = %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestHeadingLevel(t *testing.T) {
+	testCases := []struct {
+		line      string
+		wantLevel int
+		wantText  string
+	}{
+		{line: "# v1.2.3", wantLevel: 1, wantText: "v1.2.3"},
+		{line: "### Feature ", wantLevel: 3, wantText: "Feature"},
+		{line: "#hashtag", wantLevel: 0},
+		{line: "##", wantLevel: 0},
+		{line: "- A change.", wantLevel: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			level, text := headingLevel(tc.line)
+			if level != tc.wantLevel || text != tc.wantText {
+				t.Errorf("headingLevel() = %d, %q, want %d, %q", level, text, tc.wantLevel, tc.wantText)
+			}
+		})
+	}
+}
+
 // Real changelog content taken from https://raw.githubusercontent.com/kubernetes/kubernetes/refs/heads/master/CHANGELOG/CHANGELOG-1.33.md and cut down
 const fakeChangelogContent = `<!-- BEGIN MUNGE: GENERATED_TOC -->
 
@@ -0,0 +1,199 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8schangelog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultMaxBytes is the default size limit of a get_k8s_changelog response.
+const defaultMaxBytes = 200 * 1024
+
+// versionChanges are the changes of one patch version of a changelog.
+type versionChanges struct {
+	// version is the version of the heading, e.g. v1.33.6.
+	version string
+	text    string
+}
+
+// changelogPage is the part of a changelog returned by one call.
+type changelogPage struct {
+	text string
+	// truncated is the version whose changes were cut to fit, if any.
+	truncated string
+	// omitted are the versions left for the next pages.
+	omitted []string
+	// left is the text of the truncated and omitted versions.
+	left string
+}
+
+// splitVersions splits a processed changelog, newest version first, at its
+// version headings.
+func splitVersions(changes string) []versionChanges {
+	var versions []versionChanges
+	var current *strings.Builder
+	for _, line := range strings.SplitAfter(changes, "\n") {
+		if changelogVersionLineRegexp.MatchString(line) {
+			if current != nil {
+				versions[len(versions)-1].text = current.String()
+			}
+			versions = append(versions, versionChanges{version: strings.Fields(line)[1]})
+			current = new(strings.Builder)
+		}
+		if current != nil {
+			current.WriteString(line)
+		}
+	}
+	if current != nil {
+		versions[len(versions)-1].text = current.String()
+	}
+	return versions
+}
+
+// normalizeVersion adds the v prefix of the changelog headings to a version.
+func normalizeVersion(version string) string {
+	return "v" + strings.TrimPrefix(strings.TrimSpace(version), "v")
+}
+
+// versionIndex returns the index of version in versions, or -1.
+func versionIndex(versions []versionChanges, version string) int {
+	for i, v := range versions {
+		if v.version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// patchRange keeps the versions of a processed changelog after fromPatch, up
+// to and including toPatch. An empty bound leaves that end of the changelog
+// open.
+func patchRange(changes, fromPatch, toPatch string) (string, error) {
+	if fromPatch == "" && toPatch == "" {
+		return changes, nil
+	}
+	versions := splitVersions(changes)
+	// The changelog lists the newest version first.
+	end, start := len(versions), 0
+	if fromPatch != "" {
+		fromPatch = normalizeVersion(fromPatch)
+		if end = versionIndex(versions, fromPatch); end < 0 {
+			return "", fmt.Errorf("from_patch %s not found in the changelog, %s", fromPatch, describeVersions(versions))
+		}
+	}
+	if toPatch != "" {
+		toPatch = normalizeVersion(toPatch)
+		if start = versionIndex(versions, toPatch); start < 0 {
+			return "", fmt.Errorf("to_patch %s not found in the changelog, %s", toPatch, describeVersions(versions))
+		}
+	}
+	if start > end {
+		return "", fmt.Errorf("from_patch %s must not be after to_patch %s", fromPatch, toPatch)
+	}
+	var builder strings.Builder
+	for _, v := range versions[start:end] {
+		builder.WriteString(v.text)
+	}
+	return builder.String(), nil
+}
+
+func describeVersions(versions []versionChanges) string {
+	if len(versions) == 0 {
+		return "which has no versions"
+	}
+	return fmt.Sprintf("which has the versions from %s to %s", versions[len(versions)-1].version, versions[0].version)
+}
+
+// paginateChanges returns the versions of a processed changelog from
+// offsetVersion on, or from the newest one if it is empty, keeping at most
+// limit versions, all of them if limit is 0, and at most maxBytes. Versions
+// are only cut short if the first one alone is larger than maxBytes.
+func paginateChanges(changes, offsetVersion string, limit, maxBytes int) (*changelogPage, error) {
+	versions := splitVersions(changes)
+	start := 0
+	if offsetVersion != "" {
+		offsetVersion = normalizeVersion(offsetVersion)
+		start = versionIndex(versions, offsetVersion)
+		if start < 0 {
+			return nil, fmt.Errorf("offset_version %s not found in the changelog", offsetVersion)
+		}
+	}
+
+	page := &changelogPage{}
+	var builder strings.Builder
+	i := start
+	for ; i < len(versions); i++ {
+		if limit > 0 && i-start >= limit {
+			break
+		}
+		v := versions[i]
+		if builder.Len()+len(v.text) > maxBytes {
+			if i == start {
+				builder.WriteString(truncateAtEntry(v.text, maxBytes))
+				page.truncated = v.version
+				i++
+			}
+			break
+		}
+		builder.WriteString(v.text)
+	}
+	var left strings.Builder
+	if page.truncated != "" {
+		left.WriteString(versions[i-1].text)
+	}
+	for _, v := range versions[i:] {
+		page.omitted = append(page.omitted, v.version)
+		left.WriteString(v.text)
+	}
+	page.text = builder.String()
+	page.left = left.String()
+	return page, nil
+}
+
+// truncateAtEntry cuts changes to at most maxBytes, before the last change or
+// heading that doesn't fit, so that no change is cut in the middle.
+func truncateAtEntry(changes string, maxBytes int) string {
+	if len(changes) <= maxBytes {
+		return changes
+	}
+	cut := changes[:maxBytes]
+	if i := max(strings.LastIndex(cut, "\n- "), strings.LastIndex(cut, "\n#")); i >= 0 {
+		return cut[:i+1]
+	}
+	if i := strings.LastIndex(cut, "\n"); i >= 0 {
+		return cut[:i+1]
+	}
+	return ""
+}
+
+// formatChangelogPage renders a page with notes on what was left out. The
+// urgent upgrade notes left out are added in full.
+func formatChangelogPage(page *changelogPage, maxBytes int) string {
+	builder := new(strings.Builder)
+	builder.WriteString(page.text)
+	if page.truncated != "" {
+		fmt.Fprintf(builder, "\nNote: The changes of %s were truncated to fit in max_bytes %d.\n", page.truncated, maxBytes)
+	}
+	if len(page.omitted) > 0 {
+		fmt.Fprintf(builder, "\nNote: Omitted %d patch versions: %s. Call get_k8s_changelog again with offset_version %s to continue.\n", len(page.omitted), strings.Join(page.omitted, ", "), page.omitted[0])
+	}
+	for _, n := range urgentNotes(page.left) {
+		if !strings.Contains(page.text, n.section) {
+			fmt.Fprintf(builder, "\n%s\n\n%s", n.versionHeading, n.section)
+		}
+	}
+	return builder.String()
+}
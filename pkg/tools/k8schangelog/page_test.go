@@ -0,0 +1,218 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8schangelog
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const pagedChanges = `# v1.32.2
+
+- Change 2a.
+- Change 2b.
+
+# v1.32.1
+
+- Change 1.
+
+# v1.32.0
+
+## Urgent Upgrade Notes (action required before upgrading)
+
+- Urgent change.
+
+## Changes by Kind
+
+- Change 0.
+`
+
+func TestPaginateChanges(t *testing.T) {
+	testCases := []struct {
+		name          string
+		offset        string
+		limit         int
+		maxBytes      int
+		wantText      string
+		wantTruncated string
+		wantOmitted   []string
+		wantErr       string
+	}{
+		{
+			name:     "everything fits",
+			maxBytes: 1000,
+			wantText: pagedChanges,
+		},
+		{
+			name:        "limit",
+			limit:       2,
+			maxBytes:    1000,
+			wantText:    "# v1.32.2\n\n- Change 2a.\n- Change 2b.\n\n# v1.32.1\n\n- Change 1.\n\n",
+			wantOmitted: []string{"v1.32.0"},
+		},
+		{
+			name:        "max bytes at a version boundary",
+			maxBytes:    50,
+			wantText:    "# v1.32.2\n\n- Change 2a.\n- Change 2b.\n\n",
+			wantOmitted: []string{"v1.32.1", "v1.32.0"},
+		},
+		{
+			name:          "first version larger than max bytes",
+			maxBytes:      30,
+			wantText:      "# v1.32.2\n\n- Change 2a.\n",
+			wantTruncated: "v1.32.2",
+			wantOmitted:   []string{"v1.32.1", "v1.32.0"},
+		},
+		{
+			name:        "offset",
+			offset:      "1.32.1",
+			limit:       1,
+			maxBytes:    1000,
+			wantText:    "# v1.32.1\n\n- Change 1.\n\n",
+			wantOmitted: []string{"v1.32.0"},
+		},
+		{
+			name:     "unknown offset",
+			offset:   "v1.31.9",
+			maxBytes: 1000,
+			wantErr:  "offset_version v1.31.9 not found",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, err := paginateChanges(pagedChanges, tc.offset, tc.limit, tc.maxBytes)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("paginateChanges() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("paginateChanges() returned unexpected error: %v", err)
+			}
+			if page.text != tc.wantText {
+				t.Errorf("paginateChanges() text = %q, want %q", page.text, tc.wantText)
+			}
+			if page.truncated != tc.wantTruncated {
+				t.Errorf("paginateChanges() truncated = %q, want %q", page.truncated, tc.wantTruncated)
+			}
+			if !slices.Equal(page.omitted, tc.wantOmitted) {
+				t.Errorf("paginateChanges() omitted = %v, want %v", page.omitted, tc.wantOmitted)
+			}
+		})
+	}
+}
+
+func TestFormatChangelogPage(t *testing.T) {
+	page, err := paginateChanges(pagedChanges, "", 1, 1000)
+	if err != nil {
+		t.Fatalf("paginateChanges() returned unexpected error: %v", err)
+	}
+	got := formatChangelogPage(page, 1000)
+	want := `# v1.32.2
+
+- Change 2a.
+- Change 2b.
+
+
+Note: Omitted 2 patch versions: v1.32.1, v1.32.0. Call get_k8s_changelog again with offset_version v1.32.1 to continue.
+
+# v1.32.0
+
+## Urgent Upgrade Notes (action required before upgrading)
+
+- Urgent change.
+
+`
+	if got != want {
+		t.Errorf("formatChangelogPage() = %q, want %q", got, want)
+	}
+}
+
+func TestPatchRange(t *testing.T) {
+	testCases := []struct {
+		name    string
+		from    string
+		to      string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "no bounds",
+			want: pagedChanges,
+		},
+		{
+			name: "between oldest and newest",
+			from: "1.32.0",
+			to:   "v1.32.2",
+			want: "# v1.32.2\n\n- Change 2a.\n- Change 2b.\n\n# v1.32.1\n\n- Change 1.\n\n",
+		},
+		{
+			name: "to the oldest",
+			to:   "1.32.0",
+			want: "# v1.32.0\n\n## Urgent Upgrade Notes (action required before upgrading)\n\n- Urgent change.\n\n## Changes by Kind\n\n- Change 0.\n",
+		},
+		{
+			name: "from the newest",
+			from: "1.32.2",
+			want: "",
+		},
+		{
+			name: "from equals to",
+			from: "1.32.1",
+			to:   "1.32.1",
+			want: "",
+		},
+		{
+			name: "only from",
+			from: "1.32.1",
+			want: "# v1.32.2\n\n- Change 2a.\n- Change 2b.\n\n",
+		},
+		{
+			name:    "reversed",
+			from:    "1.32.2",
+			to:      "1.32.1",
+			wantErr: "from_patch v1.32.2 must not be after to_patch v1.32.1",
+		},
+		{
+			name:    "unknown from",
+			from:    "1.31.9",
+			wantErr: "from_patch v1.31.9 not found in the changelog, which has the versions from v1.32.0 to v1.32.2",
+		},
+		{
+			name:    "unknown to",
+			to:      "1.32.3",
+			wantErr: "to_patch v1.32.3 not found",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := patchRange(pagedChanges, tc.from, tc.to)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("patchRange() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("patchRange() returned unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("patchRange() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
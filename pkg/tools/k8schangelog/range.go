@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8schangelog
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// maxRangeMinors is the largest number of minor versions of a range.
+	maxRangeMinors = 5
+	// maxRangeBytes is the size cap of the changelogs of a range, shared
+	// evenly by its minor versions.
+	maxRangeBytes = 400 * 1024
+)
+
+type getK8sChangelogRangeArgs struct {
+	FromMinor           string   `json:"from_minor" jsonschema:"The first kubernetes minor version of the range, e.g. the minor version of the cluster. For example, '1.29'."`
+	ToMinor             string   `json:"to_minor" jsonschema:"The last kubernetes minor version of the range, e.g. the minor version of the upgrade target. For example, '1.32'."`
+	Refresh             bool     `json:"refresh,omitempty" jsonschema:"Download the changelogs again instead of using the cached copies."`
+	IncludeDependencies bool     `json:"include_dependencies,omitempty" jsonschema:"Keep the Dependencies sections listing the dependency bumps, which are left out by default."`
+	Kinds               []string `json:"kinds,omitempty" jsonschema:"Only keep the changes of these kinds. Each one of: deprecation, api-change, feature, bug, failing-test, cleanup, documentation. Keeps all kinds if empty."`
+	Sigs                []string `json:"sigs,omitempty" jsonschema:"Only keep the changes tagged with one of these SIGs, e.g. Network or Node. Keeps the changes of all SIGs if empty."`
+}
+
+// minorChangelog is the changelog of one minor version of a range.
+type minorChangelog struct {
+	version string
+	changes string
+	// note says where the changes come from if not from upstream.
+	note string
+	err  error
+}
+
+func installGetK8sChangelogRangeTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_k8s_changelog_range",
+		Description: fmt.Sprintf("Get the changelogs of all kubernetes minor versions from from_minor to to_minor, both included, in one call and keep only changes content. At most %d minor versions at a time. Prefer to use this tool instead of get_k8s_changelog when the changelogs of several minor versions are needed, e.g. for an upgrade.", maxRangeMinors),
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+		},
+	}, h.getK8sChangelogRange)
+}
+
+func (h *handlers) getK8sChangelogRange(ctx context.Context, _ *mcp.CallToolRequest, args *getK8sChangelogRangeArgs) (*mcp.CallToolResult, any, error) {
+	versions, err := minorRange(strings.TrimSpace(args.FromMinor), strings.TrimSpace(args.ToMinor))
+	if err != nil {
+		return nil, nil, err
+	}
+	filter, err := newChangeFilter(args.Kinds, args.Sigs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changelogs := make([]minorChangelog, len(versions))
+	var wg sync.WaitGroup
+	for i, v := range versions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			changes, note, err := h.changelog(ctx, v, args.IncludeDependencies, args.Refresh)
+			if err == nil {
+				changes = filterChanges(changes, filter)
+			}
+			changelogs[i] = minorChangelog{version: v, changes: changes, note: note, err: err}
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, c := range changelogs {
+		if c.err != nil {
+			failed++
+		}
+	}
+	if failed == len(changelogs) {
+		return nil, nil, fmt.Errorf("failed to get the changelogs of %s to %s: %w", versions[0], versions[len(versions)-1], changelogs[0].err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatChangelogRange(changelogs, maxRangeBytes/len(changelogs))},
+		},
+	}, nil, nil
+}
+
+// minorRange returns the minor versions from from to to, both included. The
+// versions must have the same major version.
+func minorRange(from, to string) ([]string, error) {
+	fromMajor, fromMinor, err := parseMinor(from)
+	if err != nil {
+		return nil, err
+	}
+	toMajor, toMinor, err := parseMinor(to)
+	if err != nil {
+		return nil, err
+	}
+	if fromMajor != toMajor {
+		return nil, fmt.Errorf("from_minor %s and to_minor %s must have the same major version", from, to)
+	}
+	if toMinor < fromMinor {
+		return nil, fmt.Errorf("from_minor %s must not be after to_minor %s", from, to)
+	}
+	if n := toMinor - fromMinor + 1; n > maxRangeMinors {
+		return nil, fmt.Errorf("the range %s to %s has %d minor versions, at most %d are allowed; split it into several calls", from, to, n, maxRangeMinors)
+	}
+	var versions []string
+	for m := fromMinor; m <= toMinor; m++ {
+		versions = append(versions, fmt.Sprintf("%d.%d", fromMajor, m))
+	}
+	return versions, nil
+}
+
+func parseMinor(version string) (major, minor int, err error) {
+	if !kubernetesMinorVersionRegexp.MatchString(version) {
+		return 0, 0, fmt.Errorf("invalid kubernetes minor version: %s", version)
+	}
+	majorStr, minorStr, _ := strings.Cut(version, ".")
+	if major, err = strconv.Atoi(majorStr); err != nil {
+		return 0, 0, fmt.Errorf("invalid kubernetes minor version: %s", version)
+	}
+	if minor, err = strconv.Atoi(minorStr); err != nil {
+		return 0, 0, fmt.Errorf("invalid kubernetes minor version: %s", version)
+	}
+	return major, minor, nil
+}
+
+// formatChangelogRange concatenates the changelogs between version delimiters,
+// truncating each one to maxBytes.
+func formatChangelogRange(changelogs []minorChangelog, maxBytes int) string {
+	builder := new(strings.Builder)
+	for i, c := range changelogs {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		fmt.Fprintf(builder, "==================== Kubernetes %s changelog ====================\n\n", c.version)
+		if c.err != nil {
+			fmt.Fprintf(builder, "Warning: Failed to get the changelog of %s: %v\n", c.version, c.err)
+			continue
+		}
+		builder.WriteString(formatNote(c.note))
+		changes, truncated := truncateChanges(c.changes, maxBytes)
+		builder.WriteString(changes)
+		if truncated {
+			fmt.Fprintf(builder, "\nNote: The changelog of %s was truncated to %d of %d bytes. Use get_k8s_changelog to get it in full.\n", c.version, len(changes), len(c.changes))
+			// The urgent upgrade notes are never dropped.
+			for _, n := range urgentNotes(c.changes) {
+				if !strings.Contains(changes, n.section) {
+					fmt.Fprintf(builder, "\n%s\n\n%s", n.versionHeading, n.section)
+				}
+			}
+		}
+	}
+	return builder.String()
+}
+
+// truncateChanges cuts changes to at most maxBytes, at the end of a line if
+// there is one.
+func truncateChanges(changes string, maxBytes int) (string, bool) {
+	if len(changes) <= maxBytes {
+		return changes, false
+	}
+	cut := changes[:maxBytes]
+	if i := strings.LastIndex(cut, "\n"); i >= 0 {
+		cut = cut[:i+1]
+	}
+	return cut, true
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8schangelog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestMinorRange(t *testing.T) {
+	testCases := []struct {
+		name    string
+		from    string
+		to      string
+		want    []string
+		wantErr string
+	}{
+		{name: "range", from: "1.29", to: "1.32", want: []string{"1.29", "1.30", "1.31", "1.32"}},
+		{name: "single minor", from: "1.31", to: "1.31", want: []string{"1.31"}},
+		{name: "largest range", from: "1.28", to: "1.32", want: []string{"1.28", "1.29", "1.30", "1.31", "1.32"}},
+		{name: "too large", from: "1.27", to: "1.32", wantErr: "at most 5 are allowed"},
+		{name: "reversed", from: "1.32", to: "1.29", wantErr: "must not be after"},
+		{name: "different majors", from: "1.32", to: "2.0", wantErr: "same major version"},
+		{name: "patch version", from: "1.29.1", to: "1.32", wantErr: "invalid kubernetes minor version: 1.29.1"},
+		{name: "empty", from: "1.29", to: "", wantErr: "invalid kubernetes minor version"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := minorRange(tc.from, tc.to)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("minorRange() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("minorRange() returned unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("minorRange() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetK8sChangelogRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "CHANGELOG-1.33.md") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, fakeChangelogContent)
+	}))
+	defer server.Close()
+
+	useChangelogSources(t, server.URL)
+
+	h := &handlers{cache: newChangelogCache("")}
+	result, _, err := h.getK8sChangelogRange(context.Background(), nil, &getK8sChangelogRangeArgs{FromMinor: "1.31", ToMinor: "1.33"})
+	if err != nil {
+		t.Fatalf("getK8sChangelogRange() returned unexpected error: %v", err)
+	}
+	got := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{
+		"==================== Kubernetes 1.31 changelog ====================\n\n" + expectedProcessedContent,
+		"==================== Kubernetes 1.32 changelog ====================\n\n" + expectedProcessedContent,
+		"==================== Kubernetes 1.33 changelog ====================\n\nWarning: Failed to get the changelog of 1.33: failed to get changelog with status code: 404\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("getK8sChangelogRange() = %q, want it to contain %q", got, want)
+		}
+	}
+
+	_, _, err = h.getK8sChangelogRange(context.Background(), nil, &getK8sChangelogRangeArgs{FromMinor: "1.33", ToMinor: "1.33"})
+	if err == nil || !strings.Contains(err.Error(), "status code: 404") {
+		t.Errorf("getK8sChangelogRange() error = %v, want the 404 of the only minor version", err)
+	}
+}
+
+func TestFormatChangelogRange(t *testing.T) {
+	changelogs := []minorChangelog{
+		{version: "1.30", changes: "line one\nline two\nline three\n"},
+		{version: "1.31", changes: "short\n"},
+		{version: "1.32", err: errors.New("boom")},
+	}
+	got := formatChangelogRange(changelogs, 12)
+	want := `==================== Kubernetes 1.30 changelog ====================
+
+line one
+
+Note: The changelog of 1.30 was truncated to 9 of 29 bytes. Use get_k8s_changelog to get it in full.
+
+==================== Kubernetes 1.31 changelog ====================
+
+short
+
+==================== Kubernetes 1.32 changelog ====================
+
+Warning: Failed to get the changelog of 1.32: boom
+`
+	if got != want {
+		t.Errorf("formatChangelogRange() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatChangelogRangeKeepsUrgentNotes(t *testing.T) {
+	changes := keepOnlyChanges(`# v1.32.1
+
+## Changes by Kind
+
+### Bug or Regression
+
+- A long bug fix description.
+
+# v1.32.0
+
+## Urgent Upgrade Notes
+
+### (No, really, you MUST read this before you upgrade)
+
+- Urgent change.
+
+## Changes by Kind
+`, sectionRules(false))
+	got := formatChangelogRange([]minorChangelog{{version: "1.32", changes: changes}}, 30)
+	want := `==================== Kubernetes 1.32 changelog ====================
+
+# v1.32.1
+
+## Changes by Kind
+
+Note: The changelog of 1.32 was truncated to 30 of 252 bytes. Use get_k8s_changelog to get it in full.
+
+# v1.32.0
+
+## Urgent Upgrade Notes (action required before upgrading)
+
+### (No, really, you MUST read this before you upgrade)
+
+- Urgent change.
+
+
+`
+	if got != want {
+		t.Errorf("formatChangelogRange() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8schangelog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+)
+
+//go:generate go run ./snapshotgen -out snapshot
+
+// snapshot holds the gzipped changelogs of the most recent minor versions,
+// downloaded when a release is built, for networks where no changelog source
+// can be reached.
+//
+//go:embed snapshot
+var snapshot embed.FS
+
+// changelogSnapshot is the snapshot changelogs are read from.
+var changelogSnapshot fs.FS = snapshot
+
+// snapshotInfo is the snapshot/snapshot.json file describing the snapshot.
+type snapshotInfo struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Versions    []string  `json:"versions"`
+}
+
+// snapshotChangelog returns the changes of a minor version from the snapshot,
+// processed with rules, with the time the snapshot was generated.
+func snapshotChangelog(version string, rules []sectionRule) (string, time.Time, error) {
+	data, err := fs.ReadFile(changelogSnapshot, "snapshot/snapshot.json")
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	var info snapshotInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to parse snapshot/snapshot.json: %w", err)
+	}
+	compressed, err := fs.ReadFile(changelogSnapshot, fmt.Sprintf("snapshot/CHANGELOG-%s.md.gz", version))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("the snapshot has no changelog of %s: %w", version, err)
+	}
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decompress the changelog of %s: %w", version, err)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decompress the changelog of %s: %w", version, err)
+	}
+	return keepOnlyChanges(string(body), rules), info.GeneratedAt, nil
+}
+
+// formatNote renders a note of changelog, if any.
+func formatNote(note string) string {
+	if note == "" {
+		return ""
+	}
+	return "Note: " + note + "\n\n"
+}
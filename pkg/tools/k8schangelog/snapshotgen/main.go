@@ -0,0 +1,122 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command snapshotgen downloads the changelogs of the most recent Kubernetes
+// minor versions into the snapshot embedded in the k8schangelog package. It is
+// run by go generate when a release is built.
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const changelogURL = "https://raw.githubusercontent.com/kubernetes/kubernetes/refs/heads/master/CHANGELOG/CHANGELOG-1.%d.md"
+
+func main() {
+	out := flag.String("out", "snapshot", "Directory to write the snapshot to.")
+	minors := flag.Int("minors", 4, "Number of the most recent minor versions to include.")
+	start := flag.Int("start", 28, "Kubernetes 1.x minor version to start looking for changelogs from.")
+	flag.Parse()
+
+	// Look for changelogs until one is missing, keeping the most recent ones.
+	changelogs := map[string][]byte{}
+	var versions []string
+	for minor := *start; ; minor++ {
+		body, err := download(fmt.Sprintf(changelogURL, minor))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if body == nil {
+			break
+		}
+		version := fmt.Sprintf("1.%d", minor)
+		versions = append(versions, version)
+		changelogs[version] = body
+		if len(versions) > *minors {
+			delete(changelogs, versions[0])
+			versions = versions[1:]
+		}
+	}
+	if len(versions) == 0 {
+		log.Fatalf("No changelog found from 1.%d on", *start)
+	}
+
+	old, err := filepath.Glob(filepath.Join(*out, "CHANGELOG-*.md.gz"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, f := range old {
+		if err := os.Remove(f); err != nil {
+			log.Fatal(err)
+		}
+	}
+	for _, v := range versions {
+		if err := writeGzip(filepath.Join(*out, fmt.Sprintf("CHANGELOG-%s.md.gz", v)), changelogs[v]); err != nil {
+			log.Fatal(err)
+		}
+	}
+	info, err := json.MarshalIndent(map[string]any{
+		"generated_at": time.Now().UTC(),
+		"versions":     versions,
+	}, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(*out, "snapshot.json"), append(info, '\n'), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Wrote the changelogs of %v to %s", versions, *out)
+}
+
+// download returns the body of url, or nil if it is not found.
+func download(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s with status code: %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func writeGzip(path string, data []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := gzip.NewWriter(f)
+	if _, err := w.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,276 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	logging "cloud.google.com/go/logging/apiv2"
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type findAuditEventsArgs struct {
+	ProjectID   string   `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location    string   `json:"location" jsonschema:"GKE cluster location. Required."`
+	ClusterName string   `json:"cluster_name" jsonschema:"GKE cluster name. Required."`
+	Kind        string   `json:"kind" jsonschema:"Kubernetes resource kind, for example 'Deployment', 'ConfigMap' or 'Namespace'. Required."`
+	Namespace   string   `json:"namespace,omitempty" jsonschema:"Kubernetes namespace of the resource. Leave empty for cluster-scoped resources or to match all namespaces."`
+	Name        string   `json:"name,omitempty" jsonschema:"Kubernetes resource name. Leave empty to match all resources of the given kind."`
+	Verbs       []string `json:"verbs,omitempty" jsonschema:"Only return events for these verbs. Supported values are: ['create', 'update', 'patch', 'delete']. Defaults to all of them."`
+	Since       string   `json:"since,omitempty" jsonschema:"Lookback window as a duration like 30m, 6h or 72h. Defaults to 24h."`
+	Limit       int      `json:"limit,omitempty" jsonschema:"Maximum number of events to return. Cannot be greater than 100. Defaults to 20."`
+}
+
+const (
+	defaultAuditSince = "24h"
+	defaultAuditLimit = 20
+)
+
+var supportedAuditVerbs = map[string]bool{
+	"create": true,
+	"update": true,
+	"patch":  true,
+	"delete": true,
+}
+
+// irregularResourcePlurals maps lower-cased kinds whose resource name isn't
+// simply the kind followed by an "s".
+var irregularResourcePlurals = map[string]string{
+	"endpoints":                "endpoints",
+	"ingress":                  "ingresses",
+	"ingressclass":             "ingressclasses",
+	"networkpolicy":            "networkpolicies",
+	"podsecuritypolicy":        "podsecuritypolicies",
+	"priorityclass":            "priorityclasses",
+	"runtimeclass":             "runtimeclasses",
+	"storageclass":             "storageclasses",
+	"podmonitoring":            "podmonitorings",
+	"volumeattributesclass":    "volumeattributesclasses",
+	"customresourcedefinition": "customresourcedefinitions",
+}
+
+func installFindAuditEventsTool(s *mcp.Server, conf *config.Config) {
+	t := &findAuditEventsTool{
+		conf: conf,
+	}
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "find_audit_events",
+		Description: "Find who created, changed or deleted a Kubernetes resource in a GKE cluster, using the Kubernetes audit logs. Returns the principal, timestamp, verb and source IP of each matching request, most recent first. Prefer this tool over query_logs for questions like 'who deleted my deployment?'.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, t.findAuditEvents)
+}
+
+type findAuditEventsTool struct {
+	conf *config.Config
+}
+
+func (t *findAuditEventsTool) findAuditEvents(ctx context.Context, _ *mcp.CallToolRequest, args *findAuditEventsArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = t.conf.DefaultProjectID()
+	}
+	if err := args.setDefaultsAndValidate(); err != nil {
+		return nil, nil, err
+	}
+
+	req := &LogQueryRequest{
+		ProjectID:      args.ProjectID,
+		Query:          buildAuditEventsFilter(args),
+		Since:          args.Since,
+		Limit:          args.Limit,
+		TimeoutSeconds: defaultTimeoutSeconds,
+	}
+
+	client, err := logging.NewClient(ctx, t.conf.ClientOptions()...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create logging client: %v", err)
+	}
+	defer client.Close()
+
+	listLogsReq := buildListLogEntriesRequest(req)
+	listLogsReq.OrderBy = "timestamp desc"
+
+	iterCtx, cancel := context.WithTimeout(ctx, time.Duration(req.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	entries, status, err := collectLogEntries(ctx, iterCtx, newLogEntryPageFetcher(client, listLogsReq), "", req.Limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "LQL Query:\n```\n%s\n```\n\n", listLogsReq.Filter)
+	if len(entries) == 0 {
+		result.WriteString("No matching audit events found.")
+	} else {
+		table, err := formatAuditEvents(entries)
+		if err != nil {
+			return nil, nil, err
+		}
+		result.WriteString(table)
+	}
+	if status.truncated {
+		fmt.Fprintf(&result, "\n\nWarning: Results limited to the %d most recent events. Use the `limit` parameter to request more (up to %d).", req.Limit, maxLimit)
+	}
+	result.WriteString(status.partialResultsWarning(req.TimeoutSeconds))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, nil, nil
+}
+
+func (a *findAuditEventsArgs) setDefaultsAndValidate() error {
+	if a.ProjectID == "" {
+		return fmt.Errorf("project_id argument cannot be empty")
+	}
+	if a.Location == "" {
+		return fmt.Errorf("location argument cannot be empty")
+	}
+	if a.ClusterName == "" {
+		return fmt.Errorf("cluster_name argument cannot be empty")
+	}
+	if a.Kind == "" {
+		return fmt.Errorf("kind argument cannot be empty")
+	}
+	for i, verb := range a.Verbs {
+		verb = strings.ToLower(strings.TrimSpace(verb))
+		if !supportedAuditVerbs[verb] {
+			return fmt.Errorf("unsupported verb: %s", a.Verbs[i])
+		}
+		a.Verbs[i] = verb
+	}
+	if a.Since == "" {
+		a.Since = defaultAuditSince
+	}
+	if _, err := time.ParseDuration(a.Since); err != nil {
+		return fmt.Errorf("invalid since argument: %w", err)
+	}
+	if a.Limit == 0 {
+		a.Limit = defaultAuditLimit
+	}
+	if a.Limit < 0 || a.Limit > maxLimit {
+		return fmt.Errorf("limit argument must be between 1 and %d", maxLimit)
+	}
+	return nil
+}
+
+// resourcePlural returns the lower-case plural resource name used in
+// Kubernetes API paths for kind.
+func resourcePlural(kind string) string {
+	k := strings.ToLower(kind)
+	if plural, ok := irregularResourcePlurals[k]; ok {
+		return plural
+	}
+	if strings.HasSuffix(k, "s") {
+		// Already plural, e.g. "pods".
+		return k
+	}
+	if strings.HasSuffix(k, "y") {
+		return strings.TrimSuffix(k, "y") + "ies"
+	}
+	return k + "s"
+}
+
+// auditResourceNamePattern returns the regular expression matching the audit
+// log resource names of the resources described by args. It is anchored at
+// both ends so that a name does not also match names it is a prefix of, e.g.
+// "web" and "web-canary". Subresources such as "status" are not matched.
+func auditResourceNamePattern(args *findAuditEventsArgs) string {
+	resourcePath := resourcePlural(args.Kind)
+	if args.Namespace != "" {
+		resourcePath = fmt.Sprintf("namespaces/%s/%s", args.Namespace, resourcePath)
+	}
+	name := "[^/]+"
+	if args.Name != "" {
+		name = regexp.QuoteMeta(args.Name)
+	}
+	return "(^|/)" + regexp.QuoteMeta(resourcePath) + "/" + name + "$"
+}
+
+// buildAuditEventsFilter builds the LQL filter matching mutating Kubernetes
+// audit log entries for the resource described by args.
+func buildAuditEventsFilter(args *findAuditEventsArgs) string {
+	clauses := []string{
+		fmt.Sprintf("logName=%s", strconv.Quote("projects/"+args.ProjectID+"/logs/cloudaudit.googleapis.com%2Factivity")),
+		`resource.type="k8s_cluster"`,
+		fmt.Sprintf("resource.labels.project_id=%s", strconv.Quote(args.ProjectID)),
+		fmt.Sprintf("resource.labels.location=%s", strconv.Quote(args.Location)),
+		fmt.Sprintf("resource.labels.cluster_name=%s", strconv.Quote(args.ClusterName)),
+		fmt.Sprintf("protoPayload.resourceName=~%s", strconv.Quote(auditResourceNamePattern(args))),
+	}
+
+	verbs := args.Verbs
+	if len(verbs) == 0 {
+		verbs = []string{"create", "update", "patch", "delete"}
+	}
+	clauses = append(clauses, fmt.Sprintf(`protoPayload.methodName=~"[.](%s)$"`, strings.Join(verbs, "|")))
+
+	return strings.Join(clauses, "\n")
+}
+
+// formatAuditEvents renders audit log entries as a compact markdown table.
+func formatAuditEvents(entries []*loggingpb.LogEntry) (string, error) {
+	var b strings.Builder
+	b.WriteString("| Timestamp | Verb | Principal | Source IP | Resource |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	for _, entry := range entries {
+		data, err := protojson.Marshal(entry)
+		if err != nil {
+			return "", fmt.Errorf("could not marshal log entry to JSON: %w", err)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(data, &m); err != nil {
+			return "", fmt.Errorf("could not unmarshal log entry: %w", err)
+		}
+		method := lookupString(m, "protoPayload", "methodName")
+		verb := method[strings.LastIndex(method, ".")+1:]
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+			lookupString(m, "timestamp"),
+			verb,
+			lookupString(m, "protoPayload", "authenticationInfo", "principalEmail"),
+			lookupString(m, "protoPayload", "requestMetadata", "callerIp"),
+			lookupString(m, "protoPayload", "resourceName"),
+		)
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// lookupString returns the string found by following keys through nested
+// maps, or an empty string if there is none.
+func lookupString(m map[string]interface{}, keys ...string) string {
+	var v interface{} = m
+	for _, key := range keys {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		v = obj[key]
+	}
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,240 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+	"google.golang.org/genproto/googleapis/cloud/audit"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestBuildAuditEventsFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		args findAuditEventsArgs
+		want string
+	}{
+		{
+			name: "namespaced resource with verbs",
+			args: findAuditEventsArgs{
+				ProjectID:   "test-project",
+				Location:    "us-central1",
+				ClusterName: "test-cluster",
+				Kind:        "Deployment",
+				Namespace:   "default",
+				Name:        "nginx",
+				Verbs:       []string{"delete"},
+			},
+			want: `logName="projects/test-project/logs/cloudaudit.googleapis.com%2Factivity"
+resource.type="k8s_cluster"
+resource.labels.project_id="test-project"
+resource.labels.location="us-central1"
+resource.labels.cluster_name="test-cluster"
+protoPayload.resourceName=~"(^|/)namespaces/default/deployments/nginx$"
+protoPayload.methodName=~"[.](delete)$"`,
+		},
+		{
+			name: "all resources of a kind",
+			args: findAuditEventsArgs{
+				ProjectID:   "test-project",
+				Location:    "us-central1",
+				ClusterName: "test-cluster",
+				Kind:        "NetworkPolicy",
+			},
+			want: `logName="projects/test-project/logs/cloudaudit.googleapis.com%2Factivity"
+resource.type="k8s_cluster"
+resource.labels.project_id="test-project"
+resource.labels.location="us-central1"
+resource.labels.cluster_name="test-cluster"
+protoPayload.resourceName=~"(^|/)networkpolicies/[^/]+$"
+protoPayload.methodName=~"[.](create|update|patch|delete)$"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildAuditEventsFilter(&tt.args); got != tt.want {
+				t.Errorf("buildAuditEventsFilter() = \n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildAuditEventsFilterQuotesValues(t *testing.T) {
+	args := findAuditEventsArgs{
+		ProjectID:   "test-project",
+		Location:    "us-central1",
+		ClusterName: `my"cluster\`,
+		Kind:        "Pod",
+		Name:        "web.1",
+	}
+	got := buildAuditEventsFilter(&args)
+	for _, want := range []string{
+		`resource.labels.cluster_name="my\"cluster\\"`,
+		`protoPayload.resourceName=~"(^|/)pods/web\\.1$"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("buildAuditEventsFilter() = \n%s\nwant it to contain %s", got, want)
+		}
+	}
+}
+
+func TestAuditResourceNamePattern(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         findAuditEventsArgs
+		resourceName string
+		want         bool
+	}{
+		{
+			name:         "exact name",
+			args:         findAuditEventsArgs{Kind: "Deployment", Namespace: "default", Name: "web"},
+			resourceName: "apps/v1/namespaces/default/deployments/web",
+			want:         true,
+		},
+		{
+			name:         "name is a prefix of another",
+			args:         findAuditEventsArgs{Kind: "Deployment", Namespace: "default", Name: "web"},
+			resourceName: "apps/v1/namespaces/default/deployments/web-canary",
+			want:         false,
+		},
+		{
+			name:         "kind is a suffix of another",
+			args:         findAuditEventsArgs{Kind: "Policy"},
+			resourceName: "networking.k8s.io/v1/namespaces/default/networkpolicies/deny-all",
+			want:         false,
+		},
+		{
+			name:         "any name of a kind",
+			args:         findAuditEventsArgs{Kind: "NetworkPolicy"},
+			resourceName: "networking.k8s.io/v1/namespaces/default/networkpolicies/deny-all",
+			want:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := regexp.MustCompile(auditResourceNamePattern(&tt.args))
+			if got := re.MatchString(tt.resourceName); got != tt.want {
+				t.Errorf("pattern %q matching %q = %v, want %v", re, tt.resourceName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindAuditEventsArgsValidate(t *testing.T) {
+	valid := func() findAuditEventsArgs {
+		return findAuditEventsArgs{
+			ProjectID:   "test-project",
+			Location:    "us-central1",
+			ClusterName: "test-cluster",
+			Kind:        "Deployment",
+		}
+	}
+	tests := []struct {
+		name    string
+		modify  func(*findAuditEventsArgs)
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			modify: func(*findAuditEventsArgs) {},
+		},
+		{
+			name:    "missing kind",
+			modify:  func(a *findAuditEventsArgs) { a.Kind = "" },
+			wantErr: true,
+		},
+		{
+			name:    "unsupported verb",
+			modify:  func(a *findAuditEventsArgs) { a.Verbs = []string{"get"} },
+			wantErr: true,
+		},
+		{
+			name:    "invalid since",
+			modify:  func(a *findAuditEventsArgs) { a.Since = "yesterday" },
+			wantErr: true,
+		},
+		{
+			name:    "limit too high",
+			modify:  func(a *findAuditEventsArgs) { a.Limit = 101 },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := valid()
+			tt.modify(&args)
+			if err := args.setDefaultsAndValidate(); (err != nil) != tt.wantErr {
+				t.Errorf("setDefaultsAndValidate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourcePlural(t *testing.T) {
+	tests := map[string]string{
+		"Deployment":    "deployments",
+		"pods":          "pods",
+		"Ingress":       "ingresses",
+		"NetworkPolicy": "networkpolicies",
+		"Endpoints":     "endpoints",
+	}
+	for kind, want := range tests {
+		if got := resourcePlural(kind); got != want {
+			t.Errorf("resourcePlural(%q) = %q, want %q", kind, got, want)
+		}
+	}
+}
+
+func TestFormatAuditEvents(t *testing.T) {
+	payload, err := anypb.New(&audit.AuditLog{
+		MethodName:   "io.k8s.apps.v1.deployments.delete",
+		ResourceName: "apps/v1/namespaces/default/deployments/nginx",
+		AuthenticationInfo: &audit.AuthenticationInfo{
+			PrincipalEmail: "user@example.com",
+		},
+		RequestMetadata: &audit.RequestMetadata{
+			CallerIp: "10.0.0.1",
+		},
+	})
+	if err != nil {
+		t.Fatalf("anypb.New() error = %v", err)
+	}
+	entries := []*loggingpb.LogEntry{
+		{
+			Timestamp: timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+			Payload:   &loggingpb.LogEntry_ProtoPayload{ProtoPayload: payload},
+		},
+	}
+
+	got, err := formatAuditEvents(entries)
+	if err != nil {
+		t.Fatalf("formatAuditEvents() error = %v", err)
+	}
+	want := `| Timestamp | Verb | Principal | Source IP | Resource |
+|---|---|---|---|---|
+| 2025-01-01T00:00:00Z | delete | user@example.com | 10.0.0.1 | apps/v1/namespaces/default/deployments/nginx |`
+	if got != want {
+		t.Errorf("formatAuditEvents() = \n%s\nwant:\n%s", got, want)
+	}
+}
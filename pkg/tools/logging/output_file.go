@@ -0,0 +1,140 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+)
+
+// resolveOutputFile returns the cleaned absolute path for an output_file
+// argument. A leading "~" is expanded to the user's home directory. Relative
+// paths are only accepted when they resolve inside the user's home or temp
+// directory; absolute paths are taken as an explicit choice by the user.
+func resolveOutputFile(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not determine home directory: %w", err)
+		}
+		return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+	}
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path), nil
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	allowedDirs := []string{os.TempDir()}
+	if home, err := os.UserHomeDir(); err == nil {
+		allowedDirs = append(allowedDirs, home)
+	}
+	for _, dir := range allowedDirs {
+		if isWithinDir(dir, abs) {
+			return abs, nil
+		}
+	}
+	return "", fmt.Errorf("relative path %q resolves to %s which is outside of the home and temp directories; use an absolute path to write there", path, abs)
+}
+
+func isWithinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// writeLogEntriesToFile streams the formatted entries returned by it into
+// req.OutputFile and returns a summary of what was written.
+func writeLogEntriesToFile(ctx, iterCtx context.Context, fetch logEntryPageFetcher, pageToken string, req *LogQueryRequest, filter string) (string, error) {
+	path, err := resolveOutputFile(req.OutputFile)
+	if err != nil {
+		return "", fmt.Errorf("invalid output_file parameter: %w", err)
+	}
+	formatter, err := formatterForRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to create formatter: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !req.Overwrite {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return "", fmt.Errorf("output file %s already exists; set overwrite to replace it", path)
+		}
+		return "", fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+	var first, last time.Time
+	status, err := iterateLogEntries(ctx, iterCtx, fetch, pageToken, req.Limit, func(entry *loggingpb.LogEntry) error {
+		logLine, err := formatter.format(entry)
+		if err != nil {
+			return fmt.Errorf("failed to format log entry: %w", err)
+		}
+		if _, err := w.WriteString(logLine + "\n"); err != nil {
+			return fmt.Errorf("failed to write log entry: %w", err)
+		}
+		if ts := entry.GetTimestamp(); ts != nil {
+			if first.IsZero() {
+				first = ts.AsTime()
+			}
+			last = ts.AsTime()
+		}
+		return nil
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat output file: %w", err)
+	}
+
+	result := fmt.Sprintf("Project ID: %s\nLQL Query:\n```\n%s\n```\nWrote %d log entries to %s (%d bytes).", req.ProjectID, filter, status.count, path, info.Size())
+	if !first.IsZero() {
+		result += fmt.Sprintf("\nTime span: %s to %s", first.Format(time.RFC3339Nano), last.Format(time.RFC3339Nano))
+	}
+	if status.truncated {
+		result += fmt.Sprintf("\n\nWarning: Results truncated. More log entries than the limit of %d may be available. You can use the `limit` parameter to request more entries (up to %d), or pass the NEXT_PAGE_TOKEN below as `page_token` to fetch the next page.", req.Limit, maxFileLimit)
+	}
+	result += status.partialResultsWarning(req.TimeoutSeconds)
+	result += nextPageFooter(req, filter, status.nextPageToken)
+	return result, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveOutputFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name    string
+		workDir string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "absolute path is accepted as is",
+			path: "/var/data/../logs/out.txt",
+			want: "/var/logs/out.txt",
+		},
+		{
+			name: "tilde expands to home",
+			path: "~/logs/out.txt",
+			want: filepath.Join(home, "logs", "out.txt"),
+		},
+		{
+			name:    "relative path inside home",
+			workDir: home,
+			path:    "logs/out.txt",
+			want:    filepath.Join(home, "logs", "out.txt"),
+		},
+		{
+			name:    "relative path escaping to root",
+			workDir: home,
+			path:    "../../../../../../../../etc/out.txt",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.workDir != "" {
+				t.Chdir(tt.workDir)
+			}
+			got, err := resolveOutputFile(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveOutputFile(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveOutputFile(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteLogEntriesToFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "logs.txt")
+	req := &LogQueryRequest{
+		ProjectID:  "test-project",
+		Limit:      3,
+		Format:     "entry",
+		OutputFile: path,
+	}
+
+	ctx := context.Background()
+	got, err := writeLogEntriesToFile(ctx, ctx, (&fakePageFetcher{n: 5}).fetch, "", req, "severity=ERROR")
+	if err != nil {
+		t.Fatalf("writeLogEntriesToFile() error = %v", err)
+	}
+	if !strings.Contains(got, "Wrote 3 log entries to "+path) {
+		t.Errorf("writeLogEntriesToFile() summary = %q, want it to mention 3 entries written to %s", got, path)
+	}
+	if !strings.Contains(got, "Results truncated") || !strings.Contains(got, "NEXT_PAGE_TOKEN: ") {
+		t.Errorf("writeLogEntriesToFile() summary = %q, want truncation warning and next page token", got)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if want := "entry\nentry\nentry\n"; string(content) != want {
+		t.Errorf("output file content = %q, want %q", content, want)
+	}
+
+	// A second write must not clobber the file unless overwrite is set.
+	if _, err := writeLogEntriesToFile(ctx, ctx, (&fakePageFetcher{n: 1}).fetch, "", req, ""); err == nil {
+		t.Errorf("writeLogEntriesToFile() to existing file succeeded, want error")
+	}
+	req.Overwrite = true
+	if _, err := writeLogEntriesToFile(ctx, ctx, (&fakePageFetcher{n: 1}).fetch, "", req, ""); err != nil {
+		t.Fatalf("writeLogEntriesToFile() with overwrite error = %v", err)
+	}
+	content, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if want := "entry\n"; string(content) != want {
+		t.Errorf("overwritten output file content = %q, want %q", content, want)
+	}
+}
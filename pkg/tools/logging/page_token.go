@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// pageCursor is what query_logs hands out as NEXT_PAGE_TOKEN. Besides the API
+// page token it records the hash of the filter the token was issued for, so
+// that a follow-up with a different query can be rejected with a clear error
+// instead of the API's, and the start time a relative 'since' resolved to, so
+// that the follow-up can reproduce the exact same filter.
+type pageCursor struct {
+	FilterHash string `json:"f"`
+	PageToken  string `json:"t"`
+	SinceStart string `json:"s,omitempty"`
+}
+
+// filterHash returns a short, stable hash of an LQL filter.
+func filterHash(filter string) string {
+	sum := sha256.Sum256([]byte(filter))
+	return hex.EncodeToString(sum[:6])
+}
+
+func (c *pageCursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodePageCursor(token string) (*pageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("malformed page_token, copy it verbatim from the NEXT_PAGE_TOKEN line of the previous response")
+	}
+	var c pageCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.PageToken == "" {
+		return nil, fmt.Errorf("malformed page_token, copy it verbatim from the NEXT_PAGE_TOKEN line of the previous response")
+	}
+	return &c, nil
+}
+
+// applyPageToken decodes r.PageToken and, if the original query used 'since',
+// pins the time range to the start time it resolved to back then. A request
+// without a page token gets an empty cursor.
+func (r *LogQueryRequest) applyPageToken() (*pageCursor, error) {
+	if r.PageToken == "" {
+		return &pageCursor{}, nil
+	}
+	c, err := decodePageCursor(r.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	if c.SinceStart != "" && r.Since != "" {
+		start, err := time.Parse(time.RFC3339, c.SinceStart)
+		if err != nil {
+			return nil, fmt.Errorf("malformed page_token, copy it verbatim from the NEXT_PAGE_TOKEN line of the previous response")
+		}
+		r.TimeRange = &TimeRange{StartTime: start}
+	}
+	return c, nil
+}
+
+// checkFilter returns an error if the cursor was issued for a query with a
+// different filter.
+func (c *pageCursor) checkFilter(filter string) error {
+	if c.FilterHash == "" {
+		return nil
+	}
+	if got := filterHash(filter); got != c.FilterHash {
+		return fmt.Errorf("page_token was issued for a different query (filter hash %s, this query has %s). Repeat the previous call with the same query, time range and exclusions, or drop page_token to start from the beginning", c.FilterHash, got)
+	}
+	return nil
+}
+
+// nextPageFooter returns the machine-readable lines a follow-up call can use to
+// continue from pageToken, or an empty string if there is nothing more to read.
+func nextPageFooter(req *LogQueryRequest, filter, pageToken string) string {
+	if pageToken == "" {
+		return ""
+	}
+	c := &pageCursor{
+		FilterHash: filterHash(filter),
+		PageToken:  pageToken,
+	}
+	if req.Since != "" && req.TimeRange != nil {
+		c.SinceStart = req.TimeRange.StartTime.Format(time.RFC3339)
+	}
+	return fmt.Sprintf("\n\nNEXT_PAGE_TOKEN: %s\nFILTER_HASH: %s", c.encode(), c.FilterHash)
+}
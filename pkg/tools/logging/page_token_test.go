@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// nextPageToken extracts the NEXT_PAGE_TOKEN value from a response footer.
+func nextPageToken(t *testing.T, footer string) string {
+	t.Helper()
+	for _, line := range strings.Split(footer, "\n") {
+		if token, ok := strings.CutPrefix(line, "NEXT_PAGE_TOKEN: "); ok {
+			return token
+		}
+	}
+	t.Fatalf("no NEXT_PAGE_TOKEN line in %q", footer)
+	return ""
+}
+
+func TestNextPageFooter(t *testing.T) {
+	if got := nextPageFooter(&LogQueryRequest{}, "severity=ERROR", ""); got != "" {
+		t.Errorf("nextPageFooter() without a page token = %q, want empty", got)
+	}
+
+	got := nextPageFooter(&LogQueryRequest{}, "severity=ERROR", "api-token")
+	if !strings.Contains(got, "\nFILTER_HASH: "+filterHash("severity=ERROR")) {
+		t.Errorf("nextPageFooter() = %q, want it to contain the filter hash", got)
+	}
+	c, err := decodePageCursor(nextPageToken(t, got))
+	if err != nil {
+		t.Fatalf("decodePageCursor() error = %v", err)
+	}
+	if c.PageToken != "api-token" {
+		t.Errorf("decoded page token = %q, want %q", c.PageToken, "api-token")
+	}
+}
+
+func TestPageTokenFollowUp(t *testing.T) {
+	first := &LogQueryRequest{
+		ProjectID: "test-project",
+		Query:     "severity=ERROR",
+		Since:     "1h",
+	}
+	firstFilter := buildListLogEntriesRequest(first).Filter
+	token := nextPageToken(t, nextPageFooter(first, firstFilter, "api-token"))
+
+	tests := []struct {
+		name    string
+		req     *LogQueryRequest
+		wantErr bool
+	}{
+		{
+			name: "identical query reproduces the filter despite since",
+			req: &LogQueryRequest{
+				ProjectID: "test-project",
+				Query:     "severity=ERROR",
+				Since:     "1h",
+				PageToken: token,
+			},
+		},
+		{
+			name: "different query is rejected",
+			req: &LogQueryRequest{
+				ProjectID: "test-project",
+				Query:     "severity=WARNING",
+				Since:     "1h",
+				PageToken: token,
+			},
+			wantErr: true,
+		},
+		{
+			name: "added exclusion is rejected",
+			req: &LogQueryRequest{
+				ProjectID: "test-project",
+				Query:     "severity=ERROR",
+				Since:     "1h",
+				Exclude:   []string{"severity=DEBUG"},
+				PageToken: token,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Make sure 'since' would resolve to a different start time.
+			time.Sleep(time.Millisecond)
+			c, err := tt.req.applyPageToken()
+			if err != nil {
+				t.Fatalf("applyPageToken() error = %v", err)
+			}
+			if c.PageToken != "api-token" {
+				t.Errorf("applyPageToken() page token = %q, want %q", c.PageToken, "api-token")
+			}
+			err = c.checkFilter(buildListLogEntriesRequest(tt.req).Filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodePageCursorRejectsGarbage(t *testing.T) {
+	for _, token := range []string{"not a token!", "bm90IGpzb24", "e30"} {
+		if _, err := decodePageCursor(token); err == nil {
+			t.Errorf("decodePageCursor(%q) succeeded, want error", token)
+		}
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -25,20 +27,26 @@ import (
 	logging "cloud.google.com/go/logging/apiv2"
 	"cloud.google.com/go/logging/apiv2/loggingpb"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/api/iterator"
-	"google.golang.org/api/option"
 	_ "google.golang.org/genproto/googleapis/cloud/audit" // Import for AuditLog proto so we can convert to JSON.
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 type LogQueryRequest struct {
-	Query     string     `json:"query" jsonschema:"LQL query string to filter and retrieve log entries. Don't specify time ranges in this filter. Use 'time_range' instead."`
-	ProjectID string     `json:"project_id" jsonschema:"GCP project ID to query logs from. Required."`
-	TimeRange *TimeRange `json:"time_range,omitempty" jsonschema:"Time range for log query. If empty, no restrictions are applied."`
-	Since     string     `json:"since,omitempty" jsonschema:"Only return logs newer than a relative duration like 5s, 2m, or 3h. The only supported units are seconds ('s'), minutes ('m'), and hours ('h')."`
-	Limit     int        `json:"limit,omitempty" jsonschema:"Maximum number of log entries to return. Cannot be greater than 100. Consider multiple calls if needed. Defaults to 10."`
-	Format    string     `json:"format,omitempty" jsonschema:"Go template string to format each log entry. If empty, the full JSON representation is returned. Note that empty fields are not included in the response. Example: '{{.timestamp}} [{{.severity}}] {{.textPayload}}'. It's strongly recommended to use a template to minimize the size of the response and only include the fields you need. Use the get_schema tool before this tool to get information about supported log types and their schemas."`
+	Query          string     `json:"query" jsonschema:"LQL query string to filter and retrieve log entries. Don't specify time ranges in this filter. Use 'time_range' instead."`
+	ProjectID      string     `json:"project_id" jsonschema:"GCP project ID to query logs from. Required."`
+	TimeRange      *TimeRange `json:"time_range,omitempty" jsonschema:"Time range for log query. If empty, no restrictions are applied."`
+	Since          string     `json:"since,omitempty" jsonschema:"Only return logs newer than a relative duration like 5s, 2m, or 3h. The only supported units are seconds ('s'), minutes ('m'), and hours ('h')."`
+	Limit          int        `json:"limit,omitempty" jsonschema:"Maximum number of log entries to return. Cannot be greater than 100, unless the server configuration sets another maximum, or 10000 when output_file is set. Consider multiple calls if needed. Defaults to 10."`
+	Format         string     `json:"format,omitempty" jsonschema:"Go template string to format each log entry. If empty, the full JSON representation is returned. Note that empty fields are not included in the response. Example: '{{.timestamp}} [{{.severity}}] {{.textPayload}}'. It's strongly recommended to use a template to minimize the size of the response and only include the fields you need. Use the get_schema tool before this tool to get information about supported log types and their schemas."`
+	TimeoutSeconds int        `json:"timeout_seconds,omitempty" jsonschema:"Maximum time in seconds to spend fetching log entries. Cannot be greater than 120. Defaults to 30. If the query times out, the entries fetched so far are returned."`
+	OutputFile     string     `json:"output_file,omitempty" jsonschema:"Local file path to write the formatted log entries to instead of returning them. Use this when many entries are needed for offline analysis; only a summary is returned. Relative paths must resolve inside the user's home or temp directory. Not available in read-only mode."`
+	Overwrite      bool       `json:"overwrite,omitempty" jsonschema:"Overwrite output_file if it already exists. Defaults to false."`
+	Exclude        []string   `json:"exclude,omitempty" jsonschema:"Log entries to leave out of the results. Each item is either a simple field=value pair like 'resource.labels.container_name=istio-proxy' or a free-form LQL clause like 'httpRequest.requestUrl:\"/healthz\"'. Each item is negated and AND-ed onto the query, so do not add NOT yourself."`
+	PageToken      string     `json:"page_token,omitempty" jsonschema:"Token to continue a previous query from, copied verbatim from the NEXT_PAGE_TOKEN line of its response. The query, time range or since, and exclude arguments must be identical to the previous call."`
 }
 
 type TimeRange struct {
@@ -47,8 +55,11 @@ type TimeRange struct {
 }
 
 const (
-	defaultLimit = 10
-	maxLimit     = 100
+	defaultLimit          = 10
+	maxLimit              = 100
+	maxFileLimit          = 10000
+	defaultTimeoutSeconds = 30
+	maxTimeoutSeconds     = 120
 )
 
 func installQueryLogsTool(s *mcp.Server, conf *config.Config) {
@@ -58,7 +69,9 @@ func installQueryLogsTool(s *mcp.Server, conf *config.Config) {
 		Name:        "query_logs",
 		Description: "Query Google Cloud Platform logs using Logging Query Language (LQL). Before using this tool, it's **strongly** recommended to call the 'get_log_schema' tool to get information about supported log types and their schemas. Logs are returned in ascending order, based on the timestamp (i.e. oldest first).",
 		Annotations: &mcp.ToolAnnotations{
-			ReadOnlyHint: true,
+			// output_file can create and overwrite local files, so the
+			// tool is only read-only when that is rejected.
+			ReadOnlyHint: conf.ReadOnly(),
 		},
 	}, t.queryLogs)
 }
@@ -74,8 +87,12 @@ func newQueryLogsTool(conf *config.Config) *queryLogsTool {
 }
 
 func (t *queryLogsTool) queryLogs(ctx context.Context, _ *mcp.CallToolRequest, req *LogQueryRequest) (*mcp.CallToolResult, any, error) {
+	// Writing to output_file creates directories and can overwrite files.
+	if req.OutputFile != "" && t.conf.ReadOnly() {
+		return nil, nil, fmt.Errorf("output_file parameter cannot be used in read-only mode")
+	}
 	req.setDefaults()
-	if err := req.validate(); err != nil {
+	if err := req.validate(t.maxLimit()); err != nil {
 		return nil, nil, err
 	}
 	result, err := t.queryGCPLogs(ctx, req)
@@ -90,19 +107,45 @@ func (t *queryLogsTool) queryLogs(ctx context.Context, _ *mcp.CallToolRequest, r
 	}, nil, nil
 }
 
+// maxLimit returns the largest limit without output_file, max_log_limit of the
+// configuration file if set.
+func (t *queryLogsTool) maxLimit() int {
+	if limit := t.conf.MaxLogLimit(); limit > 0 {
+		return limit
+	}
+	return maxLimit
+}
+
 func (r *LogQueryRequest) setDefaults() {
 	if r.Limit == 0 {
 		r.Limit = defaultLimit
 	}
+	if r.TimeoutSeconds == 0 {
+		r.TimeoutSeconds = defaultTimeoutSeconds
+	}
 }
 
-func (r *LogQueryRequest) validate() error {
+func (r *LogQueryRequest) validate(maxLimit int) error {
 	if r.ProjectID == "" {
 		return fmt.Errorf("project_id parameter is required")
 	}
-	if r.Limit > maxLimit {
+	if r.OutputFile == "" && r.Limit > maxLimit {
 		return fmt.Errorf("limit parameter cannot be greater than %d", maxLimit)
 	}
+	if r.OutputFile != "" {
+		if r.Limit > maxFileLimit {
+			return fmt.Errorf("limit parameter cannot be greater than %d when output_file is set", maxFileLimit)
+		}
+		if _, err := resolveOutputFile(r.OutputFile); err != nil {
+			return fmt.Errorf("invalid output_file parameter: %w", err)
+		}
+	}
+	if r.TimeoutSeconds < 0 {
+		return fmt.Errorf("timeout_seconds parameter cannot be negative")
+	}
+	if r.TimeoutSeconds > maxTimeoutSeconds {
+		return fmt.Errorf("timeout_seconds parameter cannot be greater than %d", maxTimeoutSeconds)
+	}
 	if r.Since != "" {
 		if _, err := time.ParseDuration(r.Since); err != nil {
 			return fmt.Errorf("invalid since parameter: %w", err)
@@ -111,6 +154,16 @@ func (r *LogQueryRequest) validate() error {
 	if r.TimeRange != nil && r.Since != "" {
 		return fmt.Errorf("since parameter cannot be used with time_range")
 	}
+	if r.PageToken != "" {
+		if _, err := decodePageCursor(r.PageToken); err != nil {
+			return err
+		}
+	}
+	for _, e := range r.Exclude {
+		if strings.TrimSpace(e) == "" {
+			return fmt.Errorf("exclude parameter cannot contain empty items")
+		}
+	}
 	if r.Format != "" {
 		var err error
 		_, err = template.New("log").Parse(r.Format)
@@ -122,36 +175,32 @@ func (r *LogQueryRequest) validate() error {
 }
 
 func (t *queryLogsTool) queryGCPLogs(ctx context.Context, req *LogQueryRequest) (string, error) {
-	client, err := logging.NewClient(ctx, option.WithUserAgent(t.conf.UserAgent()))
+	client, err := logging.NewClient(ctx, t.conf.ClientOptions()...)
 	if err != nil {
 		return "", fmt.Errorf("failed to create logging client: %v", err)
 	}
 	defer client.Close()
 
+	cursor, err := req.applyPageToken()
+	if err != nil {
+		return "", err
+	}
 	listLogsReq := buildListLogEntriesRequest(req)
-	// Request one more than the limit to check for truncation.
-	listLogsReq.PageSize = int32(req.Limit + 1)
+	if err := cursor.checkFilter(listLogsReq.Filter); err != nil {
+		return "", err
+	}
 
-	resp := client.ListLogEntries(ctx, listLogsReq)
+	iterCtx, cancel := context.WithTimeout(ctx, time.Duration(req.TimeoutSeconds)*time.Second)
+	defer cancel()
 
-	var entries []*loggingpb.LogEntry
-	for {
-		entry, err := resp.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return "", fmt.Errorf("failed to iterate log entries: %v", err)
-		}
-		entries = append(entries, entry)
-		if len(entries) > req.Limit {
-			break
-		}
+	fetch := newLogEntryPageFetcher(client, listLogsReq)
+	if req.OutputFile != "" {
+		return writeLogEntriesToFile(ctx, iterCtx, fetch, cursor.PageToken, req, listLogsReq.Filter)
 	}
 
-	truncated := len(entries) > req.Limit
-	if truncated {
-		entries = entries[:req.Limit]
+	entries, status, err := collectLogEntries(ctx, iterCtx, fetch, cursor.PageToken, req.Limit)
+	if err != nil {
+		return "", err
 	}
 
 	allLogLines := strings.Builder{}
@@ -176,17 +225,173 @@ func (t *queryLogsTool) queryGCPLogs(ctx context.Context, req *LogQueryRequest)
 	}
 
 	result := fmt.Sprintf("Project ID: %s\nLQL Query:\n```\n%s\n```\nResult:\n\n%s", req.ProjectID, listLogsReq.Filter, allLogLines.String())
-	if truncated {
-		result += fmt.Sprintf("\n\nWarning: Results truncated. The query returned more than the limit of %d log entries. You can use the `limit` parameter to request more entries (up to %d).", req.Limit, maxLimit)
+	if status.truncated {
+		result += fmt.Sprintf("\n\nWarning: Results truncated. More log entries than the limit of %d may be available. You can use the `limit` parameter to request more entries (up to %d), or pass the NEXT_PAGE_TOKEN below as `page_token` to fetch the next page.", req.Limit, t.maxLimit())
 	}
+	result += status.partialResultsWarning(req.TimeoutSeconds)
+	result += nextPageFooter(req, listLogsReq.Filter, status.nextPageToken)
 
 	return result, nil
 }
 
+// maxPageSize is the largest page size accepted by entries.list.
+const maxPageSize = 1000
+
+// logEntryPageFetcher fetches up to pageSize entries starting at pageToken and
+// returns them along with the token of the following page, which is empty when
+// there are no more entries.
+type logEntryPageFetcher func(ctx context.Context, pageSize int, pageToken string) ([]*loggingpb.LogEntry, string, error)
+
+// newLogEntryPageFetcher returns a fetcher listing the entries matching req.
+// Pages that fail to load with a retryable error are retried.
+func newLogEntryPageFetcher(client *logging.Client, req *loggingpb.ListLogEntriesRequest) logEntryPageFetcher {
+	return func(ctx context.Context, pageSize int, pageToken string) ([]*loggingpb.LogEntry, string, error) {
+		var entries []*loggingpb.LogEntry
+		var nextPageToken string
+		err := retry.Do(ctx, retry.DefaultPolicy, func() error {
+			r := proto.Clone(req).(*loggingpb.ListLogEntriesRequest)
+			r.PageSize = int32(pageSize)
+			entries = nil
+			var err error
+			nextPageToken, err = iterator.NewPager(client.ListLogEntries(ctx, r), pageSize, pageToken).NextPage(&entries)
+			return err
+		})
+		return entries, nextPageToken, err
+	}
+}
+
+// iterationStatus describes how an iteration over log entries ended.
+type iterationStatus struct {
+	// truncated is set when the limit was reached and more entries may be
+	// available.
+	truncated bool
+	// timedOut is set when the iteration deadline expired before all entries
+	// were read.
+	timedOut bool
+	// err is set when the iteration was cut short by an API error that
+	// persisted through retries.
+	err error
+	// count is the number of entries handled.
+	count int
+	// nextPageToken is the token to resume the iteration from, if it didn't
+	// reach the end of the results.
+	nextPageToken string
+}
+
+// partialResultsWarning returns the warnings to append to a response when the
+// iteration didn't complete, or an empty string.
+func (s iterationStatus) partialResultsWarning(timeoutSeconds int) string {
+	var warning string
+	if s.timedOut {
+		warning += fmt.Sprintf("\n\nWarning: Partial results: query timed out after %d seconds. Consider narrowing the query or the time range, or increasing `timeout_seconds` (up to %d).", timeoutSeconds, maxTimeoutSeconds)
+	}
+	if s.err != nil {
+		warning += fmt.Sprintf("\n\nWarning: Partial results: fetching log entries failed after %d entries were retrieved and retries were exhausted: %v", s.count, s.err)
+	}
+	return warning
+}
+
+// iterateLogEntries calls fn for up to limit entries fetched page by page,
+// starting at pageToken. ctx is the caller's context and iterCtx the (possibly
+// shorter) context the pages are fetched with. If iterCtx expires, or a page
+// keeps failing with a retryable error, the iteration stops without an error
+// so the entries handled so far can still be used, and the returned status says
+// why. Cancellation of ctx and non-retryable API errors are reported as errors.
+func iterateLogEntries(ctx, iterCtx context.Context, fetch logEntryPageFetcher, pageToken string, limit int, fn func(*loggingpb.LogEntry) error) (iterationStatus, error) {
+	var status iterationStatus
+	for status.count < limit {
+		if err := ctx.Err(); err != nil {
+			return status, fmt.Errorf("log query cancelled: %w", err)
+		}
+		status.nextPageToken = pageToken
+		if iterCtx.Err() != nil {
+			status.timedOut = true
+			return status, nil
+		}
+		entries, nextPageToken, err := fetch(iterCtx, min(limit-status.count, maxPageSize), pageToken)
+		if err != nil {
+			if ctx.Err() != nil {
+				return status, fmt.Errorf("log query cancelled: %w", ctx.Err())
+			}
+			if iterCtx.Err() != nil {
+				status.timedOut = true
+				return status, nil
+			}
+			if retry.IsRetryable(err) {
+				status.err = err
+				return status, nil
+			}
+			return status, fmt.Errorf("failed to iterate log entries: %v", err)
+		}
+		for _, entry := range entries {
+			if err := fn(entry); err != nil {
+				return status, err
+			}
+			status.count++
+		}
+		pageToken = nextPageToken
+		status.nextPageToken = pageToken
+		if pageToken == "" {
+			return status, nil
+		}
+	}
+	status.truncated = true
+	return status, nil
+}
+
+// collectLogEntries reads up to limit entries into memory. See
+// iterateLogEntries for how the iteration can end.
+func collectLogEntries(ctx, iterCtx context.Context, fetch logEntryPageFetcher, pageToken string, limit int) ([]*loggingpb.LogEntry, iterationStatus, error) {
+	var entries []*loggingpb.LogEntry
+	status, err := iterateLogEntries(ctx, iterCtx, fetch, pageToken, limit, func(entry *loggingpb.LogEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, status, err
+	}
+	return entries, status, nil
+}
+
+// simpleExclusionRegexp matches a field=value pair whose value is either
+// unquoted or a single quoted string.
+var simpleExclusionRegexp = regexp.MustCompile(`^\s*([\w.\-/\[\]"]+?)\s*=\s*("[^"]*"|[^\s"=<>!:~()]+)\s*$`)
+
+// exclusionClause turns an exclude item into a negated LQL clause. Simple
+// field=value pairs get their value quoted; anything else is used verbatim.
+// The clause is always parenthesized so that it can't bind to its neighbors.
+func exclusionClause(exclude string) string {
+	clause := strings.TrimSpace(exclude)
+	if m := simpleExclusionRegexp.FindStringSubmatch(clause); m != nil {
+		value := m[2]
+		if !strings.HasPrefix(value, `"`) {
+			value = strconv.Quote(value)
+		}
+		clause = fmt.Sprintf("%s=%s", m[1], value)
+	}
+	return fmt.Sprintf("NOT (%s)", clause)
+}
+
+// applyExclusions AND-s the negated exclude items onto filter. The original
+// filter is parenthesized so exclusions narrow it as a whole.
+func applyExclusions(filter string, exclude []string) string {
+	if len(exclude) == 0 {
+		return filter
+	}
+	var clauses []string
+	if strings.TrimSpace(filter) != "" {
+		clauses = append(clauses, fmt.Sprintf("(%s)", filter))
+	}
+	for _, e := range exclude {
+		clauses = append(clauses, exclusionClause(e))
+	}
+	return strings.Join(clauses, " AND ")
+}
+
 func buildListLogEntriesRequest(req *LogQueryRequest) *loggingpb.ListLogEntriesRequest {
-	filter := req.Query
+	filter := applyExclusions(req.Query, req.Exclude)
 
-	if req.Since != "" {
+	if req.Since != "" && req.TimeRange == nil {
 		since, err := time.ParseDuration(req.Since)
 		if err != nil {
 			return nil
@@ -15,13 +15,21 @@
 package logging
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
 	"cloud.google.com/go/logging/apiv2/loggingpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
 	"github.com/google/go-cmp/cmp"
 	ltype "google.golang.org/genproto/googleapis/logging/type"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -73,6 +81,48 @@ func TestLogQueryRequest_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "limit above 100 with output file",
+			req: LogQueryRequest{
+				ProjectID:  "test-project",
+				Limit:      5000,
+				OutputFile: "/tmp/logs.txt",
+			},
+			wantErr: false,
+		},
+		{
+			name: "limit too high with output file",
+			req: LogQueryRequest{
+				ProjectID:  "test-project",
+				Limit:      10001,
+				OutputFile: "/tmp/logs.txt",
+			},
+			wantErr: true,
+		},
+		{
+			name: "timeout too high",
+			req: LogQueryRequest{
+				ProjectID:      "test-project",
+				TimeoutSeconds: 121,
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative timeout",
+			req: LogQueryRequest{
+				ProjectID:      "test-project",
+				TimeoutSeconds: -1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty exclude item",
+			req: LogQueryRequest{
+				ProjectID: "test-project",
+				Exclude:   []string{" "},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid format template",
 			req: LogQueryRequest{
@@ -85,13 +135,192 @@ func TestLogQueryRequest_Validate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := tt.req.validate(); (err != nil) != tt.wantErr {
+			if err := tt.req.validate(maxLimit); (err != nil) != tt.wantErr {
 				t.Errorf("LogQueryRequest.validate() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func TestQueryLogsMaxLimit(t *testing.T) {
+	if got := newQueryLogsTool(&config.Config{}).maxLimit(); got != maxLimit {
+		t.Errorf("maxLimit() without max_log_limit = %d, want %d", got, maxLimit)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("max_log_limit: 500\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	conf, err := config.New("test", path)
+	if err != nil {
+		t.Fatalf("config.New() failed: %v", err)
+	}
+	tool := newQueryLogsTool(conf)
+	if got := tool.maxLimit(); got != 500 {
+		t.Errorf("maxLimit() with max_log_limit = %d, want 500", got)
+	}
+	req := &LogQueryRequest{ProjectID: "test-project", Limit: 500}
+	if err := req.validate(tool.maxLimit()); err != nil {
+		t.Errorf("validate() of a limit of max_log_limit failed: %v", err)
+	}
+	req.Limit = 501
+	if err := req.validate(tool.maxLimit()); err == nil {
+		t.Errorf("validate() of a limit over max_log_limit succeeded, want an error")
+	}
+}
+
+// fakePageFetcher serves n entries in pages of at most 3 entries, using the
+// offset of the first entry of a page as its token. Once the entries are
+// exhausted, err is returned if set, after calling cancel if that is set too.
+type fakePageFetcher struct {
+	n      int
+	cancel context.CancelFunc
+	err    error
+}
+
+func (f *fakePageFetcher) fetch(_ context.Context, pageSize int, pageToken string) ([]*loggingpb.LogEntry, string, error) {
+	offset := 0
+	if pageToken != "" {
+		offset, _ = strconv.Atoi(pageToken)
+	}
+	if offset >= f.n {
+		if f.cancel != nil {
+			f.cancel()
+		}
+		return nil, "", f.err
+	}
+	size := min(pageSize, 3, f.n-offset)
+	entries := make([]*loggingpb.LogEntry, size)
+	for i := range entries {
+		entries[i] = &loggingpb.LogEntry{InsertId: strconv.Itoa(offset + i)}
+	}
+	next := offset + size
+	if next >= f.n && f.err == nil {
+		return entries, "", nil
+	}
+	return entries, strconv.Itoa(next), nil
+}
+
+func TestCollectLogEntries(t *testing.T) {
+	tests := []struct {
+		name           string
+		entries        int
+		pageToken      string
+		limit          int
+		cancelIter     bool
+		cancelParent   bool
+		iterErr        error
+		wantEntries    int
+		wantTruncated  bool
+		wantTimedOut   bool
+		wantPartialErr bool
+		wantNextToken  string
+		wantErr        bool
+	}{
+		{
+			name:        "fewer entries than limit",
+			entries:     3,
+			limit:       10,
+			wantEntries: 3,
+		},
+		{
+			name:          "stops at limit",
+			entries:       20,
+			limit:         10,
+			wantEntries:   10,
+			wantTruncated: true,
+			wantNextToken: "10",
+		},
+		{
+			name:        "exactly limit entries",
+			entries:     10,
+			limit:       10,
+			wantEntries: 10,
+		},
+		{
+			name:        "resumes from page token",
+			entries:     8,
+			pageToken:   "5",
+			limit:       10,
+			wantEntries: 3,
+		},
+		{
+			name:          "iteration deadline returns partial results",
+			entries:       4,
+			limit:         10,
+			cancelIter:    true,
+			iterErr:       context.DeadlineExceeded,
+			wantEntries:   4,
+			wantTimedOut:  true,
+			wantNextToken: "4",
+		},
+		{
+			name:         "parent cancellation is an error",
+			entries:      4,
+			limit:        10,
+			cancelParent: true,
+			iterErr:      context.Canceled,
+			wantErr:      true,
+		},
+		{
+			name:           "exhausted retries return partial results",
+			entries:        2,
+			limit:          10,
+			iterErr:        status.Error(codes.ResourceExhausted, "quota exceeded"),
+			wantEntries:    2,
+			wantPartialErr: true,
+			wantNextToken:  "2",
+		},
+		{
+			name:    "iterator error",
+			entries: 2,
+			limit:   10,
+			iterErr: errors.New("boom"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancelParent := context.WithCancel(context.Background())
+			defer cancelParent()
+			iterCtx, cancelIter := context.WithCancel(ctx)
+			defer cancelIter()
+
+			f := &fakePageFetcher{n: tt.entries, err: tt.iterErr}
+			if tt.cancelIter {
+				f.cancel = cancelIter
+			}
+			if tt.cancelParent {
+				f.cancel = cancelParent
+			}
+
+			entries, st, err := collectLogEntries(ctx, iterCtx, f.fetch, tt.pageToken, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("collectLogEntries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(entries) != tt.wantEntries {
+				t.Errorf("collectLogEntries() returned %d entries, want %d", len(entries), tt.wantEntries)
+			}
+			if st.truncated != tt.wantTruncated {
+				t.Errorf("collectLogEntries() truncated = %v, want %v", st.truncated, tt.wantTruncated)
+			}
+			if st.timedOut != tt.wantTimedOut {
+				t.Errorf("collectLogEntries() timedOut = %v, want %v", st.timedOut, tt.wantTimedOut)
+			}
+			if (st.err != nil) != tt.wantPartialErr {
+				t.Errorf("collectLogEntries() partial error = %v, want %v", st.err, tt.wantPartialErr)
+			}
+			if st.nextPageToken != tt.wantNextToken {
+				t.Errorf("collectLogEntries() nextPageToken = %q, want %q", st.nextPageToken, tt.wantNextToken)
+			}
+		})
+	}
+}
+
 func TestBuildListLogEntriesRequest(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
@@ -131,6 +360,24 @@ func TestBuildListLogEntriesRequest(t *testing.T) {
 				OrderBy:       "timestamp asc",
 			},
 		},
+		{
+			name: "request with exclusions and time range",
+			req: LogQueryRequest{
+				ProjectID: "test-project",
+				Query:     "severity=ERROR OR severity=WARNING",
+				Exclude:   []string{"resource.labels.container_name=istio-proxy"},
+				Limit:     10,
+				TimeRange: &TimeRange{
+					StartTime: now.Add(-1 * time.Hour),
+				},
+			},
+			want: &loggingpb.ListLogEntriesRequest{
+				ResourceNames: []string{"projects/test-project"},
+				Filter:        `(severity=ERROR OR severity=WARNING) AND NOT (resource.labels.container_name="istio-proxy") AND timestamp >= "` + now.Add(-1*time.Hour).Format(time.RFC3339) + `"`,
+				PageSize:      10,
+				OrderBy:       "timestamp asc",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -143,6 +390,58 @@ func TestBuildListLogEntriesRequest(t *testing.T) {
 	}
 }
 
+func TestApplyExclusions(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		exclude []string
+		want    string
+	}{
+		{
+			name:   "no exclusions leaves filter untouched",
+			filter: `severity=ERROR OR severity=WARNING`,
+			want:   `severity=ERROR OR severity=WARNING`,
+		},
+		{
+			name:    "simple pair value gets quoted",
+			filter:  `resource.type="k8s_container"`,
+			exclude: []string{"resource.labels.container_name=istio-proxy"},
+			want:    `(resource.type="k8s_container") AND NOT (resource.labels.container_name="istio-proxy")`,
+		},
+		{
+			name:    "quoted simple pair is kept",
+			filter:  `resource.type="k8s_container"`,
+			exclude: []string{`labels."k8s-pod/app" = "probe"`},
+			want:    `(resource.type="k8s_container") AND NOT (labels."k8s-pod/app"="probe")`,
+		},
+		{
+			name:    "disjunctive query is grouped before narrowing",
+			filter:  `severity=ERROR OR severity=WARNING`,
+			exclude: []string{"resource.labels.namespace_name=kube-system"},
+			want:    `(severity=ERROR OR severity=WARNING) AND NOT (resource.labels.namespace_name="kube-system")`,
+		},
+		{
+			name:    "free-form disjunctive exclusion is negated as a whole",
+			filter:  `severity>=ERROR`,
+			exclude: []string{`httpRequest.requestUrl:"/healthz" OR httpRequest.requestUrl:"/readyz"`},
+			want:    `(severity>=ERROR) AND NOT (httpRequest.requestUrl:"/healthz" OR httpRequest.requestUrl:"/readyz")`,
+		},
+		{
+			name:    "multiple exclusions without a query",
+			exclude: []string{"severity=DEBUG", `textPayload=~"GET /healthz"`},
+			want:    `NOT (severity="DEBUG") AND NOT (textPayload=~"GET /healthz")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applyExclusions(tt.filter, tt.exclude); got != tt.want {
+				t.Errorf("applyExclusions() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFormatter(t *testing.T) {
 	entry := &loggingpb.LogEntry{
 		Payload: &loggingpb.LogEntry_TextPayload{
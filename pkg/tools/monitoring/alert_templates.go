@@ -0,0 +1,289 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type createGKEAlertPolicyArgs struct {
+	ProjectID            string   `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Template             string   `json:"template" jsonschema:"Alert template to create. One of: node-not-ready, pod-crashloop, pv-usage-high, container-oom-kill."`
+	ClusterName          string   `json:"cluster_name" jsonschema:"Name of the GKE cluster the alert policy watches."`
+	Threshold            float64  `json:"threshold,omitempty" jsonschema:"Template threshold: number of not ready nodes for node-not-ready (default 1), container restarts within duration for pod-crashloop (default 3), volume utilization ratio between 0 and 1 for pv-usage-high (default 0.9). Not used by container-oom-kill."`
+	Duration             string   `json:"duration,omitempty" jsonschema:"Template duration as a Go duration: how long the condition must hold for node-not-ready (default 5m) and pv-usage-high (default 5m), the restart counting window for pod-crashloop (default 10m), and the minimum time between notifications for container-oom-kill (default 5m)."`
+	NotificationChannels []string `json:"notification_channels,omitempty" jsonschema:"Notification channel IDs or full resource names (projects/PROJECT/notificationChannels/ID) to notify."`
+	Replace              bool     `json:"replace,omitempty" jsonschema:"Replace an existing alert policy with the same display name instead of failing."`
+}
+
+// alertTemplate is an opinionated GKE alert policy.
+type alertTemplate struct {
+	description      string
+	defaultThreshold float64
+	defaultDuration  time.Duration
+	// validate checks the threshold, it is nil when the template has none.
+	validate  func(threshold float64) error
+	condition func(cluster string, threshold float64, duration time.Duration) *monitoringpb.AlertPolicy_Condition
+	// logBased templates need a notification rate limit instead of a
+	// duration.
+	logBased bool
+}
+
+var alertTemplates = map[string]alertTemplate{
+	"node-not-ready": {
+		description:      "Nodes of the cluster are not Ready. Requires kube state metrics to be enabled on the cluster.",
+		defaultThreshold: 1,
+		defaultDuration:  5 * time.Minute,
+		validate:         positiveThreshold,
+		condition: func(cluster string, threshold float64, duration time.Duration) *monitoringpb.AlertPolicy_Condition {
+			return &monitoringpb.AlertPolicy_Condition{
+				DisplayName: "Nodes not Ready",
+				Condition: &monitoringpb.AlertPolicy_Condition_ConditionPrometheusQueryLanguage{
+					ConditionPrometheusQueryLanguage: &monitoringpb.AlertPolicy_Condition_PrometheusQueryLanguageCondition{
+						Query:              fmt.Sprintf(`count(kube_node_status_condition{cluster=%s,condition="Ready",status="true"} == 0) >= %s`, strconv.Quote(cluster), formatThreshold(threshold)),
+						Duration:           durationpb.New(duration),
+						EvaluationInterval: durationpb.New(time.Minute),
+					},
+				},
+			}
+		},
+	},
+	"pod-crashloop": {
+		description:      "Containers of the cluster restart repeatedly, e.g. because they are in CrashLoopBackOff.",
+		defaultThreshold: 3,
+		defaultDuration:  10 * time.Minute,
+		validate:         positiveThreshold,
+		condition: func(cluster string, threshold float64, duration time.Duration) *monitoringpb.AlertPolicy_Condition {
+			return &monitoringpb.AlertPolicy_Condition{
+				DisplayName: "Container restarts",
+				Condition: &monitoringpb.AlertPolicy_Condition_ConditionThreshold{
+					ConditionThreshold: &monitoringpb.AlertPolicy_Condition_MetricThreshold{
+						Filter: fmt.Sprintf(`metric.type="kubernetes.io/container/restart_count" AND resource.type="k8s_container" AND resource.label.cluster_name=%s`, strconv.Quote(cluster)),
+						Aggregations: []*monitoringpb.Aggregation{{
+							AlignmentPeriod:  durationpb.New(duration),
+							PerSeriesAligner: monitoringpb.Aggregation_ALIGN_DELTA,
+						}},
+						Comparison:     monitoringpb.ComparisonType_COMPARISON_GE,
+						ThresholdValue: threshold,
+						Duration:       durationpb.New(0),
+					},
+				},
+			}
+		},
+	},
+	"pv-usage-high": {
+		description:      "Persistent volumes mounted by pods of the cluster are almost full.",
+		defaultThreshold: 0.9,
+		defaultDuration:  5 * time.Minute,
+		validate: func(threshold float64) error {
+			if threshold <= 0 || threshold > 1 {
+				return fmt.Errorf("threshold for pv-usage-high is a utilization ratio and must be in (0, 1]")
+			}
+			return nil
+		},
+		condition: func(cluster string, threshold float64, duration time.Duration) *monitoringpb.AlertPolicy_Condition {
+			return &monitoringpb.AlertPolicy_Condition{
+				DisplayName: "Volume utilization",
+				Condition: &monitoringpb.AlertPolicy_Condition_ConditionThreshold{
+					ConditionThreshold: &monitoringpb.AlertPolicy_Condition_MetricThreshold{
+						Filter: fmt.Sprintf(`metric.type="kubernetes.io/pod/volume/utilization" AND resource.type="k8s_pod" AND resource.label.cluster_name=%s`, strconv.Quote(cluster)),
+						Aggregations: []*monitoringpb.Aggregation{{
+							AlignmentPeriod:  durationpb.New(time.Minute),
+							PerSeriesAligner: monitoringpb.Aggregation_ALIGN_MEAN,
+						}},
+						Comparison:     monitoringpb.ComparisonType_COMPARISON_GT,
+						ThresholdValue: threshold,
+						Duration:       durationpb.New(duration),
+					},
+				},
+			}
+		},
+	},
+	"container-oom-kill": {
+		description:     "The kernel OOM killer terminated processes of containers on nodes of the cluster.",
+		defaultDuration: 5 * time.Minute,
+		logBased:        true,
+		condition: func(cluster string, _ float64, _ time.Duration) *monitoringpb.AlertPolicy_Condition {
+			return &monitoringpb.AlertPolicy_Condition{
+				DisplayName: "OOM kills",
+				Condition: &monitoringpb.AlertPolicy_Condition_ConditionMatchedLog{
+					ConditionMatchedLog: &monitoringpb.AlertPolicy_Condition_LogMatch{
+						Filter: fmt.Sprintf(`resource.type="k8s_node" AND resource.labels.cluster_name=%s AND log_id("events") AND jsonPayload.reason="OOMKilling"`, strconv.Quote(cluster)),
+					},
+				},
+			}
+		},
+	},
+}
+
+func positiveThreshold(threshold float64) error {
+	if threshold <= 0 {
+		return fmt.Errorf("threshold must be positive")
+	}
+	return nil
+}
+
+func formatThreshold(threshold float64) string {
+	return strconv.FormatFloat(threshold, 'g', -1, 64)
+}
+
+func installCreateGKEAlertPolicyTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "create_gke_alert_policy",
+		Description: "Create a Cloud Monitoring alert policy for a GKE cluster from a template: node-not-ready, pod-crashloop, pv-usage-high or container-oom-kill. Fails if a policy with the same name exists unless replace is set. Confirm with the user before calling this tool.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: false,
+		},
+	}, h.createGKEAlertPolicy)
+}
+
+func (h *handlers) createGKEAlertPolicy(ctx context.Context, _ *mcp.CallToolRequest, args *createGKEAlertPolicyArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.ProjectID == "" {
+		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
+	}
+	policy, err := buildAlertPolicy(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := monitoring.NewAlertPolicyClient(ctx, h.c.ClientOptions()...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.Close()
+
+	existing, err := c.ListAlertPolicies(ctx, &monitoringpb.ListAlertPoliciesRequest{
+		Name:   fmt.Sprintf("projects/%s", args.ProjectID),
+		Filter: fmt.Sprintf("display_name=%s", strconv.Quote(policy.GetDisplayName())),
+	}).Next()
+	if err != nil && err != iterator.Done {
+		return nil, nil, fmt.Errorf("failed to look up existing alert policies: %w", err)
+	}
+
+	var result *monitoringpb.AlertPolicy
+	action := "Created"
+	if existing != nil {
+		if !args.Replace {
+			return nil, nil, fmt.Errorf("alert policy %q already exists as %s; set replace to overwrite it", policy.GetDisplayName(), existing.GetName())
+		}
+		policy.Name = existing.GetName()
+		result, err = c.UpdateAlertPolicy(ctx, &monitoringpb.UpdateAlertPolicyRequest{AlertPolicy: policy})
+		action = "Replaced"
+	} else {
+		result, err = c.CreateAlertPolicy(ctx, &monitoringpb.CreateAlertPolicyRequest{
+			Name:        fmt.Sprintf("projects/%s", args.ProjectID),
+			AlertPolicy: policy,
+		})
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("%s alert policy %q: %s", action, result.GetDisplayName(), result.GetName())},
+		},
+	}, nil, nil
+}
+
+// buildAlertPolicy renders the requested template into an alert policy.
+func buildAlertPolicy(args *createGKEAlertPolicyArgs) (*monitoringpb.AlertPolicy, error) {
+	tmpl, ok := alertTemplates[args.Template]
+	if !ok {
+		names := make([]string, 0, len(alertTemplates))
+		for name := range alertTemplates {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("unknown template %q, must be one of: %s", args.Template, strings.Join(names, ", "))
+	}
+	if args.ClusterName == "" {
+		return nil, fmt.Errorf("cluster_name argument cannot be empty")
+	}
+
+	threshold := tmpl.defaultThreshold
+	if args.Threshold != 0 {
+		if tmpl.validate == nil {
+			return nil, fmt.Errorf("template %s does not take a threshold", args.Template)
+		}
+		threshold = args.Threshold
+	}
+	if tmpl.validate != nil {
+		if err := tmpl.validate(threshold); err != nil {
+			return nil, err
+		}
+	}
+	duration := tmpl.defaultDuration
+	if args.Duration != "" {
+		d, err := time.ParseDuration(args.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration: %w", err)
+		}
+		if d < time.Minute {
+			return nil, fmt.Errorf("duration must be at least 1m")
+		}
+		duration = d
+	}
+
+	channels := make([]string, 0, len(args.NotificationChannels))
+	for _, ch := range args.NotificationChannels {
+		if ch == "" {
+			return nil, fmt.Errorf("notification_channels cannot contain empty items")
+		}
+		if !strings.HasPrefix(ch, "projects/") {
+			ch = fmt.Sprintf("projects/%s/notificationChannels/%s", args.ProjectID, ch)
+		}
+		channels = append(channels, ch)
+	}
+
+	policy := &monitoringpb.AlertPolicy{
+		DisplayName: fmt.Sprintf("GKE %s (%s)", args.Template, args.ClusterName),
+		Documentation: &monitoringpb.AlertPolicy_Documentation{
+			Content:  fmt.Sprintf("%s\n\nCluster: %s", tmpl.description, args.ClusterName),
+			MimeType: "text/markdown",
+		},
+		UserLabels: map[string]string{
+			"created_by": "gke-mcp",
+		},
+		Conditions:           []*monitoringpb.AlertPolicy_Condition{tmpl.condition(args.ClusterName, threshold, duration)},
+		Combiner:             monitoringpb.AlertPolicy_OR,
+		Enabled:              wrapperspb.Bool(true),
+		NotificationChannels: channels,
+	}
+	if tmpl.logBased {
+		policy.AlertStrategy = &monitoringpb.AlertPolicy_AlertStrategy{
+			NotificationRateLimit: &monitoringpb.AlertPolicy_AlertStrategy_NotificationRateLimit{
+				Period: durationpb.New(duration),
+			},
+			AutoClose: durationpb.New(30 * time.Minute),
+		}
+	}
+	return policy, nil
+}
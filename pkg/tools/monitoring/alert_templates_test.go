@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+)
+
+func TestBuildAlertPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    *createGKEAlertPolicyArgs
+		check   func(t *testing.T, p *monitoringpb.AlertPolicy)
+		wantErr bool
+	}{
+		{
+			name: "pod crashloop with defaults",
+			args: &createGKEAlertPolicyArgs{ProjectID: "p", Template: "pod-crashloop", ClusterName: "prod", NotificationChannels: []string{"123", "projects/other/notificationChannels/456"}},
+			check: func(t *testing.T, p *monitoringpb.AlertPolicy) {
+				if p.GetDisplayName() != "GKE pod-crashloop (prod)" {
+					t.Errorf("display name = %q", p.GetDisplayName())
+				}
+				c := p.GetConditions()[0].GetConditionThreshold()
+				if c.GetThresholdValue() != 3 || c.GetAggregations()[0].GetAlignmentPeriod().AsDuration() != 10*time.Minute {
+					t.Errorf("condition = %v, want 3 restarts in 10m", c)
+				}
+				want := []string{"projects/p/notificationChannels/123", "projects/other/notificationChannels/456"}
+				for i, ch := range p.GetNotificationChannels() {
+					if ch != want[i] {
+						t.Errorf("notification channel %d = %q, want %q", i, ch, want[i])
+					}
+				}
+			},
+		},
+		{
+			name: "node not ready with threshold",
+			args: &createGKEAlertPolicyArgs{ProjectID: "p", Template: "node-not-ready", ClusterName: "prod", Threshold: 2, Duration: "10m"},
+			check: func(t *testing.T, p *monitoringpb.AlertPolicy) {
+				c := p.GetConditions()[0].GetConditionPrometheusQueryLanguage()
+				want := `count(kube_node_status_condition{cluster="prod",condition="Ready",status="true"} == 0) >= 2`
+				if c.GetQuery() != want || c.GetDuration().AsDuration() != 10*time.Minute {
+					t.Errorf("condition = %v, want query %q for 10m", c, want)
+				}
+			},
+		},
+		{
+			name: "oom kill is log based",
+			args: &createGKEAlertPolicyArgs{ProjectID: "p", Template: "container-oom-kill", ClusterName: "prod"},
+			check: func(t *testing.T, p *monitoringpb.AlertPolicy) {
+				if p.GetConditions()[0].GetConditionMatchedLog() == nil {
+					t.Errorf("condition = %v, want a log match", p.GetConditions()[0])
+				}
+				if p.GetAlertStrategy().GetNotificationRateLimit().GetPeriod().AsDuration() != 5*time.Minute {
+					t.Errorf("alert strategy = %v, want a 5m notification rate limit", p.GetAlertStrategy())
+				}
+			},
+		},
+		{
+			name:    "unknown template",
+			args:    &createGKEAlertPolicyArgs{Template: "disk-full", ClusterName: "prod"},
+			wantErr: true,
+		},
+		{
+			name:    "missing cluster",
+			args:    &createGKEAlertPolicyArgs{Template: "pod-crashloop"},
+			wantErr: true,
+		},
+		{
+			name:    "utilization threshold out of range",
+			args:    &createGKEAlertPolicyArgs{Template: "pv-usage-high", ClusterName: "prod", Threshold: 90},
+			wantErr: true,
+		},
+		{
+			name:    "threshold on template without one",
+			args:    &createGKEAlertPolicyArgs{Template: "container-oom-kill", ClusterName: "prod", Threshold: 1},
+			wantErr: true,
+		},
+		{
+			name:    "duration too short",
+			args:    &createGKEAlertPolicyArgs{Template: "pod-crashloop", ClusterName: "prod", Duration: "30s"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildAlertPolicy(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildAlertPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// maxAlertPolicies bounds the alert policies read from a project.
+	maxAlertPolicies = 1000
+	// maxNotificationChannels bounds the notification channels resolved to
+	// their display names.
+	maxNotificationChannels = 1000
+)
+
+// gkeQueryMarkers are substrings of condition filters and queries that
+// reference GKE metrics or monitored resources.
+var gkeQueryMarkers = []string{"kubernetes.io/", "kubernetes_io:", "k8s_"}
+
+type listAlertPoliciesArgs struct {
+	ProjectID   string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	ClusterName string `json:"cluster_name,omitempty" jsonschema:"Only include policies with a condition whose filter or query contains this cluster name."`
+	All         bool   `json:"all,omitempty" jsonschema:"List all alert policies of the project, not only the ones referencing GKE metrics or resources."`
+}
+
+// conditionSummary describes a single alert policy condition.
+type conditionSummary struct {
+	displayName string
+	kind        string
+	query       string
+}
+
+func installListAlertPoliciesTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "list_alert_policies",
+		Description: "List Cloud Monitoring alert policies that reference GKE metrics or resources, with their enabled state, conditions and notification channels. Use it to check whether a cluster has alerting. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.listAlertPolicies)
+}
+
+func (h *handlers) listAlertPolicies(ctx context.Context, _ *mcp.CallToolRequest, args *listAlertPoliciesArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.ProjectID == "" {
+		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
+	}
+
+	c, err := monitoring.NewAlertPolicyClient(ctx, h.c.ClientOptions()...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.Close()
+
+	req := &monitoringpb.ListAlertPoliciesRequest{
+		Name: fmt.Sprintf("projects/%s", args.ProjectID),
+	}
+	it := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*monitoringpb.AlertPolicy] {
+		req.PageToken = pageToken
+		return c.ListAlertPolicies(ctx, req)
+	})
+	collected, err := retry.Collect(it, maxAlertPolicies)
+	if err != nil {
+		return nil, nil, err
+	}
+	var policies []*monitoringpb.AlertPolicy
+	for _, p := range collected.Items {
+		if matchesAlertPolicy(p, args.All, args.ClusterName) {
+			policies = append(policies, p)
+		}
+	}
+
+	channels := map[string]string{}
+	var channelWarning string
+	var channelErr error
+	if hasNotificationChannels(policies) {
+		channels, channelWarning, channelErr = h.notificationChannelNames(ctx, args.ProjectID)
+	}
+
+	text := formatAlertPolicies(policies, channels, len(collected.Items), args)
+	if channelErr != nil {
+		text += fmt.Sprintf("\nNote: Notification channel names could not be resolved: %v\n", channelErr)
+	}
+	text += collected.PartialWarning()
+	if collected.Truncated {
+		text += fmt.Sprintf("\n\nWarning: Only the first %d alert policies of the project were checked.", maxAlertPolicies)
+	}
+	text += channelWarning
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// notificationChannelNames maps notification channel resource names of the
+// project to their display names. The returned warning describes channels
+// that were not read.
+func (h *handlers) notificationChannelNames(ctx context.Context, projectID string) (map[string]string, string, error) {
+	c, err := monitoring.NewNotificationChannelClient(ctx, h.c.ClientOptions()...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer c.Close()
+
+	req := &monitoringpb.ListNotificationChannelsRequest{
+		Name: fmt.Sprintf("projects/%s", projectID),
+	}
+	it := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*monitoringpb.NotificationChannel] {
+		req.PageToken = pageToken
+		return c.ListNotificationChannels(ctx, req)
+	})
+	collected, err := retry.Collect(it, maxNotificationChannels)
+	if err != nil {
+		return nil, "", err
+	}
+	names := map[string]string{}
+	for _, ch := range collected.Items {
+		names[ch.GetName()] = ch.GetDisplayName()
+	}
+	warning := collected.PartialWarning()
+	if collected.Truncated {
+		warning += fmt.Sprintf("\n\nWarning: Only the first %d notification channels were resolved to their names.", maxNotificationChannels)
+	}
+	return names, warning, nil
+}
+
+func hasNotificationChannels(policies []*monitoringpb.AlertPolicy) bool {
+	for _, p := range policies {
+		if len(p.GetNotificationChannels()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// summarizeCondition returns the kind and the filter or query of a condition.
+func summarizeCondition(c *monitoringpb.AlertPolicy_Condition) conditionSummary {
+	s := conditionSummary{displayName: c.GetDisplayName()}
+	switch {
+	case c.GetConditionThreshold() != nil:
+		t := c.GetConditionThreshold()
+		s.kind = fmt.Sprintf("threshold %s %g for %s", strings.TrimPrefix(t.GetComparison().String(), "COMPARISON_"), t.GetThresholdValue(), t.GetDuration().AsDuration())
+		s.query = t.GetFilter()
+	case c.GetConditionAbsent() != nil:
+		s.kind = fmt.Sprintf("absent for %s", c.GetConditionAbsent().GetDuration().AsDuration())
+		s.query = c.GetConditionAbsent().GetFilter()
+	case c.GetConditionMatchedLog() != nil:
+		s.kind = "log match"
+		s.query = c.GetConditionMatchedLog().GetFilter()
+	case c.GetConditionMonitoringQueryLanguage() != nil:
+		s.kind = "MQL"
+		s.query = c.GetConditionMonitoringQueryLanguage().GetQuery()
+	case c.GetConditionPrometheusQueryLanguage() != nil:
+		s.kind = "PromQL"
+		s.query = c.GetConditionPrometheusQueryLanguage().GetQuery()
+	case c.GetConditionSql() != nil:
+		s.kind = "SQL"
+		s.query = c.GetConditionSql().GetQuery()
+	default:
+		s.kind = "unknown"
+	}
+	return s
+}
+
+// matchesAlertPolicy reports whether any condition of p references GKE, unless
+// all is set, and contains clusterName, if set. A policy without conditions
+// only matches all without a cluster.
+func matchesAlertPolicy(p *monitoringpb.AlertPolicy, all bool, clusterName string) bool {
+	if len(p.GetConditions()) == 0 {
+		return all && clusterName == ""
+	}
+	for _, c := range p.GetConditions() {
+		query := summarizeCondition(c).query
+		if !all && !containsAny(query, gkeQueryMarkers) {
+			continue
+		}
+		if clusterName != "" && !strings.Contains(query, clusterName) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func containsAny(s string, substrs []string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatAlertPolicies(policies []*monitoringpb.AlertPolicy, channels map[string]string, total int, args *listAlertPoliciesArgs) string {
+	builder := new(strings.Builder)
+	scope := "referencing GKE"
+	if args.All {
+		scope = "in total"
+	}
+	if args.ClusterName != "" {
+		scope += fmt.Sprintf(" and matching cluster %q", args.ClusterName)
+	}
+	fmt.Fprintf(builder, "Found %d alert policies %s out of %d in project %s.\n", len(policies), scope, total, args.ProjectID)
+
+	for _, p := range policies {
+		state := "enabled"
+		if !p.GetEnabled().GetValue() {
+			state = "disabled"
+		}
+		fmt.Fprintf(builder, "\n### %s (%s)\n", p.GetDisplayName(), state)
+		fmt.Fprintf(builder, "Name: %s\n", p.GetName())
+		builder.WriteString("Conditions:\n")
+		for _, c := range p.GetConditions() {
+			s := summarizeCondition(c)
+			fmt.Fprintf(builder, "- %s [%s]: %s\n", s.displayName, s.kind, s.query)
+		}
+		if len(p.GetNotificationChannels()) == 0 {
+			builder.WriteString("Notification channels: none\n")
+			continue
+		}
+		names := make([]string, 0, len(p.GetNotificationChannels()))
+		for _, ch := range p.GetNotificationChannels() {
+			if name, ok := channels[ch]; ok && name != "" {
+				names = append(names, name)
+			} else {
+				names = append(names, ch)
+			}
+		}
+		fmt.Fprintf(builder, "Notification channels: %s\n", strings.Join(names, ", "))
+	}
+	return builder.String()
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func thresholdPolicy(name, filter string) *monitoringpb.AlertPolicy {
+	return &monitoringpb.AlertPolicy{
+		Name:        "projects/p/alertPolicies/" + name,
+		DisplayName: name,
+		Enabled:     wrapperspb.Bool(true),
+		Conditions: []*monitoringpb.AlertPolicy_Condition{{
+			DisplayName: "high",
+			Condition: &monitoringpb.AlertPolicy_Condition_ConditionThreshold{
+				ConditionThreshold: &monitoringpb.AlertPolicy_Condition_MetricThreshold{
+					Filter:         filter,
+					Comparison:     monitoringpb.ComparisonType_COMPARISON_GT,
+					ThresholdValue: 0.9,
+					Duration:       durationpb.New(5 * time.Minute),
+				},
+			},
+		}},
+		NotificationChannels: []string{"projects/p/notificationChannels/1", "projects/p/notificationChannels/2"},
+	}
+}
+
+func TestMatchesAlertPolicy(t *testing.T) {
+	gke := thresholdPolicy("gke", `metric.type="kubernetes.io/container/cpu/limit_utilization" AND resource.label.cluster_name="prod-1"`)
+	promQL := &monitoringpb.AlertPolicy{Conditions: []*monitoringpb.AlertPolicy_Condition{{
+		Condition: &monitoringpb.AlertPolicy_Condition_ConditionPrometheusQueryLanguage{
+			ConditionPrometheusQueryLanguage: &monitoringpb.AlertPolicy_Condition_PrometheusQueryLanguageCondition{
+				Query: `kubernetes_io:container_restart_count{cluster_name="staging"} > 3`,
+			},
+		},
+	}}}
+	vm := thresholdPolicy("vm", `metric.type="compute.googleapis.com/instance/cpu/utilization"`)
+	empty := &monitoringpb.AlertPolicy{DisplayName: "empty"}
+
+	tests := []struct {
+		name        string
+		policy      *monitoringpb.AlertPolicy
+		all         bool
+		clusterName string
+		want        bool
+	}{
+		{name: "gke threshold", policy: gke, want: true},
+		{name: "gke promql", policy: promQL, want: true},
+		{name: "non gke", policy: vm, want: false},
+		{name: "non gke with all", policy: vm, all: true, want: true},
+		{name: "cluster match", policy: gke, clusterName: "prod", want: true},
+		{name: "cluster mismatch", policy: promQL, clusterName: "prod", want: false},
+		{name: "no conditions", policy: empty, want: false},
+		{name: "no conditions with all", policy: empty, all: true, want: true},
+		{name: "no conditions with all and cluster", policy: empty, all: true, clusterName: "prod", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesAlertPolicy(tt.policy, tt.all, tt.clusterName); got != tt.want {
+				t.Errorf("matchesAlertPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatAlertPolicies(t *testing.T) {
+	p := thresholdPolicy("gke", `metric.type="kubernetes.io/node/cpu/allocatable_utilization"`)
+	channels := map[string]string{"projects/p/notificationChannels/1": "oncall"}
+	got := formatAlertPolicies([]*monitoringpb.AlertPolicy{p}, channels, 3, &listAlertPoliciesArgs{ProjectID: "p"})
+
+	for _, want := range []string{
+		"Found 1 alert policies referencing GKE out of 3 in project p.",
+		"### gke (enabled)",
+		`- high [threshold GT 0.9 for 5m0s]: metric.type="kubernetes.io/node/cpu/allocatable_utilization"`,
+		"Notification channels: oncall, projects/p/notificationChannels/2",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatAlertPolicies() = %q, want it to contain %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	outputFormatTable = "table"
+	outputFormatCSV   = "csv"
+	outputFormatJSON  = "json"
+
+	// maxTableColumns is the number of series shown side by side in the
+	// table format.
+	maxTableColumns = 8
+
+	noSeriesMessage = "No time series matched the query."
+)
+
+// validateOutputFormat returns the output format to use for format, which
+// defaults to the table format.
+func validateOutputFormat(format string) (string, error) {
+	switch format {
+	case "":
+		return outputFormatTable, nil
+	case outputFormatTable, outputFormatCSV, outputFormatJSON:
+		return format, nil
+	}
+	return "", fmt.Errorf("output_format must be one of %q, %q or %q", outputFormatTable, outputFormatCSV, outputFormatJSON)
+}
+
+// renderSeries renders series in the given output format. maxPoints only
+// applies to the table format.
+func renderSeries(series []labeledSeries, format string, maxPoints int) (string, error) {
+	if len(series) == 0 {
+		return noSeriesMessage, nil
+	}
+	switch format {
+	case "", outputFormatTable:
+		return formatSeriesTable(series, maxPoints), nil
+	case outputFormatCSV:
+		return formatSeriesCSV(series)
+	case outputFormatJSON:
+		return formatSeriesJSON(series)
+	}
+	return "", fmt.Errorf("unsupported output format %q", format)
+}
+
+// formatSeriesTable renders a legend with a summary of every series and a
+// markdown table with one row per timestamp and one column per series.
+func formatSeriesTable(series []labeledSeries, maxPoints int) string {
+	if len(series) == 0 {
+		return noSeriesMessage
+	}
+	shown := series[:min(len(series), maxTableColumns)]
+
+	builder := new(strings.Builder)
+	builder.WriteString("Series:\n")
+	for i, s := range shown {
+		minV, maxV, last := summarize(s.points)
+		fmt.Fprintf(builder, "- S%d %s: %d points, min %s, max %s, last %s\n", i+1, formatLabels(s.labels), len(s.points), minV, maxV, last)
+	}
+
+	// Rows are the union of the timestamps of all shown series.
+	values := make([]map[time.Time]string, len(shown))
+	var timestamps []time.Time
+	for i, s := range shown {
+		values[i] = map[time.Time]string{}
+		for _, p := range s.points {
+			values[i][p.timestamp] = p.value
+			timestamps = append(timestamps, p.timestamp)
+		}
+	}
+	slices.SortFunc(timestamps, time.Time.Compare)
+	timestamps = slices.CompactFunc(timestamps, time.Time.Equal)
+	rows := downsample(timestamps, maxPoints)
+
+	builder.WriteString("\n| Time (UTC) |")
+	for i := range shown {
+		fmt.Fprintf(builder, " S%d |", i+1)
+	}
+	builder.WriteString("\n|---|" + strings.Repeat("---|", len(shown)) + "\n")
+	for _, t := range rows {
+		fmt.Fprintf(builder, "| %s |", t.UTC().Format(time.RFC3339))
+		for i := range shown {
+			v, ok := values[i][t]
+			if !ok {
+				v = "-"
+			}
+			fmt.Fprintf(builder, " %s |", v)
+		}
+		builder.WriteString("\n")
+	}
+	if len(rows) < len(timestamps) {
+		fmt.Fprintf(builder, "\nShowing %d of %d timestamps, evenly spaced.\n", len(rows), len(timestamps))
+	}
+	if len(series) > len(shown) {
+		fmt.Fprintf(builder, "\nShowing the first %d of %d series as columns. Use output_format csv or json to get all of them.\n", len(shown), len(series))
+	}
+	return builder.String()
+}
+
+// formatSeriesCSV renders one record per point with a column per label key.
+func formatSeriesCSV(series []labeledSeries) (string, error) {
+	var keys []string
+	for _, s := range series {
+		for k := range s.labels {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	keys = slices.Compact(keys)
+
+	builder := new(strings.Builder)
+	w := csv.NewWriter(builder)
+	if err := w.Write(append(append([]string{"timestamp"}, keys...), "value")); err != nil {
+		return "", err
+	}
+	for _, s := range series {
+		for _, p := range s.points {
+			record := make([]string, 0, len(keys)+2)
+			record = append(record, p.timestamp.UTC().Format(time.RFC3339))
+			for _, k := range keys {
+				record = append(record, s.labels[k])
+			}
+			if err := w.Write(append(record, p.value)); err != nil {
+				return "", err
+			}
+		}
+	}
+	w.Flush()
+	return builder.String(), w.Error()
+}
+
+type jsonSeries struct {
+	Labels map[string]string `json:"labels"`
+	Points []jsonPoint       `json:"points"`
+}
+
+type jsonPoint struct {
+	Timestamp string `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+// formatSeriesJSON renders the series with all their points.
+func formatSeriesJSON(series []labeledSeries) (string, error) {
+	out := make([]jsonSeries, 0, len(series))
+	for _, s := range series {
+		js := jsonSeries{Labels: s.labels, Points: make([]jsonPoint, 0, len(s.points))}
+		for _, p := range s.points {
+			js.Points = append(js.Points, jsonPoint{Timestamp: p.timestamp.UTC().Format(time.RFC3339), Value: p.value})
+		}
+		out = append(out, js)
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSeries() []labeledSeries {
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	return []labeledSeries{
+		{
+			labels: map[string]string{"namespace_name": "prod", "pod_name": "web-1"},
+			points: []samplePoint{{t0, "0.5"}, {t0.Add(time.Minute), "1.5"}},
+		},
+		{
+			labels: map[string]string{"namespace_name": "prod", "pod_name": "web-2", "container_name": "app"},
+			points: []samplePoint{{t0.Add(time.Minute), "2"}, {t0.Add(2 * time.Minute), "3"}},
+		},
+	}
+}
+
+func TestRenderSeries(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: outputFormatTable,
+			want: `Series:
+- S1 {namespace_name="prod", pod_name="web-1"}: 2 points, min 0.5, max 1.5, last 1.5
+- S2 {container_name="app", namespace_name="prod", pod_name="web-2"}: 2 points, min 2, max 3, last 3
+
+| Time (UTC) | S1 | S2 |
+|---|---|---|
+| 2025-06-01T12:00:00Z | 0.5 | - |
+| 2025-06-01T12:01:00Z | 1.5 | 2 |
+| 2025-06-01T12:02:00Z | - | 3 |
+`,
+		},
+		{
+			format: outputFormatCSV,
+			want: `timestamp,container_name,namespace_name,pod_name,value
+2025-06-01T12:00:00Z,,prod,web-1,0.5
+2025-06-01T12:01:00Z,,prod,web-1,1.5
+2025-06-01T12:01:00Z,app,prod,web-2,2
+2025-06-01T12:02:00Z,app,prod,web-2,3
+`,
+		},
+		{
+			format: outputFormatJSON,
+			want:   `[{"labels":{"namespace_name":"prod","pod_name":"web-1"},"points":[{"timestamp":"2025-06-01T12:00:00Z","value":"0.5"},{"timestamp":"2025-06-01T12:01:00Z","value":"1.5"}]},{"labels":{"container_name":"app","namespace_name":"prod","pod_name":"web-2"},"points":[{"timestamp":"2025-06-01T12:01:00Z","value":"2"},{"timestamp":"2025-06-01T12:02:00Z","value":"3"}]}]`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.format, func(t *testing.T) {
+			got, err := renderSeries(testSeries(), tc.format, 20)
+			if err != nil {
+				t.Fatalf("renderSeries() error = %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("renderSeries() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormatSeriesTableLimits(t *testing.T) {
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	var series []labeledSeries
+	for i := range maxTableColumns + 2 {
+		s := labeledSeries{labels: map[string]string{"pod_name": fmt.Sprintf("web-%d", i)}}
+		for m := range 10 {
+			s.points = append(s.points, samplePoint{t0.Add(time.Duration(m) * time.Minute), "1"})
+		}
+		series = append(series, s)
+	}
+
+	got := formatSeriesTable(series, 3)
+	for _, want := range []string{
+		"Showing 3 of 10 timestamps, evenly spaced.",
+		fmt.Sprintf("Showing the first %d of %d series as columns.", maxTableColumns, maxTableColumns+2),
+		"| 2025-06-01T12:04:00Z |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatSeriesTable() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	if got, err := validateOutputFormat(""); err != nil || got != outputFormatTable {
+		t.Errorf("validateOutputFormat(\"\") = %q, %v, want %q", got, err, outputFormatTable)
+	}
+	if _, err := validateOutputFormat("yaml"); err == nil {
+		t.Error("validateOutputFormat(\"yaml\") succeeded, want error")
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	monitoringv3 "google.golang.org/api/monitoring/v3"
+)
+
+// maxSnoozes bounds the snoozes read to tell which incidents are snoozed.
+const maxSnoozes = 1000
+
+// maxOpenAlerts bounds the open alerts read.
+const maxOpenAlerts = 1000
+
+// errStopPaging stops reading pages once enough items were read.
+var errStopPaging = errors.New("stop paging")
+
+// clusterLabels are the resource and metric labels that identify the cluster
+// an alert fired for. Prometheus metrics use "cluster".
+var clusterLabels = []string{"cluster_name", "cluster"}
+
+type listActiveIncidentsArgs struct {
+	ProjectID   string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	ClusterName string `json:"cluster_name,omitempty" jsonschema:"Only include incidents whose resource or metric labels identify this GKE cluster."`
+}
+
+// incident is an open alert together with details of its policy.
+type incident struct {
+	alert      *monitoringv3.Alert
+	openTime   time.Time
+	conditions []string
+	snoozed    bool
+}
+
+func installListActiveIncidentsTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "list_active_incidents",
+		Description: "List the currently open Cloud Monitoring alerts (incidents), optionally only for one GKE cluster, newest first, with their policy, conditions, resource and whether they are snoozed. The observed value is not available from the alerts API; use query_timeseries or list_time_series on the condition to get it. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.listActiveIncidents)
+}
+
+func (h *handlers) listActiveIncidents(ctx context.Context, _ *mcp.CallToolRequest, args *listActiveIncidentsArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.ProjectID == "" {
+		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
+	}
+	parent := fmt.Sprintf("projects/%s", args.ProjectID)
+
+	opts, err := h.c.RESTClientOptions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	svc, err := monitoringv3.NewService(ctx, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	alerts, truncated, err := listOpenAlerts(ctx, svc, parent, maxOpenAlerts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	incidents := filterIncidents(alerts, args.ClusterName)
+
+	var notes []string
+	var warning string
+	if len(incidents) > 0 {
+		var snoozed map[string]bool
+		snoozed, warning, err = h.snoozedPolicies(ctx, parent)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("Snooze information is unavailable: %v", err))
+		}
+		conditions, err := h.policyConditions(ctx, incidents)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("Some alert policy conditions could not be read: %v", err))
+		}
+		for _, inc := range incidents {
+			inc.snoozed = snoozed[inc.alert.Policy.Name]
+			inc.conditions = conditions[inc.alert.Policy.Name]
+		}
+	}
+
+	text := formatIncidents(incidents, args)
+	for _, note := range notes {
+		text += fmt.Sprintf("\nNote: %s\n", note)
+	}
+	text += warning
+	if truncated {
+		text += fmt.Sprintf("\n\nWarning: Only the first %d open alerts of the project were checked.", maxOpenAlerts)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// listOpenAlerts returns up to limit open alerts of the project, and whether
+// there are more.
+func listOpenAlerts(ctx context.Context, svc *monitoringv3.Service, parent string, limit int) ([]*monitoringv3.Alert, bool, error) {
+	var alerts []*monitoringv3.Alert
+	truncated := false
+	err := svc.Projects.Alerts.List(parent).Filter(`state="OPEN"`).Pages(ctx, func(resp *monitoringv3.ListAlertsResponse) error {
+		alerts = append(alerts, resp.Alerts...)
+		if len(alerts) > limit || len(alerts) == limit && resp.NextPageToken != "" {
+			truncated = true
+			return errStopPaging
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopPaging) {
+		return nil, false, err
+	}
+	return alerts[:min(len(alerts), limit)], truncated, nil
+}
+
+// snoozedPolicies returns the alert policies with a snooze in effect now. The
+// returned warning describes snoozes that were not read.
+func (h *handlers) snoozedPolicies(ctx context.Context, parent string) (map[string]bool, string, error) {
+	c, err := monitoring.NewSnoozeClient(ctx, h.c.ClientOptions()...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer c.Close()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	req := &monitoringpb.ListSnoozesRequest{
+		Parent: parent,
+		Filter: fmt.Sprintf(`interval.start_time <= %q AND interval.end_time > %q`, now, now),
+	}
+	it := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*monitoringpb.Snooze] {
+		req.PageToken = pageToken
+		return c.ListSnoozes(ctx, req)
+	})
+	collected, err := retry.Collect(it, maxSnoozes)
+	if err != nil {
+		return nil, "", err
+	}
+	snoozed := map[string]bool{}
+	for _, s := range collected.Items {
+		for _, p := range s.GetCriteria().GetPolicies() {
+			snoozed[p] = true
+		}
+	}
+	warning := collected.PartialWarning()
+	if collected.Truncated {
+		warning += fmt.Sprintf("\n\nWarning: Only the first %d snoozes in effect were checked.", maxSnoozes)
+	}
+	return snoozed, warning, nil
+}
+
+// policyConditions returns the condition summaries of the policies of the
+// incidents, keyed by policy name.
+func (h *handlers) policyConditions(ctx context.Context, incidents []*incident) (map[string][]string, error) {
+	c, err := monitoring.NewAlertPolicyClient(ctx, h.c.ClientOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	conditions := map[string][]string{}
+	var lastErr error
+	for _, inc := range incidents {
+		name := inc.alert.Policy.Name
+		if _, ok := conditions[name]; ok || name == "" {
+			continue
+		}
+		p, err := c.GetAlertPolicy(ctx, &monitoringpb.GetAlertPolicyRequest{Name: name})
+		if err != nil {
+			conditions[name] = nil
+			lastErr = err
+			continue
+		}
+		for _, cond := range p.GetConditions() {
+			s := summarizeCondition(cond)
+			conditions[name] = append(conditions[name], fmt.Sprintf("%s [%s]", s.displayName, s.kind))
+		}
+	}
+	return conditions, lastErr
+}
+
+// filterIncidents keeps the alerts for clusterName, if set, and sorts them by
+// open time, newest first.
+func filterIncidents(alerts []*monitoringv3.Alert, clusterName string) []*incident {
+	var incidents []*incident
+	for _, a := range alerts {
+		if a.Policy == nil {
+			a.Policy = &monitoringv3.PolicySnapshot{}
+		}
+		if clusterName != "" && alertCluster(a) != clusterName {
+			continue
+		}
+		openTime, _ := time.Parse(time.RFC3339Nano, a.OpenTime)
+		incidents = append(incidents, &incident{alert: a, openTime: openTime})
+	}
+	sort.SliceStable(incidents, func(i, j int) bool {
+		return incidents[i].openTime.After(incidents[j].openTime)
+	})
+	return incidents
+}
+
+// alertCluster returns the cluster an alert fired for, if any.
+func alertCluster(a *monitoringv3.Alert) string {
+	for _, l := range clusterLabels {
+		if a.Resource != nil && a.Resource.Labels[l] != "" {
+			return a.Resource.Labels[l]
+		}
+		if a.Metric != nil && a.Metric.Labels[l] != "" {
+			return a.Metric.Labels[l]
+		}
+	}
+	return ""
+}
+
+func formatIncidents(incidents []*incident, args *listActiveIncidentsArgs) string {
+	builder := new(strings.Builder)
+	scope := fmt.Sprintf("project %s", args.ProjectID)
+	if args.ClusterName != "" {
+		scope = fmt.Sprintf("cluster %s in %s", args.ClusterName, scope)
+	}
+	if len(incidents) == 0 {
+		fmt.Fprintf(builder, "No open incidents for %s.\n", scope)
+		return builder.String()
+	}
+
+	snoozed := 0
+	for _, inc := range incidents {
+		if inc.snoozed {
+			snoozed++
+		}
+	}
+	fmt.Fprintf(builder, "Found %d open incidents for %s", len(incidents), scope)
+	if snoozed > 0 {
+		fmt.Fprintf(builder, ", %d of them suppressed by a snooze", snoozed)
+	}
+	builder.WriteString(".\n\n")
+
+	builder.WriteString("| Opened | Policy | Severity | Conditions | Resource | Metric | Snoozed |\n")
+	builder.WriteString("|---|---|---|---|---|---|---|\n")
+	for _, inc := range incidents {
+		a := inc.alert
+		opened := a.OpenTime
+		if !inc.openTime.IsZero() {
+			opened = inc.openTime.UTC().Format(time.RFC3339)
+		}
+		severity := strings.TrimPrefix(a.Policy.Severity, "SEVERITY_")
+		if severity == "" {
+			severity = "-"
+		}
+		conditions := "-"
+		if len(inc.conditions) > 0 {
+			conditions = strings.Join(inc.conditions, "; ")
+		}
+		resource, metric := "-", "-"
+		if a.Resource != nil {
+			resource = a.Resource.Type + formatLabels(a.Resource.Labels)
+		}
+		if a.Metric != nil {
+			metric = a.Metric.Type + formatLabels(a.Metric.Labels)
+		}
+		snoozedCell := "no"
+		if inc.snoozed {
+			snoozedCell = "yes"
+		}
+		fmt.Fprintf(builder, "| %s | %s (%s) | %s | %s | %s | %s | %s |\n", opened, a.Policy.DisplayName, a.Policy.Name, severity, conditions, resource, metric, snoozedCell)
+	}
+	return builder.String()
+}
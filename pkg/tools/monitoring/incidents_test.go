@@ -0,0 +1,135 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	monitoringv3 "google.golang.org/api/monitoring/v3"
+)
+
+func testAlert(policy, openTime, cluster string) *monitoringv3.Alert {
+	return &monitoringv3.Alert{
+		OpenTime: openTime,
+		Policy:   &monitoringv3.PolicySnapshot{Name: "projects/p/alertPolicies/" + policy, DisplayName: policy, Severity: "CRITICAL"},
+		Resource: &monitoringv3.MonitoredResource{Type: "k8s_container", Labels: map[string]string{"cluster_name": cluster}},
+	}
+}
+
+func TestFilterIncidents(t *testing.T) {
+	promAlert := &monitoringv3.Alert{
+		OpenTime: "2025-06-01T12:00:00Z",
+		Resource: &monitoringv3.MonitoredResource{Type: "prometheus_target"},
+		Metric:   &monitoringv3.Metric{Labels: map[string]string{"cluster": "prod"}},
+	}
+	alerts := []*monitoringv3.Alert{
+		testAlert("old", "2025-06-01T08:00:00Z", "prod"),
+		testAlert("other-cluster", "2025-06-01T11:00:00Z", "staging"),
+		testAlert("new", "2025-06-01T10:00:00.5Z", "prod"),
+		promAlert,
+	}
+
+	got := filterIncidents(alerts, "prod")
+	var names []string
+	for _, inc := range got {
+		names = append(names, inc.alert.Policy.DisplayName)
+	}
+	want := []string{"", "new", "old"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("filterIncidents() = %q, want %q", names, want)
+	}
+
+	if got := filterIncidents(alerts, ""); len(got) != 4 {
+		t.Errorf("filterIncidents() without cluster returned %d incidents, want 4", len(got))
+	}
+}
+
+func TestFormatIncidents(t *testing.T) {
+	incidents := filterIncidents([]*monitoringv3.Alert{testAlert("crashloop", "2025-06-01T10:00:00Z", "prod")}, "")
+	incidents[0].snoozed = true
+	incidents[0].conditions = []string{"Container restarts [threshold GE 3 for 0s]"}
+
+	got := formatIncidents(incidents, &listActiveIncidentsArgs{ProjectID: "p", ClusterName: "prod"})
+	for _, want := range []string{
+		"Found 1 open incidents for cluster prod in project p, 1 of them suppressed by a snooze.",
+		`| 2025-06-01T10:00:00Z | crashloop (projects/p/alertPolicies/crashloop) | CRITICAL | Container restarts [threshold GE 3 for 0s] | k8s_container{cluster_name="prod"} | - | yes |`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatIncidents() = %q, want it to contain %q", got, want)
+		}
+	}
+
+	if got := formatIncidents(nil, &listActiveIncidentsArgs{ProjectID: "p"}); got != "No open incidents for project p.\n" {
+		t.Errorf("formatIncidents() without incidents = %q", got)
+	}
+}
+
+func TestListOpenAlerts(t *testing.T) {
+	// The fake serves 3 pages of 2 alerts.
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page := 0
+		fmt.Sscan(r.URL.Query().Get("pageToken"), &page)
+		next := ""
+		if page < 2 {
+			next = fmt.Sprint(page + 1)
+		}
+		fmt.Fprintf(w, `{"alerts": [{"name": "a%d"}, {"name": "b%d"}], "nextPageToken": %q}`, page, page, next)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := context.Background()
+	c := &config.Config{}
+	c.SetEndpoint(strings.TrimPrefix(srv.URL, "http://"))
+	opts, err := c.RESTClientOptions(ctx)
+	if err != nil {
+		t.Fatalf("RESTClientOptions() failed: %v", err)
+	}
+	svc, err := monitoringv3.NewService(ctx, opts...)
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+
+	tests := []struct {
+		limit         int
+		wantAlerts    int
+		wantTruncated bool
+		wantRequests  int
+	}{
+		{limit: 3, wantAlerts: 3, wantTruncated: true, wantRequests: 2},
+		{limit: 4, wantAlerts: 4, wantTruncated: true, wantRequests: 2},
+		{limit: 6, wantAlerts: 6, wantTruncated: false, wantRequests: 3},
+	}
+	for _, tt := range tests {
+		requests = 0
+		alerts, truncated, err := listOpenAlerts(ctx, svc, "projects/p", tt.limit)
+		if err != nil {
+			t.Fatalf("listOpenAlerts(%d) failed: %v", tt.limit, err)
+		}
+		if len(alerts) != tt.wantAlerts || truncated != tt.wantTruncated {
+			t.Errorf("listOpenAlerts(%d) = %d alerts, truncated %v, want %d, %v", tt.limit, len(alerts), truncated, tt.wantAlerts, tt.wantTruncated)
+		}
+		if requests != tt.wantRequests {
+			t.Errorf("listOpenAlerts(%d) made %d requests, want %d", tt.limit, requests, tt.wantRequests)
+		}
+	}
+}
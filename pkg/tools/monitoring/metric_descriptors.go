@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/iterator"
+	metricpb "google.golang.org/genproto/googleapis/api/metric"
+)
+
+const (
+	defaultMetricPrefix          = "kubernetes.io/"
+	defaultMetricDescriptorLimit = 50
+	maxMetricDescriptorLimit     = 200
+)
+
+type listMetricDescriptorsArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Prefix    string `json:"prefix,omitempty" jsonschema:"Metric type prefix. Defaults to 'kubernetes.io/' for GKE system metrics; use 'prometheus.googleapis.com/' for metrics collected by Managed Service for Prometheus."`
+	Contains  string `json:"contains,omitempty" jsonschema:"Only include metric types containing this substring, e.g. 'ephemeral_storage'."`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum number of descriptors to return. Defaults to 50, maximum 200."`
+	PageToken string `json:"page_token,omitempty" jsonschema:"NEXT_PAGE_TOKEN of a previous call with the same arguments, to get the next page."`
+}
+
+func installListMetricDescriptorsTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "list_metric_descriptors",
+		Description: "List Cloud Monitoring metric types with their kind, value type, unit and label keys, filtered by prefix (default kubernetes.io/) and name substring. Use it to find exact metric names before querying time series. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.listMetricDescriptors)
+}
+
+func (h *handlers) listMetricDescriptors(ctx context.Context, _ *mcp.CallToolRequest, args *listMetricDescriptorsArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.ProjectID == "" {
+		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
+	}
+	if args.Prefix == "" {
+		args.Prefix = defaultMetricPrefix
+	}
+	if args.Limit == 0 {
+		args.Limit = defaultMetricDescriptorLimit
+	}
+	if args.Limit < 1 || args.Limit > maxMetricDescriptorLimit {
+		return nil, nil, fmt.Errorf("limit must be between 1 and %d", maxMetricDescriptorLimit)
+	}
+
+	req := &monitoringpb.ListMetricDescriptorsRequest{
+		Name:   fmt.Sprintf("projects/%s", args.ProjectID),
+		Filter: metricDescriptorFilter(args.Prefix, args.Contains),
+	}
+	var descriptors []*metricpb.MetricDescriptor
+	var nextPageToken string
+	err := retry.Do(ctx, retry.DefaultPolicy, func() error {
+		descriptors = nil
+		var err error
+		nextPageToken, err = iterator.NewPager(h.metricClient.ListMetricDescriptors(ctx, req), args.Limit, args.PageToken).NextPage(&descriptors)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	text := formatMetricDescriptors(descriptors, req.Filter)
+	if nextPageToken != "" {
+		text += fmt.Sprintf("\nMore metric descriptors are available. Call again with the same arguments and page_token to get them.\nNEXT_PAGE_TOKEN: %s", nextPageToken)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+func metricDescriptorFilter(prefix, contains string) string {
+	filter := fmt.Sprintf("metric.type = starts_with(%s)", strconv.Quote(prefix))
+	if contains != "" {
+		filter += fmt.Sprintf(" AND metric.type = has_substring(%s)", strconv.Quote(contains))
+	}
+	return filter
+}
+
+// formatMetricDescriptors renders one line per descriptor, e.g.
+// "kubernetes.io/container/restart_count CUMULATIVE INT64 unit=1 labels=[]".
+func formatMetricDescriptors(descriptors []*metricpb.MetricDescriptor, filter string) string {
+	if len(descriptors) == 0 {
+		return fmt.Sprintf("No metric descriptors match filter: %s\n", filter)
+	}
+	builder := new(strings.Builder)
+	fmt.Fprintf(builder, "Showing %d metric descriptors matching filter: %s\n\n", len(descriptors), filter)
+	for _, d := range descriptors {
+		labels := make([]string, 0, len(d.GetLabels()))
+		for _, l := range d.GetLabels() {
+			labels = append(labels, l.GetKey())
+		}
+		unit := d.GetUnit()
+		if unit == "" {
+			unit = "-"
+		}
+		fmt.Fprintf(builder, "%s %s %s unit=%s labels=[%s]\n", d.GetType(), d.GetMetricKind(), d.GetValueType(), unit, strings.Join(labels, ","))
+	}
+	return builder.String()
+}
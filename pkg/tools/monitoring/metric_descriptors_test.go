@@ -0,0 +1,53 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"strings"
+	"testing"
+
+	labelpb "google.golang.org/genproto/googleapis/api/label"
+	metricpb "google.golang.org/genproto/googleapis/api/metric"
+)
+
+func TestMetricDescriptorFilter(t *testing.T) {
+	tests := []struct {
+		prefix, contains string
+		want             string
+	}{
+		{prefix: "kubernetes.io/", want: `metric.type = starts_with("kubernetes.io/")`},
+		{prefix: "prometheus.googleapis.com/", contains: "ephemeral", want: `metric.type = starts_with("prometheus.googleapis.com/") AND metric.type = has_substring("ephemeral")`},
+	}
+	for _, tt := range tests {
+		if got := metricDescriptorFilter(tt.prefix, tt.contains); got != tt.want {
+			t.Errorf("metricDescriptorFilter(%q, %q) = %q, want %q", tt.prefix, tt.contains, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMetricDescriptors(t *testing.T) {
+	descriptors := []*metricpb.MetricDescriptor{{
+		Type:       "kubernetes.io/container/ephemeral_storage/used_bytes",
+		MetricKind: metricpb.MetricDescriptor_GAUGE,
+		ValueType:  metricpb.MetricDescriptor_INT64,
+		Unit:       "By",
+		Labels:     []*labelpb.LabelDescriptor{{Key: "mode"}, {Key: "device"}},
+	}}
+	got := formatMetricDescriptors(descriptors, "f")
+	want := "kubernetes.io/container/ephemeral_storage/used_bytes GAUGE INT64 unit=By labels=[mode,device]\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("formatMetricDescriptors() = %q, want suffix %q", got, want)
+	}
+}
@@ -22,23 +22,41 @@ import (
 	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
 	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
-	"google.golang.org/api/iterator"
-	"google.golang.org/api/option"
+	monitoredres "google.golang.org/genproto/googleapis/api/monitoredres"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
 type handlers struct {
-	c *config.Config
+	c            *config.Config
+	metricClient *monitoring.MetricClient
 }
 
+const (
+	// defaultDescriptorFilter restricts descriptors to the resource types GKE
+	// workloads report against.
+	defaultDescriptorFilter = `resource.type = starts_with("k8s_") OR resource.type = "gce_instance"`
+	defaultDescriptorLimit  = 50
+	maxDescriptorLimit      = 500
+)
+
 type listMonitoredResourceDescriptorsArgs struct {
 	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Filter    string `json:"filter,omitempty" jsonschema:"Monitoring filter on the descriptors, e.g. 'resource.type = starts_with(\"k8s_\")'. Defaults to GKE related resource types (k8s_* and gce_instance)."`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum number of descriptors to return. Defaults to 50, maximum 500."`
 }
 
-func Install(_ context.Context, s *mcp.Server, c *config.Config) error {
+func Install(ctx context.Context, s *mcp.Server, c *config.Config) error {
+	metricClient, err := monitoring.NewMetricClient(ctx, c.ClientOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to create metric client: %w", err)
+	}
+	c.OnClose(metricClient.Close)
+
 	h := &handlers{
-		c: c,
+		c:            c,
+		metricClient: metricClient,
 	}
 
 	mcp.AddTool(s, &mcp.Tool{
@@ -49,6 +67,17 @@ func Install(_ context.Context, s *mcp.Server, c *config.Config) error {
 		},
 	}, h.listMRDescriptor)
 
+	installQueryTimeSeriesTool(s, h)
+	installListTimeSeriesTool(s, h)
+	installListAlertPoliciesTool(s, h)
+	if !c.ReadOnly() {
+		installCreateGKEAlertPolicyTool(s, h)
+	}
+	installListActiveIncidentsTool(s, h)
+	installListMetricDescriptorsTool(s, h)
+	installDetectOOMKillsTool(s, h)
+	installListUptimeChecksAndSLOsTool(s, h)
+
 	return nil
 }
 
@@ -59,30 +88,40 @@ func (h *handlers) listMRDescriptor(ctx context.Context, _ *mcp.CallToolRequest,
 	if args.ProjectID == "" {
 		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
 	}
-	c, err := monitoring.NewMetricClient(ctx, option.WithUserAgent(h.c.UserAgent()))
+	if args.Filter == "" {
+		args.Filter = defaultDescriptorFilter
+	}
+	if args.Limit == 0 {
+		args.Limit = defaultDescriptorLimit
+	}
+	if args.Limit < 1 || args.Limit > maxDescriptorLimit {
+		return nil, nil, fmt.Errorf("limit must be between 1 and %d", maxDescriptorLimit)
+	}
+	req := &monitoringpb.ListMonitoredResourceDescriptorsRequest{
+		Name:   fmt.Sprintf("projects/%s", args.ProjectID),
+		Filter: args.Filter,
+	}
+	it := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*monitoredres.MonitoredResourceDescriptor] {
+		req.PageToken = pageToken
+		return h.metricClient.ListMonitoredResourceDescriptors(ctx, req)
+	})
+	collected, err := retry.Collect(it, args.Limit)
 	if err != nil {
 		return nil, nil, err
 	}
-	defer c.Close()
-	req := &monitoringpb.ListMonitoredResourceDescriptorsRequest{
-		Name: fmt.Sprintf("projects/%s", args.ProjectID),
+	descriptors := make([]string, 0, len(collected.Items))
+	for _, d := range collected.Items {
+		descriptors = append(descriptors, protojson.Format(d))
 	}
-	it := c.ListMonitoredResourceDescriptors(ctx, req)
-	builder := new(strings.Builder)
-	for {
-		resp, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, nil, err
-		}
-		builder.WriteString(protojson.Format(resp))
+	warning := collected.PartialWarning()
+	if collected.Truncated {
+		warning += fmt.Sprintf("\n\nWarning: Results limited to %d descriptors. Narrow the filter or raise limit to see more.", args.Limit)
 	}
 
+	text := fmt.Sprintf("Showing %d monitored resource descriptors matching filter: %s\n\n%s%s", len(descriptors), args.Filter, strings.Join(descriptors, "\n"), warning)
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: builder.String()},
+			&mcp.TextContent{Text: text},
 		},
 	}, nil, nil
 }
@@ -0,0 +1,401 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	logging "cloud.google.com/go/logging/apiv2"
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultOOMLookback = 24 * time.Hour
+	maxOOMLookback     = 7 * 24 * time.Hour
+	// nearLimitRatio is the memory limit utilization from which containers
+	// without OOM kills are still reported.
+	nearLimitRatio = 0.9
+	maxOOMEvents   = 1000
+	// maxContainerSeries bounds the memory series read per metric.
+	maxContainerSeries = 10000
+)
+
+var (
+	// Pod name suffixes added by controllers, from the most to the least
+	// specific: Deployment (ReplicaSet hash and pod suffix), DaemonSet or Job
+	// (pod suffix) and StatefulSet (ordinal). Generated suffixes use the
+	// Kubernetes alphabet without vowels.
+	deploymentPodRegexp  = regexp.MustCompile(`^(.+)-[bcdfghjklmnpqrstvwxz2456789]{6,10}-[bcdfghjklmnpqrstvwxz2456789]{5}$`)
+	generatedPodRegexp   = regexp.MustCompile(`^(.+)-[bcdfghjklmnpqrstvwxz2456789]{5}$`)
+	statefulSetPodRegexp = regexp.MustCompile(`^(.+)-[0-9]+$`)
+)
+
+type detectOOMKillsArgs struct {
+	ProjectID   string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location    string `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	ClusterName string `json:"cluster_name" jsonschema:"GKE cluster name."`
+	Namespace   string `json:"namespace,omitempty" jsonschema:"Only include workloads in this Kubernetes namespace."`
+	Lookback    string `json:"lookback,omitempty" jsonschema:"How far back to look, as a Go duration such as '6h'. Defaults to 24h, maximum 168h."`
+}
+
+// oomEvent is an OOM kill found in the Kubernetes event logs.
+type oomEvent struct {
+	namespace string
+	pod       string
+	node      string
+	timestamp time.Time
+}
+
+// containerMemory is the peak memory limit utilization of a container.
+type containerMemory struct {
+	namespace   string
+	pod         string
+	limitBytes  int64
+	utilization float64
+}
+
+// workloadOOMSummary correlates OOM kills and memory usage of a workload.
+type workloadOOMSummary struct {
+	namespace       string
+	workload        string
+	pods            map[string]bool
+	kills           int
+	lastKill        time.Time
+	limitBytes      int64
+	peakUtilization float64
+}
+
+func installDetectOOMKillsTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "detect_oom_kills",
+		Description: "Find workloads of a GKE cluster that were OOM killed or run close to their memory limit, by correlating Kubernetes OOM events from Cloud Logging with container memory metrics from Cloud Monitoring. Returns kill count, last kill time, memory limit and peak usage per workload.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.detectOOMKills)
+}
+
+func (h *handlers) detectOOMKills(ctx context.Context, _ *mcp.CallToolRequest, args *detectOOMKillsArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.ProjectID == "" {
+		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
+	}
+	if args.ClusterName == "" {
+		return nil, nil, fmt.Errorf("cluster_name argument cannot be empty")
+	}
+	lookback := defaultOOMLookback
+	if args.Lookback != "" {
+		d, err := time.ParseDuration(args.Lookback)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid lookback: %w", err)
+		}
+		if d < time.Minute || d > maxOOMLookback {
+			return nil, nil, fmt.Errorf("lookback must be between 1m and %s", maxOOMLookback)
+		}
+		lookback = d
+	}
+	end := time.Now()
+	start := end.Add(-lookback)
+
+	var notes []string
+	events, eventsWarning, err := h.oomEvents(ctx, args, start)
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("OOM events could not be read from Cloud Logging, only memory metrics are shown: %v", err))
+	}
+	memory, memoryWarning, err := h.containerMemory(ctx, args, start, end)
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("Memory metrics could not be read from Cloud Monitoring, only OOM events are shown: %v", err))
+	}
+
+	summaries, unattributed := summarizeOOMKills(events, memory)
+	text := formatOOMSummaries(summaries, unattributed, args.ClusterName, lookback)
+	for _, note := range notes {
+		text += fmt.Sprintf("\nNote: %s\n", note)
+	}
+	text += eventsWarning + memoryWarning
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// oomEvents returns the OOM kills reported in the Kubernetes events of the
+// cluster since start, most recent first. The returned warning describes
+// events that were not read.
+func (h *handlers) oomEvents(ctx context.Context, args *detectOOMKillsArgs, start time.Time) ([]oomEvent, string, error) {
+	client, err := logging.NewClient(ctx, h.c.ClientOptions()...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer client.Close()
+
+	filter := []string{
+		`log_id("events")`,
+		fmt.Sprintf("resource.labels.cluster_name=%s", strconv.Quote(args.ClusterName)),
+		fmt.Sprintf("timestamp>=%s", strconv.Quote(start.UTC().Format(time.RFC3339))),
+		`(jsonPayload.reason="OOMKilling" OR jsonPayload.message:"OOMKilled")`,
+	}
+	if args.Location != "" {
+		filter = append(filter, fmt.Sprintf("resource.labels.location=%s", strconv.Quote(args.Location)))
+	}
+	req := &loggingpb.ListLogEntriesRequest{
+		ResourceNames: []string{fmt.Sprintf("projects/%s", args.ProjectID)},
+		Filter:        strings.Join(filter, " AND "),
+		OrderBy:       "timestamp desc",
+		PageSize:      maxOOMEvents,
+	}
+	it := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*loggingpb.LogEntry] {
+		req.PageToken = pageToken
+		return client.ListLogEntries(ctx, req)
+	})
+	collected, err := retry.Collect(it, maxOOMEvents)
+	if err != nil {
+		return nil, "", err
+	}
+	var events []oomEvent
+	for _, entry := range collected.Items {
+		e := parseOOMEvent(entry)
+		if args.Namespace != "" && e.namespace != args.Namespace && e.pod != "" {
+			continue
+		}
+		events = append(events, e)
+	}
+	warning := collected.PartialWarning()
+	if collected.Truncated {
+		warning += fmt.Sprintf("\n\nWarning: Only the most recent %d OOM events were analyzed.", maxOOMEvents)
+	}
+	return events, warning, nil
+}
+
+// parseOOMEvent extracts the pod of an OOM event from the involved object or
+// the monitored resource. Kernel OOM kills reported by the node problem
+// detector only identify the node.
+func parseOOMEvent(entry *loggingpb.LogEntry) oomEvent {
+	e := oomEvent{timestamp: entry.GetTimestamp().AsTime()}
+	labels := entry.GetResource().GetLabels()
+	e.node = labels["node_name"]
+	if entry.GetResource().GetType() == "k8s_pod" {
+		e.namespace = labels["namespace_name"]
+		e.pod = labels["pod_name"]
+	}
+	obj := entry.GetJsonPayload().GetFields()["involvedObject"].GetStructValue().GetFields()
+	if obj["kind"].GetStringValue() == "Pod" {
+		e.namespace = obj["namespace"].GetStringValue()
+		e.pod = obj["name"].GetStringValue()
+	}
+	if e.node == "" {
+		if src := entry.GetJsonPayload().GetFields()["source"].GetStructValue().GetFields(); src != nil {
+			e.node = src["host"].GetStringValue()
+		}
+	}
+	return e
+}
+
+// containerMemory returns the peak memory limit utilization of every
+// container of the cluster with a memory limit between start and end. The
+// returned warning describes series that were not read.
+func (h *handlers) containerMemory(ctx context.Context, args *detectOOMKillsArgs, start, end time.Time) ([]containerMemory, string, error) {
+	resourceFilter := fmt.Sprintf(`resource.type="k8s_container" AND resource.label.cluster_name=%s`, strconv.Quote(args.ClusterName))
+	if args.Location != "" {
+		resourceFilter += fmt.Sprintf(" AND resource.label.location=%s", strconv.Quote(args.Location))
+	}
+	if args.Namespace != "" {
+		resourceFilter += fmt.Sprintf(" AND resource.label.namespace_name=%s", strconv.Quote(args.Namespace))
+	}
+
+	type key struct{ namespace, pod, container string }
+	containers := map[key]*containerMemory{}
+	var order []key
+	var warning string
+	for _, metricType := range []string{"kubernetes.io/container/memory/limit_utilization", "kubernetes.io/container/memory/limit_bytes"} {
+		req := &monitoringpb.ListTimeSeriesRequest{
+			Name:   fmt.Sprintf("projects/%s", args.ProjectID),
+			Filter: fmt.Sprintf("metric.type=%s AND %s", strconv.Quote(metricType), resourceFilter),
+			Interval: &monitoringpb.TimeInterval{
+				StartTime: timestamppb.New(start),
+				EndTime:   timestamppb.New(end),
+			},
+			Aggregation: &monitoringpb.Aggregation{
+				// A single alignment period over the whole window yields the
+				// peak of every series.
+				AlignmentPeriod:  durationpb.New(end.Sub(start).Truncate(time.Minute)),
+				PerSeriesAligner: monitoringpb.Aggregation_ALIGN_MAX,
+			},
+			View: monitoringpb.ListTimeSeriesRequest_FULL,
+		}
+		it := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*monitoringpb.TimeSeries] {
+			req.PageToken = pageToken
+			return h.metricClient.ListTimeSeries(ctx, req)
+		})
+		collected, err := retry.Collect(it, maxContainerSeries)
+		if err != nil {
+			return nil, "", err
+		}
+		warning += collected.PartialWarning()
+		if collected.Truncated {
+			warning += fmt.Sprintf("\n\nWarning: Only the first %d containers were analyzed for %s.", maxContainerSeries, metricType)
+		}
+		for _, ts := range collected.Items {
+			labels := ts.GetResource().GetLabels()
+			k := key{labels["namespace_name"], labels["pod_name"], labels["container_name"]}
+			cm, ok := containers[k]
+			if !ok {
+				cm = &containerMemory{namespace: k.namespace, pod: k.pod}
+				containers[k] = cm
+				order = append(order, k)
+			}
+			var peak float64
+			for _, p := range ts.GetPoints() {
+				v := p.GetValue()
+				peak = max(peak, v.GetDoubleValue(), float64(v.GetInt64Value()))
+			}
+			if strings.HasSuffix(metricType, "limit_utilization") {
+				cm.utilization = peak
+			} else {
+				cm.limitBytes = int64(peak)
+			}
+		}
+	}
+
+	result := make([]containerMemory, 0, len(order))
+	for _, k := range order {
+		result = append(result, *containers[k])
+	}
+	return result, warning, nil
+}
+
+// workloadName derives the name of the controller of a pod from the pod name.
+func workloadName(pod string) string {
+	for _, re := range []*regexp.Regexp{deploymentPodRegexp, generatedPodRegexp, statefulSetPodRegexp} {
+		if m := re.FindStringSubmatch(pod); m != nil {
+			return m[1]
+		}
+	}
+	return pod
+}
+
+// summarizeOOMKills joins OOM events and memory usage by pod and aggregates
+// them per workload. Only workloads that were OOM killed or came close to
+// their memory limit are returned, most kills first. The OOM kills which do
+// not identify a pod are counted per node.
+func summarizeOOMKills(events []oomEvent, memory []containerMemory) ([]*workloadOOMSummary, map[string]int) {
+	workloads := map[string]*workloadOOMSummary{}
+	get := func(namespace, pod string) *workloadOOMSummary {
+		name := workloadName(pod)
+		k := namespace + "/" + name
+		w, ok := workloads[k]
+		if !ok {
+			w = &workloadOOMSummary{namespace: namespace, workload: name, pods: map[string]bool{}}
+			workloads[k] = w
+		}
+		w.pods[pod] = true
+		return w
+	}
+
+	unattributed := map[string]int{}
+	for _, e := range events {
+		if e.pod == "" {
+			node := e.node
+			if node == "" {
+				node = "(unknown node)"
+			}
+			unattributed[node]++
+			continue
+		}
+		w := get(e.namespace, e.pod)
+		w.kills++
+		if e.timestamp.After(w.lastKill) {
+			w.lastKill = e.timestamp
+		}
+	}
+	for _, m := range memory {
+		k := m.namespace + "/" + workloadName(m.pod)
+		if _, killed := workloads[k]; !killed && m.utilization < nearLimitRatio {
+			continue
+		}
+		w := get(m.namespace, m.pod)
+		w.limitBytes = max(w.limitBytes, m.limitBytes)
+		w.peakUtilization = max(w.peakUtilization, m.utilization)
+	}
+
+	summaries := make([]*workloadOOMSummary, 0, len(workloads))
+	for _, w := range workloads {
+		summaries = append(summaries, w)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if a.kills != b.kills {
+			return a.kills > b.kills
+		}
+		if a.peakUtilization != b.peakUtilization {
+			return a.peakUtilization > b.peakUtilization
+		}
+		return a.namespace+"/"+a.workload < b.namespace+"/"+b.workload
+	})
+	return summaries, unattributed
+}
+
+func formatOOMSummaries(summaries []*workloadOOMSummary, unattributed map[string]int, clusterName string, lookback time.Duration) string {
+	builder := new(strings.Builder)
+	if len(summaries) == 0 && len(unattributed) == 0 {
+		fmt.Fprintf(builder, "No OOM kills and no containers above %.0f%% of their memory limit in cluster %s over the last %s.\n", nearLimitRatio*100, clusterName, lookback)
+		return builder.String()
+	}
+
+	if len(summaries) > 0 {
+		fmt.Fprintf(builder, "Workloads of cluster %s that were OOM killed or used more than %.0f%% of their memory limit over the last %s:\n\n", clusterName, nearLimitRatio*100, lookback)
+		builder.WriteString("| Namespace | Workload | Pods | OOM kills | Last kill | Memory limit | Peak usage |\n")
+		builder.WriteString("|---|---|---|---|---|---|---|\n")
+		for _, w := range summaries {
+			lastKill := "-"
+			if !w.lastKill.IsZero() {
+				lastKill = w.lastKill.UTC().Format(time.RFC3339)
+			}
+			limit, peak := "-", "-"
+			if w.limitBytes > 0 {
+				limit = fmt.Sprintf("%.0fMi", float64(w.limitBytes)/(1<<20))
+				peak = fmt.Sprintf("%.0fMi (%.0f%%)", w.peakUtilization*float64(w.limitBytes)/(1<<20), w.peakUtilization*100)
+			}
+			fmt.Fprintf(builder, "| %s | %s | %d | %d | %s | %s | %s |\n", w.namespace, w.workload, len(w.pods), w.kills, lastKill, limit, peak)
+		}
+	}
+
+	if len(unattributed) > 0 {
+		nodes := make([]string, 0, len(unattributed))
+		for node := range unattributed {
+			nodes = append(nodes, node)
+		}
+		sort.Strings(nodes)
+		builder.WriteString("\nKernel OOM kills that do not identify a pod, per node:\n")
+		for _, node := range nodes {
+			fmt.Fprintf(builder, "- %s: %d\n", node, unattributed[node])
+		}
+	}
+	return builder.String()
+}
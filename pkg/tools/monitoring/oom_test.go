@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+	monitoredres "google.golang.org/genproto/googleapis/api/monitoredres"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestWorkloadName(t *testing.T) {
+	tests := map[string]string{
+		"frontend-7d9c8b6f5d-x2x9k": "frontend",
+		"fluentbit-gke-qlz7v":       "fluentbit-gke",
+		"postgres-0":                "postgres",
+		"nginx-proxy":               "nginx-proxy",
+		"standalone":                "standalone",
+	}
+	for pod, want := range tests {
+		if got := workloadName(pod); got != want {
+			t.Errorf("workloadName(%q) = %q, want %q", pod, got, want)
+		}
+	}
+}
+
+func TestParseOOMEvent(t *testing.T) {
+	payload, err := structpb.NewStruct(map[string]any{
+		"reason":         "OOMKilled",
+		"involvedObject": map[string]any{"kind": "Pod", "name": "api-5f7b9c6d8-abcde", "namespace": "prod"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	entry := &loggingpb.LogEntry{
+		Timestamp: timestamppb.New(ts),
+		Resource:  &monitoredres.MonitoredResource{Type: "k8s_node", Labels: map[string]string{"node_name": "node-1"}},
+		Payload:   &loggingpb.LogEntry_JsonPayload{JsonPayload: payload},
+	}
+	got := parseOOMEvent(entry)
+	want := oomEvent{namespace: "prod", pod: "api-5f7b9c6d8-abcde", node: "node-1", timestamp: ts}
+	if got != want {
+		t.Errorf("parseOOMEvent() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSummarizeOOMKills(t *testing.T) {
+	t1 := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	events := []oomEvent{
+		{namespace: "prod", pod: "api-5f7b9c6d8-bcdfg", timestamp: t1},
+		{namespace: "prod", pod: "api-5f7b9c6d8-hjklm", timestamp: t2},
+		{node: "node-1", timestamp: t2},
+	}
+	memory := []containerMemory{
+		{namespace: "prod", pod: "api-5f7b9c6d8-bcdfg", limitBytes: 512 << 20, utilization: 0.99},
+		{namespace: "prod", pod: "cache-0", limitBytes: 1 << 30, utilization: 0.95},
+		{namespace: "prod", pod: "web-5f7b9c6d8-bcdfg", limitBytes: 1 << 30, utilization: 0.2},
+	}
+
+	summaries, unattributed := summarizeOOMKills(events, memory)
+	if len(summaries) != 2 {
+		t.Fatalf("summarizeOOMKills() returned %d workloads, want 2", len(summaries))
+	}
+	api := summaries[0]
+	if api.workload != "api" || api.kills != 2 || !api.lastKill.Equal(t2) || len(api.pods) != 2 || api.limitBytes != 512<<20 {
+		t.Errorf("api summary = %+v", api)
+	}
+	if cache := summaries[1]; cache.workload != "cache" || cache.kills != 0 || cache.peakUtilization != 0.95 {
+		t.Errorf("cache summary = %+v", cache)
+	}
+	if unattributed["node-1"] != 1 {
+		t.Errorf("unattributed = %v, want 1 kill on node-1", unattributed)
+	}
+
+	got := formatOOMSummaries(summaries, unattributed, "prod-cluster", 24*time.Hour)
+	for _, want := range []string{
+		"| prod | api | 2 | 2 | 2025-06-01T11:00:00Z | 512Mi | 507Mi (99%) |",
+		"| prod | cache | 1 | 0 | - | 1024Mi | 973Mi (95%) |",
+		"- node-1: 1",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatOOMSummaries() = %q, want it to contain %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/googleapi"
+	monitoringv1 "google.golang.org/api/monitoring/v1"
+)
+
+const (
+	defaultQueryRange = time.Hour
+	defaultQueryStep  = "60s"
+	defaultMaxPoints  = 20
+	maxMaxPoints      = 500
+	maxSeries         = 100
+)
+
+type queryTimeSeriesArgs struct {
+	ProjectID    string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Query        string `json:"query" jsonschema:"PromQL query to evaluate, e.g. 'sum by (namespace) (rate(kubernetes_io:container_cpu_core_usage_time{monitored_resource=\"k8s_container\"}[5m]))'."`
+	Start        string `json:"start,omitempty" jsonschema:"Start of the query range. Either an RFC3339 timestamp or a duration before now such as '1h' or '30m'. Defaults to 1h ago."`
+	End          string `json:"end,omitempty" jsonschema:"End of the query range. Either an RFC3339 timestamp or a duration before now. Defaults to now."`
+	Step         string `json:"step,omitempty" jsonschema:"Query resolution step as a Prometheus duration (e.g. '60s', '5m') or floating point seconds. Defaults to 60s."`
+	MaxPoints    int    `json:"max_points,omitempty" jsonschema:"Maximum number of timestamps shown in the table output format; longer series are downsampled evenly. Defaults to 20, maximum 500."`
+	OutputFormat string `json:"output_format,omitempty" jsonschema:"Output format. 'table' (default) is a markdown table with one row per timestamp and one column per series, downsampled to max_points rows. 'csv' has one line per point with a column per label, suitable to save to a file. 'json' has the raw points of every series. csv and json are not downsampled."`
+}
+
+// promMatrixData is the data section of a Prometheus range query response.
+type promMatrixData struct {
+	ResultType string       `json:"resultType"`
+	Result     []promSeries `json:"result"`
+}
+
+type promSeries struct {
+	Metric map[string]string `json:"metric"`
+	Values [][2]any          `json:"values"`
+}
+
+// promErrorResponse is the body returned by the Prometheus API on failure.
+type promErrorResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+}
+
+type samplePoint struct {
+	timestamp time.Time
+	value     string
+}
+
+// labeledSeries is a single time series with its identifying labels and its
+// points in chronological order.
+type labeledSeries struct {
+	labels map[string]string
+	points []samplePoint
+}
+
+func installQueryTimeSeriesTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "query_timeseries",
+		Description: "Query Cloud Monitoring metrics with PromQL over a time range, e.g. CPU or memory usage of a GKE workload. Returns one row per series with its labels and downsampled values. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.queryTimeSeries)
+}
+
+func (h *handlers) queryTimeSeries(ctx context.Context, _ *mcp.CallToolRequest, args *queryTimeSeriesArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.ProjectID == "" {
+		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
+	}
+	if args.Query == "" {
+		return nil, nil, fmt.Errorf("query argument cannot be empty")
+	}
+	if args.Step == "" {
+		args.Step = defaultQueryStep
+	}
+	if args.MaxPoints == 0 {
+		args.MaxPoints = defaultMaxPoints
+	}
+	if args.MaxPoints < 1 || args.MaxPoints > maxMaxPoints {
+		return nil, nil, fmt.Errorf("max_points must be between 1 and %d", maxMaxPoints)
+	}
+	format, err := validateOutputFormat(args.OutputFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	start, err := parseQueryTime(args.Start, now, now.Add(-defaultQueryRange))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid start: %w", err)
+	}
+	end, err := parseQueryTime(args.End, now, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid end: %w", err)
+	}
+	if !start.Before(end) {
+		return nil, nil, fmt.Errorf("start (%s) must be before end (%s)", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	opts, err := h.c.RESTClientOptions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	svc, err := monitoringv1.NewService(ctx, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := svc.Projects.Location.Prometheus.Api.V1.QueryRange(fmt.Sprintf("projects/%s", args.ProjectID), "global", &monitoringv1.QueryRangeRequest{
+		Query: args.Query,
+		Start: start.Format(time.RFC3339),
+		End:   end.Format(time.RFC3339),
+		Step:  args.Step,
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, nil, promQLError(err)
+	}
+
+	// The PromQL API responds with a raw Prometheus JSON document, so the
+	// "data" section ends up in HttpBody.Data.
+	raw, err := json.Marshal(resp.Data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read query response: %w", err)
+	}
+	var data promMatrixData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse query response: %w", err)
+	}
+
+	text, err := formatTimeSeries(&data, format, args.MaxPoints)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// parseQueryTime parses either an RFC3339 timestamp or a duration that is
+// subtracted from now. An empty value yields def.
+func parseQueryTime(value string, now, def time.Time) (time.Time, error) {
+	if value == "" {
+		return def, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC3339 timestamp nor a duration", value)
+	}
+	return now.Add(-d.Abs()), nil
+}
+
+// promQLError relays the error reported by the Prometheus API verbatim, so
+// that query syntax errors can be fixed by the caller.
+func promQLError(err error) error {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Body == "" {
+		return err
+	}
+	var body promErrorResponse
+	if jsonErr := json.Unmarshal([]byte(apiErr.Body), &body); jsonErr != nil || body.Error == "" {
+		return err
+	}
+	if body.ErrorType != "" {
+		return fmt.Errorf("PromQL query failed (%s): %s", body.ErrorType, body.Error)
+	}
+	return fmt.Errorf("PromQL query failed: %s", body.Error)
+}
+
+// formatTimeSeries renders the series of a Prometheus range query result.
+func formatTimeSeries(data *promMatrixData, format string, maxPoints int) (string, error) {
+	series := make([]labeledSeries, 0, min(len(data.Result), maxSeries))
+	for _, s := range data.Result {
+		if len(series) == maxSeries {
+			break
+		}
+		series = append(series, labeledSeries{labels: s.Metric, points: parseSamplePoints(s.Values)})
+	}
+	text, err := renderSeries(series, format, maxPoints)
+	if err != nil {
+		return "", err
+	}
+	if len(data.Result) > maxSeries {
+		text += fmt.Sprintf("\nWarning: Showing the first %d of %d series. Aggregate the query (e.g. with sum by (...)) to reduce the number of series.", maxSeries, len(data.Result))
+	}
+	return text, nil
+}
+
+// formatLabels renders series labels in PromQL selector syntax with sorted
+// keys.
+func formatLabels(metric map[string]string) string {
+	keys := make([]string, 0, len(metric))
+	for k := range metric {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	labels := make([]string, 0, len(keys))
+	for _, k := range keys {
+		labels = append(labels, fmt.Sprintf("%s=%q", k, metric[k]))
+	}
+	return "{" + strings.Join(labels, ", ") + "}"
+}
+
+// parseSamplePoints converts Prometheus [timestamp, "value"] pairs, skipping
+// malformed ones.
+func parseSamplePoints(values [][2]any) []samplePoint {
+	points := make([]samplePoint, 0, len(values))
+	for _, v := range values {
+		ts, ok := v[0].(float64)
+		if !ok {
+			continue
+		}
+		value, ok := v[1].(string)
+		if !ok {
+			continue
+		}
+		sec := int64(ts)
+		points = append(points, samplePoint{
+			timestamp: time.Unix(sec, int64((ts-float64(sec))*float64(time.Second))),
+			value:     value,
+		})
+	}
+	return points
+}
+
+// downsample returns at most max points evenly spread over points, always
+// keeping the first and the last one.
+func downsample[T any](points []T, max int) []T {
+	if len(points) <= max {
+		return points
+	}
+	if max == 1 {
+		return points[len(points)-1:]
+	}
+	sampled := make([]T, 0, max)
+	for i := range max {
+		sampled = append(sampled, points[i*(len(points)-1)/(max-1)])
+	}
+	return sampled
+}
+
+// summarize returns the minimum, maximum and last value of points.
+func summarize(points []samplePoint) (minV, maxV, last string) {
+	if len(points) == 0 {
+		return "-", "-", "-"
+	}
+	var lo, hi float64
+	found := false
+	for _, p := range points {
+		f, err := strconv.ParseFloat(p.value, 64)
+		if err != nil {
+			continue
+		}
+		if !found || f < lo {
+			lo = f
+		}
+		if !found || f > hi {
+			hi = f
+		}
+		found = true
+	}
+	last = points[len(points)-1].value
+	if !found {
+		return "-", "-", last
+	}
+	return strconv.FormatFloat(lo, 'g', 6, 64), strconv.FormatFloat(hi, 'g', 6, 64), last
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestParseQueryTime(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	def := now.Add(-time.Hour)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "", want: def},
+		{value: "30m", want: now.Add(-30 * time.Minute)},
+		{value: "2025-06-01T10:00:00Z", want: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)},
+		{value: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseQueryTime(tt.value, now, def)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseQueryTime(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("parseQueryTime(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	points := make([]samplePoint, 10)
+	for i := range points {
+		points[i] = samplePoint{timestamp: time.Unix(int64(i), 0)}
+	}
+	tests := []struct {
+		max  int
+		want []int64
+	}{
+		{max: 20, want: []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{max: 4, want: []int64{0, 3, 6, 9}},
+		{max: 2, want: []int64{0, 9}},
+		{max: 1, want: []int64{9}},
+	}
+	for _, tt := range tests {
+		got := downsample(points, tt.max)
+		if len(got) != len(tt.want) {
+			t.Errorf("downsample(10 points, %d) returned %d points, want %d", tt.max, len(got), len(tt.want))
+			continue
+		}
+		for i, p := range got {
+			if p.timestamp.Unix() != tt.want[i] {
+				t.Errorf("downsample(10 points, %d)[%d] = %d, want %d", tt.max, i, p.timestamp.Unix(), tt.want[i])
+			}
+		}
+	}
+}
+
+func TestFormatTimeSeries(t *testing.T) {
+	raw := `{"resultType":"matrix","result":[{"metric":{"pod":"web-1","namespace":"prod"},"values":[[1748779200,"0.5"],[1748779260,"1.5"],[1748779320,"1"]]}]}`
+	var data promMatrixData
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	got, err := formatTimeSeries(&data, outputFormatTable, 2)
+	if err != nil {
+		t.Fatalf("formatTimeSeries() error = %v", err)
+	}
+	for _, want := range []string{
+		`- S1 {namespace="prod", pod="web-1"}: 3 points, min 0.5, max 1.5, last 1`,
+		"| 2025-06-01T12:00:00Z | 0.5 |",
+		"| 2025-06-01T12:02:00Z | 1 |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatTimeSeries() = %q, want it to contain %q", got, want)
+		}
+	}
+
+	if got, _ := formatTimeSeries(&promMatrixData{}, outputFormatTable, 2); got != "No time series matched the query." {
+		t.Errorf("formatTimeSeries() for no series = %q", got)
+	}
+}
+
+func TestPromQLError(t *testing.T) {
+	apiErr := &googleapi.Error{
+		Code: 400,
+		Body: `{"status":"error","errorType":"bad_data","error":"1:5: parse error: unexpected \")\""}`,
+	}
+	got := promQLError(apiErr).Error()
+	want := `PromQL query failed (bad_data): 1:5: parse error: unexpected ")"`
+	if got != want {
+		t.Errorf("promQLError() = %q, want %q", got, want)
+	}
+
+	other := errors.New("connection refused")
+	if got := promQLError(other); got != other {
+		t.Errorf("promQLError() = %v, want the original error", got)
+	}
+}
@@ -0,0 +1,336 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultAlignmentPeriod = time.Minute
+	defaultSeriesLimit     = 20
+	maxSeriesLimit         = maxSeries
+)
+
+type listTimeSeriesArgs struct {
+	ProjectID          string   `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Preset             string   `json:"preset,omitempty" jsonschema:"Preset GKE metric to query. Expands to the right metric type, resource type and aggregation. One of: node_cpu_allocatable_utilization, node_memory_allocatable_utilization, container_cpu_usage, container_memory_used, container_restart_count, pod_restart_count, pod_network_received_bytes, pod_network_sent_bytes."`
+	Filter             string   `json:"filter,omitempty" jsonschema:"Cloud Monitoring filter, e.g. 'metric.type=\"kubernetes.io/container/cpu/core_usage_time\" AND resource.type=\"k8s_container\"'. Combined with the preset using AND. Either preset or filter is required."`
+	ClusterName        string   `json:"cluster_name,omitempty" jsonschema:"Only include series from this GKE cluster."`
+	Namespace          string   `json:"namespace,omitempty" jsonschema:"Only include series from this Kubernetes namespace. Not supported for node presets."`
+	Start              string   `json:"start,omitempty" jsonschema:"Start of the interval. Either an RFC3339 timestamp or a duration before now such as '1h' or '30m'. Defaults to 1h ago."`
+	End                string   `json:"end,omitempty" jsonschema:"End of the interval. Either an RFC3339 timestamp or a duration before now. Defaults to now."`
+	AlignmentPeriod    string   `json:"alignment_period,omitempty" jsonschema:"Alignment period as a Go duration, e.g. '60s' or '5m'. Defaults to 60s when an aligner is used."`
+	PerSeriesAligner   string   `json:"per_series_aligner,omitempty" jsonschema:"Per-series aligner, e.g. ALIGN_MEAN, ALIGN_RATE, ALIGN_DELTA. Overrides the preset's aligner."`
+	CrossSeriesReducer string   `json:"cross_series_reducer,omitempty" jsonschema:"Cross-series reducer, e.g. REDUCE_SUM, REDUCE_MEAN, REDUCE_MAX. Overrides the preset's reducer."`
+	GroupByFields      []string `json:"group_by_fields,omitempty" jsonschema:"Fields to keep when reducing across series, e.g. ['resource.label.namespace_name']. Overrides the preset's grouping."`
+	Limit              int      `json:"limit,omitempty" jsonschema:"Maximum number of series to return. Defaults to 20, maximum 100."`
+	MaxPoints          int      `json:"max_points,omitempty" jsonschema:"Maximum number of timestamps shown in the table output format; longer series are downsampled evenly. Defaults to 20, maximum 500."`
+	OutputFormat       string   `json:"output_format,omitempty" jsonschema:"Output format. 'table' (default) is a markdown table with one row per timestamp and one column per series, downsampled to max_points rows. 'csv' has one line per point with a column per label, suitable to save to a file. 'json' has the raw points of every series. csv and json are not downsampled."`
+}
+
+// metricPreset is a well-known GKE metric together with the aggregation that
+// makes it readable.
+type metricPreset struct {
+	metricType   string
+	resourceType string
+	aligner      monitoringpb.Aggregation_Aligner
+	reducer      monitoringpb.Aggregation_Reducer
+	groupBy      []string
+}
+
+var metricPresets = map[string]metricPreset{
+	"node_cpu_allocatable_utilization": {
+		metricType:   "kubernetes.io/node/cpu/allocatable_utilization",
+		resourceType: "k8s_node",
+		aligner:      monitoringpb.Aggregation_ALIGN_MEAN,
+	},
+	"node_memory_allocatable_utilization": {
+		metricType:   "kubernetes.io/node/memory/allocatable_utilization",
+		resourceType: "k8s_node",
+		aligner:      monitoringpb.Aggregation_ALIGN_MEAN,
+	},
+	"container_cpu_usage": {
+		metricType:   "kubernetes.io/container/cpu/core_usage_time",
+		resourceType: "k8s_container",
+		aligner:      monitoringpb.Aggregation_ALIGN_RATE,
+	},
+	"container_memory_used": {
+		metricType:   "kubernetes.io/container/memory/used_bytes",
+		resourceType: "k8s_container",
+		aligner:      monitoringpb.Aggregation_ALIGN_MEAN,
+		reducer:      monitoringpb.Aggregation_REDUCE_SUM,
+		groupBy:      []string{"resource.label.namespace_name", "resource.label.pod_name", "resource.label.container_name"},
+	},
+	"container_restart_count": {
+		metricType:   "kubernetes.io/container/restart_count",
+		resourceType: "k8s_container",
+		aligner:      monitoringpb.Aggregation_ALIGN_DELTA,
+	},
+	"pod_restart_count": {
+		metricType:   "kubernetes.io/container/restart_count",
+		resourceType: "k8s_container",
+		aligner:      monitoringpb.Aggregation_ALIGN_DELTA,
+		reducer:      monitoringpb.Aggregation_REDUCE_SUM,
+		groupBy:      []string{"resource.label.namespace_name", "resource.label.pod_name"},
+	},
+	"pod_network_received_bytes": {
+		metricType:   "kubernetes.io/pod/network/received_bytes_count",
+		resourceType: "k8s_pod",
+		aligner:      monitoringpb.Aggregation_ALIGN_RATE,
+	},
+	"pod_network_sent_bytes": {
+		metricType:   "kubernetes.io/pod/network/sent_bytes_count",
+		resourceType: "k8s_pod",
+		aligner:      monitoringpb.Aggregation_ALIGN_RATE,
+	},
+}
+
+func installListTimeSeriesTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "list_time_series",
+		Description: "List Cloud Monitoring time series using a monitoring filter or a GKE metric preset, with optional alignment and cross-series aggregation. Prefer the presets over guessing kubernetes.io metric names. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.listTimeSeries)
+}
+
+func (h *handlers) listTimeSeries(ctx context.Context, _ *mcp.CallToolRequest, args *listTimeSeriesArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.ProjectID == "" {
+		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
+	}
+	if args.Limit == 0 {
+		args.Limit = defaultSeriesLimit
+	}
+	if args.Limit < 1 || args.Limit > maxSeriesLimit {
+		return nil, nil, fmt.Errorf("limit must be between 1 and %d", maxSeriesLimit)
+	}
+	if args.MaxPoints == 0 {
+		args.MaxPoints = defaultMaxPoints
+	}
+	if args.MaxPoints < 1 || args.MaxPoints > maxMaxPoints {
+		return nil, nil, fmt.Errorf("max_points must be between 1 and %d", maxMaxPoints)
+	}
+	format, err := validateOutputFormat(args.OutputFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	start, err := parseQueryTime(args.Start, now, now.Add(-defaultQueryRange))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid start: %w", err)
+	}
+	end, err := parseQueryTime(args.End, now, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid end: %w", err)
+	}
+	if !start.Before(end) {
+		return nil, nil, fmt.Errorf("start (%s) must be before end (%s)", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	req, err := buildListTimeSeriesRequest(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Interval = &monitoringpb.TimeInterval{
+		StartTime: timestamppb.New(start),
+		EndTime:   timestamppb.New(end),
+	}
+
+	it := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*monitoringpb.TimeSeries] {
+		req.PageToken = pageToken
+		return h.metricClient.ListTimeSeries(ctx, req)
+	})
+	collected, err := retry.Collect(it, args.Limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	series := make([]labeledSeries, 0, len(collected.Items))
+	for _, ts := range collected.Items {
+		series = append(series, toLabeledSeries(ts))
+	}
+	warning := collected.PartialWarning()
+	if collected.Truncated {
+		warning += fmt.Sprintf("\n\nWarning: Results limited to %d series. Narrow the filter, aggregate with cross_series_reducer, or raise limit to see more.", args.Limit)
+	}
+
+	text, err := renderSeries(series, format, args.MaxPoints)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Filter: %s\n\n%s%s", req.Filter, text, warning)},
+		},
+	}, nil, nil
+}
+
+// buildListTimeSeriesRequest builds the filter and aggregation of a
+// ListTimeSeries request from the preset and the explicit arguments, which
+// take precedence over the preset.
+func buildListTimeSeriesRequest(args *listTimeSeriesArgs) (*monitoringpb.ListTimeSeriesRequest, error) {
+	if args.Preset == "" && args.Filter == "" {
+		return nil, fmt.Errorf("either preset or filter argument must be set")
+	}
+
+	var clauses []string
+	var preset metricPreset
+	if args.Preset != "" {
+		var ok bool
+		preset, ok = metricPresets[args.Preset]
+		if !ok {
+			names := make([]string, 0, len(metricPresets))
+			for name := range metricPresets {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			return nil, fmt.Errorf("unknown preset %q, must be one of: %s", args.Preset, strings.Join(names, ", "))
+		}
+		if args.Namespace != "" && preset.resourceType == "k8s_node" {
+			return nil, fmt.Errorf("namespace is not supported for preset %q because node metrics are not namespaced", args.Preset)
+		}
+		clauses = append(clauses,
+			fmt.Sprintf("metric.type=%s", strconv.Quote(preset.metricType)),
+			fmt.Sprintf("resource.type=%s", strconv.Quote(preset.resourceType)))
+	}
+	if args.ClusterName != "" {
+		clauses = append(clauses, fmt.Sprintf("resource.label.cluster_name=%s", strconv.Quote(args.ClusterName)))
+	}
+	if args.Namespace != "" {
+		clauses = append(clauses, fmt.Sprintf("resource.label.namespace_name=%s", strconv.Quote(args.Namespace)))
+	}
+	if args.Filter != "" {
+		if len(clauses) > 0 {
+			clauses = append(clauses, "("+args.Filter+")")
+		} else {
+			clauses = append(clauses, args.Filter)
+		}
+	}
+
+	aligner := preset.aligner
+	if args.PerSeriesAligner != "" {
+		v, ok := monitoringpb.Aggregation_Aligner_value[strings.ToUpper(args.PerSeriesAligner)]
+		if !ok {
+			return nil, fmt.Errorf("unknown per_series_aligner %q", args.PerSeriesAligner)
+		}
+		aligner = monitoringpb.Aggregation_Aligner(v)
+	}
+	reducer := preset.reducer
+	groupBy := preset.groupBy
+	if args.CrossSeriesReducer != "" {
+		v, ok := monitoringpb.Aggregation_Reducer_value[strings.ToUpper(args.CrossSeriesReducer)]
+		if !ok {
+			return nil, fmt.Errorf("unknown cross_series_reducer %q", args.CrossSeriesReducer)
+		}
+		reducer = monitoringpb.Aggregation_Reducer(v)
+		groupBy = nil
+	}
+	if len(args.GroupByFields) > 0 {
+		groupBy = args.GroupByFields
+	}
+
+	req := &monitoringpb.ListTimeSeriesRequest{
+		Filter: strings.Join(clauses, " AND "),
+		View:   monitoringpb.ListTimeSeriesRequest_FULL,
+	}
+	if args.ProjectID != "" {
+		req.Name = fmt.Sprintf("projects/%s", args.ProjectID)
+	}
+	if aligner == monitoringpb.Aggregation_ALIGN_NONE && reducer == monitoringpb.Aggregation_REDUCE_NONE {
+		if args.AlignmentPeriod != "" {
+			return nil, fmt.Errorf("alignment_period requires per_series_aligner")
+		}
+		return req, nil
+	}
+	if reducer != monitoringpb.Aggregation_REDUCE_NONE && aligner == monitoringpb.Aggregation_ALIGN_NONE {
+		return nil, fmt.Errorf("cross_series_reducer requires per_series_aligner")
+	}
+
+	period := defaultAlignmentPeriod
+	if args.AlignmentPeriod != "" {
+		d, err := time.ParseDuration(args.AlignmentPeriod)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alignment_period: %w", err)
+		}
+		if d < time.Minute {
+			return nil, fmt.Errorf("alignment_period must be at least 60s")
+		}
+		period = d
+	}
+	req.Aggregation = &monitoringpb.Aggregation{
+		AlignmentPeriod:    durationpb.New(period),
+		PerSeriesAligner:   aligner,
+		CrossSeriesReducer: reducer,
+		GroupByFields:      groupBy,
+	}
+	return req, nil
+}
+
+// toLabeledSeries flattens the metric and resource labels of ts and returns its
+// points oldest first.
+func toLabeledSeries(ts *monitoringpb.TimeSeries) labeledSeries {
+	labels := map[string]string{}
+	for k, v := range ts.GetResource().GetLabels() {
+		if k == "project_id" {
+			continue
+		}
+		labels[k] = v
+	}
+	for k, v := range ts.GetMetric().GetLabels() {
+		labels[k] = v
+	}
+	points := make([]samplePoint, 0, len(ts.GetPoints()))
+	// The API returns points in reverse time order.
+	for _, p := range slices.Backward(ts.GetPoints()) {
+		points = append(points, samplePoint{
+			timestamp: p.GetInterval().GetEndTime().AsTime(),
+			value:     formatTypedValue(p.GetValue()),
+		})
+	}
+	return labeledSeries{labels: labels, points: points}
+}
+
+func formatTypedValue(v *monitoringpb.TypedValue) string {
+	switch v := v.GetValue().(type) {
+	case *monitoringpb.TypedValue_DoubleValue:
+		return strconv.FormatFloat(v.DoubleValue, 'g', 6, 64)
+	case *monitoringpb.TypedValue_Int64Value:
+		return strconv.FormatInt(v.Int64Value, 10)
+	case *monitoringpb.TypedValue_BoolValue:
+		return strconv.FormatBool(v.BoolValue)
+	case *monitoringpb.TypedValue_StringValue:
+		return v.StringValue
+	case *monitoringpb.TypedValue_DistributionValue:
+		return strconv.FormatFloat(v.DistributionValue.GetMean(), 'g', 6, 64)
+	default:
+		return "-"
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestBuildListTimeSeriesRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    *listTimeSeriesArgs
+		want    *monitoringpb.ListTimeSeriesRequest
+		wantErr bool
+	}{
+		{
+			name: "preset with cluster and namespace",
+			args: &listTimeSeriesArgs{
+				ProjectID:   "test-project",
+				Preset:      "pod_restart_count",
+				ClusterName: "prod",
+				Namespace:   "web",
+			},
+			want: &monitoringpb.ListTimeSeriesRequest{
+				Name:   "projects/test-project",
+				Filter: `metric.type="kubernetes.io/container/restart_count" AND resource.type="k8s_container" AND resource.label.cluster_name="prod" AND resource.label.namespace_name="web"`,
+				View:   monitoringpb.ListTimeSeriesRequest_FULL,
+				Aggregation: &monitoringpb.Aggregation{
+					AlignmentPeriod:    durationpb.New(time.Minute),
+					PerSeriesAligner:   monitoringpb.Aggregation_ALIGN_DELTA,
+					CrossSeriesReducer: monitoringpb.Aggregation_REDUCE_SUM,
+					GroupByFields:      []string{"resource.label.namespace_name", "resource.label.pod_name"},
+				},
+			},
+		},
+		{
+			name: "preset with overrides and extra filter",
+			args: &listTimeSeriesArgs{
+				ProjectID:          "test-project",
+				Preset:             "container_cpu_usage",
+				Filter:             `resource.label.container_name="app"`,
+				AlignmentPeriod:    "5m",
+				CrossSeriesReducer: "reduce_sum",
+				GroupByFields:      []string{"resource.label.namespace_name"},
+			},
+			want: &monitoringpb.ListTimeSeriesRequest{
+				Name:   "projects/test-project",
+				Filter: `metric.type="kubernetes.io/container/cpu/core_usage_time" AND resource.type="k8s_container" AND (resource.label.container_name="app")`,
+				View:   monitoringpb.ListTimeSeriesRequest_FULL,
+				Aggregation: &monitoringpb.Aggregation{
+					AlignmentPeriod:    durationpb.New(5 * time.Minute),
+					PerSeriesAligner:   monitoringpb.Aggregation_ALIGN_RATE,
+					CrossSeriesReducer: monitoringpb.Aggregation_REDUCE_SUM,
+					GroupByFields:      []string{"resource.label.namespace_name"},
+				},
+			},
+		},
+		{
+			name: "raw filter without aggregation",
+			args: &listTimeSeriesArgs{
+				ProjectID: "test-project",
+				Filter:    `metric.type="kubernetes.io/node/cpu/core_usage_time"`,
+			},
+			want: &monitoringpb.ListTimeSeriesRequest{
+				Name:   "projects/test-project",
+				Filter: `metric.type="kubernetes.io/node/cpu/core_usage_time"`,
+				View:   monitoringpb.ListTimeSeriesRequest_FULL,
+			},
+		},
+		{
+			name:    "neither preset nor filter",
+			args:    &listTimeSeriesArgs{ProjectID: "test-project"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown preset",
+			args:    &listTimeSeriesArgs{Preset: "cpu"},
+			wantErr: true,
+		},
+		{
+			name:    "namespace with node preset",
+			args:    &listTimeSeriesArgs{Preset: "node_cpu_allocatable_utilization", Namespace: "web"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown aligner",
+			args:    &listTimeSeriesArgs{Filter: "x", PerSeriesAligner: "ALIGN_AVERAGE"},
+			wantErr: true,
+		},
+		{
+			name:    "reducer without aligner",
+			args:    &listTimeSeriesArgs{Filter: "x", CrossSeriesReducer: "REDUCE_SUM"},
+			wantErr: true,
+		},
+		{
+			name:    "alignment period too short",
+			args:    &listTimeSeriesArgs{Filter: "x", PerSeriesAligner: "ALIGN_MEAN", AlignmentPeriod: "10s"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildListTimeSeriesRequest(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildListTimeSeriesRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("buildListTimeSeriesRequest() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,445 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// uptimeWindow is the window over which the pass ratio of uptime checks
+	// is computed.
+	uptimeWindow = 10 * time.Minute
+	// sloBurnRateLookback is the lookback of the reported SLO burn rate.
+	sloBurnRateLookback = time.Hour
+	// maxSLOs bounds the per-SLO time series queries.
+	maxSLOs = 50
+	// maxUptimeChecks bounds the uptime checks listed and their results.
+	maxUptimeChecks = 1000
+	// maxServices and maxServiceSLOs bound the Service Monitoring services
+	// and the SLOs of each service listed.
+	maxServices    = 500
+	maxServiceSLOs = 100
+)
+
+type listUptimeChecksAndSLOsArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Host      string `json:"host,omitempty" jsonschema:"Only include uptime checks whose target host contains this substring."`
+}
+
+// uptimeCheck is an uptime check config with its recent pass ratio.
+type uptimeCheck struct {
+	id, displayName, target string
+	period                  time.Duration
+	// passRatio is the fraction of passing checks in uptimeWindow, or -1 if
+	// there is no data.
+	passRatio float64
+}
+
+// sloSummary is an SLO with its current burn rate and remaining budget.
+// hasBurnRate and hasBudget tell whether the values were reported.
+type sloSummary struct {
+	service, name, displayName string
+	goal                       float64
+	period                     string
+	burnRate, budget           float64
+	hasBurnRate, hasBudget     bool
+}
+
+func installListUptimeChecksAndSLOsTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "list_uptime_checks_and_slos",
+		Description: "List the uptime checks of the project with their pass ratio over the last 10 minutes, and the SLOs of Service Monitoring services with their goal, 1h error budget burn rate and remaining error budget. Use it to answer whether anything is failing or burning error budget right now. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.listUptimeChecksAndSLOs)
+}
+
+func (h *handlers) listUptimeChecksAndSLOs(ctx context.Context, _ *mcp.CallToolRequest, args *listUptimeChecksAndSLOsArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.ProjectID == "" {
+		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
+	}
+	parent := fmt.Sprintf("projects/%s", args.ProjectID)
+
+	checks, warning, err := h.uptimeChecks(ctx, parent, args.Host)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list uptime checks: %w", err)
+	}
+
+	var notes []string
+	if len(checks) > 0 {
+		ratiosWarning, err := uptimePassRatios(ctx, h.metricClient, parent, checks)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("Uptime check results are unavailable: %v", err))
+		}
+		warning += ratiosWarning
+	}
+	slos, slosWarning, err := h.slos(ctx, parent)
+	warning += slosWarning
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("Some SLOs could not be listed: %v", err))
+	}
+	if len(slos) > maxSLOs {
+		notes = append(notes, fmt.Sprintf("Burn rates are only shown for the first %d of %d SLOs.", maxSLOs, len(slos)))
+	}
+	var sloErr error
+	for _, slo := range slos[:min(len(slos), maxSLOs)] {
+		if err := sloValues(ctx, h.metricClient, parent, slo); err != nil {
+			sloErr = err
+		}
+	}
+	if sloErr != nil {
+		notes = append(notes, fmt.Sprintf("Some SLO burn rates could not be read: %v", sloErr))
+	}
+
+	text := formatUptimeChecksAndSLOs(checks, slos)
+	for _, note := range notes {
+		text += fmt.Sprintf("\nNote: %s\n", note)
+	}
+	text += warning
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// uptimeChecks lists the uptime checks of the project whose target host
+// contains host. The returned warning describes checks that were not read.
+func (h *handlers) uptimeChecks(ctx context.Context, parent, host string) ([]*uptimeCheck, string, error) {
+	c, err := monitoring.NewUptimeCheckClient(ctx, h.c.ClientOptions()...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer c.Close()
+
+	req := &monitoringpb.ListUptimeCheckConfigsRequest{Parent: parent}
+	it := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*monitoringpb.UptimeCheckConfig] {
+		req.PageToken = pageToken
+		return c.ListUptimeCheckConfigs(ctx, req)
+	})
+	collected, err := retry.Collect(it, maxUptimeChecks)
+	if err != nil {
+		return nil, "", err
+	}
+	var checks []*uptimeCheck
+	for _, cfg := range collected.Items {
+		if host != "" && !strings.Contains(cfg.GetMonitoredResource().GetLabels()["host"], host) {
+			continue
+		}
+		checks = append(checks, &uptimeCheck{
+			id:          path.Base(cfg.GetName()),
+			displayName: cfg.GetDisplayName(),
+			target:      uptimeCheckTarget(cfg),
+			period:      cfg.GetPeriod().AsDuration(),
+			passRatio:   -1,
+		})
+	}
+	warning := collected.PartialWarning()
+	if collected.Truncated {
+		warning += fmt.Sprintf("\n\nWarning: Only the first %d uptime checks of the project were listed.", maxUptimeChecks)
+	}
+	return checks, warning, nil
+}
+
+// uptimeCheckTarget describes what an uptime check probes, e.g.
+// "HTTPS example.com:443/healthz".
+func uptimeCheckTarget(cfg *monitoringpb.UptimeCheckConfig) string {
+	var target string
+	if r := cfg.GetMonitoredResource(); r != nil {
+		if host := r.GetLabels()["host"]; host != "" {
+			target = host
+		} else {
+			target = r.GetType() + formatLabels(r.GetLabels())
+		}
+	} else if g := cfg.GetResourceGroup(); g != nil {
+		target = fmt.Sprintf("group %s (%s)", g.GetGroupId(), g.GetResourceType())
+	} else if cfg.GetSyntheticMonitor() != nil {
+		target = "synthetic monitor " + cfg.GetSyntheticMonitor().GetCloudFunctionV2().GetName()
+	}
+
+	switch {
+	case cfg.GetHttpCheck() != nil:
+		hc := cfg.GetHttpCheck()
+		scheme := "HTTP"
+		if hc.GetUseSsl() {
+			scheme = "HTTPS"
+		}
+		if hc.GetPort() != 0 {
+			target += fmt.Sprintf(":%d", hc.GetPort())
+		}
+		return fmt.Sprintf("%s %s%s", scheme, target, hc.GetPath())
+	case cfg.GetTcpCheck() != nil:
+		return fmt.Sprintf("TCP %s:%d", target, cfg.GetTcpCheck().GetPort())
+	}
+	return target
+}
+
+// uptimePassRatios sets the fraction of passing checks over uptimeWindow on
+// the checks. The returned warning describes results that were not read.
+func uptimePassRatios(ctx context.Context, c *monitoring.MetricClient, parent string, checks []*uptimeCheck) (string, error) {
+	now := time.Now()
+	req := &monitoringpb.ListTimeSeriesRequest{
+		Name:   parent,
+		Filter: `metric.type="monitoring.googleapis.com/uptime_check/check_passed"`,
+		Interval: &monitoringpb.TimeInterval{
+			StartTime: timestamppb.New(now.Add(-uptimeWindow)),
+			EndTime:   timestamppb.New(now),
+		},
+		Aggregation: &monitoringpb.Aggregation{
+			AlignmentPeriod:    durationpb.New(uptimeWindow),
+			PerSeriesAligner:   monitoringpb.Aggregation_ALIGN_FRACTION_TRUE,
+			CrossSeriesReducer: monitoringpb.Aggregation_REDUCE_MEAN,
+			GroupByFields:      []string{"metric.label.check_id"},
+		},
+		View: monitoringpb.ListTimeSeriesRequest_FULL,
+	}
+	it := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*monitoringpb.TimeSeries] {
+		req.PageToken = pageToken
+		return c.ListTimeSeries(ctx, req)
+	})
+	byID := map[string]*uptimeCheck{}
+	for _, check := range checks {
+		byID[check.id] = check
+	}
+	collected, err := retry.Collect(it, maxUptimeChecks)
+	if err != nil {
+		return "", err
+	}
+	for _, ts := range collected.Items {
+		check, ok := byID[ts.GetMetric().GetLabels()["check_id"]]
+		if ok && len(ts.GetPoints()) > 0 {
+			check.passRatio = ts.GetPoints()[0].GetValue().GetDoubleValue()
+		}
+	}
+	warning := collected.PartialWarning()
+	if collected.Truncated {
+		warning += fmt.Sprintf("\n\nWarning: Only the results of the first %d uptime checks were read.", maxUptimeChecks)
+	}
+	return warning, nil
+}
+
+// slos lists the SLOs of all Service Monitoring services of the project. The
+// returned warning describes services and SLOs that were not read. On error,
+// the SLOs listed until then are returned too.
+func (h *handlers) slos(ctx context.Context, parent string) ([]*sloSummary, string, error) {
+	c, err := monitoring.NewServiceMonitoringClient(ctx, h.c.ClientOptions()...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer c.Close()
+
+	req := &monitoringpb.ListServicesRequest{Parent: parent}
+	services := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*monitoringpb.Service] {
+		req.PageToken = pageToken
+		return c.ListServices(ctx, req)
+	})
+	collectedServices, err := retry.Collect(services, maxServices)
+	if err != nil {
+		return nil, "", err
+	}
+	warning := collectedServices.PartialWarning()
+	if collectedServices.Truncated {
+		warning += fmt.Sprintf("\n\nWarning: Only the SLOs of the first %d services were listed.", maxServices)
+	}
+	var slos []*sloSummary
+	for _, svc := range collectedServices.Items {
+		serviceName := svc.GetDisplayName()
+		if serviceName == "" {
+			serviceName = path.Base(svc.GetName())
+		}
+		sloReq := &monitoringpb.ListServiceLevelObjectivesRequest{Parent: svc.GetName()}
+		it := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*monitoringpb.ServiceLevelObjective] {
+			sloReq.PageToken = pageToken
+			return c.ListServiceLevelObjectives(ctx, sloReq)
+		})
+		collected, err := retry.Collect(it, maxServiceSLOs)
+		if err != nil {
+			return slos, warning, err
+		}
+		warning += collected.PartialWarning()
+		if collected.Truncated {
+			warning += fmt.Sprintf("\n\nWarning: Only the first %d SLOs of service %s were listed.", maxServiceSLOs, serviceName)
+		}
+		for _, slo := range collected.Items {
+			slos = append(slos, &sloSummary{
+				service:     serviceName,
+				name:        slo.GetName(),
+				displayName: slo.GetDisplayName(),
+				goal:        slo.GetGoal(),
+				period:      sloPeriod(slo),
+			})
+		}
+	}
+	return slos, warning, nil
+}
+
+func sloPeriod(slo *monitoringpb.ServiceLevelObjective) string {
+	if p := slo.GetRollingPeriod(); p != nil {
+		d := p.AsDuration()
+		if d%(24*time.Hour) == 0 {
+			return fmt.Sprintf("rolling %dd", d/(24*time.Hour))
+		}
+		return "rolling " + d.String()
+	}
+	return "calendar " + strings.ToLower(slo.GetCalendarPeriod().String())
+}
+
+// sloValues sets the current burn rate and remaining error budget of the SLO
+// using the SLO time series selectors.
+func sloValues(ctx context.Context, c *monitoring.MetricClient, parent string, slo *sloSummary) error {
+	now := time.Now()
+	interval := &monitoringpb.TimeInterval{
+		StartTime: timestamppb.New(now.Add(-uptimeWindow)),
+		EndTime:   timestamppb.New(now),
+	}
+	selectors := []struct {
+		filter string
+		value  *float64
+		ok     *bool
+	}{
+		{fmt.Sprintf("select_slo_burn_rate(%s, %s)", strconv.Quote(slo.name), strconv.Quote(fmt.Sprintf("%ds", int(sloBurnRateLookback.Seconds())))), &slo.burnRate, &slo.hasBurnRate},
+		{fmt.Sprintf("select_slo_budget_fraction(%s)", strconv.Quote(slo.name)), &slo.budget, &slo.hasBudget},
+	}
+	for _, sel := range selectors {
+		req := &monitoringpb.ListTimeSeriesRequest{
+			Name:     parent,
+			Filter:   sel.filter,
+			Interval: interval,
+			View:     monitoringpb.ListTimeSeriesRequest_FULL,
+		}
+		var series []*monitoringpb.TimeSeries
+		err := retry.Do(ctx, retry.DefaultPolicy, func() error {
+			series = nil
+			_, err := iterator.NewPager(c.ListTimeSeries(ctx, req), 1, "").NextPage(&series)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		// Points are returned newest first.
+		if len(series) > 0 && len(series[0].GetPoints()) > 0 {
+			*sel.value = series[0].GetPoints()[0].GetValue().GetDoubleValue()
+			*sel.ok = true
+		}
+	}
+	return nil
+}
+
+// sloStatus classifies an SLO: a burn rate above 1 consumes the error budget
+// faster than the SLO period allows.
+func sloStatus(slo *sloSummary) string {
+	switch {
+	case slo.hasBudget && slo.budget <= 0:
+		return "BUDGET EXHAUSTED"
+	case slo.hasBurnRate && slo.burnRate > 1:
+		return "BURNING"
+	case !slo.hasBurnRate && !slo.hasBudget:
+		return "no data"
+	}
+	return "ok"
+}
+
+func sloStatusRank(slo *sloSummary) int {
+	switch sloStatus(slo) {
+	case "BUDGET EXHAUSTED":
+		return 0
+	case "BURNING":
+		return 1
+	case "ok":
+		return 2
+	}
+	return 3
+}
+
+func formatUptimeChecksAndSLOs(checks []*uptimeCheck, slos []*sloSummary) string {
+	builder := new(strings.Builder)
+
+	failing := 0
+	for _, c := range checks {
+		if c.passRatio >= 0 && c.passRatio < 1 {
+			failing++
+		}
+	}
+	burning := 0
+	for _, s := range slos {
+		if st := sloStatus(s); st == "BURNING" || st == "BUDGET EXHAUSTED" {
+			burning++
+		}
+	}
+	fmt.Fprintf(builder, "%d of %d uptime checks failed in the last %s; %d of %d SLOs are burning error budget faster than sustainable or have exhausted it.\n",
+		failing, len(checks), uptimeWindow, burning, len(slos))
+
+	builder.WriteString("\nUptime checks:\n\n")
+	if len(checks) == 0 {
+		builder.WriteString("No uptime checks found.\n")
+	} else {
+		builder.WriteString("| Check | Target | Period | Passed (last 10m) |\n")
+		builder.WriteString("|---|---|---|---|\n")
+		for _, c := range checks {
+			passed := "no data"
+			if c.passRatio >= 0 {
+				passed = fmt.Sprintf("%.0f%%", c.passRatio*100)
+			}
+			fmt.Fprintf(builder, "| %s (%s) | %s | %s | %s |\n", c.displayName, c.id, c.target, c.period, passed)
+		}
+	}
+
+	builder.WriteString("\nSLOs:\n\n")
+	if len(slos) == 0 {
+		builder.WriteString("No SLOs found.\n")
+		return builder.String()
+	}
+	// SLOs that need attention first.
+	sorted := append([]*sloSummary(nil), slos...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sloStatusRank(sorted[i]) < sloStatusRank(sorted[j])
+	})
+	builder.WriteString("| Service | SLO | Goal | Period | Burn rate (1h) | Budget remaining | Status |\n")
+	builder.WriteString("|---|---|---|---|---|---|---|\n")
+	for _, s := range sorted {
+		burnRate, budget := "-", "-"
+		if s.hasBurnRate {
+			burnRate = strconv.FormatFloat(s.burnRate, 'f', 2, 64)
+		}
+		if s.hasBudget {
+			budget = fmt.Sprintf("%.1f%%", s.budget*100)
+		}
+		name := s.displayName
+		if name == "" {
+			name = path.Base(s.name)
+		}
+		fmt.Fprintf(builder, "| %s | %s | %s%% | %s | %s | %s | %s |\n", s.service, name, strconv.FormatFloat(s.goal*100, 'f', -1, 64), s.period, burnRate, budget, sloStatus(s))
+	}
+	return builder.String()
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	monitoredres "google.golang.org/genproto/googleapis/api/monitoredres"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestUptimeCheckTarget(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *monitoringpb.UptimeCheckConfig
+		want string
+	}{
+		{
+			name: "https",
+			cfg: &monitoringpb.UptimeCheckConfig{
+				Resource: &monitoringpb.UptimeCheckConfig_MonitoredResource{MonitoredResource: &monitoredres.MonitoredResource{
+					Type:   "uptime_url",
+					Labels: map[string]string{"host": "shop.example.com"},
+				}},
+				CheckRequestType: &monitoringpb.UptimeCheckConfig_HttpCheck_{HttpCheck: &monitoringpb.UptimeCheckConfig_HttpCheck{
+					UseSsl: true,
+					Port:   443,
+					Path:   "/healthz",
+				}},
+			},
+			want: "HTTPS shop.example.com:443/healthz",
+		},
+		{
+			name: "tcp on service",
+			cfg: &monitoringpb.UptimeCheckConfig{
+				Resource: &monitoringpb.UptimeCheckConfig_MonitoredResource{MonitoredResource: &monitoredres.MonitoredResource{
+					Type:   "k8s_service",
+					Labels: map[string]string{"service_name": "web"},
+				}},
+				CheckRequestType: &monitoringpb.UptimeCheckConfig_TcpCheck_{TcpCheck: &monitoringpb.UptimeCheckConfig_TcpCheck{Port: 80}},
+			},
+			want: `TCP k8s_service{service_name="web"}:80`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := uptimeCheckTarget(tc.cfg); got != tc.want {
+				t.Errorf("uptimeCheckTarget() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSLOPeriod(t *testing.T) {
+	slo := &monitoringpb.ServiceLevelObjective{
+		Period: &monitoringpb.ServiceLevelObjective_RollingPeriod{RollingPeriod: durationpb.New(28 * 24 * time.Hour)},
+	}
+	if got, want := sloPeriod(slo), "rolling 28d"; got != want {
+		t.Errorf("sloPeriod() = %q, want %q", got, want)
+	}
+}
+
+func TestSLOStatus(t *testing.T) {
+	tests := []struct {
+		slo  sloSummary
+		want string
+	}{
+		{sloSummary{burnRate: 0.5, hasBurnRate: true, budget: 0.8, hasBudget: true}, "ok"},
+		{sloSummary{burnRate: 3, hasBurnRate: true, budget: 0.8, hasBudget: true}, "BURNING"},
+		{sloSummary{burnRate: 3, hasBurnRate: true, budget: -0.1, hasBudget: true}, "BUDGET EXHAUSTED"},
+		{sloSummary{}, "no data"},
+	}
+	for _, tc := range tests {
+		if got := sloStatus(&tc.slo); got != tc.want {
+			t.Errorf("sloStatus(%+v) = %q, want %q", tc.slo, got, tc.want)
+		}
+	}
+}
+
+func TestFormatUptimeChecksAndSLOs(t *testing.T) {
+	checks := []*uptimeCheck{
+		{id: "shop-abc", displayName: "shop", target: "HTTPS shop.example.com/", period: time.Minute, passRatio: 0.5},
+		{id: "api-def", displayName: "api", target: "HTTPS api.example.com/", period: time.Minute, passRatio: -1},
+	}
+	slos := []*sloSummary{
+		{service: "web", name: "projects/p/services/web/serviceLevelObjectives/ok", displayName: "latency", goal: 0.99, period: "rolling 28d", burnRate: 0.2, hasBurnRate: true, budget: 0.9, hasBudget: true},
+		{service: "web", name: "projects/p/services/web/serviceLevelObjectives/avail", goal: 0.999, period: "rolling 28d", burnRate: 14.4, hasBurnRate: true, budget: 0.3, hasBudget: true},
+	}
+
+	got := formatUptimeChecksAndSLOs(checks, slos)
+	for _, want := range []string{
+		"1 of 2 uptime checks failed in the last 10m0s; 1 of 2 SLOs are burning",
+		"| shop (shop-abc) | HTTPS shop.example.com/ | 1m0s | 50% |",
+		"| api (api-def) | HTTPS api.example.com/ | 1m0s | no data |",
+		"| web | avail | 99.9% | rolling 28d | 14.40 | 30.0% | BURNING |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatUptimeChecksAndSLOs() = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Index(got, "| avail |") > strings.Index(got, "| latency |") {
+		t.Errorf("formatUptimeChecksAndSLOs() = %q, want burning SLOs first", got)
+	}
+
+	if got := formatUptimeChecksAndSLOs(nil, nil); !strings.Contains(got, "No uptime checks found.") || !strings.Contains(got, "No SLOs found.") {
+		t.Errorf("formatUptimeChecksAndSLOs(nil, nil) = %q", got)
+	}
+}
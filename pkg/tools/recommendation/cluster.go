@@ -0,0 +1,283 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommendation
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	recommenderpb "cloud.google.com/go/recommender/apiv1/recommenderpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type clusterRecommendationsArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location  string `json:"location" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Name      string `json:"name" jsonschema:"GKE cluster name. Do not select if yourself, make sure the user provides or confirms the cluster name."`
+}
+
+// clusterMatcher tells whether a recommendation targets a cluster, either
+// through the GKE resource of the cluster or the instance groups and VMs of
+// its node pools.
+type clusterMatcher struct {
+	clusterPath string
+	// instanceGroups are the managed instance group names of the node pools.
+	// Node VMs are named after their group without the "-grp" suffix.
+	instanceGroups []string
+}
+
+func newClusterMatcher(cluster *containerpb.Cluster) *clusterMatcher {
+	m := &clusterMatcher{clusterPath: "/clusters/" + cluster.GetName()}
+	urls := cluster.GetInstanceGroupUrls()
+	for _, np := range cluster.GetNodePools() {
+		urls = append(urls, np.GetInstanceGroupUrls()...)
+	}
+	for _, u := range urls {
+		g := path.Base(u)
+		if g != "" && g != "." && !slices.Contains(m.instanceGroups, g) {
+			m.instanceGroups = append(m.instanceGroups, g)
+		}
+	}
+	return m
+}
+
+func (m *clusterMatcher) matches(rec *recommenderpb.Recommendation) bool {
+	for _, g := range rec.GetContent().GetOperationGroups() {
+		for _, op := range g.GetOperations() {
+			if m.matchesResource(op.GetResource()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *clusterMatcher) matchesResource(resource string) bool {
+	if resource == "" {
+		return false
+	}
+	if strings.HasPrefix(resource, "//container.googleapis.com/") {
+		return strings.HasSuffix(resource, m.clusterPath) || strings.Contains(resource, m.clusterPath+"/")
+	}
+	name := path.Base(resource)
+	for _, g := range m.instanceGroups {
+		if name == g || strings.HasPrefix(name, strings.TrimSuffix(g, "-grp")+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+func installClusterRecommendationsTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "cluster_recommendations",
+		Description: "Report the active recommendations for one GKE cluster, from the GKE diagnosis recommender and the machine type and idle resource recommenders of its node VMs, grouped by category with the remediation steps. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.clusterRecommendations)
+}
+
+func (h *handlers) clusterRecommendations(ctx context.Context, _ *mcp.CallToolRequest, args *clusterRecommendationsArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.ProjectID == "" {
+		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
+	}
+	if args.Name == "" {
+		return nil, nil, fmt.Errorf("name argument cannot be empty")
+	}
+	location, err := h.c.ResolveClusterLocation(ctx, args.ProjectID, args.Location, args.Name, h.locateCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	if location == "" {
+		return nil, nil, fmt.Errorf("location argument cannot be empty")
+	}
+	args.Location = location
+
+	cluster, err := h.getCluster(ctx, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var queries []locationRecommendations
+	for _, r := range slices.Sorted(maps.Keys(gkeRecommenders)) {
+		locations := []string{cluster.GetLocation()}
+		if gkeRecommenders[r].zonal {
+			locations = cluster.GetLocations()
+		}
+		for _, l := range locations {
+			queries = append(queries, locationRecommendations{recommender: r, location: l})
+		}
+	}
+	listArgs := &listRecommendationsArgs{
+		ProjectID: args.ProjectID,
+		Limit:     maxRecommendationLimit,
+	}
+	filter := "stateInfo.state = " + recommenderpb.RecommendationStateInfo_ACTIVE.String()
+	var wg sync.WaitGroup
+	for i := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			listLocationRecommendations(ctx, h.recClient, listArgs, filter, recommenderpb.Recommendation_PRIORITY_UNSPECIFIED, &queries[i])
+		}()
+	}
+	wg.Wait()
+
+	matcher := newClusterMatcher(cluster)
+	var recs []*recommenderpb.Recommendation
+	var warnings []string
+	for _, q := range queries {
+		if q.err != nil {
+			warnings = append(warnings, fmt.Sprintf("Listing %s recommendations in %s failed: %v", q.recommender, q.location, q.err))
+			continue
+		}
+		if q.collected.Err != nil {
+			warnings = append(warnings, fmt.Sprintf("Listing %s recommendations in %s stopped early: %v", q.recommender, q.location, q.collected.Err))
+		}
+		for _, rec := range q.collected.Items {
+			if matcher.matches(rec) {
+				recs = append(recs, rec)
+			}
+		}
+	}
+
+	text := formatClusterRecommendations(args.Name, cluster.GetLocation(), recs)
+	for _, w := range warnings {
+		text += fmt.Sprintf("\nWarning: %s\n", w)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+func (h *handlers) getCluster(ctx context.Context, args *clusterRecommendationsArgs) (*containerpb.Cluster, error) {
+	var cluster *containerpb.Cluster
+	err := retry.Do(ctx, retry.DefaultPolicy, func() error {
+		var err error
+		cluster, err = h.cmClient.GetCluster(ctx, &containerpb.GetClusterRequest{
+			Name: fmt.Sprintf("projects/%s/locations/%s/clusters/%s", args.ProjectID, args.Location, args.Name),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s: %w", args.Name, err)
+	}
+	return cluster, nil
+}
+
+// locateCluster returns the locations of the clusters named name in
+// projectID.
+func (h *handlers) locateCluster(ctx context.Context, projectID, name string) ([]string, error) {
+	clusters, err := h.listClusters(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	var locations []string
+	for _, cluster := range clusters {
+		if cluster.GetName() == name {
+			locations = append(locations, cluster.GetLocation())
+		}
+	}
+	return locations, nil
+}
+
+// remediationSteps describes the operations of a recommendation that change
+// resources. "test" operations are preconditions and are left out.
+func remediationSteps(rec *recommenderpb.Recommendation) []string {
+	var steps []string
+	for _, g := range rec.GetContent().GetOperationGroups() {
+		for _, op := range g.GetOperations() {
+			if op.GetAction() == "test" {
+				continue
+			}
+			step := fmt.Sprintf("%s %s", op.GetAction(), describeTarget(op.GetResource()))
+			if op.GetPath() != "" {
+				step += " at " + op.GetPath()
+			}
+			if op.GetValue() != nil {
+				if v, err := protojson.Marshal(op.GetValue()); err == nil {
+					step += " with " + string(v)
+				}
+			}
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// formatClusterRecommendations groups the recommendations by category, with
+// the highest priority first in every category.
+func formatClusterRecommendations(cluster, location string, recs []*recommenderpb.Recommendation) string {
+	builder := new(strings.Builder)
+	if len(recs) == 0 {
+		fmt.Fprintf(builder, "No active recommendations found for cluster %s (%s).\n", cluster, location)
+		return builder.String()
+	}
+	fmt.Fprintf(builder, "Found %d active recommendations for cluster %s (%s).\n", len(recs), cluster, location)
+
+	byCategory := map[string][]*recommenderpb.Recommendation{}
+	for _, rec := range recs {
+		category := rec.GetPrimaryImpact().GetCategory().String()
+		byCategory[category] = append(byCategory[category], rec)
+	}
+	categories := make([]string, 0, len(byCategory))
+	for c := range byCategory {
+		categories = append(categories, c)
+	}
+	sort.Strings(categories)
+
+	for _, category := range categories {
+		group := byCategory[category]
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].GetPriority() > group[j].GetPriority()
+		})
+		fmt.Fprintf(builder, "\n## %s (%d)\n", category, len(group))
+		for _, rec := range group {
+			s := summarizeRecommendation(rec)
+			fmt.Fprintf(builder, "\n- %s (%s): %s\n", s.id, s.priority, s.description)
+			if s.impact != "" {
+				fmt.Fprintf(builder, "  Impact: %s\n", s.impact)
+			}
+			if steps := remediationSteps(rec); len(steps) > 0 {
+				builder.WriteString("  Steps:\n")
+				for i, step := range steps {
+					fmt.Fprintf(builder, "    %d. %s\n", i+1, step)
+				}
+			}
+			for _, c := range suggestedCommands(rec) {
+				if c.command != "" {
+					fmt.Fprintf(builder, "  Suggested command: %s\n", c.command)
+				}
+			}
+			fmt.Fprintf(builder, "  Name: %s\n", rec.GetName())
+		}
+	}
+	return builder.String()
+}
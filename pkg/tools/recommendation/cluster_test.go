@@ -0,0 +1,98 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommendation
+
+import (
+	"strings"
+	"testing"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	recommenderpb "cloud.google.com/go/recommender/apiv1/recommenderpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func recommendationFor(name string, category recommenderpb.Impact_Category, priority recommenderpb.Recommendation_Priority, ops ...*recommenderpb.Operation) *recommenderpb.Recommendation {
+	return &recommenderpb.Recommendation{
+		Name:          "projects/p/locations/us-central1/recommenders/r/recommendations/" + name,
+		Description:   "recommendation " + name,
+		Priority:      priority,
+		PrimaryImpact: &recommenderpb.Impact{Category: category},
+		Content: &recommenderpb.RecommendationContent{OperationGroups: []*recommenderpb.OperationGroup{{
+			Operations: ops,
+		}}},
+	}
+}
+
+func TestClusterMatcher(t *testing.T) {
+	cluster := &containerpb.Cluster{
+		Name: "prod",
+		NodePools: []*containerpb.NodePool{{
+			Name:              "pool-1",
+			InstanceGroupUrls: []string{"https://www.googleapis.com/compute/v1/projects/p/zones/us-central1-a/instanceGroupManagers/gke-prod-pool-1-1a2b3c4d-grp"},
+		}},
+	}
+	m := newClusterMatcher(cluster)
+
+	tests := map[string]bool{
+		"//container.googleapis.com/projects/p/locations/us-central1/clusters/prod":                                  true,
+		"//container.googleapis.com/projects/p/locations/us-central1/clusters/prod/nodePools/pool-1":                 true,
+		"//container.googleapis.com/projects/p/locations/us-central1/clusters/prod-2":                                false,
+		"//compute.googleapis.com/projects/p/zones/us-central1-a/instanceGroupManagers/gke-prod-pool-1-1a2b3c4d-grp": true,
+		"//compute.googleapis.com/projects/p/zones/us-central1-a/instances/gke-prod-pool-1-1a2b3c4d-x9z8":            true,
+		"//compute.googleapis.com/projects/p/zones/us-central1-a/instances/gke-staging-pool-1-5e6f7a8b-x9z8":         false,
+		"": false,
+	}
+	for resource, want := range tests {
+		if got := m.matchesResource(resource); got != want {
+			t.Errorf("matchesResource(%q) = %v, want %v", resource, got, want)
+		}
+	}
+}
+
+func TestFormatClusterRecommendations(t *testing.T) {
+	recs := []*recommenderpb.Recommendation{
+		recommendationFor("low", recommenderpb.Impact_RELIABILITY, recommenderpb.Recommendation_P4),
+		recommendationFor("resize", recommenderpb.Impact_COST, recommenderpb.Recommendation_P3,
+			&recommenderpb.Operation{Action: "test", Resource: "//compute.googleapis.com/projects/p/zones/z/instances/gke-prod-pool-1-abc-x1", Path: "/machineType"},
+			&recommenderpb.Operation{Action: "replace", Resource: "//compute.googleapis.com/projects/p/zones/z/instances/gke-prod-pool-1-abc-x1", Path: "/machineType", PathValue: &recommenderpb.Operation_Value{Value: structpb.NewStringValue("e2-standard-4")}},
+		),
+		recommendationFor("high", recommenderpb.Impact_RELIABILITY, recommenderpb.Recommendation_P1),
+	}
+
+	got := formatClusterRecommendations("prod", "us-central1", recs)
+	for _, want := range []string{
+		"Found 3 active recommendations for cluster prod (us-central1).",
+		"## COST (1)",
+		`1. replace compute.googleapis.com/projects/p/zones/z/instances/gke-prod-pool-1-abc-x1 at /machineType with "e2-standard-4"`,
+		"## RELIABILITY (2)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatClusterRecommendations() = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "2. ") {
+		t.Errorf("formatClusterRecommendations() = %q, want test operations left out", got)
+	}
+	if strings.Index(got, "## COST") > strings.Index(got, "## RELIABILITY") {
+		t.Errorf("formatClusterRecommendations() = %q, want categories sorted", got)
+	}
+	if strings.Index(got, "- high") > strings.Index(got, "- low") {
+		t.Errorf("formatClusterRecommendations() = %q, want the highest priority first", got)
+	}
+
+	if got := formatClusterRecommendations("prod", "us-central1", nil); !strings.Contains(got, "No active recommendations found for cluster prod") {
+		t.Errorf("formatClusterRecommendations() for no recommendations = %q", got)
+	}
+}
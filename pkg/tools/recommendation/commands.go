@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommendation
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+
+	recommenderpb "cloud.google.com/go/recommender/apiv1/recommenderpb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var (
+	// computeResourceRegexp matches zonal Compute Engine resource names, e.g.
+	// "//compute.googleapis.com/projects/p/zones/z/instances/i".
+	computeResourceRegexp = regexp.MustCompile(`^//compute\.googleapis\.com/projects/([^/]+)/zones/([^/]+)/(instances|disks)/([^/]+)$`)
+	// nodePoolResourceRegexp matches GKE node pool resource names.
+	nodePoolResourceRegexp = regexp.MustCompile(`^//container\.googleapis\.com/projects/([^/]+)/(?:locations|zones)/([^/]+)/clusters/([^/]+)/nodePools/([^/]+)$`)
+)
+
+// suggestedCommand is the remediation of one operation: a gcloud command for
+// the common operation shapes, or the raw operation otherwise.
+type suggestedCommand struct {
+	command string
+	raw     string
+}
+
+// suggestedCommands translates the operations of a recommendation that change
+// resources into gcloud commands. "test" operations are preconditions and are
+// left out.
+func suggestedCommands(rec *recommenderpb.Recommendation) []suggestedCommand {
+	var commands []suggestedCommand
+	for _, g := range rec.GetContent().GetOperationGroups() {
+		for _, op := range g.GetOperations() {
+			if op.GetAction() == "test" {
+				continue
+			}
+			if cmd, ok := operationCommand(op); ok {
+				commands = append(commands, suggestedCommand{command: cmd})
+				continue
+			}
+			raw, err := protojson.Marshal(op)
+			if err != nil {
+				continue
+			}
+			commands = append(commands, suggestedCommand{raw: string(raw)})
+		}
+	}
+	return commands
+}
+
+// operationCommand returns the gcloud command for the known operation
+// shapes: machine type changes, stopping and deleting idle VMs and disks, and
+// node pool resizes and machine type changes.
+func operationCommand(op *recommenderpb.Operation) (string, bool) {
+	value := op.GetValue().GetStringValue()
+	if m := computeResourceRegexp.FindStringSubmatch(op.GetResource()); m != nil {
+		project, zone, kind, name := m[1], m[2], m[3], m[4]
+		switch {
+		case kind == "instances" && op.GetAction() == "replace" && op.GetPath() == "/machineType" && value != "":
+			return fmt.Sprintf("gcloud compute instances set-machine-type %s --machine-type=%s --zone=%s --project=%s", name, path.Base(value), zone, project), true
+		case kind == "instances" && op.GetAction() == "replace" && op.GetPath() == "/status" && value == "TERMINATED":
+			return fmt.Sprintf("gcloud compute instances stop %s --zone=%s --project=%s", name, zone, project), true
+		case op.GetAction() == "remove" && op.GetPath() == "/":
+			return fmt.Sprintf("gcloud compute %s delete %s --zone=%s --project=%s", kind, name, zone, project), true
+		}
+		return "", false
+	}
+	if m := nodePoolResourceRegexp.FindStringSubmatch(op.GetResource()); m != nil && op.GetAction() == "replace" {
+		project, location, cluster, nodePool := m[1], m[2], m[3], m[4]
+		switch op.GetPath() {
+		case "/nodeCount", "/initialNodeCount":
+			count, ok := nodeCount(op)
+			if !ok {
+				return "", false
+			}
+			return fmt.Sprintf("gcloud container clusters resize %s --node-pool=%s --num-nodes=%d --location=%s --project=%s", cluster, nodePool, count, location, project), true
+		case "/config/machineType":
+			if value == "" {
+				return "", false
+			}
+			return fmt.Sprintf("gcloud container node-pools update %s --cluster=%s --machine-type=%s --location=%s --project=%s", nodePool, cluster, path.Base(value), location, project), true
+		}
+	}
+	return "", false
+}
+
+// nodeCount reads a node count given either as a number or a string.
+func nodeCount(op *recommenderpb.Operation) (int, bool) {
+	v := op.GetValue()
+	if v == nil {
+		return 0, false
+	}
+	if s := v.GetStringValue(); s != "" {
+		n, err := strconv.Atoi(s)
+		return n, err == nil && n >= 0
+	}
+	n := v.GetNumberValue()
+	return int(n), n >= 0 && n == float64(int(n))
+}
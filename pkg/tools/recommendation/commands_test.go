@@ -0,0 +1,115 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommendation
+
+import (
+	"strings"
+	"testing"
+
+	recommenderpb "cloud.google.com/go/recommender/apiv1/recommenderpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func operation(action, resource, path string, value *structpb.Value) *recommenderpb.Operation {
+	op := &recommenderpb.Operation{Action: action, Resource: resource, Path: path}
+	if value != nil {
+		op.PathValue = &recommenderpb.Operation_Value{Value: value}
+	}
+	return op
+}
+
+func TestOperationCommand(t *testing.T) {
+	const (
+		instance = "//compute.googleapis.com/projects/p/zones/us-central1-a/instances/gke-prod-pool-1-abc-x1"
+		nodePool = "//container.googleapis.com/projects/p/locations/us-central1/clusters/prod/nodePools/pool-1"
+	)
+	tests := []struct {
+		name   string
+		op     *recommenderpb.Operation
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "machine type change",
+			op:     operation("replace", instance, "/machineType", structpb.NewStringValue("zones/us-central1-a/machineTypes/e2-standard-4")),
+			want:   "gcloud compute instances set-machine-type gke-prod-pool-1-abc-x1 --machine-type=e2-standard-4 --zone=us-central1-a --project=p",
+			wantOK: true,
+		},
+		{
+			name:   "node pool resize",
+			op:     operation("replace", nodePool, "/nodeCount", structpb.NewNumberValue(2)),
+			want:   "gcloud container clusters resize prod --node-pool=pool-1 --num-nodes=2 --location=us-central1 --project=p",
+			wantOK: true,
+		},
+		{
+			name:   "node pool resize with string count",
+			op:     operation("replace", nodePool, "/initialNodeCount", structpb.NewStringValue("3")),
+			want:   "gcloud container clusters resize prod --node-pool=pool-1 --num-nodes=3 --location=us-central1 --project=p",
+			wantOK: true,
+		},
+		{
+			name:   "node pool machine type change",
+			op:     operation("replace", nodePool, "/config/machineType", structpb.NewStringValue("e2-standard-2")),
+			want:   "gcloud container node-pools update pool-1 --cluster=prod --machine-type=e2-standard-2 --location=us-central1 --project=p",
+			wantOK: true,
+		},
+		{
+			name:   "idle disk",
+			op:     operation("remove", "//compute.googleapis.com/projects/p/zones/us-central1-a/disks/pvc-123", "/", nil),
+			want:   "gcloud compute disks delete pvc-123 --zone=us-central1-a --project=p",
+			wantOK: true,
+		},
+		{
+			name: "fractional node count",
+			op:   operation("replace", nodePool, "/nodeCount", structpb.NewNumberValue(1.5)),
+		},
+		{
+			name: "unknown shape",
+			op:   operation("add", instance, "/labels/env", structpb.NewStringValue("prod")),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := operationCommand(tc.op)
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("operationCommand() = %q, %v, want %q, %v", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestSuggestedCommandsFallBackToRawOperation(t *testing.T) {
+	rec := recommendationFor("r", recommenderpb.Impact_COST, recommenderpb.Recommendation_P2,
+		operation("test", "//compute.googleapis.com/projects/p/zones/z/instances/i", "/machineType", structpb.NewStringValue("n1-standard-4")),
+		operation("replace", "//compute.googleapis.com/projects/p/zones/z/instances/i", "/machineType", structpb.NewStringValue("e2-standard-2")),
+		operation("add", "//compute.googleapis.com/projects/p/zones/z/instances/i", "/labels/env", structpb.NewStringValue("prod")),
+	)
+
+	got := suggestedCommands(rec)
+	if len(got) != 2 {
+		t.Fatalf("suggestedCommands() = %+v, want 2 entries", got)
+	}
+	if got[0].command == "" {
+		t.Errorf("suggestedCommands()[0] = %+v, want a command", got[0])
+	}
+	if got[1].command != "" || !strings.Contains(got[1].raw, `"path":"/labels/env"`) {
+		t.Errorf("suggestedCommands()[1] = %+v, want the raw operation", got[1])
+	}
+
+	summary := formatRecommendation(rec, viewSummary)
+	if !strings.Contains(summary, "Suggested command: gcloud compute instances set-machine-type i --machine-type=e2-standard-2 --zone=z --project=p") {
+		t.Errorf("formatRecommendation() = %q, want the suggested command", summary)
+	}
+}
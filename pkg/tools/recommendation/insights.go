@@ -0,0 +1,141 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommendation
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	recommenderpb "cloud.google.com/go/recommender/apiv1/recommenderpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// gkeInsightType holds the observations behind the recommendations of the
+// google.container.DiagnosisRecommender.
+const gkeInsightType = "google.container.DiagnosisInsight"
+
+const maxInsights = 100
+
+type listInsightsArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location  string `json:"location,omitempty" jsonschema:"GKE cluster location. Use the default if the user doesn't provide it."`
+	State     string `json:"state,omitempty" jsonschema:"Only include insights in this state: ACTIVE, ACCEPTED or DISMISSED. Defaults to all states."`
+	Severity  string `json:"severity,omitempty" jsonschema:"Only include insights of this severity: LOW, MEDIUM, HIGH or CRITICAL. Defaults to all severities."`
+}
+
+func installListInsightsTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "list_insights",
+		Description: "List GKE insights, the observations behind GKE recommendations such as deprecated API usage, with their description, category, severity, target resources and associated recommendations. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.listInsights)
+}
+
+func (h *handlers) listInsights(ctx context.Context, _ *mcp.CallToolRequest, args *listInsightsArgs) (*mcp.CallToolResult, any, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.ProjectID == "" {
+		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
+	}
+	args.Location = h.c.ResolveLocation(args.Location)
+	if args.Location == "" {
+		return nil, nil, fmt.Errorf("location argument cannot be empty")
+	}
+	filter, err := insightFilter(args.State, args.Severity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req := &recommenderpb.ListInsightsRequest{
+		Parent: fmt.Sprintf("projects/%s/locations/%s/insightTypes/%s", args.ProjectID, args.Location, gkeInsightType),
+		Filter: filter,
+	}
+	it := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*recommenderpb.Insight] {
+		req.PageToken = pageToken
+		return h.recClient.ListInsights(ctx, req)
+	})
+	collected, err := retry.Collect(it, maxInsights)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	text := formatInsights(collected.Items) + collected.PartialWarning()
+	if collected.Truncated {
+		text += fmt.Sprintf("\n\nWarning: Results limited to %d insights. Filter by state or severity to narrow them down.", maxInsights)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// insightFilter builds the ListInsights filter for the state and severity
+// arguments.
+func insightFilter(state, severity string) (string, error) {
+	var clauses []string
+	if state != "" {
+		state = strings.ToUpper(state)
+		if _, ok := recommenderpb.InsightStateInfo_State_value[state]; !ok || state == "STATE_UNSPECIFIED" {
+			return "", fmt.Errorf("unknown state %q, must be one of ACTIVE, ACCEPTED or DISMISSED", state)
+		}
+		clauses = append(clauses, "stateInfo.state = "+state)
+	}
+	if severity != "" {
+		severity = strings.ToUpper(severity)
+		if _, ok := recommenderpb.Insight_Severity_value[severity]; !ok || severity == "SEVERITY_UNSPECIFIED" {
+			return "", fmt.Errorf("unknown severity %q, must be one of LOW, MEDIUM, HIGH or CRITICAL", severity)
+		}
+		clauses = append(clauses, "severity = "+severity)
+	}
+	return strings.Join(clauses, " AND "), nil
+}
+
+func formatInsights(insights []*recommenderpb.Insight) string {
+	if len(insights) == 0 {
+		return "No insights found."
+	}
+	builder := new(strings.Builder)
+	fmt.Fprintf(builder, "Found %d insights.\n", len(insights))
+	for _, in := range insights {
+		fmt.Fprintf(builder, "\n- %s [%s, %s, %s]\n", path.Base(in.GetName()), in.GetSeverity(), in.GetCategory(), in.GetStateInfo().GetState())
+		fmt.Fprintf(builder, "  Description: %s\n", in.GetDescription())
+		if in.GetInsightSubtype() != "" {
+			fmt.Fprintf(builder, "  Subtype: %s\n", in.GetInsightSubtype())
+		}
+		if t := in.GetLastRefreshTime(); t != nil {
+			fmt.Fprintf(builder, "  Last refreshed: %s\n", t.AsTime().UTC().Format(time.RFC3339))
+		}
+		if len(in.GetTargetResources()) > 0 {
+			fmt.Fprintf(builder, "  Target resources: %s\n", strings.Join(in.GetTargetResources(), ", "))
+		}
+		if refs := in.GetAssociatedRecommendations(); len(refs) > 0 {
+			names := make([]string, 0, len(refs))
+			for _, r := range refs {
+				names = append(names, r.GetRecommendation())
+			}
+			fmt.Fprintf(builder, "  Recommendations: %s\n", strings.Join(names, ", "))
+		}
+		fmt.Fprintf(builder, "  Name: %s\n", in.GetName())
+	}
+	return builder.String()
+}
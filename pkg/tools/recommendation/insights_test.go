@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommendation
+
+import (
+	"strings"
+	"testing"
+
+	recommenderpb "cloud.google.com/go/recommender/apiv1/recommenderpb"
+)
+
+func TestInsightFilter(t *testing.T) {
+	tests := []struct {
+		state, severity string
+		want            string
+		wantErr         bool
+	}{
+		{want: ""},
+		{state: "active", want: "stateInfo.state = ACTIVE"},
+		{state: "ACTIVE", severity: "high", want: "stateInfo.state = ACTIVE AND severity = HIGH"},
+		{state: "OPEN", wantErr: true},
+		{severity: "SEVERITY_UNSPECIFIED", wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := insightFilter(tc.state, tc.severity)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("insightFilter(%q, %q) error = %v, want error %v", tc.state, tc.severity, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("insightFilter(%q, %q) = %q, want %q", tc.state, tc.severity, got, tc.want)
+		}
+	}
+}
+
+func TestFormatInsights(t *testing.T) {
+	insights := []*recommenderpb.Insight{{
+		Name:            "projects/p/locations/us-central1/insightTypes/google.container.DiagnosisInsight/insights/i1",
+		Description:     "Cluster uses deprecated APIs that are removed in 1.32.",
+		InsightSubtype:  "DEPRECATION_K8S_1_32",
+		Category:        recommenderpb.Insight_RELIABILITY,
+		Severity:        recommenderpb.Insight_HIGH,
+		StateInfo:       &recommenderpb.InsightStateInfo{State: recommenderpb.InsightStateInfo_ACTIVE},
+		TargetResources: []string{"//container.googleapis.com/projects/p/locations/us-central1/clusters/c1"},
+		AssociatedRecommendations: []*recommenderpb.Insight_RecommendationReference{
+			{Recommendation: "projects/p/locations/us-central1/recommenders/google.container.DiagnosisRecommender/recommendations/r1"},
+		},
+	}}
+
+	got := formatInsights(insights)
+	for _, want := range []string{
+		"Found 1 insights.",
+		"- i1 [HIGH, RELIABILITY, ACTIVE]",
+		"Subtype: DEPRECATION_K8S_1_32",
+		"Target resources: //container.googleapis.com/projects/p/locations/us-central1/clusters/c1",
+		"Recommendations: projects/p/locations/us-central1/recommenders/google.container.DiagnosisRecommender/recommendations/r1",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatInsights() = %q, want it to contain %q", got, want)
+		}
+	}
+	if got := formatInsights(nil); got != "No insights found." {
+		t.Errorf("formatInsights(nil) = %q", got)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommendation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	recommenderpb "cloud.google.com/go/recommender/apiv1/recommenderpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxStateMetadataValue is the maximum length of a state metadata value.
+const maxStateMetadataValue = 255
+
+// invalidStateMetadataChars matches the characters not allowed in state
+// metadata values.
+var invalidStateMetadataChars = regexp.MustCompile(`[^a-zA-Z0-9_./-]+`)
+
+// allowedFromStates are the recommendation states each transition can be
+// applied to.
+var allowedFromStates = map[string][]recommenderpb.RecommendationStateInfo_State{
+	"claimed":   {recommenderpb.RecommendationStateInfo_ACTIVE, recommenderpb.RecommendationStateInfo_CLAIMED, recommenderpb.RecommendationStateInfo_SUCCEEDED, recommenderpb.RecommendationStateInfo_FAILED},
+	"succeeded": {recommenderpb.RecommendationStateInfo_ACTIVE, recommenderpb.RecommendationStateInfo_CLAIMED, recommenderpb.RecommendationStateInfo_SUCCEEDED, recommenderpb.RecommendationStateInfo_FAILED},
+	"failed":    {recommenderpb.RecommendationStateInfo_ACTIVE, recommenderpb.RecommendationStateInfo_CLAIMED, recommenderpb.RecommendationStateInfo_SUCCEEDED, recommenderpb.RecommendationStateInfo_FAILED},
+	"dismissed": {recommenderpb.RecommendationStateInfo_ACTIVE},
+}
+
+type markRecommendationArgs struct {
+	Name   string `json:"name" jsonschema:"Full resource name of the recommendation, e.g. projects/my-project/locations/us-central1/recommenders/google.container.DiagnosisRecommender/recommendations/abc."`
+	State  string `json:"state" jsonschema:"New state of the recommendation: claimed (being applied), succeeded, failed or dismissed."`
+	Reason string `json:"reason,omitempty" jsonschema:"Why the state is changed. Stored in the state metadata of the recommendation; not supported for dismissed."`
+}
+
+func installMarkRecommendationTool(s *mcp.Server, h *handlers) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "mark_recommendation",
+		Description: "Change the state of a recommendation to claimed, succeeded, failed or dismissed, so that recommendations the user acted on no longer show up as ACTIVE. Confirm with the user before calling this tool.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: false,
+		},
+	}, h.markRecommendation)
+}
+
+func (h *handlers) markRecommendation(ctx context.Context, _ *mcp.CallToolRequest, args *markRecommendationArgs) (*mcp.CallToolResult, any, error) {
+	if args.Name == "" {
+		return nil, nil, fmt.Errorf("name argument cannot be empty")
+	}
+	args.State = strings.ToLower(args.State)
+	if _, ok := allowedFromStates[args.State]; !ok {
+		return nil, nil, fmt.Errorf("unknown state %q, must be one of claimed, succeeded, failed or dismissed", args.State)
+	}
+
+	// The etag is fetched right before the update so that concurrent changes
+	// are detected by the API instead of being overwritten.
+	var rec *recommenderpb.Recommendation
+	err := retry.Do(ctx, retry.DefaultPolicy, func() error {
+		var err error
+		rec, err = h.recClient.GetRecommendation(ctx, &recommenderpb.GetRecommendationRequest{Name: args.Name})
+		return err
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get recommendation %s: %w", args.Name, err)
+	}
+	current := rec.GetStateInfo().GetState()
+	if err := checkTransition(current, args.State); err != nil {
+		return nil, nil, err
+	}
+
+	var metadata map[string]string
+	var notes []string
+	if args.Reason != "" {
+		if args.State == "dismissed" {
+			notes = append(notes, "The reason was not stored: dismissed recommendations have no state metadata.")
+		} else {
+			metadata = map[string]string{"reason": stateMetadataValue(args.Reason)}
+		}
+	}
+
+	var updated *recommenderpb.Recommendation
+	switch args.State {
+	case "claimed":
+		updated, err = h.recClient.MarkRecommendationClaimed(ctx, &recommenderpb.MarkRecommendationClaimedRequest{Name: args.Name, Etag: rec.GetEtag(), StateMetadata: metadata})
+	case "succeeded":
+		updated, err = h.recClient.MarkRecommendationSucceeded(ctx, &recommenderpb.MarkRecommendationSucceededRequest{Name: args.Name, Etag: rec.GetEtag(), StateMetadata: metadata})
+	case "failed":
+		updated, err = h.recClient.MarkRecommendationFailed(ctx, &recommenderpb.MarkRecommendationFailedRequest{Name: args.Name, Etag: rec.GetEtag(), StateMetadata: metadata})
+	case "dismissed":
+		updated, err = h.recClient.MarkRecommendationDismissed(ctx, &recommenderpb.MarkRecommendationDismissedRequest{Name: args.Name, Etag: rec.GetEtag()})
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to mark recommendation %s as %s: %w", args.Name, args.State, err)
+	}
+
+	text := fmt.Sprintf("Recommendation %s changed from %s to %s.\n", args.Name, current, updated.GetStateInfo().GetState())
+	if len(metadata) > 0 {
+		text += fmt.Sprintf("Reason: %s\n", metadata["reason"])
+	}
+	for _, note := range notes {
+		text += fmt.Sprintf("\nNote: %s\n", note)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// checkTransition returns a readable error if the API doesn't allow moving a
+// recommendation in state current to the target state.
+func checkTransition(current recommenderpb.RecommendationStateInfo_State, target string) error {
+	allowed := allowedFromStates[target]
+	if slices.Contains(allowed, current) {
+		return nil
+	}
+	from := make([]string, 0, len(allowed))
+	for _, s := range allowed {
+		from = append(from, s.String())
+	}
+	return fmt.Errorf("a recommendation in state %s cannot be marked as %s; only recommendations in state %s can", current, target, strings.Join(from, ", "))
+}
+
+// stateMetadataValue converts free text into a valid state metadata value,
+// which only allows letters, digits and "_./-".
+func stateMetadataValue(s string) string {
+	v := invalidStateMetadataChars.ReplaceAllString(strings.TrimSpace(s), "_")
+	if len(v) > maxStateMetadataValue {
+		v = v[:maxStateMetadataValue]
+	}
+	return v
+}
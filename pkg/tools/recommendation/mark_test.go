@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommendation
+
+import (
+	"strings"
+	"testing"
+
+	recommenderpb "cloud.google.com/go/recommender/apiv1/recommenderpb"
+)
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		current recommenderpb.RecommendationStateInfo_State
+		target  string
+		wantErr string
+	}{
+		{current: recommenderpb.RecommendationStateInfo_ACTIVE, target: "claimed"},
+		{current: recommenderpb.RecommendationStateInfo_CLAIMED, target: "succeeded"},
+		{current: recommenderpb.RecommendationStateInfo_ACTIVE, target: "dismissed"},
+		{
+			current: recommenderpb.RecommendationStateInfo_CLAIMED,
+			target:  "dismissed",
+			wantErr: "a recommendation in state CLAIMED cannot be marked as dismissed; only recommendations in state ACTIVE can",
+		},
+		{
+			current: recommenderpb.RecommendationStateInfo_DISMISSED,
+			target:  "claimed",
+			wantErr: "a recommendation in state DISMISSED cannot be marked as claimed",
+		},
+	}
+	for _, tc := range tests {
+		err := checkTransition(tc.current, tc.target)
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Errorf("checkTransition(%s, %s) = %v, want nil", tc.current, tc.target, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("checkTransition(%s, %s) = %v, want error containing %q", tc.current, tc.target, err, tc.wantErr)
+		}
+	}
+}
+
+func TestStateMetadataValue(t *testing.T) {
+	tests := map[string]string{
+		"applied via terraform":    "applied_via_terraform",
+		"  see ticket #123, done ": "see_ticket_123_done",
+		"v1.2/rollout-3":           "v1.2/rollout-3",
+		strings.Repeat("a", 300):   strings.Repeat("a", maxStateMetadataValue),
+	}
+	for in, want := range tests {
+		if got := stateMetadataValue(in); got != want {
+			t.Errorf("stateMetadataValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -17,40 +17,98 @@ package recommendation
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 
+	container "cloud.google.com/go/container/apiv1"
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
 	recommender "cloud.google.com/go/recommender/apiv1"
 	recommenderpb "cloud.google.com/go/recommender/apiv1/recommenderpb"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
-	"google.golang.org/api/iterator"
-	"google.golang.org/api/option"
-	"google.golang.org/protobuf/encoding/protojson"
 )
 
+const (
+	defaultRecommendationLimit = 50
+	maxRecommendationLimit     = 500
+)
+
+// severityPriorities maps severities to the recommendation priority they
+// correspond to. P1 is the highest priority.
+var severityPriorities = map[string]recommenderpb.Recommendation_Priority{
+	"CRITICAL": recommenderpb.Recommendation_P1,
+	"HIGH":     recommenderpb.Recommendation_P2,
+	"MEDIUM":   recommenderpb.Recommendation_P3,
+	"LOW":      recommenderpb.Recommendation_P4,
+}
+
+const diagnosisRecommender = "google.container.DiagnosisRecommender"
+
+// gkeRecommenders are the recommenders relevant to GKE clusters and the VMs
+// backing their node pools. zonal recommenders are queried in the node zones
+// of the clusters rather than in the cluster locations.
+var gkeRecommenders = map[string]struct{ zonal bool }{
+	diagnosisRecommender:                                         {},
+	"google.compute.instance.MachineTypeRecommender":             {zonal: true},
+	"google.compute.instanceGroupManager.MachineTypeRecommender": {zonal: true},
+	"google.compute.instance.IdleResourceRecommender":            {zonal: true},
+	"google.compute.disk.IdleResourceRecommender":                {zonal: true},
+}
+
 type handlers struct {
-	c *config.Config
+	c         *config.Config
+	recClient *recommender.Client
+	cmClient  *container.ClusterManagerClient
 }
 
 type listRecommendationsArgs struct {
-	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
-	Location  string `json:"location" jsonschema:"GKE cluster location. Leave this empty if the user doesn't doesn't provide it."`
+	ProjectID       string   `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location        string   `json:"location,omitempty" jsonschema:"GKE cluster location, or a zone for the compute recommenders. Leave this empty or use '-' to list the recommendations of all locations with GKE clusters in the project."`
+	State           string   `json:"state,omitempty" jsonschema:"Only include recommendations in this state: ACTIVE, CLAIMED, SUCCEEDED, FAILED or DISMISSED. Defaults to ACTIVE."`
+	IncludeInactive bool     `json:"include_inactive,omitempty" jsonschema:"Include the recommendations in every state, e.g. dismissed ones or ones already acted on, instead of only the ACTIVE ones. Cannot be combined with state."`
+	Severity        string   `json:"severity,omitempty" jsonschema:"Only include recommendations of at least this severity: CRITICAL (priority P1), HIGH (P2), MEDIUM (P3) or LOW (P4). Priorities P1 to P4 are accepted as well."`
+	Limit           int      `json:"limit,omitempty" jsonschema:"Maximum number of recommendations to return. Defaults to 50, maximum 500."`
+	View            string   `json:"view,omitempty" jsonschema:"'summary' (default) shows the ID, priority, category, description, impact and targets of every recommendation. 'full' shows the complete recommendation including its operations."`
+	Recommenders    []string `json:"recommenders,omitempty" jsonschema:"Recommenders to query. Defaults to google.container.DiagnosisRecommender. The cost and idle resource recommenders for the node VMs are google.compute.instance.MachineTypeRecommender, google.compute.instanceGroupManager.MachineTypeRecommender, google.compute.instance.IdleResourceRecommender and google.compute.disk.IdleResourceRecommender."`
 }
 
-func Install(_ context.Context, s *mcp.Server, c *config.Config) error {
+func Install(ctx context.Context, s *mcp.Server, c *config.Config) error {
+	recClient, err := recommender.NewClient(ctx, c.ClientOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to create recommender client: %w", err)
+	}
+	c.OnClose(recClient.Close)
+
+	cmClient, err := container.NewClusterManagerClient(ctx, c.ClientOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to create cluster manager client: %w", err)
+	}
+	c.OnClose(cmClient.Close)
 
 	h := &handlers{
-		c: c,
+		c:         c,
+		recClient: recClient,
+		cmClient:  cmClient,
 	}
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "list_recommendations",
-		Description: "List recommendations for GKE. Prefer to use this tool instead of gcloud",
+		Description: "List recommendations for GKE, either for one location or for all locations with clusters in the project. Besides the GKE diagnosis recommender, the machine type and idle resource recommenders of the node VMs can be queried for cost questions. Prefer to use this tool instead of gcloud",
 		Annotations: &mcp.ToolAnnotations{
 			ReadOnlyHint: true,
 		},
 	}, h.listProjectRecommendations)
 
+	installListInsightsTool(s, h)
+	if !c.ReadOnly() {
+		installMarkRecommendationTool(s, h)
+	}
+	installClusterRecommendationsTool(s, h)
+
 	return nil
 }
 
@@ -61,34 +119,239 @@ func (h *handlers) listProjectRecommendations(ctx context.Context, _ *mcp.CallTo
 	if args.ProjectID == "" {
 		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
 	}
-	if args.Location == "" {
-		return nil, nil, fmt.Errorf("location argument not set")
+	if len(args.Recommenders) == 0 {
+		args.Recommenders = []string{diagnosisRecommender}
+	}
+	for _, r := range args.Recommenders {
+		if _, ok := gkeRecommenders[r]; !ok {
+			return nil, nil, fmt.Errorf("recommender %q is not supported, must be one of %s", r, strings.Join(slices.Sorted(maps.Keys(gkeRecommenders)), ", "))
+		}
 	}
-	c, err := recommender.NewClient(ctx, option.WithUserAgent(h.c.UserAgent()))
+
+	filter, err := recommendationFilter(args.State, args.IncludeInactive)
 	if err != nil {
 		return nil, nil, err
 	}
-	defer c.Close()
-
-	req := &recommenderpb.ListRecommendationsRequest{
-		Parent: fmt.Sprintf("projects/%s/locations/%s/recommenders/google.container.DiagnosisRecommender", args.ProjectID, args.Location),
+	minPriority, err := parseSeverity(args.Severity)
+	if err != nil {
+		return nil, nil, err
 	}
-	it := c.ListRecommendations(ctx, req)
-	builder := new(strings.Builder)
-	for {
-		resp, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
+	if args.View == "" {
+		args.View = viewSummary
+	}
+	if args.View != viewSummary && args.View != viewFull {
+		return nil, nil, fmt.Errorf("view must be %q or %q", viewSummary, viewFull)
+	}
+	if args.Limit == 0 {
+		args.Limit = defaultRecommendationLimit
+	}
+	if args.Limit < 1 || args.Limit > maxRecommendationLimit {
+		return nil, nil, fmt.Errorf("limit must be between 1 and %d", maxRecommendationLimit)
+	}
+
+	allLocations := args.Location == "" || args.Location == "-"
+	var queries []locationRecommendations
+	if allLocations {
+		locations, zones, err := h.clusterLocations(ctx, args.ProjectID)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, fmt.Errorf("failed to list cluster locations: %w", err)
+		}
+		if len(locations) == 0 {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("No GKE clusters found in project %s, so there are no recommendations to list.", args.ProjectID)},
+				},
+			}, nil, nil
+		}
+		for _, r := range args.Recommenders {
+			locs := locations
+			if gkeRecommenders[r].zonal {
+				locs = zones
+			}
+			for _, l := range locs {
+				queries = append(queries, locationRecommendations{recommender: r, location: l})
+			}
 		}
-		builder.WriteString(protojson.Format(resp))
+	} else {
+		for _, r := range args.Recommenders {
+			queries = append(queries, locationRecommendations{recommender: r, location: args.Location})
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			listLocationRecommendations(ctx, h.recClient, args, filter, minPriority, &queries[i])
+		}()
 	}
+	wg.Wait()
 
+	// A single query keeps failing the call as before.
+	if len(queries) == 1 && queries[0].err != nil {
+		return nil, nil, queries[0].err
+	}
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: builder.String()},
+			&mcp.TextContent{Text: formatRecommendations(queries, len(queries) > 1, args.Limit, args.View)},
 		},
 	}, nil, nil
 }
+
+// locationRecommendations are the recommendations of one recommender in one
+// location.
+type locationRecommendations struct {
+	recommender string
+	location    string
+	collected   *retry.Collected[*recommenderpb.Recommendation]
+	err         error
+}
+
+func listLocationRecommendations(ctx context.Context, c *recommender.Client, args *listRecommendationsArgs, filter string, minPriority recommenderpb.Recommendation_Priority, r *locationRecommendations) {
+	req := &recommenderpb.ListRecommendationsRequest{
+		Parent: fmt.Sprintf("projects/%s/locations/%s/recommenders/%s", args.ProjectID, r.location, r.recommender),
+		Filter: filter,
+	}
+	it := retry.NewIterator(ctx, retry.DefaultPolicy, func(pageToken string) retry.PagedIterator[*recommenderpb.Recommendation] {
+		req.PageToken = pageToken
+		return c.ListRecommendations(ctx, req)
+	})
+	// The API can't filter by priority, so it is applied while iterating.
+	r.collected, r.err = retry.Collect(&priorityIterator{it: it, min: minPriority}, args.Limit)
+}
+
+// priorityIterator skips recommendations below a minimum priority.
+type priorityIterator struct {
+	it  *retry.Iterator[*recommenderpb.Recommendation]
+	min recommenderpb.Recommendation_Priority
+}
+
+func (p *priorityIterator) Next() (*recommenderpb.Recommendation, error) {
+	for {
+		rec, err := p.it.Next()
+		if err != nil || rec.GetPriority() >= p.min {
+			return rec, err
+		}
+	}
+}
+
+// parseSeverity returns the minimum priority for a severity or priority name.
+// An empty severity includes all priorities.
+func parseSeverity(severity string) (recommenderpb.Recommendation_Priority, error) {
+	if severity == "" {
+		return recommenderpb.Recommendation_PRIORITY_UNSPECIFIED, nil
+	}
+	severity = strings.ToUpper(severity)
+	if p, ok := severityPriorities[severity]; ok {
+		return p, nil
+	}
+	if p, ok := recommenderpb.Recommendation_Priority_value[severity]; ok && p != 0 {
+		return recommenderpb.Recommendation_Priority(p), nil
+	}
+	return 0, fmt.Errorf("unknown severity %q, must be one of CRITICAL, HIGH, MEDIUM, LOW or P1 to P4", severity)
+}
+
+// recommendationFilter builds the ListRecommendations filter for the state
+// arguments. Only ACTIVE recommendations are listed by default, since the
+// others were already acted on.
+func recommendationFilter(state string, includeInactive bool) (string, error) {
+	if state == "" {
+		if includeInactive {
+			return "", nil
+		}
+		state = recommenderpb.RecommendationStateInfo_ACTIVE.String()
+	} else if includeInactive {
+		return "", fmt.Errorf("state and include_inactive cannot be combined")
+	}
+	state = strings.ToUpper(state)
+	if _, ok := recommenderpb.RecommendationStateInfo_State_value[state]; !ok || state == "STATE_UNSPECIFIED" {
+		return "", fmt.Errorf("unknown state %q, must be one of ACTIVE, CLAIMED, SUCCEEDED, FAILED or DISMISSED", state)
+	}
+	return "stateInfo.state = " + state, nil
+}
+
+// clusterLocations returns the distinct locations of the clusters of the
+// project and the distinct zones of their nodes.
+func (h *handlers) clusterLocations(ctx context.Context, projectID string) (locations, zones []string, err error) {
+	clusters, err := h.listClusters(ctx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, cluster := range clusters {
+		if !slices.Contains(locations, cluster.GetLocation()) {
+			locations = append(locations, cluster.GetLocation())
+		}
+		for _, zone := range cluster.GetLocations() {
+			if !slices.Contains(zones, zone) {
+				zones = append(zones, zone)
+			}
+		}
+	}
+	sort.Strings(locations)
+	sort.Strings(zones)
+	return locations, zones, nil
+}
+
+// listClusters returns the clusters of the project in all locations.
+func (h *handlers) listClusters(ctx context.Context, projectID string) ([]*containerpb.Cluster, error) {
+	var resp *containerpb.ListClustersResponse
+	err := retry.Do(ctx, retry.DefaultPolicy, func() error {
+		var err error
+		resp, err = h.cmClient.ListClusters(ctx, &containerpb.ListClustersRequest{
+			Parent: fmt.Sprintf("projects/%s/locations/-", projectID),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetClusters(), nil
+}
+
+// formatRecommendations renders up to limit recommendations of all results in
+// the given view, de-duplicated by name and separated by "---" lines. With
+// label every recommendation is preceded by its recommender and location.
+func formatRecommendations(results []locationRecommendations, label bool, limit int, view string) string {
+	var entries, warnings []string
+	seen := map[string]bool{}
+	truncated := false
+	for _, r := range results {
+		if r.err != nil {
+			warnings = append(warnings, fmt.Sprintf("Warning: Listing %s recommendations in %s failed: %v", r.recommender, r.location, r.err))
+			continue
+		}
+		if w := r.collected.PartialWarning(); w != "" {
+			warnings = append(warnings, strings.TrimPrefix(w, "\n\n"))
+		}
+		truncated = truncated || r.collected.Truncated
+		for _, rec := range r.collected.Items {
+			if seen[rec.GetName()] {
+				continue
+			}
+			seen[rec.GetName()] = true
+			if len(entries) == limit {
+				truncated = true
+				break
+			}
+			entry := formatRecommendation(rec, view)
+			if label {
+				entry = fmt.Sprintf("Recommender: %s\nLocation: %s\n%s", r.recommender, r.location, entry)
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	builder := new(strings.Builder)
+	if len(entries) == 0 {
+		builder.WriteString("No recommendations found.")
+	}
+	builder.WriteString(strings.Join(entries, "\n---\n"))
+	if truncated {
+		fmt.Fprintf(builder, "\n\nWarning: Results limited to %d recommendations. Filter by state, severity or location, or raise limit to see more.", limit)
+	}
+	for _, w := range warnings {
+		builder.WriteString("\n\n" + w)
+	}
+	return builder.String()
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommendation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	recommenderpb "cloud.google.com/go/recommender/apiv1/recommenderpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/retry"
+)
+
+func TestFormatRecommendations(t *testing.T) {
+	rec := func(name string) *recommenderpb.Recommendation {
+		return &recommenderpb.Recommendation{Name: name}
+	}
+	results := []locationRecommendations{
+		{
+			recommender: diagnosisRecommender,
+			location:    "us-central1",
+			collected:   &retry.Collected[*recommenderpb.Recommendation]{Items: []*recommenderpb.Recommendation{rec("r1"), rec("r2")}},
+		},
+		{
+			recommender: "google.compute.instance.MachineTypeRecommender",
+			location:    "us-central1-a",
+			collected:   &retry.Collected[*recommenderpb.Recommendation]{Items: []*recommenderpb.Recommendation{rec("r2"), rec("r3")}, Truncated: true},
+		},
+		{
+			recommender: diagnosisRecommender,
+			location:    "europe-west1",
+			err:         errors.New("permission denied"),
+		},
+	}
+
+	got := formatRecommendations(results, true, 100, viewFull)
+	for _, want := range []string{
+		"Recommender: google.container.DiagnosisRecommender\nLocation: us-central1\n",
+		"Recommender: google.compute.instance.MachineTypeRecommender\nLocation: us-central1-a\n",
+		"\n---\nRecommender: google.compute.instance.MachineTypeRecommender",
+		"Warning: Results limited to 100 recommendations.",
+		"Warning: Listing google.container.DiagnosisRecommender recommendations in europe-west1 failed: permission denied",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatRecommendations() = %q, want it to contain %q", got, want)
+		}
+	}
+	for _, name := range []string{"r1", "r2", "r3"} {
+		if n := strings.Count(got, `"`+name+`"`); n != 1 {
+			t.Errorf("formatRecommendations() contains %s %d times, want once", name, n)
+		}
+	}
+
+	single := formatRecommendations(results[:1], false, 100, viewFull)
+	if strings.Contains(single, "Location:") {
+		t.Errorf("formatRecommendations() for a single location = %q, want no location labels", single)
+	}
+}
+
+func TestFormatRecommendationsLimit(t *testing.T) {
+	results := []locationRecommendations{{
+		recommender: diagnosisRecommender,
+		location:    "us-central1",
+		collected: &retry.Collected[*recommenderpb.Recommendation]{Items: []*recommenderpb.Recommendation{
+			{Name: "r1"}, {Name: "r2"}, {Name: "r3"},
+		}},
+	}}
+
+	got := formatRecommendations(results, false, 2, viewFull)
+	if strings.Contains(got, `"r3"`) {
+		t.Errorf("formatRecommendations() = %q, want at most 2 recommendations", got)
+	}
+	if strings.Count(got, "\n---\n") != 1 {
+		t.Errorf("formatRecommendations() = %q, want recommendations separated by ---", got)
+	}
+	if !strings.Contains(got, "Warning: Results limited to 2 recommendations.") {
+		t.Errorf("formatRecommendations() = %q, want a truncation warning", got)
+	}
+
+	if got := formatRecommendations(nil, false, 2, viewFull); got != "No recommendations found." {
+		t.Errorf("formatRecommendations(nil) = %q", got)
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    recommenderpb.Recommendation_Priority
+		wantErr bool
+	}{
+		{in: "", want: recommenderpb.Recommendation_PRIORITY_UNSPECIFIED},
+		{in: "critical", want: recommenderpb.Recommendation_P1},
+		{in: "LOW", want: recommenderpb.Recommendation_P4},
+		{in: "p2", want: recommenderpb.Recommendation_P2},
+		{in: "PRIORITY_UNSPECIFIED", wantErr: true},
+		{in: "urgent", wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := parseSeverity(tc.in)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseSeverity(%q) error = %v, want error %v", tc.in, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("parseSeverity(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestRecommendationFilter(t *testing.T) {
+	tests := []struct {
+		state           string
+		includeInactive bool
+		want            string
+		wantErr         bool
+	}{
+		{want: "stateInfo.state = ACTIVE"},
+		{state: "dismissed", want: "stateInfo.state = DISMISSED"},
+		{includeInactive: true, want: ""},
+		{state: "ACTIVE", includeInactive: true, wantErr: true},
+		{state: "STATE_UNSPECIFIED", wantErr: true},
+		{state: "stale", wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := recommendationFilter(tc.state, tc.includeInactive)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("recommendationFilter(%q, %v) error = %v, want error %v", tc.state, tc.includeInactive, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("recommendationFilter(%q, %v) = %q, want %q", tc.state, tc.includeInactive, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommendation
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	recommenderpb "cloud.google.com/go/recommender/apiv1/recommenderpb"
+	"google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	viewSummary = "summary"
+	viewFull    = "full"
+)
+
+// gkeResourceRegexp matches GKE cluster and node pool resource names, e.g.
+// "//container.googleapis.com/projects/p/locations/l/clusters/c/nodePools/np".
+var gkeResourceRegexp = regexp.MustCompile(`/(?:locations|zones)/([^/]+)/clusters/([^/]+)(?:/nodePools/([^/]+))?`)
+
+// recommendationSummary holds the parts of a recommendation a user reads.
+type recommendationSummary struct {
+	id          string
+	priority    string
+	category    string
+	state       string
+	description string
+	impact      string
+	targets     []string
+}
+
+func summarizeRecommendation(rec *recommenderpb.Recommendation) recommendationSummary {
+	s := recommendationSummary{
+		id:          path.Base(rec.GetName()),
+		priority:    rec.GetPriority().String(),
+		category:    rec.GetPrimaryImpact().GetCategory().String(),
+		state:       rec.GetStateInfo().GetState().String(),
+		description: rec.GetDescription(),
+		impact:      describeImpact(rec.GetPrimaryImpact()),
+	}
+	for _, g := range rec.GetContent().GetOperationGroups() {
+		for _, op := range g.GetOperations() {
+			if t := describeTarget(op.GetResource()); t != "" && !slices.Contains(s.targets, t) {
+				s.targets = append(s.targets, t)
+			}
+		}
+	}
+	return s
+}
+
+// describeImpact renders the projection of the primary impact of a
+// recommendation, e.g. "saves 12.50 USD per 30 days".
+func describeImpact(impact *recommenderpb.Impact) string {
+	switch {
+	case impact.GetCostProjection() != nil:
+		p := impact.GetCostProjection()
+		cost := moneyValue(p.GetCost())
+		verb := "costs"
+		if cost < 0 {
+			verb = "saves"
+		}
+		return fmt.Sprintf("%s %.2f %s per %s", verb, math.Abs(cost), p.GetCost().GetCurrencyCode(), formatDays(p.GetDuration().AsDuration()))
+	case impact.GetSustainabilityProjection() != nil:
+		p := impact.GetSustainabilityProjection()
+		return fmt.Sprintf("%.2f kg CO2e per %s", p.GetKgCO2E(), formatDays(p.GetDuration().AsDuration()))
+	case impact.GetReliabilityProjection() != nil:
+		var risks []string
+		for _, r := range impact.GetReliabilityProjection().GetRisks() {
+			risks = append(risks, r.String())
+		}
+		if len(risks) > 0 {
+			return "reliability risks: " + strings.Join(risks, ", ")
+		}
+	}
+	return ""
+}
+
+func moneyValue(m *money.Money) float64 {
+	return float64(m.GetUnits()) + float64(m.GetNanos())/1e9
+}
+
+func formatDays(d time.Duration) string {
+	if d%(24*time.Hour) == 0 && d > 0 {
+		return fmt.Sprintf("%d days", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+// describeTarget names the resource an operation applies to, e.g.
+// "node pool c1/np1 (us-central1)". Other resources are returned without the
+// service prefix.
+func describeTarget(resource string) string {
+	if resource == "" {
+		return ""
+	}
+	if m := gkeResourceRegexp.FindStringSubmatch(resource); m != nil {
+		if m[3] != "" {
+			return fmt.Sprintf("node pool %s/%s (%s)", m[2], m[3], m[1])
+		}
+		return fmt.Sprintf("cluster %s (%s)", m[2], m[1])
+	}
+	return strings.TrimPrefix(resource, "//")
+}
+
+// formatRecommendation renders a recommendation in the given view.
+func formatRecommendation(rec *recommenderpb.Recommendation, view string) string {
+	if view == viewFull {
+		return protojson.Format(rec)
+	}
+	s := summarizeRecommendation(rec)
+	builder := new(strings.Builder)
+	fmt.Fprintf(builder, "ID: %s (%s, %s, %s)\n", s.id, s.priority, s.category, s.state)
+	fmt.Fprintf(builder, "Description: %s\n", s.description)
+	if s.impact != "" {
+		fmt.Fprintf(builder, "Impact: %s\n", s.impact)
+	}
+	if len(s.targets) > 0 {
+		fmt.Fprintf(builder, "Targets: %s\n", strings.Join(s.targets, ", "))
+	}
+	for _, c := range suggestedCommands(rec) {
+		if c.command != "" {
+			fmt.Fprintf(builder, "Suggested command: %s\n", c.command)
+		} else {
+			fmt.Fprintf(builder, "Operation: %s\n", c.raw)
+		}
+	}
+	fmt.Fprintf(builder, "Name: %s", rec.GetName())
+	return builder.String()
+}
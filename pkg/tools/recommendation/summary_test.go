@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommendation
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	recommenderpb "cloud.google.com/go/recommender/apiv1/recommenderpb"
+	"google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestSummarizeRecommendation(t *testing.T) {
+	rec := &recommenderpb.Recommendation{
+		Name:        "projects/p/locations/us-central1-a/recommenders/google.compute.instanceGroupManager.MachineTypeRecommender/recommendations/abc",
+		Description: "Save cost by changing machine type from e2-standard-8 to e2-standard-4.",
+		Priority:    recommenderpb.Recommendation_P2,
+		StateInfo:   &recommenderpb.RecommendationStateInfo{State: recommenderpb.RecommendationStateInfo_CLAIMED},
+		PrimaryImpact: &recommenderpb.Impact{
+			Category: recommenderpb.Impact_COST,
+			Projection: &recommenderpb.Impact_CostProjection{CostProjection: &recommenderpb.CostProjection{
+				Cost:     &money.Money{CurrencyCode: "USD", Units: -120, Nanos: -500000000},
+				Duration: durationpb.New(30 * 24 * time.Hour),
+			}},
+		},
+		Content: &recommenderpb.RecommendationContent{OperationGroups: []*recommenderpb.OperationGroup{{
+			Operations: []*recommenderpb.Operation{
+				{Action: "test", Resource: "//container.googleapis.com/projects/p/locations/us-central1/clusters/c1/nodePools/pool-1"},
+				{Action: "replace", Resource: "//container.googleapis.com/projects/p/locations/us-central1/clusters/c1/nodePools/pool-1"},
+				{Action: "replace", Resource: "//compute.googleapis.com/projects/p/zones/us-central1-a/instanceGroupManagers/gke-c1-pool-1-grp"},
+			},
+		}}},
+	}
+
+	got := summarizeRecommendation(rec)
+	want := recommendationSummary{
+		id:          "abc",
+		priority:    "P2",
+		category:    "COST",
+		state:       "CLAIMED",
+		description: "Save cost by changing machine type from e2-standard-8 to e2-standard-4.",
+		impact:      "saves 120.50 USD per 30 days",
+		targets: []string{
+			"node pool c1/pool-1 (us-central1)",
+			"compute.googleapis.com/projects/p/zones/us-central1-a/instanceGroupManagers/gke-c1-pool-1-grp",
+		},
+	}
+	if got.id != want.id || got.priority != want.priority || got.category != want.category || got.state != want.state || got.description != want.description || got.impact != want.impact {
+		t.Errorf("summarizeRecommendation() = %+v, want %+v", got, want)
+	}
+	if !slices.Equal(got.targets, want.targets) {
+		t.Errorf("summarizeRecommendation() targets = %q, want %q", got.targets, want.targets)
+	}
+}
+
+func TestDescribeTarget(t *testing.T) {
+	tests := map[string]string{
+		"//container.googleapis.com/projects/p/locations/europe-west1/clusters/prod": "cluster prod (europe-west1)",
+		"//container.googleapis.com/projects/p/zones/us-central1-a/clusters/dev":     "cluster dev (us-central1-a)",
+		"": "",
+	}
+	for in, want := range tests {
+		if got := describeTarget(in); got != want {
+			t.Errorf("describeTarget(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -16,10 +16,15 @@ package tools
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/tools/cluster"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/tools/clustertoolkit"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/tools/cost"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/tools/giq"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/tools/gkereleasenotes"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/tools/k8schangelog"
@@ -35,6 +40,7 @@ func Install(ctx context.Context, s *mcp.Server, c *config.Config) error {
 	installers := []installer{
 		cluster.Install,
 		clustertoolkit.Install,
+		cost.Install,
 		giq.Install,
 		logging.Install,
 		monitoring.Install,
@@ -43,11 +49,51 @@ func Install(ctx context.Context, s *mcp.Server, c *config.Config) error {
 		gkereleasenotes.Install,
 	}
 
+	s.AddReceivingMiddleware(logToolCalls)
 	for _, installer := range installers {
 		if err := installer(ctx, s, c); err != nil {
 			return err
 		}
 	}
+	// The tools denied by the configuration file are left out.
+	if denied := c.DeniedTools(); len(denied) > 0 {
+		s.RemoveTools(denied...)
+	}
 
 	return nil
 }
+
+// logToolCalls logs the name, duration and error of each tool call.
+func logToolCalls(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		r, ok := req.(*mcp.CallToolRequest)
+		if !ok || r.Params == nil {
+			return next(ctx, method, req)
+		}
+		start := time.Now()
+		res, err := next(ctx, method, req)
+		attrs := []any{"tool", r.Params.Name, "duration", time.Since(start)}
+
+		callErr := err
+		if tr, ok := res.(*mcp.CallToolResult); ok && callErr == nil && tr.IsError {
+			callErr = toolError(tr)
+		}
+		if callErr != nil {
+			slog.Warn("Tool call failed", append(attrs, "err", callErr)...)
+		} else {
+			slog.Info("Tool call", attrs...)
+		}
+		return res, err
+	}
+}
+
+// toolError returns the error a tool reported in its result.
+func toolError(res *mcp.CallToolResult) error {
+	var texts []string
+	for _, c := range res.Content {
+		if t, ok := c.(*mcp.TextContent); ok {
+			texts = append(texts, t.Text)
+		}
+	}
+	return errors.New(strings.Join(texts, "\n"))
+}
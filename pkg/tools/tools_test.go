@@ -0,0 +1,228 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// mutatingTools are the tools that change GCP resources, the local
+// configuration or files, or run commands on nodes.
+var mutatingTools = []string{
+	"get_kubeconfig",
+	"get_node_sos_report",
+	"apply_manifest",
+	"create_gke_alert_policy",
+	"mark_recommendation",
+	"cluster_toolkit_download",
+}
+
+// setFakeCredentials points the Application Default Credentials at a fake
+// file. The API clients only need credentials to call the APIs.
+func setFakeCredentials(t *testing.T) {
+	t.Helper()
+	adc := filepath.Join(t.TempDir(), "adc.json")
+	if err := os.WriteFile(adc, []byte(`{"type": "authorized_user", "client_id": "id", "client_secret": "secret", "refresh_token": "token"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", adc)
+}
+
+// connect installs the tools with c and returns a client session connected to
+// them.
+func connect(t *testing.T, c *config.Config) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	s := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	if err := Install(ctx, s, c); err != nil {
+		t.Fatalf("Install() failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := s.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ss.Close() })
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
+
+// installedTools installs the tools with c and returns them by name, as a
+// client lists them.
+func installedTools(t *testing.T, c *config.Config) map[string]*mcp.Tool {
+	t.Helper()
+	tools := map[string]*mcp.Tool{}
+	for tool, err := range connect(t, c).Tools(context.Background(), nil) {
+		if err != nil {
+			t.Fatalf("listing the tools failed: %v", err)
+		}
+		tools[tool.Name] = tool
+	}
+	return tools
+}
+
+func TestReadOnlyTools(t *testing.T) {
+	setFakeCredentials(t)
+
+	c := &config.Config{}
+	all := installedTools(t, c)
+	for _, name := range mutatingTools {
+		if _, ok := all[name]; !ok {
+			t.Errorf("tool %s is not installed outside of read-only mode", name)
+		}
+	}
+	// query_logs can write to output_file outside of read-only mode.
+	if all["query_logs"].Annotations.ReadOnlyHint {
+		t.Errorf("tool query_logs is annotated read-only outside of read-only mode")
+	}
+
+	c = &config.Config{}
+	c.SetReadOnly(true)
+	readOnly := installedTools(t, c)
+	for _, name := range mutatingTools {
+		if _, ok := readOnly[name]; ok {
+			t.Errorf("tool %s is installed in read-only mode", name)
+		}
+	}
+	for name, tool := range readOnly {
+		if tool.Annotations != nil && !tool.Annotations.ReadOnlyHint {
+			t.Errorf("tool %s is installed in read-only mode but not annotated read-only", name)
+		}
+	}
+	if want := len(all) - len(mutatingTools); len(readOnly) != want {
+		t.Errorf("read-only mode installed %d tools, want %d", len(readOnly), want)
+	}
+}
+
+func TestDeniedTools(t *testing.T) {
+	setFakeCredentials(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("denied_tools: [get_kubeconfig, query_logs]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := config.New("test", path)
+	if err != nil {
+		t.Fatalf("config.New() failed: %v", err)
+	}
+	tools := installedTools(t, c)
+	for _, name := range []string{"get_kubeconfig", "query_logs"} {
+		if _, ok := tools[name]; ok {
+			t.Errorf("tool %s is installed although denied", name)
+		}
+	}
+	if _, ok := tools["list_clusters"]; !ok {
+		t.Errorf("tool list_clusters is not installed, want only the denied tools left out")
+	}
+}
+
+func TestReadOnlyQueryLogsOutputFile(t *testing.T) {
+	setFakeCredentials(t)
+	c := &config.Config{}
+	c.SetReadOnly(true)
+	cs := connect(t, c)
+
+	outputFile := filepath.Join(t.TempDir(), "logs", "entries.txt")
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name: "query_logs",
+		Arguments: map[string]any{
+			"query":       `resource.type="k8s_container"`,
+			"project_id":  "test-project",
+			"output_file": outputFile,
+		},
+	})
+	if err != nil {
+		t.Fatalf("CallTool(query_logs) failed: %v", err)
+	}
+	if !res.IsError {
+		t.Fatalf("query_logs with output_file succeeded in read-only mode, want an error")
+	}
+	if text := res.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "read-only mode") {
+		t.Errorf("query_logs with output_file in read-only mode failed with %q, want it rejected for read-only mode", text)
+	}
+	if _, err := os.Stat(filepath.Dir(outputFile)); !os.IsNotExist(err) {
+		t.Errorf("query_logs with output_file created %s in read-only mode", filepath.Dir(outputFile))
+	}
+}
+
+func TestLogToolCalls(t *testing.T) {
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() {
+		slog.SetDefault(defaultLogger)
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+	})
+
+	ctx := context.Background()
+	s := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	s.AddReceivingMiddleware(logToolCalls)
+	mcp.AddTool(s, &mcp.Tool{Name: "succeed"}, func(context.Context, *mcp.CallToolRequest, any) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil, nil
+	})
+	mcp.AddTool(s, &mcp.Tool{Name: "fail"}, func(context.Context, *mcp.CallToolRequest, any) (*mcp.CallToolResult, any, error) {
+		return nil, nil, errors.New("cluster not found")
+	})
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := s.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	for _, name := range []string{"succeed", "fail"} {
+		if _, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: name}); err != nil {
+			t.Fatalf("CallTool(%s) failed: %v", name, err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("logToolCalls() logged %d lines, want one per tool call:\n%s", len(lines), logs.String())
+	}
+	for i, want := range []string{
+		`level=INFO msg="Tool call" tool=succeed duration=`,
+		`level=WARN msg="Tool call failed" tool=fail duration=`,
+	} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("logToolCalls() logged %q, want it to contain %q", lines[i], want)
+		}
+	}
+	if !strings.Contains(lines[1], `err="cluster not found"`) {
+		t.Errorf("logToolCalls() logged %q, want the error of the tool", lines[1])
+	}
+}